@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+)
+
+// AccessTokenProvider supplies the access tokens of the client from a central token service, replacing
+// the token fetching of the SDK entirely: tokens are neither fetched by the AppSecret nor cached.
+// Unlike a TokenSource, the provider is told of the tokens reported invalid by WeChat.
+type AccessTokenProvider interface {
+	// AccessToken returns the current access token of the app.
+	AccessToken(ctx context.Context) (string, error)
+
+	// RefreshAccessToken returns a new access token replacing the stale one reported invalid by WeChat
+	// (40001/40014/42001), the stale token is empty when a refresh is forced by the caller.
+	RefreshAccessToken(ctx context.Context, staleToken string) (string, error)
+}
+
+// WithAccessTokenProvider sets the provider of access tokens, replacing the token fetching of the SDK.
+func WithAccessTokenProvider(p AccessTokenProvider) func(*Client) {
+	return func(c *Client) {
+		c.AccessTokenProvider = p
+	}
+}
+
+// TokenProvider returns the external provider of the access tokens of the client: the AccessTokenProvider,
+// or else the TokenSource acquiring tokens again on refreshes. It returns nil if the SDK fetches the tokens.
+func (c *Client) TokenProvider() AccessTokenProvider {
+	if c.AccessTokenProvider != nil {
+		return c.AccessTokenProvider
+	}
+
+	if c.TokenSource != nil {
+		return tokenSourceProvider{c.TokenSource}
+	}
+
+	return nil
+}
+
+// tokenSourceProvider adapts a TokenSource to an AccessTokenProvider.
+type tokenSourceProvider struct {
+	TokenSource
+}
+
+func (p tokenSourceProvider) AccessToken(ctx context.Context) (string, error) {
+	return p.Token(ctx)
+}
+
+func (p tokenSourceProvider) RefreshAccessToken(ctx context.Context, _ string) (string, error) {
+	return p.Token(ctx)
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WeChat API domains, the backup and regional domains serve the same APIs for disaster recovery.
+const (
+	APIDomain         = "api.weixin.qq.com"
+	APIDomainBackup   = "api2.weixin.qq.com"
+	APIDomainShanghai = "sh.api.weixin.qq.com"
+	APIDomainShenzhen = "sz.api.weixin.qq.com"
+	APIDomainHongKong = "hk.api.weixin.qq.com"
+)
+
+// WithBaseURL sends the API requests to the base url instead of https://api.weixin.qq.com,
+// e.g. an internal forwarding proxy; the path of the base url prefixes the API paths.
+func WithBaseURL(baseURL string) func(*Client) {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithAPIDomain sends the API requests to the domain, e.g. APIDomainBackup.
+func WithAPIDomain(domain string) func(*Client) {
+	return WithBaseURL("https://" + domain)
+}
+
+// rewriteBaseURL points the request to api.weixin.qq.com at the base url of the client.
+func (c *Client) rewriteBaseURL(req *http.Request) (*http.Request, error) {
+	if c.BaseURL == "" || req.URL.Host != APIDomain {
+		return req, nil
+	}
+
+	base, err := url.Parse(c.BaseURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid base url: %s", c.BaseURL)
+	}
+
+	r := req.Clone(req.Context())
+	r.URL.Scheme = base.Scheme
+	r.URL.Host = base.Host
+	r.URL.Path = base.Path + req.URL.Path
+	r.URL.RawPath = ""
+	r.Host = base.Host
+
+	return r, nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseURL(t *testing.T) {
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("appid", "secret", WithBaseURL(server.URL+"/wechat/"))
+
+	resp, err := client.Get(context.Background(), "https://api.weixin.qq.com/cgi-bin/token?appid=appid")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	// requests to other hosts are kept
+	resp, err = client.Get(context.Background(), server.URL+"/other")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, []string{"/wechat/cgi-bin/token?appid=appid", "/other"}, paths)
+
+	assert.Equal(t, "https://api2.weixin.qq.com", NewClient("appid", "secret", WithAPIDomain(APIDomainBackup)).BaseURL)
+
+	_, err = NewClient("appid", "secret", WithBaseURL("://invalid")).Get(context.Background(), "https://api.weixin.qq.com/cgi-bin/token")
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request when the circuit of the endpoint is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of the circuit of an endpoint.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 正常放行
+	CircuitOpen                         // 熔断，请求直接失败
+	CircuitHalfOpen                     // 冷却结束，放行一个探测请求
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker fails the calls of an endpoint fast after consecutive failures (network errors,
+// 5xx responses and errcode -1), instead of blocking on the timeouts of an outage. After the
+// cooldown a single probe call is let through, closing the circuit on success and opening it
+// again on failure. Errors of the WeChat business logic do not count as failures.
+type CircuitBreaker struct {
+	Threshold int           // 连续失败多少次后熔断
+	Cooldown  time.Duration // 熔断多久后放行探测请求
+
+	// OnStateChange is called when the circuit of an endpoint changes state, e.g. to alert.
+	// It is called without holding the lock of the breaker, so it can query the states.
+	OnStateChange func(endpoint string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// stateChange is a state change of a circuit to be notified after the lock is released.
+type stateChange struct {
+	endpoint string
+	from, to CircuitState
+}
+
+// NewCircuitBreaker creates a circuit breaker opening after threshold consecutive failures of an
+// endpoint, and probing the endpoint after the cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker of the client, opening the circuit of an endpoint
+// after threshold consecutive failures and probing the endpoint after the cooldown.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.CircuitBreaker = NewCircuitBreaker(threshold, cooldown)
+	}
+}
+
+// State returns the state of the circuit of the endpoint.
+func (b *CircuitBreaker) State(endpoint string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ct := b.circuits[endpoint]; ct != nil {
+		return ct.state
+	}
+
+	return CircuitClosed
+}
+
+// Allow reports whether a call of the endpoint can be sent, it returns ErrCircuitOpen if the
+// circuit is open or a probe is already in flight. The result of an allowed call must be recorded.
+func (b *CircuitBreaker) Allow(endpoint string) error {
+	change, err := b.allow(endpoint)
+	b.notify(change)
+
+	return err
+}
+
+func (b *CircuitBreaker) allow(endpoint string) (*stateChange, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ct := b.circuits[endpoint]
+	if ct == nil || ct.state == CircuitClosed {
+		return nil, nil
+	}
+
+	if ct.state == CircuitOpen && time.Since(ct.openedAt) >= b.Cooldown {
+		return b.transit(endpoint, ct, CircuitHalfOpen), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, endpoint)
+}
+
+// Record records the result of an allowed call of the endpoint.
+func (b *CircuitBreaker) Record(endpoint string, success bool) {
+	b.notify(b.record(endpoint, success))
+}
+
+func (b *CircuitBreaker) record(endpoint string, success bool) *stateChange {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ct := b.circuits[endpoint]
+	if ct == nil {
+		if success {
+			return nil
+		}
+
+		ct = &circuit{}
+		b.circuits[endpoint] = ct
+	}
+
+	if success {
+		ct.failures = 0
+		if ct.state != CircuitClosed {
+			return b.transit(endpoint, ct, CircuitClosed)
+		}
+		return nil
+	}
+
+	ct.failures++
+
+	if ct.state == CircuitHalfOpen || (ct.state == CircuitClosed && ct.failures >= b.Threshold) {
+		ct.openedAt = time.Now()
+		return b.transit(endpoint, ct, CircuitOpen)
+	}
+
+	return nil
+}
+
+// release gives up the probe of a call whose result is unknown, e.g. canceled by the caller.
+func (b *CircuitBreaker) release(endpoint string) {
+	b.mu.Lock()
+
+	var change *stateChange
+	if ct := b.circuits[endpoint]; ct != nil && ct.state == CircuitHalfOpen {
+		// keep the cooldown elapsed so that the next call probes at once
+		change = b.transit(endpoint, ct, CircuitOpen)
+	}
+
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// transit changes the state of the circuit, the caller must hold the lock and notify the returned change.
+func (b *CircuitBreaker) transit(endpoint string, ct *circuit, to CircuitState) *stateChange {
+	from := ct.state
+	ct.state = to
+
+	return &stateChange{endpoint: endpoint, from: from, to: to}
+}
+
+func (b *CircuitBreaker) notify(change *stateChange) {
+	if change != nil && b.OnStateChange != nil {
+		b.OnStateChange(change.endpoint, change.from, change.to)
+	}
+}
+
+// breakCircuit records the result of the call of the endpoint to the circuit breaker.
+func (c *Client) breakCircuit(ctx context.Context, endpoint string, resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			c.CircuitBreaker.release(endpoint)
+		} else {
+			c.CircuitBreaker.Record(endpoint, false)
+		}
+
+		return resp, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		c.CircuitBreaker.Record(endpoint, false)
+		return resp, nil
+	}
+
+	resp, errCode, err := peekErrCode(resp)
+	if err != nil {
+		c.CircuitBreaker.Record(endpoint, false)
+		return nil, err
+	}
+
+	c.CircuitBreaker.Record(endpoint, errCode != ErrCodeSystemBusy)
+
+	return resp, nil
+}
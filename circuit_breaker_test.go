@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system error"}`))
+			return
+		}
+
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	var transitions []string
+	client := NewClient("appid", "secret", WithRetryPolicy(nil), WithCircuitBreaker(2, 50*time.Millisecond))
+	client.CircuitBreaker.OnStateChange = func(endpoint string, from, to CircuitState) {
+		transitions = append(transitions, endpoint+" "+from.String()+"->"+to.String())
+		// the states can be queried by the callback
+		assert.Equal(t, to, client.CircuitBreaker.State(endpoint))
+	}
+
+	ctx := context.Background()
+	get := func(path string) error {
+		resp, err := client.Get(ctx, server.URL+path)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.NoError(t, get("/wxa/msg_sec_check"))
+	assert.NoError(t, get("/wxa/msg_sec_check"))
+	assert.Equal(t, CircuitOpen, client.CircuitBreaker.State("/wxa/msg_sec_check"))
+
+	// calls fail fast while open, other endpoints are not affected
+	assert.ErrorIs(t, get("/wxa/msg_sec_check"), ErrCircuitOpen)
+	assert.NoError(t, get("/wxa/generate_urllink"))
+
+	// the probe after the cooldown opens the circuit again on failure
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, get("/wxa/msg_sec_check"))
+	assert.ErrorIs(t, get("/wxa/msg_sec_check"), ErrCircuitOpen)
+
+	// and closes it on success
+	down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, get("/wxa/msg_sec_check"))
+	assert.Equal(t, CircuitClosed, client.CircuitBreaker.State("/wxa/msg_sec_check"))
+
+	assert.Equal(t, []string{
+		"/wxa/msg_sec_check closed->open",
+		"/wxa/msg_sec_check open->half-open",
+		"/wxa/msg_sec_check half-open->open",
+		"/wxa/msg_sec_check open->half-open",
+		"/wxa/msg_sec_check half-open->closed",
+	}, transitions)
+
+	// system busy counts as failure
+	assert.NoError(t, get("/busy"))
+	assert.NoError(t, get("/busy"))
+	assert.ErrorIs(t, get("/busy"), ErrCircuitOpen)
+}
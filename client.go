@@ -20,6 +20,9 @@ package vwx
 
 import (
 	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -30,8 +33,74 @@ type Client struct {
 
 	EnvVersion string // release, trial, develop
 
+	// EnvProfiles holds the settings of the env versions switched per call by the WithEnv option.
+	EnvProfiles map[string]*EnvProfile
+
+	// DryRun short-circuits mutating calls (e.g. subscribe message sending),
+	// logging what would have been sent and returning synthesized success responses.
+	DryRun bool
+
 	CacheKeyPrefix string
 	CacheProvider  CacheProvider
+
+	// StableToken acquires access tokens from the stable_token endpoint, which does not invalidate
+	// the tokens issued before.
+	StableToken bool
+
+	// TokenSource supplies access tokens instead of fetching them with the AppSecret.
+	TokenSource TokenSource
+
+	// AccessTokenProvider supplies and refreshes access tokens instead of the SDK, it takes precedence over the TokenSource.
+	AccessTokenProvider AccessTokenProvider
+
+	// EndpointTokenSources supply the access tokens of groups of endpoints instead of the client, e.g. the
+	// sensitive apis served by a dedicated app.
+	EndpointTokenSources []*EndpointTokenSource
+
+	// Locker makes the instances sharing the cache provider refresh tokens and tickets one at a time, nil disables it.
+	Locker Locker
+
+	// TokenRefresher fetches a new access token when WeChat reports the access token of a call invalid
+	// (40001/40014/42001), the call is then sent once more with the new token. It is set by vwxauth.NewService.
+	TokenRefresher func(ctx context.Context, staleToken string) (string, error)
+
+	// BaseURL replaces https://api.weixin.qq.com of the API requests, e.g. a backup domain or a forwarding proxy.
+	BaseURL string
+
+	// RateLimiter limits the calls per endpoint to the quotas of WeChat, nil disables it.
+	RateLimiter *RateLimiter
+
+	// CircuitBreaker fails the calls of an endpoint fast during WeChat outages, nil disables it.
+	CircuitBreaker *CircuitBreaker
+
+	// RetryPolicy retries idempotent calls failing with transient errors, nil disables retrying.
+	RetryPolicy *RetryPolicy
+
+	HTTPClient *http.Client
+
+	// Proxy is the forward proxy of the API requests, nil uses the proxy of the environment.
+	Proxy *url.URL
+
+	// TLSConfig configures the TLS connections to WeChat, nil uses the default config.
+	TLSConfig *tls.Config
+
+	// MaxResponseSize limits the size of the responses read from the apis, DefaultMaxResponseSize if not positive.
+	MaxResponseSize int64
+
+	// Interceptors wrap every outbound request, the first one is the outermost.
+	Interceptors []Interceptor
+
+	// Logger logs the calls of the client and the services created on it, vlog by default.
+	Logger Logger
+
+	// LogRedaction masks access tokens, session keys, openids and phone numbers in the logs, enabled by default.
+	LogRedaction bool
+
+	// Metrics collects the metrics of the client and the services created on it, nil disables them.
+	Metrics MetricsCollector
+
+	// MetricsHook observes the calls to the WeChat APIs, nil disables it.
+	MetricsHook MetricsHook
 }
 
 // CacheProvider defines the interface for caching access tokens and other data.
@@ -40,18 +109,35 @@ type CacheProvider interface {
 	Set(ctx context.Context, key string, value string, expire time.Duration) error
 }
 
+// CacheDeleter is implemented by cache providers supporting the deletion of keys,
+// the cached access token is deleted before a forced refresh.
+type CacheDeleter interface {
+	Delete(ctx context.Context, key string) error
+}
+
 // NewClient creates a new WeChat Mini Program API client with the given app ID and secret.
 func NewClient(appID, appSecret string, options ...func(*Client)) *Client {
 	c := &Client{
-		AppID:      appID,
-		AppSecret:  appSecret,
-		EnvVersion: "release",
+		AppID:         appID,
+		AppSecret:     appSecret,
+		EnvVersion:    "release",
+		HTTPClient:    http.DefaultClient,
+		RetryPolicy:   DefaultRetryPolicy(),
+		CacheProvider: NewMemoryCache(),
+		Logger:        vlogLogger{},
+		LogRedaction:  true,
 	}
 
 	for _, option := range options {
 		option(c)
 	}
 
+	if _, nop := c.Logger.(NopLogger); c.LogRedaction && !nop {
+		c.Logger = RedactLogger(c.Logger)
+	}
+
+	c.configureTransport()
+
 	return c
 }
 
@@ -62,6 +148,14 @@ func WithEnvVersion(env string) func(*Client) {
 	}
 }
 
+// WithDryRun enables or disables the dry-run mode, protecting staging environments
+// from accidentally notifying real users.
+func WithDryRun(dryRun bool) func(*Client) {
+	return func(c *Client) {
+		c.DryRun = dryRun
+	}
+}
+
 // WithCacheKeyPrefix sets the cache key prefix for the client.
 func WithCacheKeyPrefix(prefix string) func(*Client) {
 	return func(c *Client) {
@@ -69,9 +163,24 @@ func WithCacheKeyPrefix(prefix string) func(*Client) {
 	}
 }
 
-// WithCacheProvider sets the cache provider for the client.
+// WithCacheProvider sets the cache provider for the client, replacing the default in-memory cache.
+// A nil provider disables caching.
 func WithCacheProvider(provider CacheProvider) func(*Client) {
 	return func(c *Client) {
 		c.CacheProvider = provider
 	}
 }
+
+// WithStableToken switches the access token acquisition to the stable_token endpoint.
+func WithStableToken(stable bool) func(*Client) {
+	return func(c *Client) {
+		c.StableToken = stable
+	}
+}
+
+// WithHTTPClient sets the http client used to call WeChat APIs.
+func WithHTTPClient(httpClient *http.Client) func(*Client) {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
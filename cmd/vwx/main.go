@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command vwx is an operator utility built on the vwx library, for debugging
+// WeChat production configuration.
+//
+// Usage:
+//
+//	vwx [global flags] <command> [command flags]
+//
+// Global flags:
+//
+//	-appid   app id, default $VWX_APPID
+//	-secret  app secret, default $VWX_SECRET
+//	-env     mini program env version (release, trial, develop)
+//
+// Commands:
+//
+//	token      fetch an access token
+//	urllink    generate a URL Link
+//	urlscheme  generate a URL Scheme
+//	qrcode     generate an unlimited QR code to a file
+//	subscribe  send a test subscribe message
+//	seccheck   run msg_sec_check on a string
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxauth"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(client *vwx.Client, args []string) error
+}
+
+var commands = []*command{
+	{name: "token", usage: "fetch an access token", run: runToken},
+	{name: "urllink", usage: "generate a URL Link", run: runURLLink},
+	{name: "urlscheme", usage: "generate a URL Scheme", run: runURLScheme},
+	{name: "qrcode", usage: "generate an unlimited QR code to a file", run: runQRCode},
+	{name: "subscribe", usage: "send a test subscribe message", run: runSubscribe},
+	{name: "seccheck", usage: "run msg_sec_check on a string", run: runSecCheck},
+}
+
+func main() {
+	appID := flag.String("appid", os.Getenv("VWX_APPID"), "app id, default $VWX_APPID")
+	appSecret := flag.String("secret", os.Getenv("VWX_SECRET"), "app secret, default $VWX_SECRET")
+	envVersion := flag.String("env", "release", "mini program env version (release, trial, develop)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if *appID == "" || *appSecret == "" {
+		fatalf("appid and secret are required")
+	}
+
+	client := vwx.NewClient(*appID, *appSecret, vwx.WithEnvVersion(*envVersion))
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(client, flag.Args()[1:]); err != nil {
+				fatalf("%s: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fatalf("unknown command: %s", name)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: vwx [global flags] <command> [command flags]\n\nGlobal flags:\n")
+	flag.PrintDefaults()
+	_, _ = fmt.Fprintf(out, "\nCommands:\n")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, "vwx: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func runToken(client *vwx.Client, _ []string) error {
+	token, err := vwxauth.NewService(client).GetAccessToken()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+func runURLLink(client *vwx.Client, args []string) error {
+	fs := flag.NewFlagSet("urllink", flag.ExitOnError)
+	path := fs.String("path", "", "mini program page path")
+	query := fs.String("query", "", "mini program page query")
+	days := fs.Int("days", 0, "expire interval days, 0 for the default expiration")
+	_ = fs.Parse(args)
+
+	svc := vwxa.NewService(client)
+
+	var (
+		link string
+		err  error
+	)
+	if *days > 0 {
+		link, err = svc.GenerateIntervalURLLink(*path, *query, *days)
+	} else {
+		link, err = svc.GenerateSimpleURLLink(*path, *query)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(link)
+	return nil
+}
+
+func runURLScheme(client *vwx.Client, args []string) error {
+	fs := flag.NewFlagSet("urlscheme", flag.ExitOnError)
+	path := fs.String("path", "", "mini program page path")
+	query := fs.String("query", "", "mini program page query")
+	days := fs.Int("days", 0, "expire interval days, 0 for the default expiration")
+	_ = fs.Parse(args)
+
+	svc := vwxa.NewService(client)
+
+	var (
+		scheme string
+		err    error
+	)
+	if *days > 0 {
+		scheme, err = svc.GenerateIntervalURLScheme(*path, *query, *days)
+	} else {
+		scheme, err = svc.GenerateSimpleURLScheme(*path, *query)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(scheme)
+	return nil
+}
+
+func runQRCode(client *vwx.Client, args []string) error {
+	fs := flag.NewFlagSet("qrcode", flag.ExitOnError)
+	scene := fs.String("scene", "", "scene value")
+	page := fs.String("page", "", "mini program page")
+	output := fs.String("o", "qrcode.png", "output file")
+	_ = fs.Parse(args)
+
+	data, err := vwxa.NewService(client).GenerateQRCode(*scene, *page)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("qrcode written to %s (%d bytes)\n", *output, len(data))
+	return nil
+}
+
+func runSubscribe(client *vwx.Client, args []string) error {
+	fs := flag.NewFlagSet("subscribe", flag.ExitOnError)
+	openID := fs.String("openid", "", "receiver openid")
+	templateID := fs.String("template", "", "template id")
+	page := fs.String("page", "", "mini program page")
+	data := fs.String("data", "", "template data, e.g. thing1=hello,time2=2024-01-01 12:00")
+	_ = fs.Parse(args)
+
+	values, err := parseKeyValues(*data)
+	if err != nil {
+		return err
+	}
+
+	resp, err := vwxa.NewService(client).SendSubscribeMessageSimple(*openID, *templateID, *page, values)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("sent: %d %s\n", resp.ErrCode, resp.ErrMsg)
+	return nil
+}
+
+func runSecCheck(client *vwx.Client, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("content is required")
+	}
+
+	safe, err := vwxa.NewService(client).IsMsgContentSafe(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	if safe {
+		fmt.Println("pass")
+	} else {
+		fmt.Println("risky")
+	}
+	return nil
+}
+
+func parseKeyValues(s string) (map[string]string, error) {
+	values := make(map[string]string)
+	if s == "" {
+		return values, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid data pair: %s", pair)
+		}
+		values[strings.TrimSpace(k)] = v
+	}
+
+	return values, nil
+}
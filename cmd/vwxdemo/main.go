@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command vwxdemo is an end-to-end example HTTP server wiring the push receiver and
+// dispatcher, subscribe message sending and web OAuth together. It serves as living
+// documentation and a smoke test target.
+//
+// Configuration is read from environment variables:
+//
+//	VWX_ADDR              listen address, default :8080
+//	VWX_APPID             mini program app id
+//	VWX_SECRET            mini program app secret
+//	VWX_MP_APPID          official account app id, default $VWX_APPID
+//	VWX_MP_SECRET         official account app secret, default $VWX_SECRET
+//	VWX_PUSH_TOKEN        push token
+//	VWX_PUSH_AES_KEY      push encoding aes key
+//	VWX_PUSH_MODE         push security mode: plain, secure
+//	VWX_PUSH_DATA_TYPE    push data type: xml, json
+//	VWX_OAUTH_REDIRECT    oauth callback url, e.g. https://example.com/oauth/callback
+//
+// Routes:
+//
+//	GET|POST /wx/push         WeChat push endpoint
+//	GET      /oauth/login     redirect to the WeChat authorization page
+//	GET      /oauth/callback  exchange the code and return the user profile
+//	POST     /subscribe/send  send a subscribe message, body: {"openid","template_id","page","data"}
+//	GET      /healthz         health check
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+type server struct {
+	receiver      *vwxpush.WxPushReceiver
+	dispatcher    *vwxpush.Dispatcher
+	wxaSvc        *vwxa.Service
+	mpSvc         *vwxmp.Service
+	oauthRedirect string
+}
+
+func main() {
+	appID := os.Getenv("VWX_APPID")
+	appSecret := os.Getenv("VWX_SECRET")
+
+	s := &server{
+		receiver: vwxpush.NewWxPushReceiver(
+			appID,
+			os.Getenv("VWX_PUSH_TOKEN"),
+			os.Getenv("VWX_PUSH_AES_KEY"),
+			getenv("VWX_PUSH_MODE", "plain"),
+			getenv("VWX_PUSH_DATA_TYPE", "xml"),
+		),
+		dispatcher:    vwxpush.NewDispatcher(),
+		wxaSvc:        vwxa.NewService(vwx.NewClient(appID, appSecret)),
+		mpSvc:         vwxmp.NewService(vwx.NewClient(getenv("VWX_MP_APPID", appID), getenv("VWX_MP_SECRET", appSecret))),
+		oauthRedirect: os.Getenv("VWX_OAUTH_REDIRECT"),
+	}
+
+	s.registerHandlers()
+
+	mux := http.NewServeMux()
+	mux.Handle("/wx/push", s.receiver.HTTPHandler(s.dispatcher.Dispatch))
+	mux.HandleFunc("/oauth/login", s.handleOAuthLogin)
+	mux.HandleFunc("/oauth/callback", s.handleOAuthCallback)
+	mux.HandleFunc("/subscribe/send", s.handleSubscribeSend)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	addr := getenv("VWX_ADDR", ":8080")
+	vlog.Infof("vwxdemo listening on %s", addr)
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		vlog.Fatalf("listen error: %v", err)
+	}
+}
+
+func (s *server) registerHandlers() {
+	s.dispatcher.HandleEvent("wxa_media_check", func(_ string, _ *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+		result, err := s.wxaSvc.ParseMediaCheckCallback(data)
+		if err != nil {
+			return nil, err
+		}
+
+		info := s.wxaSvc.CheckMediaViolation(result)
+		vlog.Infof("media check result | trace_id: %s | violation: %v | reason: %s",
+			result.TraceID, info.IsViolation, info.Reason)
+		return nil, nil
+	})
+
+	s.dispatcher.HandleEvent("subscribe_msg_popup_event", func(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+		vlog.Infof("subscribe popup | user: %s | data: %s", baseInfo.FromUserName, string(data))
+		return nil, nil
+	})
+
+	s.dispatcher.HandleDefault(func(_ string, baseInfo *vwxpush.PushBaseInfo, _ []byte) ([]byte, error) {
+		vlog.Infof("unhandled push | msg_type: %s | event: %s | user: %s",
+			baseInfo.MsgType, baseInfo.Event, baseInfo.FromUserName)
+		return nil, nil
+	})
+}
+
+func (s *server) handleOAuthLogin(w http.ResponseWriter, r *http.Request) {
+	authorizeURL := s.mpSvc.BuildAuthorizeURL(s.oauthRedirect, vwxmp.ScopeUserInfo, r.URL.Query().Get("state"), false)
+	http.Redirect(w, r, authorizeURL, http.StatusFound)
+}
+
+func (s *server) handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	token, err := s.mpSvc.GetOAuthAccessTokenContext(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	userInfo, err := s.mpSvc.GetUserInfoContext(r.Context(), token.AccessToken, token.OpenID, vwxmp.LangZhCN)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(w, userInfo)
+}
+
+func (s *server) handleSubscribeSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		OpenID     string            `json:"openid"`
+		TemplateID string            `json:"template_id"`
+		Page       string            `json:"page"`
+		Data       map[string]string `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.wxaSvc.SendSubscribeMessageSimpleContext(r.Context(), req.OpenID, req.TemplateID, req.Page, req.Data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func getenv(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"strings"
+)
+
+// Endpoint groups of the sensitive apis, deployments may serve them by a dedicated app.
+var (
+	// SecurityEndpoints are the content security apis.
+	SecurityEndpoints = []string{"/wxa/msg_sec_check", "/wxa/img_sec_check", "/wxa/media_check_async"}
+
+	// OCREndpoints are the OCR apis.
+	OCREndpoints = []string{"/cv/ocr/"}
+
+	// PhoneEndpoints are the phone number apis.
+	PhoneEndpoints = []string{"/wxa/business/getuserphonenumber"}
+
+	// RiskEndpoints are the user risk rank apis.
+	RiskEndpoints = []string{"/wxa/getuserriskrank"}
+)
+
+// EndpointTokenSource supplies the access tokens of a group of endpoints instead of the token
+// source of the client, e.g. the phone number and risk apis served by a dedicated appid and secret.
+type EndpointTokenSource struct {
+	Endpoints []string    // 接口路径，如 /wxa/msg_sec_check，以 / 结尾的匹配其下所有接口
+	Source    TokenSource // 该组接口的 access token 来源
+}
+
+// WithEndpointTokenSource supplies the access tokens of the endpoints by the token source, the source
+// of the first group matching an endpoint wins. If the source implements
+// RefreshAccessToken(ctx, staleToken string) (string, error), e.g. *vwxauth.Service, it refreshes the
+// tokens of the group reported invalid by WeChat, the source is asked again otherwise.
+func WithEndpointTokenSource(ts TokenSource, endpoints ...string) func(*Client) {
+	return func(c *Client) {
+		c.EndpointTokenSources = append(c.EndpointTokenSources, &EndpointTokenSource{Endpoints: endpoints, Source: ts})
+	}
+}
+
+// TokenSourceFor returns the token source of the endpoint group of the api url, nil if the access
+// token of the client serves the api.
+func (c *Client) TokenSourceFor(apiURL string) TokenSource {
+	if len(c.EndpointTokenSources) == 0 {
+		return nil
+	}
+
+	endpoint := endpointOf(apiURL)
+
+	for _, group := range c.EndpointTokenSources {
+		for _, e := range group.Endpoints {
+			if endpoint == e || (strings.HasSuffix(e, "/") && strings.HasPrefix(endpoint, e)) {
+				return group.Source
+			}
+		}
+	}
+
+	return nil
+}
+
+// tokenRefresher returns the refresher of the invalidated access tokens of the endpoint: the one of
+// its endpoint group, or else the TokenRefresher of the client.
+func (c *Client) tokenRefresher(endpoint string) func(ctx context.Context, staleToken string) (string, error) {
+	ts := c.TokenSourceFor(endpoint)
+	if ts == nil {
+		return c.TokenRefresher
+	}
+
+	if refresher, ok := ts.(interface {
+		RefreshAccessToken(ctx context.Context, staleToken string) (string, error)
+	}); ok {
+		return refresher.RefreshAccessToken
+	}
+
+	return func(ctx context.Context, _ string) (string, error) {
+		return ts.Token(ctx)
+	}
+}
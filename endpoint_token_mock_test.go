@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestEndpointTokenSource(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.AcceptAccessToken("risk-token")
+
+	// the first token of the dedicated app is reported invalid and refreshed from the same source
+	var calls atomic.Int32
+	riskTokens := vwx.TokenSourceFunc(func(context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			return "stale-risk-token", nil
+		}
+		return "risk-token", nil
+	})
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithEndpointTokenSource(riskTokens, vwx.SecurityEndpoints...))
+	svc := vwxa.NewService(client)
+
+	_, err := svc.MsgViolationCheck("hello")
+	assert.NoError(t, err)
+
+	requests := server.Requests("/wxa/msg_sec_check")
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "stale-risk-token", requests[0].Query.Get("access_token"))
+	assert.Equal(t, "risk-token", requests[1].Query.Get("access_token"))
+
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, server.Requests("/wxa/generate_urllink")[0].Query.Get("access_token"))
+
+	// the auth service of the dedicated app is a token source
+	riskClient := vwx.NewClient("risk-appid", "risk-secret", vwxmock.WithServer(server))
+	client = vwx.NewClient("appid", "", vwxmock.WithServer(server),
+		vwx.WithTokenSource(vwx.StaticTokenSource("main-token")),
+		vwx.WithEndpointTokenSource(vwxauth.NewService(riskClient), vwx.SecurityEndpoints...))
+
+	_, err = vwxa.NewService(client).MsgViolationCheck("hello")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, server.Requests("/wxa/msg_sec_check")[2].Query.Get("access_token"))
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSourceFor(t *testing.T) {
+	client := NewClient("appid", "secret",
+		WithEndpointTokenSource(StaticTokenSource("security"), SecurityEndpoints...),
+		WithEndpointTokenSource(StaticTokenSource("ocr"), OCREndpoints...),
+		WithEndpointTokenSource(StaticTokenSource("sensitive"), append(PhoneEndpoints, RiskEndpoints...)...))
+
+	tokenOf := func(apiURL string) string {
+		token, _ := client.TokenSourceFor(apiURL).Token(context.Background())
+		return token
+	}
+
+	assert.Equal(t, "security", tokenOf("https://api.weixin.qq.com/wxa/msg_sec_check?access_token=%s"))
+	assert.Equal(t, "ocr", tokenOf("https://api.weixin.qq.com/cv/ocr/idcard?type=photo&access_token=%s"))
+	assert.Equal(t, "sensitive", tokenOf("https://api.weixin.qq.com/wxa/business/getuserphonenumber?access_token=%s"))
+	assert.Equal(t, "sensitive", tokenOf("https://api.weixin.qq.com/wxa/getuserriskrank?access_token=%s"))
+	assert.Nil(t, client.TokenSourceFor("https://api.weixin.qq.com/wxa/msg_sec_check_v2?access_token=%s"))
+	assert.Nil(t, client.TokenSourceFor("https://api.weixin.qq.com/wxa/generate_urllink?access_token=%s"))
+
+	// tokens of the groups are refreshed by their source, the others by the refresher of the client
+	token, err := client.tokenRefresher("/cv/ocr/comm")(context.Background(), "stale")
+	assert.NoError(t, err)
+	assert.Equal(t, "ocr", token)
+	assert.Nil(t, client.tokenRefresher("/wxa/generate_urllink"))
+}
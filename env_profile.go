@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+const (
+	EnvRelease = "release" // 正式版
+	EnvTrial   = "trial"   // 体验版
+	EnvDevelop = "develop" // 开发版
+)
+
+// EnvProfile holds the settings of a mini program environment for the env_version dependent apis,
+// e.g. url link, url scheme, unlimited QR code, subscribe message and cloud storage.
+type EnvProfile struct {
+	EnvVersion       string // 小程序版本：release、trial、develop
+	MiniProgramState string // 订阅消息跳转小程序类型：formal、trial、developer
+	CheckPath        bool   // 生成小程序码时是否检查页面已发布，开发版和体验版需为false
+	CloudEnv         string // 云开发环境id，云存储接口未指定环境时使用
+}
+
+// defaultEnvProfile returns the profile of the env version when no profile is registered for it.
+func defaultEnvProfile(envVersion string) *EnvProfile {
+	state := "formal"
+	switch envVersion {
+	case EnvTrial:
+		state = "trial"
+	case EnvDevelop:
+		state = "developer"
+	}
+
+	return &EnvProfile{EnvVersion: envVersion, MiniProgramState: state}
+}
+
+// WithEnvProfile registers the profile of the env version, calls switch to it by the WithEnv option.
+func WithEnvProfile(env string, profile *EnvProfile) func(*Client) {
+	return func(c *Client) {
+		if c.EnvProfiles == nil {
+			c.EnvProfiles = make(map[string]*EnvProfile)
+		}
+
+		if profile.EnvVersion == "" {
+			profile.EnvVersion = env
+		}
+
+		c.EnvProfiles[env] = profile
+	}
+}
+
+// EnvProfile returns the profile of the env version, an empty env returns the profile of the EnvVersion
+// of the client. Env versions without registered profile get the default settings of the version.
+func (c *Client) EnvProfile(env string) *EnvProfile {
+	if env == "" {
+		env = c.EnvVersion
+	}
+
+	if profile, ok := c.EnvProfiles[env]; ok {
+		return profile
+	}
+
+	return defaultEnvProfile(env)
+}
+
+// WithEnv switches the call to the profile of the env version, e.g. to generate a url link of the
+// trial version with a client of the release version.
+func WithEnv(env string) RequestOption {
+	return func(o *RequestOptions) {
+		o.Env = env
+	}
+}
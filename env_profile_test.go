@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestEnvProfile(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithEnvProfile(vwx.EnvRelease, &vwx.EnvProfile{MiniProgramState: "formal", CheckPath: true}))
+	svc := vwxa.NewService(client)
+
+	_, err := svc.GenerateSimpleURLLink("/pages/index", "", vwx.WithEnv(vwx.EnvTrial))
+	assert.NoError(t, err)
+	_, err = svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	_, err = svc.GenerateQRCode("scene", "pages/index", vwx.WithEnv(vwx.EnvDevelop))
+	assert.NoError(t, err)
+	_, err = svc.SendSubscribeMessageSimple("openid", "template", "", map[string]string{"thing1": "hi"}, vwx.WithEnv(vwx.EnvDevelop))
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(server.Requests("/wxa/generate_urllink")[0].Body), `"env_version":"trial"`)
+
+	requests := server.Requests("/wxa/getwxacodeunlimit")
+	assert.Len(t, requests, 2)
+	assert.Contains(t, string(requests[0].Body), `"check_path":true,"env_version":"release"`)
+	assert.Contains(t, string(requests[1].Body), `"check_path":false,"env_version":"develop"`)
+
+	assert.Contains(t, string(server.Requests("/cgi-bin/message/subscribe/send")[0].Body), `"miniprogram_state":"developer"`)
+}
@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+const (
+	// ErrCodeInvalidOpenID is returned when the openid is invalid or not of the app.
+	ErrCodeInvalidOpenID = 40003
+	// ErrCodeInvalidMediaID is returned when the media id is invalid or expired.
+	ErrCodeInvalidMediaID = 40007
+	// ErrCodeInvalidAppID is returned when the appid is invalid.
+	ErrCodeInvalidAppID = 40013
+	// ErrCodeInvalidCode is returned when the login or OAuth code is invalid.
+	ErrCodeInvalidCode = 40029
+	// ErrCodeInvalidTemplateID is returned when the template id is invalid.
+	ErrCodeInvalidTemplateID = 40037
+	// ErrCodeInvalidAppSecret is returned when the app secret is invalid.
+	ErrCodeInvalidAppSecret = 40125
+	// ErrCodeCodeUsed is returned when the login or OAuth code has been used.
+	ErrCodeCodeUsed = 40163
+	// ErrCodeIPNotWhitelisted is returned when the caller ip is not in the api ip whitelist.
+	ErrCodeIPNotWhitelisted = 40164
+	// ErrCodeAccessTokenMissing is returned when the access token is missing.
+	ErrCodeAccessTokenMissing = 41001
+	// ErrCodeInvalidPage is returned when the page does not exist in the published mini program.
+	ErrCodeInvalidPage = 41030
+	// ErrCodeUserRefused is returned when the user refused to accept the message.
+	ErrCodeUserRefused = 43101
+	// ErrCodeResponseTimeLimit is returned when the user did not interact within 48 hours.
+	ErrCodeResponseTimeLimit = 45015
+	// ErrCodeResponseCountLimit is returned when the customer service messages to the user exceed the limit.
+	ErrCodeResponseCountLimit = 45047
+	// ErrCodeDataFormat is returned when the request data is malformed.
+	ErrCodeDataFormat = 47001
+	// ErrCodeAPIUnauthorized is returned when the app has no permission of the api.
+	ErrCodeAPIUnauthorized = 48001
+	// ErrCodeAPIBlocked is returned when the api is blocked for the app.
+	ErrCodeAPIBlocked = 48004
+	// ErrCodeUserUnauthorized is returned when the user did not authorize the api.
+	ErrCodeUserUnauthorized = 50001
+	// ErrCodeUserLimited is returned when the user is restricted.
+	ErrCodeUserLimited = 50002
+	// ErrCodeRiskyContent is returned when the content is risky.
+	ErrCodeRiskyContent = 87014
+)
+
+// ErrClass classifies errcodes by how the caller should handle them.
+type ErrClass string
+
+const (
+	// ErrClassUnknown is the class of errcodes not in the knowledge base.
+	ErrClassUnknown ErrClass = ""
+	// ErrClassSystem means WeChat is busy, the call can be retried.
+	ErrClassSystem ErrClass = "system"
+	// ErrClassToken means the access token is missing, invalid or expired, the call may succeed with a new one.
+	ErrClassToken ErrClass = "token"
+	// ErrClassConfig means the app credentials are wrong, the call fails until the configuration is fixed.
+	ErrClassConfig ErrClass = "config"
+	// ErrClassRateLimited means the quota of the api is reached, the call succeeds after the quota is reset.
+	ErrClassRateLimited ErrClass = "rate_limited"
+	// ErrClassPermission means the app, the caller ip or the user has no permission of the api.
+	ErrClassPermission ErrClass = "permission"
+	// ErrClassInvalidParam means the request is invalid, the same call never succeeds.
+	ErrClassInvalidParam ErrClass = "invalid_param"
+	// ErrClassRiskyContent means the content is rejected by the content security check.
+	ErrClassRiskyContent ErrClass = "risky_content"
+	// ErrClassUser means the user state does not allow the call, e.g. the user refused the messages or
+	// received too many customer service messages.
+	ErrClassUser ErrClass = "user"
+)
+
+// ErrCodeInfo describes a WeChat errcode.
+type ErrCodeInfo struct {
+	Code        int      // 错误码
+	Description string   // 错误说明
+	Class       ErrClass // 错误分类
+}
+
+// errCodes is the knowledge base of the common errcodes.
+var errCodes = map[int]*ErrCodeInfo{
+	ErrCodeSystemBusy:         {ErrCodeSystemBusy, "system busy, retry later", ErrClassSystem},
+	ErrCodeInvalidCredential:  {ErrCodeInvalidCredential, "invalid credential, access token is invalid or not latest", ErrClassToken},
+	ErrCodeInvalidAccessToken: {ErrCodeInvalidAccessToken, "invalid access token", ErrClassToken},
+	ErrCodeAccessTokenMissing: {ErrCodeAccessTokenMissing, "access token missing", ErrClassToken},
+	ErrCodeAccessTokenExpired: {ErrCodeAccessTokenExpired, "access token expired", ErrClassToken},
+	ErrCodeInvalidAppID:       {ErrCodeInvalidAppID, "invalid appid", ErrClassConfig},
+	ErrCodeInvalidAppSecret:   {ErrCodeInvalidAppSecret, "invalid appsecret", ErrClassConfig},
+	ErrCodeDailyQuotaLimit:    {ErrCodeDailyQuotaLimit, "daily api quota reached, reset at 0:00 Beijing time", ErrClassRateLimited},
+	ErrCodeMinuteQuotaLimit:   {ErrCodeMinuteQuotaLimit, "api called too frequently", ErrClassRateLimited},
+	ErrCodeIPNotWhitelisted:   {ErrCodeIPNotWhitelisted, "caller ip not in the api ip whitelist", ErrClassPermission},
+	ErrCodeAPIUnauthorized:    {ErrCodeAPIUnauthorized, "api unauthorized for the app", ErrClassPermission},
+	ErrCodeAPIBlocked:         {ErrCodeAPIBlocked, "api blocked for the app", ErrClassPermission},
+	ErrCodeUserUnauthorized:   {ErrCodeUserUnauthorized, "user unauthorized the api", ErrClassPermission},
+	ErrCodeUserLimited:        {ErrCodeUserLimited, "user restricted", ErrClassPermission},
+	ErrCodeInvalidOpenID:      {ErrCodeInvalidOpenID, "invalid openid", ErrClassInvalidParam},
+	ErrCodeInvalidMediaID:     {ErrCodeInvalidMediaID, "invalid or expired media id", ErrClassInvalidParam},
+	ErrCodeInvalidCode:        {ErrCodeInvalidCode, "invalid code", ErrClassInvalidParam},
+	ErrCodeInvalidTemplateID:  {ErrCodeInvalidTemplateID, "invalid template id", ErrClassInvalidParam},
+	ErrCodeCodeUsed:           {ErrCodeCodeUsed, "code has been used", ErrClassInvalidParam},
+	ErrCodeInvalidPage:        {ErrCodeInvalidPage, "page not found in the published mini program", ErrClassInvalidParam},
+	ErrCodeDataFormat:         {ErrCodeDataFormat, "request data format error", ErrClassInvalidParam},
+	ErrCodeRiskyContent:       {ErrCodeRiskyContent, "content is risky", ErrClassRiskyContent},
+	ErrCodeUserRefused:        {ErrCodeUserRefused, "user refused to accept the message", ErrClassUser},
+	ErrCodeResponseTimeLimit:  {ErrCodeResponseTimeLimit, "user did not interact within 48 hours", ErrClassUser},
+	ErrCodeResponseCountLimit: {ErrCodeResponseCountLimit, "customer service messages to the user exceed the limit", ErrClassUser},
+}
+
+// LookupErrCode returns the description of the errcode from the knowledge base.
+func LookupErrCode(code int) (*ErrCodeInfo, bool) {
+	info, ok := errCodes[code]
+
+	return info, ok
+}
+
+// ErrClassOf returns the class of the APIError in the chain of err, ErrClassUnknown if none or
+// if its errcode is not in the knowledge base.
+func ErrClassOf(err error) ErrClass {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return ErrClassUnknown
+	}
+
+	return apiErr.Class()
+}
+
+// IsRateLimited reports whether err is an APIError of a reached quota (45009/45011).
+func IsRateLimited(err error) bool {
+	return ErrClassOf(err) == ErrClassRateLimited
+}
+
+// IsTokenExpired reports whether err is an APIError of a missing, invalid or expired access token
+// (40001/40014/41001/42001).
+func IsTokenExpired(err error) bool {
+	return ErrClassOf(err) == ErrClassToken
+}
+
+// IsPermissionDenied reports whether err is an APIError of a missing permission of the app, the
+// caller ip or the user (40164/48001/48004/50001/50002).
+func IsPermissionDenied(err error) bool {
+	return ErrClassOf(err) == ErrClassPermission
+}
+
+// IsInvalidParam reports whether err is an APIError of an invalid request, e.g. 40003, 41030, 47001.
+func IsInvalidParam(err error) bool {
+	return ErrClassOf(err) == ErrClassInvalidParam
+}
+
+// IsRiskyContent reports whether err is an APIError of risky content (87014).
+func IsRiskyContent(err error) bool {
+	return ErrClassOf(err) == ErrClassRiskyContent
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrCodeClassification(t *testing.T) {
+	info, ok := LookupErrCode(ErrCodeInvalidPage)
+	assert.True(t, ok)
+	assert.Equal(t, ErrClassInvalidParam, info.Class)
+
+	_, ok = LookupErrCode(99999)
+	assert.False(t, ok)
+
+	wrap := func(code int) error {
+		return fmt.Errorf("send error: %w", NewAPIError("https://api.weixin.qq.com/wxa/msg_sec_check", code, "errmsg", "req-1"))
+	}
+
+	assert.True(t, IsRateLimited(wrap(ErrCodeDailyQuotaLimit)))
+	assert.True(t, IsRateLimited(wrap(ErrCodeMinuteQuotaLimit)))
+	assert.False(t, IsRateLimited(wrap(ErrCodeResponseCountLimit)))
+	assert.True(t, IsTokenExpired(wrap(ErrCodeAccessTokenExpired)))
+	assert.True(t, IsTokenExpired(wrap(ErrCodeInvalidCredential)))
+	assert.True(t, IsPermissionDenied(wrap(ErrCodeAPIUnauthorized)))
+	assert.True(t, IsPermissionDenied(wrap(ErrCodeIPNotWhitelisted)))
+	assert.True(t, IsInvalidParam(wrap(ErrCodeDataFormat)))
+	assert.True(t, IsRiskyContent(wrap(ErrCodeRiskyContent)))
+	assert.Equal(t, ErrClassUser, ErrClassOf(wrap(ErrCodeUserRefused)))
+	assert.Equal(t, ErrClassUnknown, ErrClassOf(wrap(99999)))
+	assert.Equal(t, ErrClassUnknown, ErrClassOf(errors.New("network error")))
+	assert.False(t, IsTokenExpired(nil))
+
+	apiErr := NewAPIError("/wxa/msg_sec_check", ErrCodeRiskyContent, "risky content hint: [abc]", "req-1")
+	assert.Equal(t, "content is risky", apiErr.Description())
+	assert.Equal(t, "unknown", NewAPIError("/wxa/msg_sec_check", 99999, "unknown", "req-1").Description())
+}
+
+// TestErrCodeClassConsistency keeps the knowledge base consistent with the APIError methods.
+func TestErrCodeClassConsistency(t *testing.T) {
+	for code, info := range errCodes {
+		assert.Equal(t, code, info.Code)
+
+		apiErr := &APIError{Code: code}
+		assert.Equal(t, info.Class == ErrClassRateLimited, apiErr.IsRateLimited(), code)
+		assert.Equal(t, info.Class == ErrClassSystem, apiErr.IsSystemBusy(), code)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ErrCodeInvalidCredential is returned when the access token or app secret is invalid.
+	ErrCodeInvalidCredential = 40001
+	// ErrCodeInvalidAccessToken is returned when the access token is malformed.
+	ErrCodeInvalidAccessToken = 40014
+	// ErrCodeAccessTokenExpired is returned when the access token is expired.
+	ErrCodeAccessTokenExpired = 42001
+	// ErrCodeDailyQuotaLimit is returned when the daily quota of the api is reached.
+	ErrCodeDailyQuotaLimit = 45009
+	// ErrCodeMinuteQuotaLimit is returned when the per-minute quota of the api is reached.
+	ErrCodeMinuteQuotaLimit = 45011
+)
+
+// APIError is returned when a WeChat api responds with a non-zero errcode.
+type APIError struct {
+	Code      int    // 微信返回的错误码 errcode
+	Msg       string // 微信返回的错误信息 errmsg
+	Endpoint  string // 接口路径，如 /wxa/generate_urllink
+	RequestID string // 本次调用的关联 id
+	RID       string // 微信返回的 rid，用于向微信反馈问题
+
+	Response *RawResponse // 原始响应，仅开启响应捕获时记录
+}
+
+// NewAPIError creates the error of the api of the url, the query of the url is dropped
+// so that the access token is never part of the error.
+func NewAPIError(apiURL string, code int, msg, requestID string) *APIError {
+	return &APIError{
+		Code:      code,
+		Msg:       msg,
+		Endpoint:  endpointOf(apiURL),
+		RequestID: requestID,
+		RID:       ParseRID(msg),
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Response != nil {
+		return fmt.Sprintf("wechat error: %d %s | endpoint: %s | request_id: %s | %s",
+			e.Code, e.Msg, e.Endpoint, e.RequestID, e.Response)
+	}
+
+	return fmt.Sprintf("wechat error: %d %s | endpoint: %s | request_id: %s", e.Code, e.Msg, e.Endpoint, e.RequestID)
+}
+
+// IsInvalidCredential reports whether the access token is invalid or expired (40001/40014/42001),
+// the call may succeed with a refreshed access token.
+func (e *APIError) IsInvalidCredential() bool {
+	switch e.Code {
+	case ErrCodeInvalidCredential, ErrCodeInvalidAccessToken, ErrCodeAccessTokenExpired:
+		return true
+	}
+
+	return false
+}
+
+// IsRateLimited reports whether the api quota is reached (45009/45011).
+func (e *APIError) IsRateLimited() bool {
+	return e.Code == ErrCodeDailyQuotaLimit || e.Code == ErrCodeMinuteQuotaLimit
+}
+
+// Description returns the description of the errcode from the knowledge base, the errmsg if unknown.
+func (e *APIError) Description() string {
+	if info, ok := LookupErrCode(e.Code); ok {
+		return info.Description
+	}
+
+	return e.Msg
+}
+
+// Class returns the class of the errcode from the knowledge base.
+func (e *APIError) Class() ErrClass {
+	if info, ok := LookupErrCode(e.Code); ok {
+		return info.Class
+	}
+
+	return ErrClassUnknown
+}
+
+// IsSystemBusy reports whether WeChat is busy (-1), the call can be retried.
+func (e *APIError) IsSystemBusy() bool {
+	return e.Code == ErrCodeSystemBusy
+}
+
+// AsAPIError finds the first APIError in the chain of err.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// IsErrCode reports whether err is an APIError of any of the codes.
+func IsErrCode(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endpointOf returns the path of the api url, e.g. /wxa/generate_urllink.
+func endpointOf(apiURL string) string {
+	apiURL, _, _ = strings.Cut(apiURL, "?")
+
+	if _, rest, ok := strings.Cut(apiURL, "://"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return rest[i:]
+		}
+		return "/"
+	}
+
+	return apiURL
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	apiErr := NewAPIError("https://api.weixin.qq.com/cgi-bin/message/custom/send?access_token=%s", 42001, "access_token expired rid: 64c1d2a3-1a2b3c4d-5e6f7a8b", "req-1")
+	assert.Equal(t, "/cgi-bin/message/custom/send", apiErr.Endpoint)
+	assert.Equal(t, "64c1d2a3-1a2b3c4d-5e6f7a8b", apiErr.RID)
+	assert.True(t, apiErr.IsInvalidCredential())
+	assert.False(t, apiErr.IsRateLimited())
+	assert.Equal(t, "wechat error: 42001 access_token expired rid: 64c1d2a3-1a2b3c4d-5e6f7a8b | endpoint: /cgi-bin/message/custom/send | request_id: req-1", apiErr.Error())
+
+	err := fmt.Errorf("get access token error: %w", apiErr)
+	found, ok := AsAPIError(err)
+	assert.True(t, ok)
+	assert.Same(t, apiErr, found)
+	assert.True(t, IsErrCode(err, ErrCodeInvalidCredential, ErrCodeAccessTokenExpired))
+	assert.False(t, IsErrCode(err, ErrCodeSystemBusy))
+	assert.False(t, IsErrCode(errors.New("network error"), ErrCodeAccessTokenExpired))
+}
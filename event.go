@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"time"
+)
+
+// EventSource is the module emitting the events of WeChat callbacks.
+type EventSource string
+
+const (
+	EventSourcePush       EventSource = "vwxpush"          // 消息推送
+	EventSourceMediaCheck EventSource = "vwxa.media_check" // 多媒体内容安全异步检测结果
+	EventSourcePay        EventSource = "vwxpay"           // 微信支付通知
+	EventSourceOpen       EventSource = "vwxopen"          // 第三方平台授权事件及 ticket 推送
+)
+
+// Event is a WeChat callback normalized from the pushes, the media check results, the payment notifications
+// and the third-party platform pushes, so that all callbacks can be consumed by one EventHandler.
+type Event struct {
+	Source     EventSource // 事件来源模块
+	AppID      string      // 事件所属的 appid，支付通知为商户号
+	Kind       string      // 事件类型，推送为 Event 或 MsgType，支付通知为 event_type，第三方平台为 InfoType
+	CreateTime time.Time   // 事件产生时间
+	RequestID  string      // 本次回调处理的关联 id
+	Payload    any         // 来源模块解析的事件内容，如 *vwxpush.PushBaseInfo、*vwxpay.Notification
+	Raw        []byte      // 解密后的原始数据
+}
+
+// Context returns a context carrying the request id of the event, so that API calls made by the handler
+// can be correlated with the callback.
+func (e *Event) Context(ctx context.Context) context.Context {
+	return ContextWithRequestID(ctx, e.RequestID)
+}
+
+// EventHandler handles the events of all WeChat callbacks, an error makes WeChat send the callback again.
+type EventHandler func(ctx context.Context, event *Event) error
+
+// ChanEventHandler returns the handler sending the events to the channel, waiting until the channel
+// accepts the event or the context is done.
+func ChanEventHandler(ch chan<- *Event) EventHandler {
+	return func(ctx context.Context, event *Event) error {
+		select {
+		case ch <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanEventHandler(t *testing.T) {
+	ch := make(chan *Event, 1)
+	handler := ChanEventHandler(ch)
+
+	event := &Event{Source: EventSourcePush, Kind: "subscribe", RequestID: "rid"}
+	assert.NoError(t, handler(context.Background(), event))
+	assert.Equal(t, event, <-ch)
+
+	assert.Equal(t, "rid", RequestIDFromContext(event.Context(context.Background())))
+
+	// the full channel blocks the handler until the context is done
+	assert.NoError(t, handler(context.Background(), event))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, handler(ctx, event), context.Canceled)
+}
@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Do sends the request by the http client of the client, requests with an Idempotent context
+// are retried by the retry policy if the body can be sent again. Requests failing with an
+// invalidated access token are sent once more with a refreshed token, unless the token is overridden
+// by WithAccessToken. Requests to api.weixin.qq.com are sent to the BaseURL of the client if set.
+// Calls wait for the RateLimiter, pass the CircuitBreaker and are reported to the MetricsHook of the
+// client if set. The headers of the context set by ContextWithHeader are added to the request.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if header := headerFromContext(req.Context()); len(header) > 0 {
+		req = req.Clone(req.Context())
+		for key, values := range header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+
+	if c.MetricsHook != nil {
+		return c.observe(req, c.do)
+	}
+
+	return c.do(req)
+}
+
+// do sends the request once allowed by the rate limiter and the circuit breaker, recording its
+// result to the circuit breaker.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Path
+
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(req.Context(), endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.CircuitBreaker == nil {
+		return c.sendAndRefresh(req)
+	}
+
+	if err := c.CircuitBreaker.Allow(endpoint); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendAndRefresh(req)
+
+	return c.breakCircuit(req.Context(), endpoint, resp, err)
+}
+
+// sendAndRefresh sends the request to the base url, resending it with a refreshed token if invalidated.
+func (c *Client) sendAndRefresh(req *http.Request) (*http.Response, error) {
+	// the endpoint group is matched by the path before the base url prefixes it
+	refresh := c.tokenRefresher(req.URL.Path)
+	if accessTokenOverridden(req.Context()) {
+		refresh = nil
+	}
+
+	req, err := c.rewriteBaseURL(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(req)
+	if err != nil || refresh == nil {
+		return resp, err
+	}
+
+	return c.refreshTokenAndResend(req, resp, refresh)
+}
+
+// refreshTokenAndResend refreshes the access token in the query of the request and sends it again
+// if the response reports the token invalid. The web OAuth apis (/sns/) carrying user access tokens
+// are never refreshed.
+func (c *Client) refreshTokenAndResend(req *http.Request, resp *http.Response,
+	refresh func(ctx context.Context, staleToken string) (string, error),
+) (*http.Response, error) {
+	staleToken := req.URL.Query().Get("access_token")
+	if staleToken == "" || strings.HasPrefix(req.URL.Path, "/sns/") || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	resp, errCode, err := peekErrCode(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch errCode {
+	case ErrCodeInvalidCredential, ErrCodeInvalidAccessToken, ErrCodeAccessTokenExpired:
+	default:
+		return resp, nil
+	}
+
+	ctx := req.Context()
+
+	token, err := refresh(ctx, staleToken)
+	if err != nil || token == staleToken {
+		c.Logger.Warnf("refresh access token failed | request_id: %s | path: %s | errcode: %d | err: %v",
+			RequestIDFromContext(ctx), req.URL.Path, errCode, err)
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	c.Logger.Warnf("access token invalidated, resend with refreshed token | request_id: %s | path: %s | errcode: %d",
+		RequestIDFromContext(ctx), req.URL.Path, errCode)
+
+	resend := req.Clone(ctx)
+
+	query := resend.URL.Query()
+	query.Set("access_token", token)
+	resend.URL.RawQuery = query.Encode()
+
+	if req.GetBody != nil {
+		if resend.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.send(resend)
+}
+
+// send sends the request, retrying it by the retry policy of the context.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if policy := c.retryPolicy(req.Context()); policy != nil && policy.MaxAttempts > 1 &&
+		(req.Body == nil || req.GetBody != nil) {
+		return c.doWithRetry(req, policy)
+	}
+
+	return c.roundTrip(req)
+}
+
+// Get sends a GET request bound to the context, the request is canceled when the context is done.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+// Post sends a POST request bound to the context, the request is canceled when the context is done.
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	return c.Do(req)
+}
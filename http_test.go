@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestContext(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxa.NewService(client)
+
+	ctx := vwx.ContextWithRequestID(context.Background(), "req-ctx-1")
+
+	server.SetError("/wxa/generate_urllink", 40165, "invalid weapp pagepath")
+	_, err := svc.GenerateSimpleURLLinkContext(ctx, "/pages/index", "a=1")
+	assert.ErrorContains(t, err, "request_id: req-ctx-1")
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = svc.GenerateSimpleURLLinkContext(canceled, "/pages/index", "a=1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, server.Requests("/wxa/generate_urllink")[1:])
+
+	_, err = vwxmp.NewService(client).GetMenuContext(canceled)
+	assert.ErrorContains(t, err, context.Canceled.Error())
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import "net/http"
+
+// RoundTrip sends the request and returns its response.
+type RoundTrip func(req *http.Request) (*http.Response, error)
+
+// Interceptor intercepts the outbound requests of all modules, e.g. to add headers, trace or
+// mutate the requests. It calls next to send the request, or returns without calling it to
+// short-circuit. Each attempt of retried requests passes the interceptors.
+type Interceptor func(req *http.Request, next RoundTrip) (*http.Response, error)
+
+// WithInterceptor appends the interceptors to the client, the first one added is the outermost.
+func WithInterceptor(interceptors ...Interceptor) func(*Client) {
+	return func(c *Client) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
+
+// roundTrip sends the request by the http client through the interceptors.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := c.HTTPClient.Do
+
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.Interceptors[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, inner)
+		}
+	}
+
+	return next(req)
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "outer,inner", r.Header.Get("X-Trace"))
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	var order []string
+
+	tag := func(name string) Interceptor {
+		return func(req *http.Request, next RoundTrip) (*http.Response, error) {
+			order = append(order, name)
+
+			trace := req.Header.Get("X-Trace")
+			if trace != "" {
+				trace += ","
+			}
+			req.Header.Set("X-Trace", trace+name)
+
+			resp, err := next(req)
+			order = append(order, name+" done")
+			return resp, err
+		}
+	}
+
+	client := NewClient("appid", "secret", WithInterceptor(tag("outer")), WithInterceptor(tag("inner")))
+
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, []string{"outer", "inner", "inner done", "outer done"}, order)
+
+	// interceptors can short-circuit the request
+	errBlocked := errors.New("blocked")
+	client = NewClient("appid", "secret", WithInterceptor(func(*http.Request, RoundTrip) (*http.Response, error) {
+		return nil, errBlocked
+	}))
+
+	_, err = client.Get(context.Background(), server.URL)
+	assert.ErrorIs(t, err, errBlocked)
+}
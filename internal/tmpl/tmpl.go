@@ -0,0 +1,283 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tmpl implements the template definitions shared by the subscribe messages and the template
+// messages: the keywords declared in the template content, the constraints of the keyword types, and
+// the cache of the definitions fetched from the account.
+package tmpl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	keywordRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_]+[0-9]*)\.DATA\s*\}\}`)
+
+	numberValueRegexp          = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	letterValueRegexp          = regexp.MustCompile(`^[A-Za-z]+$`)
+	characterStringValueRegexp = regexp.MustCompile(`^[\x21-\x7e]+$`)
+	amountValueRegexp          = regexp.MustCompile(`^[^0-9]?[0-9]{1,10}(\.[0-9]+)?元?$`)
+	phoneNumberValueRegexp     = regexp.MustCompile(`^[0-9+\-() ]+$`)
+)
+
+// Keywords returns the data keys (e.g. thing1, time2) declared in the template content.
+func Keywords(content string) []string {
+	matches := keywordRegexp.FindAllStringSubmatch(content, -1)
+	keywords := make([]string, 0, len(matches))
+	for _, match := range matches {
+		keywords = append(keywords, match[1])
+	}
+	return keywords
+}
+
+// ValidationError describes the mismatches between the data of a message and its template.
+type ValidationError struct {
+	TemplateID string
+	Missing    []string          // 模板中声明但未提供的字段
+	Extra      []string          // 提供了但模板中未声明的字段
+	Invalid    map[string]string // 不满足字段类型约束的字段及原因
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing keys: "+strings.Join(e.Missing, ","))
+	}
+	if len(e.Extra) > 0 {
+		parts = append(parts, "extra keys: "+strings.Join(e.Extra, ","))
+	}
+	if len(e.Invalid) > 0 {
+		keys := make([]string, 0, len(e.Invalid))
+		for k := range e.Invalid {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, fmt.Sprintf("invalid %s: %s", k, e.Invalid[k]))
+		}
+	}
+	return fmt.Sprintf("template %s data mismatch: %s", e.TemplateID, strings.Join(parts, "; "))
+}
+
+// Validate verifies the data keys match the keywords declared in the template content exactly and
+// each value satisfies the constraint of its keyword type, nil items are missing.
+func Validate[V any](templateID, content string, data map[string]*V, value func(*V) string) error {
+	validationErr := &ValidationError{
+		TemplateID: templateID,
+		Invalid:    make(map[string]string),
+	}
+
+	declared := make(map[string]bool)
+	for _, keyword := range Keywords(content) {
+		declared[keyword] = true
+
+		item, ok := data[keyword]
+		if !ok || item == nil {
+			validationErr.Missing = append(validationErr.Missing, keyword)
+			continue
+		}
+
+		if reason := validateValue(keyword, value(item)); reason != "" {
+			validationErr.Invalid[keyword] = reason
+		}
+	}
+
+	for key := range data {
+		if !declared[key] {
+			validationErr.Extra = append(validationErr.Extra, key)
+		}
+	}
+
+	if len(validationErr.Missing) == 0 && len(validationErr.Extra) == 0 && len(validationErr.Invalid) == 0 {
+		return nil
+	}
+
+	sort.Strings(validationErr.Missing)
+	sort.Strings(validationErr.Extra)
+
+	return validationErr
+}
+
+// validateValue returns the reason if the value doesn't satisfy the constraint of the keyword type,
+// the type is the keyword without its trailing digits. Keywords of unknown types only require a value.
+func validateValue(keyword, value string) string {
+	if value == "" {
+		return "empty value"
+	}
+
+	kind := strings.TrimRight(keyword, "0123456789")
+	length := utf8.RuneCountInString(value)
+
+	checkLen := func(limit int) string {
+		if length > limit {
+			return fmt.Sprintf("length %d exceeds %d", length, limit)
+		}
+		return ""
+	}
+
+	checkPattern := func(limit int, pattern *regexp.Regexp, desc string) string {
+		if reason := checkLen(limit); reason != "" {
+			return reason
+		}
+		if !pattern.MatchString(value) {
+			return "expect " + desc
+		}
+		return ""
+	}
+
+	switch kind {
+	case "thing":
+		return checkLen(20)
+	case "number":
+		return checkPattern(32, numberValueRegexp, "digits")
+	case "letter":
+		return checkPattern(32, letterValueRegexp, "letters")
+	case "symbol":
+		return checkLen(5)
+	case "character_string":
+		return checkPattern(32, characterStringValueRegexp, "letters, digits or symbols")
+	case "amount":
+		return checkPattern(14, amountValueRegexp, "currency amount")
+	case "phone_number":
+		return checkPattern(17, phoneNumberValueRegexp, "phone number")
+	case "car_number":
+		return checkLen(8)
+	case "name":
+		if len(value) != length {
+			return checkLen(10)
+		}
+		return checkLen(20)
+	case "phrase":
+		return checkLen(5)
+	default:
+		return ""
+	}
+}
+
+// Cache caches the template definitions of an account by template id. Lookups of unknown templates
+// fetch the definitions again, templates still not found after a fetch are not fetched again until
+// the definitions expire. Concurrent fetches share a single request, the lock is not held during it.
+type Cache[T any] struct {
+	mu        sync.Mutex
+	templates map[string]T
+	missing   map[string]bool // 最近一次拉取后仍未找到的模板ID
+	fetchedAt time.Time
+	fetching  *flight // 进行中的模板列表拉取
+}
+
+// flight is an in-flight fetch of the template definitions.
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+// Get returns the definition of the template cached for ttl, fetching the definitions by fetch
+// if the cache is expired or the template is unknown.
+func (c *Cache[T]) Get(ctx context.Context, templateID string, ttl time.Duration,
+	fetch func(ctx context.Context) (map[string]T, error),
+) (T, error) {
+	var zero T
+
+	if t, found, cached := c.cached(templateID, ttl); cached {
+		if !found {
+			return zero, fmt.Errorf("template %s not found", templateID)
+		}
+
+		return t, nil
+	}
+
+	if err := c.fetch(ctx, ttl, fetch); err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.templates[templateID]
+	if !ok {
+		c.missing[templateID] = true
+		return zero, fmt.Errorf("template %s not found", templateID)
+	}
+
+	return t, nil
+}
+
+// cached returns the definition of the template if the cache answers the lookup, found is false
+// if the template was not found by the last fetch.
+func (c *Cache[T]) cached(templateID string, ttl time.Duration) (t T, found, cached bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.templates == nil || time.Since(c.fetchedAt) >= ttl {
+		return t, false, false
+	}
+
+	if t, ok := c.templates[templateID]; ok {
+		return t, true, true
+	}
+
+	return t, false, c.missing[templateID]
+}
+
+// fetch fetches the definitions and replaces the cached ones, joining the fetch in flight if any.
+func (c *Cache[T]) fetch(ctx context.Context, ttl time.Duration, fetch func(ctx context.Context) (map[string]T, error)) error {
+	c.mu.Lock()
+	call := c.fetching
+	if call == nil {
+		call = &flight{done: make(chan struct{})}
+		c.fetching = call
+
+		go func() {
+			// the fetch is shared, it is not canceled with the context of the first caller
+			templates, err := fetch(context.WithoutCancel(ctx))
+
+			c.mu.Lock()
+			if err == nil {
+				if c.templates == nil || time.Since(c.fetchedAt) >= ttl {
+					c.missing = make(map[string]bool)
+				}
+
+				if templates == nil {
+					templates = make(map[string]T)
+				}
+
+				c.templates = templates
+				c.fetchedAt = time.Now()
+			}
+			c.fetching = nil
+			c.mu.Unlock()
+
+			call.err = err
+			close(call.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
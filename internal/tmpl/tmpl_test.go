@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tmpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		cache   Cache[string]
+		fetches int
+		fail    bool
+	)
+	fetch := func(context.Context) (map[string]string, error) {
+		fetches++
+		if fail {
+			return nil, errors.New("fetch failed")
+		}
+		return map[string]string{"tmpl-1": "content"}, nil
+	}
+
+	content, err := cache.Get(context.Background(), "tmpl-1", time.Minute, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", content)
+
+	// an unknown template is fetched once until the cache expires
+	for range 3 {
+		_, err = cache.Get(context.Background(), "tmpl-typo", time.Minute, fetch)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, fetches)
+
+	// failed fetches keep nothing, the next lookup fetches again
+	fail = true
+	_, err = cache.Get(context.Background(), "tmpl-1", 0, fetch)
+	assert.EqualError(t, err, "fetch failed")
+	assert.Equal(t, 3, fetches)
+
+	fail = false
+	_, err = cache.Get(context.Background(), "tmpl-1", 0, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fetches)
+}
+
+func TestValidate(t *testing.T) {
+	value := func(v *string) string { return *v }
+	str := func(s string) *string { return &s }
+
+	assert.NoError(t, Validate("tmpl-1", "{{thing1.DATA}}{{number2.DATA}}", map[string]*string{
+		"thing1":  str("咖啡"),
+		"number2": str("12"),
+	}, value))
+
+	err := Validate("tmpl-1", "{{thing1.DATA}}{{number2.DATA}}", map[string]*string{
+		"thing1":  nil,
+		"number2": str("twelve"),
+		"phrase3": str("ok"),
+	}, value)
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"thing1"}, validationErr.Missing)
+	assert.Equal(t, []string{"phrase3"}, validationErr.Extra)
+	assert.Equal(t, map[string]string{"number2": "expect digits"}, validationErr.Invalid)
+	assert.EqualError(t, err, "template tmpl-1 data mismatch: missing keys: thing1; extra keys: phrase3; invalid number2: expect digits")
+}
@@ -0,0 +1,196 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package token implements the token acquisition shared by the api services: tokens are cached by
+// the cache provider of the client, concurrent fetches share a single request, and the instances
+// sharing the cache provider refresh them one at a time.
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+// expireMargin is subtracted from the validity of the tokens, so that cached tokens are replaced
+// before WeChat rejects them.
+const expireMargin = 300
+
+// Fetch requests a new token from WeChat, the token is returned with its validity in seconds.
+// A forced refresh invalidates the tokens issued before.
+type Fetch func(ctx context.Context, forceRefresh bool) (string, int, error)
+
+// Manager manages a token of a client, e.g. the access token or the jsapi ticket.
+type Manager struct {
+	Client   *vwx.Client
+	Name     string // 日志中的令牌名称，如 access token
+	CacheKey string
+	Fetch    Fetch
+
+	// OnCacheHit, OnCacheMiss and OnFetch observe the token acquisition, nil ones are skipped.
+	// The reason of a miss is "miss", or "no_cache" if the client has no CacheProvider.
+	OnCacheHit  func()
+	OnCacheMiss func(reason string)
+	OnFetch     func(start time.Time, expiresIn int, err error)
+}
+
+// Get returns the cached token, or fetches a new one if none is cached.
+func (m *Manager) Get(ctx context.Context) (string, error) {
+	if m.Client.CacheProvider == nil {
+		m.cacheMiss("no_cache")
+		return m.fetch(ctx, "", false)
+	}
+
+	if token := m.Client.CacheProvider.Get(ctx, m.CacheKey); token != "" {
+		if m.OnCacheHit != nil {
+			m.OnCacheHit()
+		}
+
+		return token, nil
+	}
+
+	m.cacheMiss("miss")
+
+	return m.fetch(ctx, "", false)
+}
+
+// Refresh replaces the stale token rejected by WeChat with a new one, the cached token is returned
+// if it was already refreshed by another call.
+func (m *Manager) Refresh(ctx context.Context, staleToken string) (string, error) {
+	if m.Client.CacheProvider != nil {
+		token := m.Client.CacheProvider.Get(ctx, m.CacheKey)
+		if token != "" && token != staleToken {
+			return token, nil
+		}
+	}
+
+	return m.fetch(ctx, staleToken, true)
+}
+
+// ForceRefresh discards the cached token, fetches a fresh one, caches and returns it.
+func (m *Manager) ForceRefresh(ctx context.Context) (string, error) {
+	var staleToken string
+	if m.Client.CacheProvider != nil {
+		staleToken = m.Client.CacheProvider.Get(ctx, m.CacheKey)
+	}
+
+	if deleter, ok := m.Client.CacheProvider.(vwx.CacheDeleter); ok {
+		if err := deleter.Delete(ctx, m.CacheKey); err != nil {
+			m.Client.Logger.Errorf("failed to delete %s from cache | err: %v", m.Name, err)
+		}
+	}
+
+	return m.fetch(ctx, staleToken, true)
+}
+
+func (m *Manager) cacheMiss(reason string) {
+	if m.OnCacheMiss != nil {
+		m.OnCacheMiss(reason)
+	}
+}
+
+// fetch fetches a new token and caches it. Concurrent fetches of the same token share a single
+// request, so that they do not invalidate the tokens of each other. The stale token is the cached
+// token being replaced, empty if none.
+func (m *Manager) fetch(ctx context.Context, staleToken string, forceRefresh bool) (string, error) {
+	key := flightKey{client: m.Client, cacheKey: m.CacheKey, forceRefresh: forceRefresh}
+
+	flightMu.Lock()
+	call, ok := flights[key]
+	if !ok {
+		call = &flight{done: make(chan struct{})}
+		flights[key] = call
+
+		go func() {
+			// the fetch is shared, it is not canceled with the context of the first caller
+			call.token, call.err = m.refresh(context.WithoutCancel(ctx), staleToken, forceRefresh)
+
+			flightMu.Lock()
+			delete(flights, key)
+			flightMu.Unlock()
+
+			close(call.done)
+		}()
+	}
+	flightMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// flightKey identifies the fetches of a token, the services created on the same client share them.
+type flightKey struct {
+	client       *vwx.Client
+	cacheKey     string
+	forceRefresh bool
+}
+
+// flight is an in-flight token fetch.
+type flight struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+var (
+	flightMu sync.Mutex
+	flights  = make(map[flightKey]*flight)
+)
+
+// refresh fetches a new token holding the refresh lock of the client, the token cached meanwhile
+// by another instance sharing the cache provider is returned instead.
+func (m *Manager) refresh(ctx context.Context, staleToken string, forceRefresh bool) (string, error) {
+	request := func(ctx context.Context) (string, error) {
+		start := time.Now()
+
+		token, expiresIn, err := m.Fetch(ctx, forceRefresh)
+		if m.OnFetch != nil {
+			m.OnFetch(start, expiresIn, err)
+		}
+
+		if err != nil || m.Client.CacheProvider == nil {
+			return token, err
+		}
+
+		expire := time.Duration(expiresIn-expireMargin) * time.Second
+		if err := m.Client.CacheProvider.Set(ctx, m.CacheKey, token, expire); err != nil {
+			m.Client.Logger.Errorf("failed to set %s to cache | err: %v", m.Name, err)
+		}
+
+		return token, nil
+	}
+
+	if m.Client.CacheProvider == nil {
+		return request(ctx)
+	}
+
+	fresh := func(ctx context.Context) string {
+		if token := m.Client.CacheProvider.Get(ctx, m.CacheKey); token != staleToken {
+			return token
+		}
+
+		return ""
+	}
+
+	return m.Client.RefreshLocked(ctx, m.CacheKey, fresh, request)
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package token
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches, forced atomic.Int32
+	m := &Manager{
+		Client:   vwx.NewClient("appid", "secret", vwx.WithLogger(vwx.NopLogger{})),
+		Name:     "test token",
+		CacheKey: "test:token",
+		Fetch: func(_ context.Context, forceRefresh bool) (string, int, error) {
+			time.Sleep(10 * time.Millisecond)
+			if forceRefresh {
+				forced.Add(1)
+			}
+
+			return "token-" + strconv.Itoa(int(fetches.Add(1))), 7200, nil
+		},
+	}
+
+	// concurrent gets share a single fetch
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := m.Get(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", token)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// a stale token already replaced in the cache is not fetched again
+	token, err := m.Refresh(ctx, "token-0")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	token, err = m.Refresh(ctx, "token-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, int32(1), forced.Load())
+
+	token, err = m.ForceRefresh(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "token-3", token)
+
+	token, err = m.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "token-3", token)
+	assert.Equal(t, int32(2), forced.Load())
+}
@@ -0,0 +1,252 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package wxapi implements the JSON calls shared by the api services: the access token is injected
+// into the url, the request and response are logged, the content type and size of the response are
+// checked, and the errcode of the response is checked.
+package wxapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/vogo/vwx"
+)
+
+// maxPooledBufferSize avoids keeping large buffers in the pool.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// Call is a call of a WeChat JSON api.
+type Call struct {
+	Client *vwx.Client
+	Name   string // 日志中的接口名称，如 generate urllink
+
+	// URL is the format of the api url, the access token is its first verb followed by Args.
+	URL  string
+	Args []any
+
+	// AccessToken returns the access token of the call, nil if the api needs none.
+	AccessToken func(ctx context.Context) (string, error)
+
+	// Error builds the error of a response with a non-zero errcode, vwx.NewAPIError by default.
+	Error func(apiURL string, errCode int, errMsg, requestID string) error
+
+	// Idempotent marks the call safe to be retried by the retry policy, calls exchanging one-time
+	// codes are not.
+	Idempotent bool
+
+	// Summary is logged instead of the request and response, for calls carrying large or sensitive data.
+	Summary string
+
+	// Options are the per-call options applied to the request: the timeout, the retry override and the headers.
+	Options []vwx.RequestOption
+
+	// CaptureLimit is the max size of the response body attached to the returned errors, 0 attaches none.
+	CaptureLimit int
+}
+
+// errResponse is the errcode carried by all api responses.
+type errResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// PostJSON posts the request encoded in json and decodes the response into a T. The response is
+// returned with the error if its errcode is not zero.
+func PostJSON[T any](ctx context.Context, call *Call, req any) (*T, error) {
+	reqBuf := getBuffer()
+	defer putBuffer(reqBuf)
+
+	data, err := encodeJSON(reqBuf, req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request error: %w", err)
+	}
+
+	return do[T](ctx, call, http.MethodPost, data)
+}
+
+// EncodeJSON encodes v as PostJSON sends it, without escaping html characters (e.g. & in queries).
+func EncodeJSON(v any) ([]byte, error) {
+	return encodeJSON(new(bytes.Buffer), v)
+}
+
+// encodeJSON encodes v into buf and returns the encoded bytes without the trailing newline,
+// the returned bytes are only valid until buf is reused.
+func encodeJSON(buf *bytes.Buffer, v any) ([]byte, error) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// GetJSON gets the api and decodes the response into a T. The response is returned with the error
+// if its errcode is not zero.
+func GetJSON[T any](ctx context.Context, call *Call) (*T, error) {
+	return do[T](ctx, call, http.MethodGet, nil)
+}
+
+func do[T any](ctx context.Context, call *Call, method string, data []byte) (*T, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+	client := call.Client
+
+	args := call.Args
+	if call.AccessToken != nil {
+		accessToken, err := call.AccessToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get access token error: %w", err)
+		}
+
+		args = append([]any{accessToken}, args...)
+	}
+
+	apiURL := call.URL
+	if len(args) > 0 {
+		apiURL = fmt.Sprintf(call.URL, args...)
+	}
+
+	switch {
+	case call.Summary != "":
+		client.Logger.Infof("%s | request_id: %s | %s", call.Name, requestID, call.Summary)
+	case data != nil:
+		client.Logger.Infof("%s | request_id: %s | req: %s", call.Name, requestID, data)
+	}
+
+	ctx, cancel := vwx.NewRequestOptions(call.Options...).Context(ctx)
+	defer cancel()
+
+	if call.Idempotent {
+		ctx = vwx.Idempotent(ctx)
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	if method == http.MethodGet {
+		resp, err = client.Get(ctx, apiURL)
+	} else {
+		resp, err = client.Post(ctx, apiURL, "application/json", bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("send request error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := vwx.CheckContentType(resp, vwx.JSONContentTypes...); err != nil {
+		return nil, err
+	}
+
+	respBuf := getBuffer()
+	defer putBuffer(respBuf)
+
+	if _, err := respBuf.ReadFrom(client.LimitResponse(resp)); err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+	body := respBuf.Bytes()
+
+	if call.Summary == "" {
+		client.Logger.Infof("%s | request_id: %s | resp: %s", call.Name, requestID, body)
+	}
+
+	var result T
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, call.malformed(resp, body, err)
+	}
+
+	var errResp errResponse
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return nil, call.malformed(resp, body, err)
+	}
+
+	if errResp.ErrCode != 0 {
+		newError := call.Error
+		if newError == nil {
+			newError = func(apiURL string, errCode int, errMsg, requestID string) error {
+				return vwx.NewAPIError(apiURL, errCode, errMsg, requestID)
+			}
+		}
+
+		err := newError(call.URL, errResp.ErrCode, errResp.ErrMsg, requestID)
+		if apiErr, ok := vwx.AsAPIError(err); ok {
+			apiErr.Response = call.capture(resp, body)
+		}
+
+		return &result, err
+	}
+
+	return &result, nil
+}
+
+// malformed returns the error of a response failing to be decoded.
+func (call *Call) malformed(resp *http.Response, body []byte, err error) error {
+	var endpoint string
+	if resp.Request != nil {
+		endpoint = resp.Request.URL.Path
+	}
+
+	return &vwx.ResponseError{
+		Endpoint:    endpoint,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Err:         fmt.Errorf("%w: %w", vwx.ErrMalformedResponse, err),
+		Response:    call.capture(resp, body),
+	}
+}
+
+// capture returns the raw response attached to the errors of the call, nil if not captured.
+func (call *Call) capture(resp *http.Response, body []byte) *vwx.RawResponse {
+	if call.CaptureLimit <= 0 {
+		return nil
+	}
+
+	return vwx.NewRawResponse(resp.StatusCode, body, call.CaptureLimit)
+}
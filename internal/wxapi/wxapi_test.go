@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wxapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+type echoResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Echo    string `json:"echo"`
+}
+
+func TestPostJSON(t *testing.T) {
+	var gotToken, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.URL.Query().Get("access_token")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+
+		if r.URL.Query().Get("fail") != "" {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential","echo":"partial"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","echo":"pong"}`))
+	}))
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwx.WithLogger(vwx.NopLogger{}))
+	call := &Call{
+		Client:      client,
+		Name:        "echo",
+		URL:         server.URL + "/echo?access_token=%s&page=%s",
+		Args:        []any{"home"},
+		AccessToken: func(context.Context) (string, error) { return "token", nil },
+	}
+
+	result, err := PostJSON[echoResponse](context.Background(), call, map[string]string{"path": "a?b=1&c=2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pong", result.Echo)
+	assert.Equal(t, "token", gotToken)
+	assert.Equal(t, `{"path":"a?b=1&c=2"}`, gotBody)
+
+	call.URL = server.URL + "/echo?access_token=%s&fail=%s"
+	result, err = PostJSON[echoResponse](context.Background(), call, struct{}{})
+
+	var apiErr *vwx.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 40001, apiErr.Code)
+	assert.Equal(t, "partial", result.Echo)
+}
+
+func TestGetJSONAccessTokenError(t *testing.T) {
+	tokenErr := errors.New("no token")
+	call := &Call{
+		Client:      vwx.NewClient("appid", "secret", vwx.WithLogger(vwx.NopLogger{})),
+		Name:        "echo",
+		URL:         "http://127.0.0.1/echo?access_token=%s",
+		AccessToken: func(context.Context) (string, error) { return "", tokenErr },
+	}
+
+	_, err := GetJSON[echoResponse](context.Background(), call)
+	assert.ErrorIs(t, err, tokenErr)
+}
+
+func TestGetJSONResponseChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/html" {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html>gateway</html>`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","echo":"a long echo"}`))
+	}))
+	defer server.Close()
+
+	call := &Call{
+		Client: vwx.NewClient("appid", "secret", vwx.WithLogger(vwx.NopLogger{}), vwx.WithMaxResponseSize(16)),
+		Name:   "echo",
+		URL:    server.URL + "/html",
+	}
+
+	_, err := GetJSON[echoResponse](context.Background(), call)
+	assert.ErrorIs(t, err, vwx.ErrUnexpectedContentType)
+
+	call.URL = server.URL + "/echo"
+	_, err = GetJSON[echoResponse](context.Background(), call)
+	assert.ErrorIs(t, err, vwx.ErrResponseTooLarge)
+}
+
+type requestIDHook struct {
+	requestIDs []string
+}
+
+func (h *requestIDHook) OnRequest(context.Context, *vwx.APICall) {}
+
+func (h *requestIDHook) OnResponse(_ context.Context, call *vwx.APICall) {
+	h.requestIDs = append(h.requestIDs, call.RequestID)
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":45009,"errmsg":"reach max api daily quota limit"}`))
+	}))
+	defer server.Close()
+
+	hook := &requestIDHook{}
+	var tokenRequestID string
+	call := &Call{
+		Client: vwx.NewClient("appid", "secret", vwx.WithLogger(vwx.NopLogger{}), vwx.WithMetricsHook(hook)),
+		Name:   "echo",
+		URL:    server.URL + "/echo?access_token=%s",
+		AccessToken: func(ctx context.Context) (string, error) {
+			tokenRequestID = vwx.RequestIDFromContext(ctx)
+			return "token", nil
+		},
+	}
+
+	// the generated request id is shared by the token acquisition, the metrics and the error
+	_, err := GetJSON[echoResponse](context.Background(), call)
+	apiErr, ok := vwx.AsAPIError(err)
+	assert.True(t, ok)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.Equal(t, apiErr.RequestID, tokenRequestID)
+	assert.Equal(t, []string{apiErr.RequestID}, hook.requestIDs)
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// RefreshLockTTL is the ttl of the lock held while refreshing an access token or ticket,
+	// the instances waiting for the holder refresh by themselves after it.
+	RefreshLockTTL = 15 * time.Second
+
+	// refreshLockPollInterval is the interval of polling the value refreshed by the lock holder.
+	refreshLockPollInterval = 100 * time.Millisecond
+)
+
+// Locker is a distributed lock shared by the instances of a service, so that only one of them
+// refreshes an access token or ticket at a time.
+type Locker interface {
+	// TryLock acquires the lock of the key for the ttl without waiting, reporting false if it is held.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock of the key acquired by TryLock.
+	Unlock(ctx context.Context, key string) error
+}
+
+// WithLocker sets the lock around token and ticket refreshes, use a lock shared with the other
+// instances sharing the cache provider, e.g. the redis cache.
+func WithLocker(locker Locker) func(*Client) {
+	return func(c *Client) {
+		c.Locker = locker
+	}
+}
+
+// RefreshLocked refreshes the value cached by the key holding the lock of the Locker, so that the
+// instances sharing the cache refresh it once. fresh loads the cached value if it is usable, and is
+// checked after acquiring the lock in case another instance refreshed it meanwhile. When the lock
+// is held by another instance, fresh is polled until it returns a value or RefreshLockTTL elapses,
+// the value is refreshed without the lock after it. Without a Locker the value is refreshed directly.
+func (c *Client) RefreshLocked(ctx context.Context, key string,
+	fresh func(ctx context.Context) string, refresh func(ctx context.Context) (string, error),
+) (string, error) {
+	if c.Locker == nil {
+		return refresh(ctx)
+	}
+
+	lockKey := key + ":lock"
+
+	locked, err := c.Locker.TryLock(ctx, lockKey, RefreshLockTTL)
+	if err != nil {
+		c.Logger.Warnf("try refresh lock failed, refresh without lock | key: %s | err: %v", key, err)
+		return refresh(ctx)
+	}
+
+	if !locked {
+		return c.waitRefreshed(ctx, key, fresh, refresh)
+	}
+
+	defer func() {
+		if unlockErr := c.Locker.Unlock(context.WithoutCancel(ctx), lockKey); unlockErr != nil {
+			c.Logger.Errorf("release refresh lock failed | key: %s | err: %v", key, unlockErr)
+		}
+	}()
+
+	if value := fresh(ctx); value != "" {
+		return value, nil
+	}
+
+	return refresh(ctx)
+}
+
+// waitRefreshed polls the value refreshed by the instance holding the lock.
+func (c *Client) waitRefreshed(ctx context.Context, key string,
+	fresh func(ctx context.Context) string, refresh func(ctx context.Context) (string, error),
+) (string, error) {
+	ticker := time.NewTicker(refreshLockPollInterval)
+	defer ticker.Stop()
+
+	timeout := time.NewTimer(RefreshLockTTL)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timeout.C:
+			c.Logger.Warnf("wait refresh lock timeout, refresh without lock | key: %s", key)
+			return refresh(ctx)
+		case <-ticker.C:
+			if value := fresh(ctx); value != "" {
+				return value, nil
+			}
+		}
+	}
+}
+
+// MemoryLocker is an in-memory Locker, suitable for the clients of a single instance.
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]time.Time
+}
+
+// NewMemoryLocker creates an in-memory locker.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: make(map[string]time.Time)}
+}
+
+// TryLock implements Locker.
+func (m *MemoryLocker) TryLock(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if expireAt, ok := m.locks[key]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+
+	m.locks[key] = now.Add(ttl)
+
+	return true, nil
+}
+
+// Unlock implements Locker.
+func (m *MemoryLocker) Unlock(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.locks, key)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	ctx := context.Background()
+	locker := NewMemoryLocker()
+
+	locked, err := locker.TryLock(ctx, "key", 20*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	locked, _ = locker.TryLock(ctx, "key", time.Minute)
+	assert.False(t, locked)
+
+	time.Sleep(30 * time.Millisecond)
+	locked, _ = locker.TryLock(ctx, "key", time.Minute)
+	assert.True(t, locked)
+
+	assert.NoError(t, locker.Unlock(ctx, "key"))
+	locked, _ = locker.TryLock(ctx, "key", time.Minute)
+	assert.True(t, locked)
+}
+
+func TestRefreshLocked(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+	locker := NewMemoryLocker()
+	client := NewClient("appid", "secret", WithLocker(locker), WithLogger(NopLogger{}))
+
+	fresh := func(ctx context.Context) string { return cache.Get(ctx, "token") }
+	refreshes := 0
+	refresh := func(ctx context.Context) (string, error) {
+		refreshes++
+		return "refreshed", cache.Set(ctx, "token", "refreshed", time.Hour)
+	}
+
+	token, err := client.RefreshLocked(ctx, "token", fresh, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refreshed", token)
+	assert.Equal(t, 1, refreshes)
+
+	// the value refreshed by the lock holder is returned after acquiring the lock
+	token, err = client.RefreshLocked(ctx, "token", fresh, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refreshed", token)
+	assert.Equal(t, 1, refreshes)
+
+	// another instance holds the lock and caches the value later
+	assert.NoError(t, cache.Delete(ctx, "token"))
+	locked, _ := locker.TryLock(ctx, "token:lock", time.Minute)
+	assert.True(t, locked)
+
+	time.AfterFunc(150*time.Millisecond, func() { _ = cache.Set(ctx, "token", "other", time.Hour) })
+
+	token, err = client.RefreshLocked(ctx, "token", fresh, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", token)
+	assert.Equal(t, 1, refreshes)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, cache.Delete(ctx, "token"))
+	_, err = client.RefreshLocked(timeoutCtx, "token", fresh, refresh)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// refreshed without the lock if the locker fails
+	client.Locker = failingLocker{}
+	token, err = client.RefreshLocked(ctx, "token", fresh, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refreshed", token)
+	assert.Equal(t, 2, refreshes)
+}
+
+type failingLocker struct{}
+
+func (failingLocker) TryLock(context.Context, string, time.Duration) (bool, error) {
+	return false, errors.New("locker down")
+}
+
+func (failingLocker) Unlock(context.Context, string) error {
+	return nil
+}
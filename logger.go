@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import "github.com/vogo/vogo/vlog"
+
+// Logger logs the calls of the SDK, e.g. adapted to zap or slog. The requests and responses
+// are logged at info level, failures at warn and error levels.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// vlogLogger logs by vlog, the default logger.
+type vlogLogger struct{}
+
+func (vlogLogger) Debugf(format string, args ...any) { vlog.Debugf(format, args...) }
+func (vlogLogger) Infof(format string, args ...any)  { vlog.Infof(format, args...) }
+func (vlogLogger) Warnf(format string, args ...any)  { vlog.Warnf(format, args...) }
+func (vlogLogger) Errorf(format string, args ...any) { vlog.Errorf(format, args...) }
+
+// NopLogger discards all logs.
+type NopLogger struct{}
+
+func (NopLogger) Debugf(string, ...any) {}
+func (NopLogger) Infof(string, ...any)  {}
+func (NopLogger) Warnf(string, ...any)  {}
+func (NopLogger) Errorf(string, ...any) {}
+
+// DefaultLogger returns the logger used when none is set, logging by vlog with the sensitive data masked.
+func DefaultLogger() Logger {
+	return RedactLogger(vlogLogger{})
+}
+
+// WithLogger routes the logs of the client and the services created on it to the logger,
+// a nil logger silences them. The logs are masked by Redact unless WithLogRedaction(false).
+func WithLogger(logger Logger) func(*Client) {
+	return func(c *Client) {
+		if logger == nil {
+			logger = NopLogger{}
+		}
+		c.Logger = logger
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordLogger struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (l *recordLogger) record(level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logs = append(l.logs, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordLogger) Debugf(format string, args ...any) { l.record("debug", format, args...) }
+func (l *recordLogger) Infof(format string, args ...any)  { l.record("info", format, args...) }
+func (l *recordLogger) Warnf(format string, args ...any)  { l.record("warn", format, args...) }
+func (l *recordLogger) Errorf(format string, args ...any) { l.record("error", format, args...) }
+
+func TestWithLogger(t *testing.T) {
+	assert.Equal(t, DefaultLogger(), NewClient("appid", "secret").Logger)
+	assert.Equal(t, NopLogger{}, NewClient("appid", "secret", WithLogger(nil)).Logger)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	logger := &recordLogger{}
+	client := NewClient("appid", "secret", WithLogger(logger),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	resp, err := client.Get(Idempotent(context.Background()), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Len(t, logger.logs, 1)
+	assert.Contains(t, logger.logs[0], "warn retry request")
+}
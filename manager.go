@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ErrAppNotRegistered is returned when no client is registered for the appid or original id.
+var ErrAppNotRegistered = errors.New("app not registered")
+
+// AppConfig is the config of an app registered to the Manager.
+type AppConfig struct {
+	AppID      string          // 小程序或公众号的appid
+	AppSecret  string          // AppSecret，使用TokenSource或AccessTokenProvider时可为空
+	OriginalID string          // 原始id（gh_开头），即推送消息的ToUserName
+	Options    []func(*Client) // 该应用的客户端选项，在管理器的公共选项之后应用
+}
+
+// Manager holds the clients of many mini programs and official accounts served by one service,
+// sharing one CacheProvider and http.Client, and resolves the client of incoming push messages
+// by appid or ToUserName.
+type Manager struct {
+	cacheProvider CacheProvider
+	httpClient    *http.Client
+	options       []func(*Client)
+
+	mu          sync.RWMutex
+	clients     map[string]*Client
+	originalIDs map[string]string
+	services    map[serviceKey]any
+}
+
+type serviceKey struct {
+	appID string
+	typ   reflect.Type
+}
+
+// NewManager creates a manager of the clients of many apps.
+func NewManager(options ...func(*Manager)) *Manager {
+	m := &Manager{
+		cacheProvider: NewMemoryCache(),
+		httpClient:    http.DefaultClient,
+		clients:       make(map[string]*Client),
+		originalIDs:   make(map[string]string),
+		services:      make(map[serviceKey]any),
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// WithManagerCacheProvider sets the cache provider shared by the clients, the cache keys contain
+// the appid so that the apps never collide.
+func WithManagerCacheProvider(provider CacheProvider) func(*Manager) {
+	return func(m *Manager) {
+		m.cacheProvider = provider
+	}
+}
+
+// WithManagerHTTPClient sets the http client shared by the clients.
+func WithManagerHTTPClient(httpClient *http.Client) func(*Manager) {
+	return func(m *Manager) {
+		m.httpClient = httpClient
+	}
+}
+
+// WithManagerClientOptions appends the options applied to the clients of all apps, e.g. the logger
+// or the retry policy.
+func WithManagerClientOptions(options ...func(*Client)) func(*Manager) {
+	return func(m *Manager) {
+		m.options = append(m.options, options...)
+	}
+}
+
+// Register creates the client of the app with the shared cache provider and http client.
+func (m *Manager) Register(config *AppConfig) (*Client, error) {
+	if config.AppID == "" {
+		return nil, errors.New("appid is required")
+	}
+
+	options := make([]func(*Client), 0, len(m.options)+len(config.Options)+2)
+	options = append(options, WithCacheProvider(m.cacheProvider), WithHTTPClient(m.httpClient))
+	options = append(options, m.options...)
+	options = append(options, config.Options...)
+
+	client := NewClient(config.AppID, config.AppSecret, options...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.clients[config.AppID]; ok {
+		return nil, fmt.Errorf("app %s already registered", config.AppID)
+	}
+
+	if config.OriginalID != "" {
+		if appID, ok := m.originalIDs[config.OriginalID]; ok {
+			return nil, fmt.Errorf("original id %s already registered by app %s", config.OriginalID, appID)
+		}
+
+		m.originalIDs[config.OriginalID] = config.AppID
+	}
+
+	m.clients[config.AppID] = client
+
+	return client, nil
+}
+
+// Client returns the client of the app of the appid or original id, the appid of encrypted push
+// messages and the ToUserName of all push messages resolve the app receiving them.
+func (m *Manager) Client(appIDOrOriginalID string) (*Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.client(appIDOrOriginalID)
+}
+
+func (m *Manager) client(appIDOrOriginalID string) (*Client, error) {
+	if client, ok := m.clients[appIDOrOriginalID]; ok {
+		return client, nil
+	}
+
+	if appID, ok := m.originalIDs[appIDOrOriginalID]; ok {
+		return m.clients[appID], nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAppNotRegistered, appIDOrOriginalID)
+}
+
+// AppIDs returns the appids of the registered apps in order.
+func (m *Manager) AppIDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	appIDs := make([]string, 0, len(m.clients))
+	for appID := range m.clients {
+		appIDs = append(appIDs, appID)
+	}
+
+	sort.Strings(appIDs)
+
+	return appIDs
+}
+
+// ManagedService returns the service of type T of the app of the appid or original id, created by
+// newService on first use and reused afterwards, e.g.
+//
+//	svc, err := vwx.ManagedService(manager, baseInfo.ToUserName, func(c *vwx.Client) *vwxmp.Service {
+//		return vwxmp.NewService(c)
+//	})
+func ManagedService[T any](m *Manager, appIDOrOriginalID string, newService func(*Client) T) (T, error) {
+	var zero T
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, err := m.client(appIDOrOriginalID)
+	if err != nil {
+		return zero, err
+	}
+
+	key := serviceKey{appID: client.AppID, typ: reflect.TypeFor[T]()}
+	if svc, ok := m.services[key]; ok {
+		return svc.(T), nil
+	}
+
+	svc := newService(client)
+	m.services[key] = svc
+
+	return svc, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager(t *testing.T) {
+	cache := NewMemoryCache()
+	httpClient := &http.Client{}
+
+	m := NewManager(WithManagerCacheProvider(cache), WithManagerHTTPClient(httpClient),
+		WithManagerClientOptions(WithEnvVersion("trial")))
+
+	mini, err := m.Register(&AppConfig{AppID: "wx-mini", AppSecret: "secret", OriginalID: "gh_mini"})
+	assert.NoError(t, err)
+	assert.Same(t, cache, mini.CacheProvider)
+	assert.Same(t, httpClient, mini.HTTPClient)
+	assert.Equal(t, "trial", mini.EnvVersion)
+
+	official, err := m.Register(&AppConfig{AppID: "wx-official", AppSecret: "secret", OriginalID: "gh_official",
+		Options: []func(*Client){WithEnvVersion("release"), WithDryRun(true)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "release", official.EnvVersion)
+	assert.True(t, official.DryRun)
+
+	_, err = m.Register(&AppConfig{AppID: "wx-mini"})
+	assert.Error(t, err)
+	_, err = m.Register(&AppConfig{AppID: "wx-other", OriginalID: "gh_mini"})
+	assert.Error(t, err)
+
+	// resolved by appid or by the ToUserName of push messages
+	client, err := m.Client("wx-official")
+	assert.NoError(t, err)
+	assert.Same(t, official, client)
+
+	client, err = m.Client("gh_mini")
+	assert.NoError(t, err)
+	assert.Same(t, mini, client)
+
+	_, err = m.Client("gh_unknown")
+	assert.ErrorIs(t, err, ErrAppNotRegistered)
+
+	assert.Equal(t, []string{"wx-mini", "wx-official"}, m.AppIDs())
+
+	type service struct{ client *Client }
+	created := 0
+	newService := func(c *Client) *service {
+		created++
+		return &service{client: c}
+	}
+
+	svc, err := ManagedService(m, "gh_mini", newService)
+	assert.NoError(t, err)
+	assert.Same(t, mini, svc.client)
+
+	again, err := ManagedService(m, "wx-mini", newService)
+	assert.NoError(t, err)
+	assert.Same(t, svc, again)
+
+	_, err = ManagedService(m, "wx-official", newService)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-memory CacheProvider with expiry, suitable for a single instance.
+// It is the default cache provider of new clients.
+type MemoryCache struct {
+	Jitter float64 // 过期时间的随机提前比例，取值0-1，避免多个键同时过期
+
+	mu    sync.RWMutex
+	items map[string]*memoryCacheItem
+}
+
+type memoryCacheItem struct {
+	value    string
+	expireAt time.Time // 零值表示永不过期
+}
+
+// NewMemoryCache creates an in-memory cache.
+func NewMemoryCache(options ...func(*MemoryCache)) *MemoryCache {
+	m := &MemoryCache{items: make(map[string]*memoryCacheItem)}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// WithCacheJitter expires the values earlier by a random fraction of up to jitter of their expiry,
+// so that values set together do not expire at the same time.
+func WithCacheJitter(jitter float64) func(*MemoryCache) {
+	return func(m *MemoryCache) {
+		m.Jitter = jitter
+	}
+}
+
+// Get returns the value of the key, empty if not found or expired.
+func (m *MemoryCache) Get(_ context.Context, key string) string {
+	m.mu.RLock()
+	item, ok := m.items[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		return ""
+	}
+
+	if !item.expireAt.IsZero() && time.Now().After(item.expireAt) {
+		m.mu.Lock()
+		if m.items[key] == item {
+			delete(m.items, key)
+		}
+		m.mu.Unlock()
+
+		return ""
+	}
+
+	return item.value
+}
+
+// Set sets the value of the key expiring after expire, a non-positive expire never expires.
+func (m *MemoryCache) Set(_ context.Context, key, value string, expire time.Duration) error {
+	item := &memoryCacheItem{value: value}
+
+	if expire > 0 {
+		if m.Jitter > 0 {
+			expire -= time.Duration(rand.Float64() * m.Jitter * float64(expire))
+		}
+		item.expireAt = time.Now().Add(expire)
+	}
+
+	m.mu.Lock()
+	m.items[key] = item
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Delete deletes the key.
+func (m *MemoryCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.items, key)
+	m.mu.Unlock()
+
+	return nil
+}
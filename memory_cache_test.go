@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(WithCacheJitter(0.5))
+
+	assert.NoError(t, cache.Set(ctx, "token", "value", time.Hour))
+	assert.NoError(t, cache.Set(ctx, "forever", "value", 0))
+	assert.NoError(t, cache.Set(ctx, "short", "value", 20*time.Millisecond))
+
+	assert.Equal(t, "value", cache.Get(ctx, "token"))
+	assert.Equal(t, "value", cache.Get(ctx, "short"))
+	assert.Empty(t, cache.Get(ctx, "missing"))
+
+	expireAt := cache.items["token"].expireAt
+	assert.True(t, expireAt.After(time.Now().Add(29*time.Minute)))
+	assert.True(t, expireAt.Before(time.Now().Add(time.Hour)))
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, cache.Get(ctx, "short"))
+	assert.NotContains(t, cache.items, "short")
+	assert.Equal(t, "value", cache.Get(ctx, "forever"))
+
+	assert.NoError(t, cache.Delete(ctx, "token"))
+	assert.Empty(t, cache.Get(ctx, "token"))
+
+	assert.IsType(t, &MemoryCache{}, NewClient("appid", "secret").CacheProvider)
+	assert.Nil(t, NewClient("appid", "secret", WithCacheProvider(nil)).CacheProvider)
+}
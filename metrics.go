@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// MetricsCollector receives the metrics of the SDK, e.g. adapted to Prometheus or StatsD.
+// The names are prometheus style, the labels are given as key values.
+type MetricsCollector interface {
+	// IncCounter increments the counter by one.
+	IncCounter(name string, labels map[string]string)
+	// SetGauge sets the gauge to the value.
+	SetGauge(name string, value float64, labels map[string]string)
+	// ObserveDuration records the duration in the histogram.
+	ObserveDuration(name string, d time.Duration, labels map[string]string)
+}
+
+// WithMetricsCollector sets the collector of the metrics of the client and the services created on it.
+func WithMetricsCollector(metrics MetricsCollector) func(*Client) {
+	return func(c *Client) {
+		c.Metrics = metrics
+	}
+}
+
+// APICall describes a call to a WeChat API, reported to the MetricsHook.
+type APICall struct {
+	AppID     string        // 小程序或公众号的appid
+	Method    string        // HTTP方法
+	Endpoint  string        // 接口路径，不含query
+	RequestID string        // 关联id，与日志中的request_id一致
+	Start     time.Time     // 开始时间
+	Duration  time.Duration // 耗时，包含重试和刷新token重发，仅OnResponse时有值
+	Status    int           // HTTP状态码，请求失败时为0
+	ErrCode   int           // 响应中的errcode，非json响应为0
+	Err       error         // 请求错误
+}
+
+// MetricsHook observes the calls to the WeChat APIs of all modules, OnResponse is called once per
+// call after the retries and the resend with a refreshed token.
+type MetricsHook interface {
+	OnRequest(ctx context.Context, call *APICall)
+	OnResponse(ctx context.Context, call *APICall)
+}
+
+// WithMetricsHook sets the hook observing the calls to the WeChat APIs.
+func WithMetricsHook(hook MetricsHook) func(*Client) {
+	return func(c *Client) {
+		c.MetricsHook = hook
+	}
+}
+
+// MultiMetricsHook returns the hook notifying all the hooks in order, nil hooks are skipped,
+// e.g. to export metrics and trace the calls at once.
+func MultiMetricsHook(hooks ...MetricsHook) MetricsHook {
+	var multi multiMetricsHook
+	for _, hook := range hooks {
+		switch h := hook.(type) {
+		case nil:
+		case multiMetricsHook:
+			multi = append(multi, h...)
+		default:
+			multi = append(multi, h)
+		}
+	}
+
+	switch len(multi) {
+	case 0:
+		return nil
+	case 1:
+		return multi[0]
+	}
+
+	return multi
+}
+
+type multiMetricsHook []MetricsHook
+
+func (m multiMetricsHook) OnRequest(ctx context.Context, call *APICall) {
+	for _, hook := range m {
+		hook.OnRequest(ctx, call)
+	}
+}
+
+func (m multiMetricsHook) OnResponse(ctx context.Context, call *APICall) {
+	for _, hook := range m {
+		hook.OnResponse(ctx, call)
+	}
+}
+
+// observe reports the call of the request to the metrics hook around do.
+func (c *Client) observe(req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+
+	call := &APICall{
+		AppID:     c.AppID,
+		Method:    req.Method,
+		Endpoint:  req.URL.Path,
+		RequestID: RequestIDFromContext(ctx),
+		Start:     time.Now(),
+	}
+
+	c.MetricsHook.OnRequest(ctx, call)
+
+	resp, err := do(req)
+
+	call.Duration = time.Since(call.Start)
+	call.Err = err
+
+	if err == nil {
+		call.Status = resp.StatusCode
+		resp, call.ErrCode, err = peekErrCode(resp)
+		call.Err = err
+	}
+
+	c.MetricsHook.OnResponse(ctx, call)
+
+	return resp, err
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	requests  []APICall
+	responses []APICall
+}
+
+func (h *recordHook) OnRequest(_ context.Context, call *APICall) {
+	h.requests = append(h.requests, *call)
+}
+
+func (h *recordHook) OnResponse(_ context.Context, call *APICall) {
+	h.responses = append(h.responses, *call)
+}
+
+func TestMetricsHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errcode":45009,"errmsg":"reach max api daily quota limit"}`))
+	}))
+	defer server.Close()
+
+	hook := &recordHook{}
+	client := NewClient("appid", "secret", WithMetricsHook(hook))
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	resp, err := client.Get(ctx, server.URL+"/cgi-bin/openapi/quota/get?access_token=token")
+	assert.NoError(t, err)
+
+	// the body peeked for the errcode is still readable
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "45009")
+	_ = resp.Body.Close()
+
+	assert.Len(t, hook.requests, 1)
+	assert.Len(t, hook.responses, 1)
+
+	call := hook.responses[0]
+	assert.Equal(t, "appid", call.AppID)
+	assert.Equal(t, http.MethodGet, call.Method)
+	assert.Equal(t, "/cgi-bin/openapi/quota/get", call.Endpoint)
+	assert.Equal(t, "req-1", call.RequestID)
+	assert.Equal(t, http.StatusOK, call.Status)
+	assert.Equal(t, ErrCodeDailyQuotaLimit, call.ErrCode)
+	assert.NoError(t, call.Err)
+	assert.Positive(t, call.Duration)
+}
+
+func TestMultiMetricsHook(t *testing.T) {
+	first, second := &recordHook{}, &recordHook{}
+
+	assert.True(t, MultiMetricsHook(nil, nil) == nil)
+	assert.Equal(t, first, MultiMetricsHook(nil, first))
+
+	hook := MultiMetricsHook(MultiMetricsHook(first, nil), second)
+
+	call := &APICall{Endpoint: "/wxa/generate_urllink"}
+	hook.OnRequest(context.Background(), call)
+	hook.OnResponse(context.Background(), call)
+
+	assert.Len(t, first.requests, 1)
+	assert.Len(t, first.responses, 1)
+	assert.Len(t, second.requests, 1)
+	assert.Len(t, second.responses, 1)
+}
@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExceeded is returned when a call waiting for the rate limiter would exceed the deadline
+// of its context.
+var ErrRateLimitExceeded = errors.New("client rate limit exceeded")
+
+// RateLimit is the quota of an endpoint, Requests calls per Per.
+type RateLimit struct {
+	Requests int           // 周期内允许的调用次数，不大于0时不限制
+	Per      time.Duration // 周期
+}
+
+// DefaultRateLimits are the per-minute quotas of the WeChat APIs by endpoint, calls beyond them are
+// rejected with errcode 45009.
+var DefaultRateLimits = map[string]RateLimit{
+	"/wxa/msg_sec_check":     {Requests: 4000, Per: time.Minute},
+	"/wxa/img_sec_check":     {Requests: 2000, Per: time.Minute},
+	"/wxa/media_check_async": {Requests: 2000, Per: time.Minute},
+	"/wxa/getwxacodeunlimit": {Requests: 5000, Per: time.Minute},
+}
+
+// RateLimiter limits the calls per endpoint with token buckets, each holding the quota of a period,
+// so that the quota can be used in bursts. Calls beyond the quota wait for a token.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]RateLimit
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the limits by endpoint, e.g. DefaultRateLimits.
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	l := &RateLimiter{
+		limits:  make(map[string]RateLimit, len(limits)),
+		buckets: make(map[string]*tokenBucket),
+	}
+
+	for endpoint, limit := range limits {
+		l.limits[endpoint] = limit
+	}
+
+	return l
+}
+
+// SetLimit replaces the limit of the endpoint, a zero limit removes it.
+func (l *RateLimiter) SetLimit(endpoint string, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits[endpoint] = limit
+	delete(l.buckets, endpoint)
+}
+
+// Wait waits until the endpoint can be called, it returns ErrRateLimitExceeded at once if the wait
+// would exceed the deadline of the context. Endpoints without limit are not waited.
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	delay, ok := l.reserve(endpoint)
+	if !ok || delay <= 0 {
+		return nil
+	}
+
+	if deadline, has := ctx.Deadline(); has && time.Until(deadline) < delay {
+		l.cancel(endpoint)
+		return ErrRateLimitExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(endpoint)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token of the endpoint, returning how long to wait for it.
+func (l *RateLimiter) reserve(endpoint string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.buckets[endpoint]
+	if bucket == nil {
+		limit, ok := l.limits[endpoint]
+		if !ok || limit.Requests <= 0 || limit.Per <= 0 {
+			return 0, false
+		}
+
+		bucket = &tokenBucket{
+			tokens:   float64(limit.Requests),
+			capacity: float64(limit.Requests),
+			rate:     float64(limit.Requests) / limit.Per.Seconds(),
+			last:     time.Now(),
+		}
+		l.buckets[endpoint] = bucket
+	}
+
+	now := time.Now()
+	bucket.tokens = min(bucket.capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate)
+	bucket.last = now
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0, true
+	}
+
+	return time.Duration(-bucket.tokens / bucket.rate * float64(time.Second)), true
+}
+
+// cancel gives back the token reserved for a call given up.
+func (l *RateLimiter) cancel(endpoint string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket := l.buckets[endpoint]; bucket != nil {
+		bucket.tokens = min(bucket.capacity, bucket.tokens+1)
+	}
+}
+
+// WithRateLimiter sets the rate limiter of the calls of the client, share a limiter among the clients
+// of the same appid. NewRateLimiter(DefaultRateLimits) honors the known quotas of WeChat.
+func WithRateLimiter(limiter *RateLimiter) func(*Client) {
+	return func(c *Client) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithRateLimit limits the calls of the endpoint (e.g. /wxa/msg_sec_check) to the limit, enabling
+// the rate limiter with the DefaultRateLimits if not set. A zero limit removes the limit of the endpoint.
+func WithRateLimit(endpoint string, limit RateLimit) func(*Client) {
+	return func(c *Client) {
+		if c.RateLimiter == nil {
+			c.RateLimiter = NewRateLimiter(DefaultRateLimits)
+		}
+
+		c.RateLimiter.SetLimit(endpoint, limit)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(map[string]RateLimit{"/wxa/msg_sec_check": {Requests: 2, Per: 200 * time.Millisecond}})
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(ctx, "/wxa/msg_sec_check"))
+	assert.NoError(t, limiter.Wait(ctx, "/wxa/msg_sec_check"))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// the third call waits for a token refilled in 100ms
+	assert.NoError(t, limiter.Wait(ctx, "/wxa/msg_sec_check"))
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	// endpoints without limit are not waited
+	for range 10 {
+		assert.NoError(t, limiter.Wait(ctx, "/wxa/generate_urllink"))
+	}
+
+	// calls which would wait beyond the deadline fail at once
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(timeoutCtx, "/wxa/msg_sec_check"), ErrRateLimitExceeded)
+
+	limiter.SetLimit("/wxa/msg_sec_check", RateLimit{})
+	assert.NoError(t, limiter.Wait(timeoutCtx, "/wxa/msg_sec_check"))
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("appid", "secret", WithRateLimit("/wxa/generate_urllink", RateLimit{Requests: 1, Per: time.Hour}))
+	assert.Equal(t, DefaultRateLimits["/wxa/msg_sec_check"], client.RateLimiter.limits["/wxa/msg_sec_check"])
+
+	resp, err := client.Get(context.Background(), server.URL+"/wxa/generate_urllink")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = client.Get(ctx, server.URL+"/wxa/generate_urllink")
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+}
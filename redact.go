@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// redactKeyPattern matches the values of sensitive keys in urls, json and log fields,
+	// e.g. access_token=xxx, "session_key":"xxx" and openid: xxx.
+	redactKeyPattern = regexp.MustCompile(`(?i)((?:access_token|refresh_token|session_?key|secret|encrypted_?data|\biv|\w*openid|touser|FromUserName)"?\s*[:=]\s*"?)([^\s&"',|}<]+)`)
+
+	// redactXMLPattern matches the openid of the user in xml push messages.
+	redactXMLPattern = regexp.MustCompile(`(<FromUserName><!\[CDATA\[)([^\]]+)`)
+
+	// redactPhonePattern matches mainland China mobile phone numbers.
+	redactPhonePattern = regexp.MustCompile(`\b(1[3-9]\d)\d{4}(\d{4})\b`)
+)
+
+// Redact masks the sensitive data in the log line: access tokens, session keys, secrets,
+// encrypted data, openids and phone numbers. A few leading and trailing characters of long
+// values are kept to correlate the lines.
+func Redact(s string) string {
+	s = redactKeyPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := redactKeyPattern.FindStringSubmatch(m)
+		return parts[1] + maskValue(parts[2])
+	})
+
+	s = redactXMLPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := redactXMLPattern.FindStringSubmatch(m)
+		return parts[1] + maskValue(parts[2])
+	})
+
+	return redactPhonePattern.ReplaceAllString(s, "$1****$2")
+}
+
+func maskValue(v string) string {
+	if len(v) <= 10 {
+		return strings.Repeat("*", len(v))
+	}
+
+	return v[:3] + "***" + v[len(v)-3:]
+}
+
+// redactLogger masks the sensitive data of the log lines before writing them to the logger.
+type redactLogger struct {
+	logger Logger
+}
+
+// RedactLogger returns a logger masking the sensitive data of the log lines by Redact.
+func RedactLogger(logger Logger) Logger {
+	if _, ok := logger.(redactLogger); ok {
+		return logger
+	}
+
+	return redactLogger{logger: logger}
+}
+
+func (l redactLogger) Debugf(format string, args ...any) {
+	l.logger.Debugf("%s", Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactLogger) Infof(format string, args ...any) {
+	l.logger.Infof("%s", Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactLogger) Warnf(format string, args ...any) {
+	l.logger.Warnf("%s", Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactLogger) Errorf(format string, args ...any) {
+	l.logger.Errorf("%s", Redact(fmt.Sprintf(format, args...)))
+}
+
+// WithLogRedaction enables or disables masking the sensitive data in the logs of the client,
+// enabled by default. Disable it only for debugging.
+func WithLogRedaction(enabled bool) func(*Client) {
+	return func(c *Client) {
+		c.LogRedaction = enabled
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	for _, c := range []struct {
+		line     string
+		redacted string
+	}{
+		{
+			"get | url: https://api.weixin.qq.com/cgi-bin/user/info?access_token=ACCESS_TOKEN_VALUE&openid=oABCDEFGHIJKLMNOPQRS&lang=zh_CN",
+			"get | url: https://api.weixin.qq.com/cgi-bin/user/info?access_token=ACC***LUE&openid=oAB***QRS&lang=zh_CN",
+		},
+		{
+			`resp: {"session_key":"tiihtNczf5v6AKRyjwEUhQ==","openid":"oABCDEFGHIJKLMNOPQRS","phoneNumber":"13812345678"}`,
+			`resp: {"session_key":"tii***Q==","openid":"oAB***QRS","phoneNumber":"138****5678"}`,
+		},
+		{
+			"decrypt phone number | sessionKey: tiihtNczf5v6AKRyjwEUhQ== | iv: r7BXXKkLb8qrSNn05n0qiA==",
+			"decrypt phone number | sessionKey: tii***Q== | iv: r7B***A==",
+		},
+		{
+			"push message | <xml><FromUserName><![CDATA[oABCDEFGHIJKLMNOPQRS]]></FromUserName></xml>",
+			"push message | <xml><FromUserName><![CDATA[oAB***QRS]]></FromUserName></xml>",
+		},
+		{
+			"subscribe | openid: short | template: tmpl",
+			"subscribe | openid: ***** | template: tmpl",
+		},
+		{
+			"privilege: none | errcode: 40001",
+			"privilege: none | errcode: 40001",
+		},
+	} {
+		assert.Equal(t, c.redacted, Redact(c.line))
+	}
+}
+
+func TestLogRedaction(t *testing.T) {
+	logger := &recordLogger{}
+	NewClient("appid", "secret", WithLogger(logger)).Logger.Infof("openid: %s", "oABCDEFGHIJKLMNOPQRS")
+	NewClient("appid", "secret", WithLogger(logger), WithLogRedaction(false)).Logger.Infof("openid: %s", "oABCDEFGHIJKLMNOPQRS")
+
+	assert.Equal(t, []string{"info openid: oAB***QRS", "info openid: oABCDEFGHIJKLMNOPQRS"}, logger.logs)
+}
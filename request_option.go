@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOptions holds the per-call options of an API request.
+type RequestOptions struct {
+	// AccessToken overrides the access token of the client for the call, e.g. a token supplied
+	// by a central token service or an authorizer token of a third-party platform.
+	AccessToken string
+
+	// Env switches the env_version dependent settings of the call to the profile of the env version.
+	Env string
+
+	// Timeout bounds the call including its retries and the read of its response, zero keeps the
+	// deadline of the context.
+	Timeout time.Duration
+
+	// OverrideRetry replaces the retry policy of the client by RetryPolicy for the call, a nil
+	// RetryPolicy disables retrying. Only idempotent calls are retried.
+	OverrideRetry bool
+	RetryPolicy   *RetryPolicy
+
+	// Header is added to the http requests of the call, e.g. the headers required by a forwarding proxy.
+	Header http.Header
+}
+
+// RequestOption configures a single API call.
+type RequestOption func(*RequestOptions)
+
+// NewRequestOptions applies the options and returns the resulting per-call options.
+func NewRequestOptions(opts ...RequestOption) *RequestOptions {
+	o := &RequestOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithAccessToken uses the given access token for the call instead of acquiring one by the client.
+// The token is never refreshed by the client, a call failing with an invalidated token returns the
+// api error so that the owner of the token refreshes it.
+func WithAccessToken(accessToken string) RequestOption {
+	return func(o *RequestOptions) {
+		o.AccessToken = accessToken
+	}
+}
+
+// WithTimeout bounds the call by the timeout, e.g. a longer deadline for a slow endpoint.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithRetry retries the call by the policy instead of the retry policy of the client.
+func WithRetry(policy *RetryPolicy) RequestOption {
+	return func(o *RequestOptions) {
+		o.OverrideRetry = true
+		o.RetryPolicy = policy
+	}
+}
+
+// WithoutRetry disables retrying for the call.
+func WithoutRetry() RequestOption {
+	return WithRetry(nil)
+}
+
+// WithHeader adds the header to the http requests of the call.
+func WithHeader(key, value string) RequestOption {
+	return func(o *RequestOptions) {
+		if o.Header == nil {
+			o.Header = make(http.Header)
+		}
+
+		o.Header.Add(key, value)
+	}
+}
+
+// Context applies the timeout, the retry override, the headers and the access token override of the
+// options to the context of the call. The cancel func must be called once the response of the call is read.
+func (o *RequestOptions) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.AccessToken != "" {
+		ctx = context.WithValue(ctx, accessTokenOverriddenKey{}, true)
+	}
+
+	if o.OverrideRetry {
+		ctx = ContextWithRetryPolicy(ctx, o.RetryPolicy)
+	}
+
+	if len(o.Header) > 0 {
+		ctx = ContextWithHeader(ctx, o.Header)
+	}
+
+	if o.Timeout > 0 {
+		return context.WithTimeout(ctx, o.Timeout)
+	}
+
+	return ctx, func() {}
+}
+
+type accessTokenOverriddenKey struct{}
+
+// accessTokenOverridden reports whether the access token of the call is overridden by WithAccessToken,
+// such tokens are not owned by the client and never refreshed by it.
+func accessTokenOverridden(ctx context.Context) bool {
+	overridden, _ := ctx.Value(accessTokenOverriddenKey{}).(bool)
+	return overridden
+}
+
+type headerKey struct{}
+
+// ContextWithHeader adds the header to the http requests sent by the client with the context,
+// the headers of outer contexts are kept.
+func ContextWithHeader(ctx context.Context, header http.Header) context.Context {
+	merged := headerFromContext(ctx).Clone()
+	if merged == nil {
+		merged = make(http.Header, len(header))
+	}
+
+	for key, values := range header {
+		for _, value := range values {
+			merged.Add(key, value)
+		}
+	}
+
+	return context.WithValue(ctx, headerKey{}, merged)
+}
+
+func headerFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return header
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestWithAccessToken(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxa.NewService(client)
+
+	// the token is issued by a central token service, not by the client
+	server.AccessToken = "external-token"
+
+	_, err := svc.GenerateSimpleURLLink("/pages/index", "a=1", vwx.WithAccessToken("external-token"))
+	assert.NoError(t, err)
+	assert.Empty(t, server.Requests("/cgi-bin/token"))
+
+	requests := server.Requests("/wxa/generate_urllink")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "external-token", requests[0].Query.Get("access_token"))
+}
+
+func TestRequestOptions(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithRetryPolicy(&vwx.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	svc := vwxa.NewService(client)
+
+	_, err := svc.GenerateSimpleURLLink("/pages/index", "a=1", vwx.WithHeader("X-Tenant", "t1"))
+	assert.NoError(t, err)
+
+	requests := server.Requests("/wxa/generate_urllink")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "t1", requests[0].Header.Get("X-Tenant"))
+
+	// the headers of the call are not added to the token request
+	assert.Empty(t, server.Requests("/cgi-bin/token")[0].Header.Get("X-Tenant"))
+
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1", vwx.WithTimeout(time.Nanosecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	media, err := svc.GenerateQRCodeMedia("scene", "pages/index", vwx.WithTimeout(time.Minute), vwx.WithHeader("X-Tenant", "t2"))
+	assert.NoError(t, err)
+	data, err := io.ReadAll(media)
+	assert.NoError(t, err)
+	assert.NoError(t, media.Close())
+	assert.True(t, bytes.HasPrefix(data, vwxmock.PNGHeader))
+	assert.Equal(t, "t2", server.Requests("/wxa/getwxacodeunlimit")[0].Header.Get("X-Tenant"))
+
+	// the retry policy of the client is overridden per call
+	server.SetError("/wxa/getwxacodeunlimit", vwx.ErrCodeSystemBusy, "system busy")
+
+	_, _ = svc.GenerateQRCode("scene", "pages/index", vwx.WithoutRetry())
+	assert.Len(t, server.Requests("/wxa/getwxacodeunlimit"), 2)
+
+	_, _ = svc.GenerateQRCode("scene", "pages/index", vwx.WithRetry(&vwx.RetryPolicy{MaxAttempts: 2}))
+	assert.Len(t, server.Requests("/wxa/getwxacodeunlimit"), 4)
+
+	_, _ = svc.GenerateQRCode("scene", "pages/index")
+	assert.Len(t, server.Requests("/wxa/getwxacodeunlimit"), 7)
+}
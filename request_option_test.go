@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOptionsContext(t *testing.T) {
+	o := NewRequestOptions(WithHeader("X-A", "1"), WithHeader("X-A", "2"), WithoutRetry(), WithTimeout(time.Minute))
+
+	parent := ContextWithHeader(context.Background(), http.Header{"X-B": {"b"}})
+	ctx, cancel := o.Context(Idempotent(parent))
+	defer cancel()
+
+	header := headerFromContext(ctx)
+	assert.Equal(t, []string{"1", "2"}, header.Values("X-A"))
+	assert.Equal(t, "b", header.Get("X-B"))
+	assert.Empty(t, headerFromContext(parent).Get("X-A"))
+
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+
+	client := NewClient("appid", "secret")
+	assert.Nil(t, client.retryPolicy(ctx))
+
+	// no options keep the context as is
+	ctx, cancel = NewRequestOptions().Context(Idempotent(context.Background()))
+	defer cancel()
+
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, client.RetryPolicy, client.retryPolicy(ctx))
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+type requestIDKey struct{}
+
+var ridRegexp = regexp.MustCompile(`rid:\s*([0-9a-zA-Z-]+)`)
+
+// NewRequestID generates a random correlation id for an API call or push message.
+func NewRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ContextWithRequestID returns a context carrying the correlation id.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the correlation id carried by the context, or empty if absent.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestIDOrNew returns the correlation id carried by the context, or generates a new one.
+func RequestIDOrNew(ctx context.Context) string {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return requestID
+	}
+	return NewRequestID()
+}
+
+// ParseRID extracts the WeChat rid from an errmsg like "invalid credential rid: 64c1d2a3-1a2b3c4d-5e6f7a8b".
+func ParseRID(errMsg string) string {
+	match := ridRegexp.FindStringSubmatch(errMsg)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
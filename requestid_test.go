@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	id := NewRequestID()
+	assert.Len(t, id, 16)
+	assert.NotEqual(t, id, NewRequestID())
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+	assert.Equal(t, "req-1", RequestIDOrNew(ctx))
+	assert.Empty(t, RequestIDFromContext(context.Background()))
+	assert.Len(t, RequestIDOrNew(context.Background()), 16)
+}
+
+func TestParseRID(t *testing.T) {
+	assert.Equal(t, "64c1d2a3-1a2b3c4d-5e6f7a8b",
+		ParseRID("invalid credential, access_token is invalid or not latest rid: 64c1d2a3-1a2b3c4d-5e6f7a8b"))
+	assert.Empty(t, ParseRID("ok"))
+}
@@ -0,0 +1,207 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseSize is the max size of the responses read from the apis, 10MB.
+const DefaultMaxResponseSize int64 = 10 << 20
+
+var (
+	// ErrResponseTooLarge is the cause of the ResponseError of a response exceeding the max response size.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrUnexpectedContentType is the cause of the ResponseError of a response of an unexpected content type.
+	ErrUnexpectedContentType = errors.New("unexpected content type")
+
+	// ErrMalformedResponse is the cause of the ResponseError of a response failing to be decoded.
+	ErrMalformedResponse = errors.New("malformed response")
+)
+
+// RawResponse is the http status and the body of a response captured for troubleshooting,
+// the body is truncated to the capture limit.
+type RawResponse struct {
+	StatusCode int    // 响应的 http 状态码
+	Body       []byte // 响应体，超出上限时截断
+	Truncated  bool   // 响应体是否被截断
+}
+
+// NewRawResponse captures a copy of the body of at most limit bytes.
+func NewRawResponse(statusCode int, body []byte, limit int) *RawResponse {
+	raw := &RawResponse{StatusCode: statusCode}
+
+	if len(body) > limit {
+		body = body[:limit]
+		raw.Truncated = true
+	}
+
+	raw.Body = bytes.Clone(body)
+
+	return raw
+}
+
+func (r *RawResponse) String() string {
+	if r.Truncated {
+		return fmt.Sprintf("status: %d | body: %q...", r.StatusCode, r.Body)
+	}
+
+	return fmt.Sprintf("status: %d | body: %q", r.StatusCode, r.Body)
+}
+
+// JSONContentTypes are the content types of the json apis, WeChat answers some of them in text/plain.
+var JSONContentTypes = []string{"application/json", "text/plain"}
+
+// ResponseError is returned when a response is rejected or fails to be decoded, the cause is
+// ErrResponseTooLarge, ErrUnexpectedContentType or ErrMalformedResponse.
+type ResponseError struct {
+	Endpoint    string       // 接口路径，如 /wxa/getwxacodeunlimit
+	StatusCode  int          // 响应的 http 状态码
+	ContentType string       // 响应的 Content-Type
+	Limit       int64        // 响应大小上限，仅 ErrResponseTooLarge 时有值
+	Err         error        // ErrResponseTooLarge、ErrUnexpectedContentType 或 ErrMalformedResponse
+	Response    *RawResponse // 原始响应，仅开启响应捕获时记录
+}
+
+func (e *ResponseError) Error() string {
+	if errors.Is(e.Err, ErrResponseTooLarge) {
+		return fmt.Sprintf("%v: exceeds %d bytes | endpoint: %s", e.Err, e.Limit, e.Endpoint)
+	}
+
+	if e.Response != nil {
+		return fmt.Sprintf("%v: %q | endpoint: %s | %s", e.Err, e.ContentType, e.Endpoint, e.Response)
+	}
+
+	return fmt.Sprintf("%v: %q | endpoint: %s | status: %d", e.Err, e.ContentType, e.Endpoint, e.StatusCode)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// WithMaxResponseSize sets the max size of the responses read from the apis, DefaultMaxResponseSize
+// if not positive. Streamed downloads (e.g. bills and temp media) are not limited.
+func WithMaxResponseSize(size int64) func(*Client) {
+	return func(c *Client) {
+		c.MaxResponseSize = size
+	}
+}
+
+// maxResponseSize returns the max response size of the client.
+func (c *Client) maxResponseSize() int64 {
+	if c.MaxResponseSize > 0 {
+		return c.MaxResponseSize
+	}
+
+	return DefaultMaxResponseSize
+}
+
+// ReadResponse reads the body of the response up to the max response size of the client. The content
+// type of the response is checked first against the expected content types if any, see CheckContentType.
+func (c *Client) ReadResponse(resp *http.Response, contentTypes ...string) ([]byte, error) {
+	if err := CheckContentType(resp, contentTypes...); err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(c.LimitResponse(resp))
+}
+
+// LimitResponse returns the reader of the body of the response failing with a ResponseError of
+// ErrResponseTooLarge once the max response size of the client is exceeded.
+func (c *Client) LimitResponse(resp *http.Response) io.Reader {
+	limit := c.maxResponseSize()
+
+	return &limitedReader{resp: resp, limit: limit, remaining: limit}
+}
+
+// limitedReader reads a response body up to the limit, reading one byte more to detect a larger body.
+type limitedReader struct {
+	resp      *http.Response
+	limit     int64
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, r.tooLarge()
+	}
+
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.resp.Body.Read(p)
+	r.remaining -= int64(n)
+
+	if r.remaining < 0 {
+		return n + int(r.remaining), r.tooLarge()
+	}
+
+	return n, err
+}
+
+func (r *limitedReader) tooLarge() error {
+	return &ResponseError{
+		Endpoint:    responseEndpoint(r.resp),
+		StatusCode:  r.resp.StatusCode,
+		ContentType: r.resp.Header.Get("Content-Type"),
+		Limit:       r.limit,
+		Err:         ErrResponseTooLarge,
+	}
+}
+
+// CheckContentType checks the media type of the response against the content types, a content
+// type ending with "/" matches all subtypes (e.g. "image/"). Responses without a content type pass.
+func CheckContentType(resp *http.Response, contentTypes ...string) error {
+	contentType := resp.Header.Get("Content-Type")
+	if len(contentTypes) == 0 || contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		for _, expected := range contentTypes {
+			if mediaType == expected || (strings.HasSuffix(expected, "/") && strings.HasPrefix(mediaType, expected)) {
+				return nil
+			}
+		}
+	}
+
+	return &ResponseError{
+		Endpoint:    responseEndpoint(resp),
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		Err:         ErrUnexpectedContentType,
+	}
+}
+
+// responseEndpoint returns the path of the request of the response.
+func responseEndpoint(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+
+	return resp.Request.URL.Path
+}
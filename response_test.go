@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestResponse(contentType, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{URL: &url.URL{Path: "/wxa/getwxacodeunlimit"}},
+	}
+}
+
+func TestReadResponse(t *testing.T) {
+	client := NewClient("appid", "secret", WithMaxResponseSize(8))
+
+	body, err := client.ReadResponse(newTestResponse("image/jpeg", "12345678"), "image/")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678", string(body))
+
+	_, err = client.ReadResponse(newTestResponse("image/jpeg", "123456789"), "image/")
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	var respErr *ResponseError
+	assert.True(t, errors.As(err, &respErr))
+	assert.Equal(t, int64(8), respErr.Limit)
+	assert.Equal(t, "/wxa/getwxacodeunlimit", respErr.Endpoint)
+
+	_, err = client.ReadResponse(newTestResponse("application/json; encoding=utf-8", `{}`), "image/")
+	assert.ErrorIs(t, err, ErrUnexpectedContentType)
+	assert.True(t, errors.As(err, &respErr))
+	assert.Equal(t, "application/json; encoding=utf-8", respErr.ContentType)
+
+	body, err = client.ReadResponse(newTestResponse("text/plain; charset=utf-8", `{}`), JSONContentTypes...)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(body))
+
+	// responses without a content type pass
+	_, err = client.ReadResponse(newTestResponse("", `{}`), JSONContentTypes...)
+	assert.NoError(t, err)
+
+	assert.Equal(t, DefaultMaxResponseSize, NewClient("appid", "secret").maxResponseSize())
+}
+
+func TestNewRawResponse(t *testing.T) {
+	body := []byte(`{"errcode":-1}`)
+
+	raw := NewRawResponse(200, body, 64)
+	assert.Equal(t, body, raw.Body)
+	assert.False(t, raw.Truncated)
+	assert.Equal(t, `status: 200 | body: "{\"errcode\":-1}"`, raw.String())
+
+	// the body is copied
+	body[0] = '['
+	assert.Equal(t, `{"errcode":-1}`, string(raw.Body))
+
+	raw = NewRawResponse(502, []byte("<html>bad gateway</html>"), 6)
+	assert.Equal(t, "<html>", string(raw.Body))
+	assert.True(t, raw.Truncated)
+	assert.Equal(t, `status: 502 | body: "<html>"...`, raw.String())
+}
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrCodeSystemBusy is the errcode returned by WeChat when the system is busy, the call can be retried.
+const ErrCodeSystemBusy = -1
+
+// RetryPolicy configures the automatic retry of idempotent calls failing with transient errors:
+// network errors, 5xx responses, errcode -1 (system busy) and the RetryableCodes. Calls exceeding
+// the api quota (45009/45011) are never retried, retrying would only consume more quota.
+type RetryPolicy struct {
+	MaxAttempts    int           // 最大尝试次数（含首次请求），小于等于1表示不重试
+	BaseDelay      time.Duration // 首次重试前的等待时间，之后每次翻倍
+	MaxDelay       time.Duration // 单次等待时间上限
+	Jitter         float64       // 等待时间的随机抖动比例，取值0-1
+	RetryableCodes []int         // 系统繁忙之外可重试的错误码
+}
+
+// DefaultRetryPolicy returns the retry policy of new clients: 3 attempts with 100ms exponential backoff.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// Backoff returns the delay before the retry after the given attempt (starting from 1).
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+
+	return max(delay, 0)
+}
+
+// WithRetryPolicy sets the retry policy of idempotent calls, nil disables retrying.
+func WithRetryPolicy(policy *RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+type (
+	idempotentKey     struct{}
+	retryPolicyKey    struct{}
+	retryableCodesKey struct{}
+)
+
+// Idempotent marks the calls made with the context as safe to be retried by the retry policy.
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// ContextWithRetryPolicy overrides the retry policy of the client for the calls made with the context.
+func ContextWithRetryPolicy(ctx context.Context, policy *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// ContextWithoutRetry disables retrying for the calls made with the context.
+func ContextWithoutRetry(ctx context.Context) context.Context {
+	return ContextWithRetryPolicy(ctx, nil)
+}
+
+// ContextWithRetryableCodes makes the errcodes retryable for the calls made with the context
+// in addition to those of the retry policy, e.g. the transient errcodes of an endpoint.
+func ContextWithRetryableCodes(ctx context.Context, codes ...int) context.Context {
+	return context.WithValue(ctx, retryableCodesKey{}, codes)
+}
+
+// retryableCode reports whether the call failing with the errcode should be retried.
+func retryableCode(ctx context.Context, policy *RetryPolicy, errCode int) bool {
+	switch errCode {
+	case 0, ErrCodeDailyQuotaLimit, ErrCodeMinuteQuotaLimit:
+		return false
+	case ErrCodeSystemBusy:
+		return true
+	}
+
+	codes, _ := ctx.Value(retryableCodesKey{}).([]int)
+
+	return slices.Contains(policy.RetryableCodes, errCode) || slices.Contains(codes, errCode)
+}
+
+func (c *Client) retryPolicy(ctx context.Context) *RetryPolicy {
+	if idempotent, _ := ctx.Value(idempotentKey{}).(bool); !idempotent {
+		return nil
+	}
+
+	if policy, ok := ctx.Value(retryPolicyKey{}).(*RetryPolicy); ok {
+		return policy
+	}
+
+	return c.RetryPolicy
+}
+
+// doWithRetry sends the request, retrying transient failures by the policy.
+func (c *Client) doWithRetry(req *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.roundTrip(req)
+
+		reason := ""
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case resp.StatusCode >= http.StatusInternalServerError:
+			reason = resp.Status
+		default:
+			var errCode int
+			if resp, errCode, err = peekErrCode(resp); err == nil && retryableCode(ctx, policy, errCode) {
+				reason = fmt.Sprintf("errcode %d", errCode)
+			}
+		}
+
+		if reason == "" || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		delay := policy.Backoff(attempt)
+		c.Logger.Warnf("retry request | request_id: %s | path: %s | attempt: %d | delay: %s | reason: %s",
+			RequestIDFromContext(ctx), req.URL.Path, attempt, delay, reason)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// maxPeekSize bounds the bytes of a response buffered to peek its errcode, error responses are small.
+const maxPeekSize = 64 << 10
+
+// peekedBody is a response body whose peeked head is read again before the rest.
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// peekErrCode reads the errcode of a json response, the body is restored for the caller.
+// Zero is returned if the response carries no errcode.
+func peekErrCode(resp *http.Response) (*http.Response, int, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/") {
+		return resp, 0, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPeekSize))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, 0, err
+	}
+
+	resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+
+	var result struct {
+		ErrCode int `json:"errcode"`
+	}
+	if json.Unmarshal(body, &result) != nil {
+		return resp, 0, nil
+	}
+
+	return resp, result.ErrCode, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	failures := int32(2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "ping", string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch n := calls.Add(1); {
+		case n == 1 && failures > 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case n <= failures:
+			_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system error"}`))
+		default:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("appid", "secret", WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	post := func(ctx context.Context) string {
+		resp, err := client.Post(ctx, server.URL, "text/plain", strings.NewReader("ping"))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	// 502, system busy, then ok
+	assert.Equal(t, `{"errcode":0,"errmsg":"ok"}`, post(Idempotent(context.Background())))
+	assert.Equal(t, int32(3), calls.Load())
+
+	// calls not marked idempotent are not retried
+	calls.Store(0)
+	failures = 1
+	assert.Equal(t, `{"errcode":-1,"errmsg":"system error"}`, post(context.Background()))
+	assert.Equal(t, int32(1), calls.Load())
+
+	calls.Store(0)
+	assert.Equal(t, `{"errcode":-1,"errmsg":"system error"}`, post(ContextWithoutRetry(Idempotent(context.Background()))))
+	assert.Equal(t, int32(1), calls.Load())
+
+	// attempts exhausted, the last response is returned
+	calls.Store(0)
+	failures = 5
+	assert.Equal(t, `{"errcode":-1,"errmsg":"system error"}`, post(Idempotent(context.Background())))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryableCodes(t *testing.T) {
+	var calls atomic.Int32
+	var errCode atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if calls.Add(1) == 1 {
+			_, _ = fmt.Fprintf(w, `{"errcode":%d,"errmsg":"failed"}`, errCode.Load())
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("appid", "secret", WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryableCodes: []int{85074}}))
+
+	get := func(ctx context.Context, code int) int32 {
+		calls.Store(0)
+		errCode.Store(int32(code))
+
+		resp, err := client.Get(Idempotent(ctx), server.URL)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+
+		return calls.Load()
+	}
+
+	assert.Equal(t, int32(2), get(context.Background(), 85074))
+	assert.Equal(t, int32(1), get(context.Background(), 40097))
+	assert.Equal(t, int32(2), get(ContextWithRetryableCodes(context.Background(), 40097), 40097))
+
+	// exceeding the quota is never retried
+	assert.Equal(t, int32(1), get(ContextWithRetryableCodes(context.Background(), ErrCodeMinuteQuotaLimit), ErrCodeMinuteQuotaLimit))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, time.Second, policy.Backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		delay := policy.Backoff(1)
+		assert.True(t, delay >= 50*time.Millisecond && delay <= 150*time.Millisecond, delay)
+	}
+}
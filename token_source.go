@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAppSecretRequired is returned by operations needing the AppSecret on a client created without it.
+var ErrAppSecretRequired = errors.New("app secret is required, the client is in secret-free mode")
+
+// TokenSource supplies access tokens acquired outside of the client, e.g. from a central token service.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token returns the token of the function.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource always supplying the given token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenSource sets the source of access tokens, replacing the token acquisition by the AppSecret.
+func WithTokenSource(ts TokenSource) func(*Client) {
+	return func(c *Client) {
+		c.TokenSource = ts
+	}
+}
+
+// NewSecretFreeClient creates a client without the AppSecret for edge services that must never hold it,
+// access tokens are supplied by the token source and operations needing the secret (e.g. code2session)
+// fail with ErrAppSecretRequired.
+func NewSecretFreeClient(appID string, ts TokenSource, options ...func(*Client)) *Client {
+	c := NewClient(appID, "", options...)
+	c.TokenSource = ts
+
+	return c
+}
+
+// SecretFree reports whether the client holds no AppSecret.
+func (c *Client) SecretFree() bool {
+	return c.AppSecret == ""
+}
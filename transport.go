@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// WithProxy sends the API requests through the forward proxy, e.g. http://proxy.corp:3128, instead of
+// the proxy of the HTTP_PROXY and HTTPS_PROXY environment variables.
+func WithProxy(proxyURL *url.URL) func(*Client) {
+	return func(c *Client) {
+		c.Proxy = proxyURL
+	}
+}
+
+// WithTLSConfig sets the TLS config of the connections to WeChat, e.g. the root CAs trusting the
+// certificate of an intercepting proxy.
+func WithTLSConfig(config *tls.Config) func(*Client) {
+	return func(c *Client) {
+		c.TLSConfig = config
+	}
+}
+
+// configureTransport replaces the http client with a copy whose transport uses the proxy and the
+// TLS config of the client, the http client given by WithHTTPClient is never modified. Custom
+// transports which are not *http.Transport are kept as is.
+func (c *Client) configureTransport() {
+	if c.Proxy == nil && c.TLSConfig == nil {
+		return
+	}
+
+	httpClient := http.DefaultClient
+	if c.HTTPClient != nil {
+		httpClient = c.HTTPClient
+	}
+
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		c.Logger.Warnf("proxy and tls config ignored, the transport of the http client is %T", base)
+		return
+	}
+
+	transport = transport.Clone()
+
+	if c.Proxy != nil {
+		transport.Proxy = http.ProxyURL(c.Proxy)
+	}
+
+	if c.TLSConfig != nil {
+		transport.TLSClientConfig = c.TLSConfig.Clone()
+	}
+
+	configured := *httpClient
+	configured.Transport = transport
+	c.HTTPClient = &configured
+}
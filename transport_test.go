@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) {
+	var hosts []string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.URL.Host)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	httpClient := &http.Client{}
+	client := NewClient("appid", "secret", WithHTTPClient(httpClient), WithProxy(proxyURL), WithLogger(NopLogger{}))
+
+	resp, err := client.Get(context.Background(), "http://api.weixin.qq.com/cgi-bin/token?appid=appid")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, []string{"api.weixin.qq.com"}, hosts)
+
+	// the given http client is not modified
+	assert.Nil(t, httpClient.Transport)
+	assert.NotSame(t, httpClient, client.HTTPClient)
+}
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("appid", "secret", WithLogger(NopLogger{})).Get(context.Background(), server.URL)
+	assert.Error(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client := NewClient("appid", "secret", WithTLSConfig(&tls.Config{RootCAs: roots}), WithLogger(NopLogger{}))
+
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	// custom transports are kept
+	custom := &wrappedTransport{base: server.Client().Transport}
+	client = NewClient("appid", "secret", WithHTTPClient(&http.Client{Transport: custom}),
+		WithTLSConfig(&tls.Config{}), WithLogger(NopLogger{}))
+
+	resp, err = client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+}
+
+type wrappedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *wrappedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req)
+}
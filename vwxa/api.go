@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"io"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+// call returns the json call of the api url with the access token of the call options or the service,
+// the args follow the access token in the url.
+func (c *Service) call(name, apiURL string, opts []vwx.RequestOption, args ...any) *wxapi.Call {
+	return &wxapi.Call{
+		Client: c.client,
+		Name:   name,
+		URL:    apiURL,
+		Args:   args,
+		AccessToken: func(ctx context.Context) (string, error) {
+			return c.accessToken(ctx, apiURL, opts)
+		},
+		Error:        wechatError,
+		Options:      opts,
+		CaptureLimit: c.responseCaptureLimit,
+	}
+}
+
+// cancelOnClose cancels the context of a call once the response body is closed, so that the timeout
+// of the call options bounds the read of streamed responses.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the context of the call.
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
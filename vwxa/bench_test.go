@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func newBenchService(b *testing.B) *Service {
+	b.Helper()
+
+	vlog.SetOutput(io.Discard)
+	server := vwxmock.NewServer()
+	b.Cleanup(func() {
+		server.Close()
+		vlog.SetOutput(os.Stdout)
+	})
+
+	return NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+}
+
+func BenchmarkSendSubscribeMessage(b *testing.B) {
+	svc := newBenchService(b)
+	request := &SubscribeMessageRequest{
+		ToUser:     "openid",
+		TemplateID: "template",
+		Page:       "pages/index?a=1&b=2",
+		Data: map[string]*SubscribeMessageDataItem{
+			"thing1": {Value: "Hello World"},
+			"time2":  {Value: "2024-01-01 12:00:00"},
+		},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.SendSubscribeMessage(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMsgViolationCheck(b *testing.B) {
+	svc := newBenchService(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.MsgViolationCheck("hello world"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
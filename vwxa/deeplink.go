@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxSceneLength is the max length of the scene of unlimited QR codes.
+	MaxSceneLength = 32
+	// MaxURLLinkQueryLength is the max length of the query of URL links.
+	MaxURLLinkQueryLength = 1024
+	// MaxURLSchemeQueryLength is the max length of the query of URL schemes.
+	MaxURLSchemeQueryLength = 512
+
+	deepLinkTag = "deeplink"
+)
+
+var (
+	// ErrDeepLinkTooLong is returned when the encoded parameters exceed the limit of the target.
+	ErrDeepLinkTooLong = errors.New("deep link parameters too long")
+	// ErrDeepLinkInvalidChar is returned when the parameters contain characters the target does not accept.
+	ErrDeepLinkInvalidChar = errors.New("deep link parameters contain invalid characters")
+)
+
+// DeepLink is a mini program page with its parameters, encoded the same way for
+// QR code scenes, URL links and URL schemes so that the page always parses them alike.
+type DeepLink struct {
+	Path   string            // 小程序页面路径，不以/开头，不带参数
+	Params map[string]string // 页面参数
+}
+
+// NewDeepLink creates a deep link to the page with the params, which is either a
+// map[string]string or a struct (pointer) whose string, integer and bool fields are
+// encoded by the name of their `deeplink` tag, e.g. `deeplink:"id,omitempty"`.
+func NewDeepLink(path string, params any) (*DeepLink, error) {
+	path = strings.TrimPrefix(path, "/")
+	if strings.Contains(path, "?") {
+		return nil, fmt.Errorf("deep link path must not contain query: %s", path)
+	}
+
+	values, err := encodeDeepLinkParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeepLink{Path: path, Params: values}, nil
+}
+
+// Query returns the canonical query of the parameters: keys sorted, and characters
+// out of the set WeChat accepts percent-encoded.
+func (d *DeepLink) Query() string {
+	keys := make([]string, 0, len(d.Params))
+	for k := range d.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(escapeDeepLink(k))
+		b.WriteByte('=')
+		b.WriteString(escapeDeepLink(d.Params[k]))
+	}
+
+	return b.String()
+}
+
+// Scene returns the query as the scene of unlimited QR codes, which accepts at most
+// 32 characters and no percent-encoding.
+func (d *DeepLink) Scene() (string, error) {
+	scene := d.Query()
+
+	if strings.Contains(scene, "%") {
+		return "", fmt.Errorf("%w: %s", ErrDeepLinkInvalidChar, scene)
+	}
+
+	if len(scene) > MaxSceneLength {
+		return "", fmt.Errorf("%w: scene %d > %d", ErrDeepLinkTooLong, len(scene), MaxSceneLength)
+	}
+
+	return scene, nil
+}
+
+// URLLinkRequest returns the request generating a URL link to the deep link.
+func (d *DeepLink) URLLinkRequest() (*URLLinkRequest, error) {
+	query := d.Query()
+	if len(query) > MaxURLLinkQueryLength {
+		return nil, fmt.Errorf("%w: query %d > %d", ErrDeepLinkTooLong, len(query), MaxURLLinkQueryLength)
+	}
+
+	path := d.Path
+	return &URLLinkRequest{Path: &path, Query: &query}, nil
+}
+
+// URLSchemeRequest returns the request generating a URL scheme to the deep link.
+func (d *DeepLink) URLSchemeRequest() (*URLSchemeRequest, error) {
+	query := d.Query()
+	if len(query) > MaxURLSchemeQueryLength {
+		return nil, fmt.Errorf("%w: query %d > %d", ErrDeepLinkTooLong, len(query), MaxURLSchemeQueryLength)
+	}
+
+	return &URLSchemeRequest{JumpWxa: &JumpWxa{Path: d.Path, Query: query}}, nil
+}
+
+// ParseDeepLink parses the query or scene received by the mini program page into params,
+// a *map[string]string or a struct pointer tagged as for NewDeepLink. The scene passed
+// to the page is URL-encoded by WeChat, it is decoded here as well.
+func ParseDeepLink(raw string, params any) error {
+	if !strings.Contains(raw, "=") && strings.Contains(raw, "%") {
+		unescaped, err := url.QueryUnescape(raw)
+		if err != nil {
+			return fmt.Errorf("unescape scene error: %w", err)
+		}
+		raw = unescaped
+	}
+
+	values := make(map[string]string)
+	for _, pair := range strings.Split(raw, "&") {
+		if pair == "" {
+			continue
+		}
+
+		k, v, _ := strings.Cut(pair, "=")
+
+		key, err := url.QueryUnescape(k)
+		if err != nil {
+			return fmt.Errorf("unescape key error: %w", err)
+		}
+
+		value, err := url.QueryUnescape(v)
+		if err != nil {
+			return fmt.Errorf("unescape value of %s error: %w", key, err)
+		}
+
+		values[key] = value
+	}
+
+	return decodeDeepLinkParams(values, params)
+}
+
+// isDeepLinkChar reports whether the char is accepted by WeChat in scenes and queries,
+// the separators and the percent sign are left out as they must be encoded in values.
+func isDeepLinkChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$'()*,/:;?@-._~", c) >= 0
+}
+
+func escapeDeepLink(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isDeepLinkChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+// deepLinkField is a struct field bound to a parameter.
+type deepLinkField struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+func deepLinkFields(t reflect.Type) []deepLinkField {
+	fields := make([]deepLinkField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get(deepLinkTag)
+		if tag == "-" {
+			continue
+		}
+
+		name, opt, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, deepLinkField{name: name, index: i, omitEmpty: opt == "omitempty"})
+	}
+
+	return fields
+}
+
+func encodeDeepLinkParams(params any) (map[string]string, error) {
+	values := make(map[string]string)
+
+	switch p := params.(type) {
+	case nil:
+		return values, nil
+	case map[string]string:
+		for k, v := range p {
+			values[k] = v
+		}
+		return values, nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(params))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported deep link params type: %T", params)
+	}
+
+	for _, f := range deepLinkFields(v.Type()) {
+		fv := v.Field(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.String:
+			values[f.name] = fv.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			values[f.name] = strconv.FormatInt(fv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			values[f.name] = strconv.FormatUint(fv.Uint(), 10)
+		case reflect.Bool:
+			values[f.name] = strconv.FormatBool(fv.Bool())
+		default:
+			return nil, fmt.Errorf("unsupported deep link param type of %s: %s", f.name, fv.Kind())
+		}
+	}
+
+	return values, nil
+}
+
+func decodeDeepLinkParams(values map[string]string, params any) error {
+	if m, ok := params.(*map[string]string); ok {
+		*m = values
+		return nil
+	}
+
+	v := reflect.ValueOf(params)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported deep link params type: %T", params)
+	}
+	v = v.Elem()
+
+	for _, f := range deepLinkFields(v.Type()) {
+		s, ok := values[f.name]
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(f.index)
+
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(s)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("parse deep link param %s error: %w", f.name, err)
+			}
+			fv.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("parse deep link param %s error: %w", f.name, err)
+			}
+			fv.SetUint(n)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return fmt.Errorf("parse deep link param %s error: %w", f.name, err)
+			}
+			fv.SetBool(b)
+		default:
+			return fmt.Errorf("unsupported deep link param type of %s: %s", f.name, fv.Kind())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderDeepLink struct {
+	OrderID int64  `deeplink:"id"`
+	Source  string `deeplink:"src,omitempty"`
+	Share   bool   `deeplink:"s,omitempty"`
+}
+
+func TestDeepLink(t *testing.T) {
+	link, err := NewDeepLink("/pages/order/detail", &orderDeepLink{OrderID: 42, Source: "ad"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pages/order/detail", link.Path)
+	assert.Equal(t, "id=42&src=ad", link.Query())
+
+	scene, err := link.Scene()
+	assert.NoError(t, err)
+	assert.Equal(t, "id=42&src=ad", scene)
+
+	linkReq, err := link.URLLinkRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, "pages/order/detail", *linkReq.Path)
+	assert.Equal(t, scene, *linkReq.Query)
+
+	schemeReq, err := link.URLSchemeRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, scene, schemeReq.JumpWxa.Query)
+
+	var parsed orderDeepLink
+	assert.NoError(t, ParseDeepLink(scene, &parsed))
+	assert.Equal(t, orderDeepLink{OrderID: 42, Source: "ad"}, parsed)
+
+	// the scene passed to the page is url encoded
+	parsed = orderDeepLink{}
+	assert.NoError(t, ParseDeepLink(url.QueryEscape(scene), &parsed))
+	assert.Equal(t, int64(42), parsed.OrderID)
+
+	_, err = NewDeepLink("pages/index?a=1", nil)
+	assert.Error(t, err)
+}
+
+func TestDeepLinkEncoding(t *testing.T) {
+	link, err := NewDeepLink("pages/index", map[string]string{"q": "a b&c=d+中", "from": "x/y"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from=x/y&q=a%20b%26c%3Dd%2B%E4%B8%AD", link.Query())
+
+	_, err = link.Scene()
+	assert.ErrorIs(t, err, ErrDeepLinkInvalidChar)
+
+	var parsed map[string]string
+	assert.NoError(t, ParseDeepLink(link.Query(), &parsed))
+	assert.Equal(t, link.Params, parsed)
+
+	long, err := NewDeepLink("pages/index", map[string]string{"v": strings.Repeat("a", MaxURLSchemeQueryLength)})
+	assert.NoError(t, err)
+
+	_, err = long.Scene()
+	assert.ErrorIs(t, err, ErrDeepLinkTooLong)
+	_, err = long.URLSchemeRequest()
+	assert.ErrorIs(t, err, ErrDeepLinkTooLong)
+	_, err = long.URLLinkRequest()
+	assert.NoError(t, err)
+
+	_, err = NewDeepLink("pages/index", []string{"a"})
+	assert.Error(t, err)
+}
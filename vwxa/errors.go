@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	// ErrCodeDailyQuotaLimit is returned when the daily quota of the api is reached.
+	ErrCodeDailyQuotaLimit = vwx.ErrCodeDailyQuotaLimit
+	// ErrCodeMinuteQuotaLimit is returned when the per-minute quota of the api is reached.
+	ErrCodeMinuteQuotaLimit = vwx.ErrCodeMinuteQuotaLimit
+)
+
+// quotaLocation is the timezone where WeChat resets the daily quotas.
+var quotaLocation = time.FixedZone("CST", 8*3600)
+
+// now is replaced in tests.
+var now = time.Now
+
+// RateLimitedError is returned when an api hits the WeChat rate limits (45009/45011),
+// RetryAfter suggests how long to back off before the quota is reset. It wraps the *vwx.APIError.
+type RateLimitedError struct {
+	*vwx.APIError
+	RetryAfter time.Duration // 距离配额重置的建议等待时间，分钟配额为到下一分钟，日配额为到北京时间次日零点
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.APIError)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.APIError
+}
+
+// DefaultResponseCaptureLimit is the capture limit of WithResponseCapture if not positive, 4KB.
+const DefaultResponseCaptureLimit = 4 << 10
+
+// WithResponseCapture attaches the http status and the body of the response, truncated to limit
+// bytes, to the errors of the json apis: the *vwx.APIError of a non-zero errcode and the
+// *vwx.ResponseError of a malformed response. The raw response is part of the error message, so
+// intermittent failures can be diagnosed from the error logs without enabling debug logs.
+func WithResponseCapture(limit int) func(*Service) {
+	return func(s *Service) {
+		if limit <= 0 {
+			limit = DefaultResponseCaptureLimit
+		}
+
+		s.responseCaptureLimit = limit
+	}
+}
+
+// wechatError builds the *vwx.APIError of a failed call of the api url, rate limit errors are
+// returned as *RateLimitedError.
+func wechatError(apiURL string, errCode int, errMsg, requestID string) error {
+	apiErr := vwx.NewAPIError(apiURL, errCode, errMsg, requestID)
+
+	var retryAfter time.Duration
+
+	switch errCode {
+	case ErrCodeMinuteQuotaLimit:
+		t := now()
+		retryAfter = t.Truncate(time.Minute).Add(time.Minute).Sub(t)
+	case ErrCodeDailyQuotaLimit:
+		t := now().In(quotaLocation)
+		year, month, day := t.Date()
+		retryAfter = time.Date(year, month, day+1, 0, 0, 0, 0, quotaLocation).Sub(t)
+	default:
+		return apiErr
+	}
+
+	return &RateLimitedError{
+		APIError:   apiErr,
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestRateLimitedError(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time {
+		return time.Date(2024, 1, 1, 23, 59, 45, 0, quotaLocation)
+	}
+
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	server.SetError("/cgi-bin/message/subscribe/send", ErrCodeMinuteQuotaLimit, "api minute-quota reach limit")
+	_, err := svc.SendSubscribeMessageSimple("openid", "template", "", map[string]string{"thing1": "hi"})
+
+	var rateLimited *RateLimitedError
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, ErrCodeMinuteQuotaLimit, rateLimited.Code)
+	assert.Equal(t, 15*time.Second, rateLimited.RetryAfter)
+
+	server.SetError("/wxa/generate_urllink", ErrCodeDailyQuotaLimit, "reach max api daily quota limit")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, 15*time.Second, rateLimited.RetryAfter)
+
+	now = func() time.Time {
+		return time.Date(2024, 1, 1, 12, 0, 0, 0, quotaLocation)
+	}
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, 12*time.Hour, rateLimited.RetryAfter)
+
+	var apiErr *vwx.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, apiErr.IsRateLimited())
+
+	server.SetError("/wxa/generate_urllink", 40165, "invalid weapp pagepath rid: 64c1d2a3-1a2b3c4d-5e6f7a8b")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &rateLimited))
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 40165, apiErr.Code)
+	assert.Equal(t, "/wxa/generate_urllink", apiErr.Endpoint)
+	assert.Equal(t, "64c1d2a3-1a2b3c4d-5e6f7a8b", apiErr.RID)
+	assert.NotContains(t, err.Error(), "access_token")
+}
+
+func TestResponseCapture(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+
+	// nothing is captured by default
+	server.SetError("/wxa/generate_urllink", 40097, "invalid args")
+	_, err := NewService(client).GenerateSimpleURLLink("/pages/index", "")
+	apiErr, ok := vwx.AsAPIError(err)
+	assert.True(t, ok)
+	assert.Nil(t, apiErr.Response)
+
+	svc := NewService(client, WithResponseCapture(32))
+
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	apiErr, ok = vwx.AsAPIError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 200, apiErr.Response.StatusCode)
+	assert.Equal(t, `{"errcode":40097,"errmsg":"inval`, string(apiErr.Response.Body))
+	assert.True(t, apiErr.Response.Truncated)
+	assert.Contains(t, err.Error(), `body: "{\"errcode\":40097`)
+
+	// rate limit errors carry it too
+	server.SetError("/wxa/generate_urllink", ErrCodeDailyQuotaLimit, "quota")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	var rateLimited *RateLimitedError
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, `{"errcode":45009,"errmsg":"quota`, string(rateLimited.Response.Body))
+
+	// malformed responses
+	server.ClearError("/wxa/generate_urllink")
+	server.SetRawResponse("/wxa/generate_urllink", "application/json", []byte(`{"errcode":0,"url_link":`))
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	var respErr *vwx.ResponseError
+	assert.True(t, errors.As(err, &respErr))
+	assert.ErrorIs(t, err, vwx.ErrMalformedResponse)
+	assert.Equal(t, `{"errcode":0,"url_link":`, string(respErr.Response.Body))
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getFeedbackURL = "https://api.weixin.qq.com/wxaapi/feedback/list?access_token=%s&type=%d&page=%d&num=%d"
+
+	feedbackPageSize = 100
+)
+
+// FeedbackType is the type of user feedback.
+type FeedbackType int
+
+// 反馈类型，0 为全部类型
+const (
+	FeedbackAll         FeedbackType = 0 // 全部类型
+	FeedbackCannotOpen  FeedbackType = 1 // 无法打开小程序
+	FeedbackCrash       FeedbackType = 2 // 小程序闪退
+	FeedbackStuck       FeedbackType = 3 // 卡顿
+	FeedbackBlankScreen FeedbackType = 4 // 黑屏白屏
+	FeedbackDeadlock    FeedbackType = 5 // 死机
+	FeedbackUIError     FeedbackType = 6 // 界面错位
+	FeedbackSlowLoading FeedbackType = 7 // 界面加载慢
+	FeedbackOther       FeedbackType = 8 // 其他异常
+)
+
+// Feedback represents a piece of user feedback of the mini program.
+type Feedback struct {
+	RecordID   int64        `json:"record_id"`   // 反馈 id
+	CreateTime int64        `json:"create_time"` // 反馈时间，秒级时间戳
+	Content    string       `json:"content"`     // 反馈内容
+	Phone      string       `json:"phone"`       // 用户联系电话
+	OpenID     string       `json:"openid"`      // 用户 openid
+	Nickname   string       `json:"nickname"`    // 用户昵称
+	HeadURL    string       `json:"head_url"`    // 用户头像
+	Type       FeedbackType `json:"type"`        // 反馈类型
+	MediaIDs   []string     `json:"mediaIds"`    // 反馈图片的 media id
+	SystemInfo string       `json:"systemInfo"`  // 用户设备信息
+}
+
+// FeedbackResponse represents the response of getting the user feedback.
+type FeedbackResponse struct {
+	ErrCode  int         `json:"errcode"`
+	ErrMsg   string      `json:"errmsg"`
+	List     []*Feedback `json:"list"`      // 反馈列表
+	TotalNum int         `json:"total_num"` // 反馈总数
+}
+
+// GetFeedback retrieves a page of the user feedback of the type, pages start from 1.
+func (c *Service) GetFeedback(typ FeedbackType, page, num int, opts ...vwx.RequestOption) (*FeedbackResponse, error) {
+	return c.GetFeedbackContext(context.Background(), typ, page, num, opts...)
+}
+
+// GetFeedbackContext is GetFeedback with the given context.
+func (c *Service) GetFeedbackContext(ctx context.Context, typ FeedbackType, page, num int, opts ...vwx.RequestOption) (*FeedbackResponse, error) {
+	call := c.call("get feedback", getFeedbackURL, opts, typ, page, num)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[FeedbackResponse](ctx, call)
+}
+
+// Feedbacks iterates all user feedback of the type.
+func (c *Service) Feedbacks(typ FeedbackType, opts ...vwx.RequestOption) *Iterator[*Feedback] {
+	return c.FeedbacksContext(context.Background(), typ, opts...)
+}
+
+// FeedbacksContext is Feedbacks with the given context.
+func (c *Service) FeedbacksContext(ctx context.Context, typ FeedbackType, opts ...vwx.RequestOption) *Iterator[*Feedback] {
+	return newIterator(ctx, func(ctx context.Context, offset int) (*page[*Feedback], error) {
+		resp, err := c.GetFeedbackContext(ctx, typ, offset/feedbackPageSize+1, feedbackPageSize, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &page[*Feedback]{items: resp.List, total: resp.TotalNum}, nil
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	imgSecCheckURL = "https://api.weixin.qq.com/wxa/img_sec_check?access_token=%s"
+)
+
+// ImgSecCheckResponse represents the response of the synchronous image check.
+type ImgSecCheckResponse struct {
+	ErrCode int    `json:"errcode"` // 0 表示内容正常，87014 表示内容含有违法违规内容
+	ErrMsg  string `json:"errmsg"`
+}
+
+// ImgSecCheck checks synchronously whether the image read from r contains risky content,
+// the image is streamed to WeChat without buffering.
+// filename: file name with extension, e.g. avatar.png
+func (c *Service) ImgSecCheck(filename string, r io.Reader, opts ...vwx.RequestOption) (*ImgSecCheckResponse, error) {
+	return c.ImgSecCheckContext(context.Background(), filename, r, opts...)
+}
+
+// ImgSecCheckContext is ImgSecCheck with the given context.
+func (c *Service) ImgSecCheckContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*ImgSecCheckResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := c.accessToken(ctx, imgSecCheckURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("get access token error: %w", err)
+	}
+
+	url := fmt.Sprintf(imgSecCheckURL, accessToken)
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+	defer cancel()
+
+	var response ImgSecCheckResponse
+	if err := c.postMultipart(ctx, "img sec check", requestID, url, "media", filename, r, &response); err != nil {
+		return nil, err
+	}
+
+	// 87014 means risky content, not an error
+	if response.ErrCode != 0 && response.ErrCode != vwx.ErrCodeRiskyContent {
+		return &response, wechatError(imgSecCheckURL, response.ErrCode, response.ErrMsg, requestID)
+	}
+
+	return &response, nil
+}
+
+// IsImageSafe is a convenient method to check if the image is safe.
+func (c *Service) IsImageSafe(filename string, r io.Reader, opts ...vwx.RequestOption) (bool, error) {
+	return c.IsImageSafeContext(context.Background(), filename, r, opts...)
+}
+
+// IsImageSafeContext is IsImageSafe with the given context.
+func (c *Service) IsImageSafeContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (bool, error) {
+	response, err := c.ImgSecCheckContext(ctx, filename, r, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	return response.ErrCode == 0, nil
+}
@@ -0,0 +1,263 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxauth"
+)
+
+// QRCodeGenerator generates Mini Program QR codes.
+type QRCodeGenerator interface {
+	GenerateQRCode(scene, page string, opts ...vwx.RequestOption) ([]byte, error)
+	GenerateQRCodeContext(ctx context.Context, scene, page string, opts ...vwx.RequestOption) ([]byte, error)
+}
+
+// UnlimitedQRCodeGenerator generates Mini Program QR codes with the full options.
+type UnlimitedQRCodeGenerator interface {
+	GenerateUnlimitedQRCode(req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) ([]byte, error)
+	GenerateUnlimitedQRCodeContext(ctx context.Context, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) ([]byte, error)
+	GenerateQRCodeTo(w io.Writer, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) (int64, error)
+	GenerateQRCodeToContext(ctx context.Context, w io.Writer, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) (int64, error)
+}
+
+// WxaCodeGenerator generates the limited Mini Program QR codes of page paths.
+type WxaCodeGenerator interface {
+	GetWxaCode(path string, opts ...vwx.RequestOption) ([]byte, error)
+	GetWxaCodeContext(ctx context.Context, path string, opts ...vwx.RequestOption) ([]byte, error)
+	GenerateWxaCode(req *WxaCodeRequest, opts ...vwx.RequestOption) ([]byte, error)
+	GenerateWxaCodeContext(ctx context.Context, req *WxaCodeRequest, opts ...vwx.RequestOption) ([]byte, error)
+	CreateWxaQRCode(path string, width int, opts ...vwx.RequestOption) ([]byte, error)
+	CreateWxaQRCodeContext(ctx context.Context, path string, width int, opts ...vwx.RequestOption) ([]byte, error)
+}
+
+// SubscribeMessageSender sends subscribe messages.
+type SubscribeMessageSender interface {
+	SendSubscribeMessage(request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error)
+	SendSubscribeMessageContext(ctx context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error)
+	SendSubscribeMessageSimple(openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error)
+	SendSubscribeMessageSimpleContext(ctx context.Context, openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error)
+}
+
+// URLLinkGenerator generates Mini Program URL Links.
+type URLLinkGenerator interface {
+	GenerateURLLink(req *URLLinkRequest, opts ...vwx.RequestOption) (*URLLinkResponse, error)
+	GenerateURLLinkContext(ctx context.Context, req *URLLinkRequest, opts ...vwx.RequestOption) (*URLLinkResponse, error)
+	GenerateSimpleURLLink(path, query string, opts ...vwx.RequestOption) (string, error)
+	GenerateSimpleURLLinkContext(ctx context.Context, path, query string, opts ...vwx.RequestOption) (string, error)
+	GenerateExpirableURLLink(path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error)
+	GenerateExpirableURLLinkContext(ctx context.Context, path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error)
+	GenerateIntervalURLLink(path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error)
+	GenerateIntervalURLLinkContext(ctx context.Context, path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error)
+	QueryURLLink(urlLink string, opts ...vwx.RequestOption) (*URLLinkQueryResponse, error)
+	QueryURLLinkContext(ctx context.Context, urlLink string, opts ...vwx.RequestOption) (*URLLinkQueryResponse, error)
+}
+
+// ShortLinkGenerator generates Mini Program Short Links.
+type ShortLinkGenerator interface {
+	GenerateShortLink(pageURL, pageTitle string, isPermanent bool, opts ...vwx.RequestOption) (string, error)
+	GenerateShortLinkContext(ctx context.Context, pageURL, pageTitle string, isPermanent bool, opts ...vwx.RequestOption) (string, error)
+}
+
+// URLSchemeGenerator generates Mini Program URL Schemes.
+type URLSchemeGenerator interface {
+	GenerateURLScheme(req *URLSchemeRequest, opts ...vwx.RequestOption) (*URLSchemeResponse, error)
+	GenerateURLSchemeContext(ctx context.Context, req *URLSchemeRequest, opts ...vwx.RequestOption) (*URLSchemeResponse, error)
+	GenerateSimpleURLScheme(path, query string, opts ...vwx.RequestOption) (string, error)
+	GenerateSimpleURLSchemeContext(ctx context.Context, path, query string, opts ...vwx.RequestOption) (string, error)
+	GenerateExpirableURLScheme(path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error)
+	GenerateExpirableURLSchemeContext(ctx context.Context, path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error)
+	GenerateIntervalURLScheme(path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error)
+	GenerateIntervalURLSchemeContext(ctx context.Context, path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error)
+	QueryURLScheme(scheme string, opts ...vwx.RequestOption) (*URLSchemeQueryResponse, error)
+	QueryURLSchemeContext(ctx context.Context, scheme string, opts ...vwx.RequestOption) (*URLSchemeQueryResponse, error)
+}
+
+// MsgChecker checks whether text content is safe.
+type MsgChecker interface {
+	MsgViolationCheck(content string, opts ...vwx.RequestOption) (*MsgViolationCheckResponse, error)
+	MsgViolationCheckContext(ctx context.Context, content string, opts ...vwx.RequestOption) (*MsgViolationCheckResponse, error)
+	IsMsgContentSafe(content string, opts ...vwx.RequestOption) (bool, error)
+	IsMsgContentSafeContext(ctx context.Context, content string, opts ...vwx.RequestOption) (bool, error)
+	CheckMsgContent(req *MsgContentCheckRequest, opts ...vwx.RequestOption) (*MsgContentVerdict, error)
+	CheckMsgContentContext(ctx context.Context, req *MsgContentCheckRequest, opts ...vwx.RequestOption) (*MsgContentVerdict, error)
+}
+
+// MediaChecker checks asynchronously whether images/audio are safe.
+type MediaChecker interface {
+	MediaViolationCheckAsync(mediaURL string, mediaType, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	MediaViolationCheckAsyncContext(ctx context.Context, mediaURL string, mediaType, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	CheckImageAsync(imageURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	CheckImageAsyncContext(ctx context.Context, imageURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	CheckAudioAsync(audioURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	CheckAudioAsyncContext(ctx context.Context, audioURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error)
+	ParseMediaCheckCallback(callbackData []byte) (*MediaViolationCheckCallbackResult, error)
+	CheckMediaViolation(result *MediaViolationCheckCallbackResult) *MediaViolationInfo
+}
+
+// ContentChecker checks whether text and media content are safe.
+type ContentChecker interface {
+	MsgChecker
+	MediaChecker
+}
+
+// ImgChecker checks synchronously whether images are safe.
+type ImgChecker interface {
+	ImgSecCheck(filename string, r io.Reader, opts ...vwx.RequestOption) (*ImgSecCheckResponse, error)
+	ImgSecCheckContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*ImgSecCheckResponse, error)
+	IsImageSafe(filename string, r io.Reader, opts ...vwx.RequestOption) (bool, error)
+	IsImageSafeContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (bool, error)
+}
+
+// MediaUploader uploads temporary media.
+type MediaUploader interface {
+	UploadTempMedia(filename string, r io.Reader, opts ...vwx.RequestOption) (*UploadMediaResponse, error)
+	UploadTempMediaContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*UploadMediaResponse, error)
+}
+
+// MediaDownloader streams the binary bodies of QR codes and temporary media.
+type MediaDownloader interface {
+	GenerateQRCodeMedia(scene, page string, opts ...vwx.RequestOption) (*Media, error)
+	GenerateQRCodeMediaContext(ctx context.Context, scene, page string, opts ...vwx.RequestOption) (*Media, error)
+	GetTempMedia(mediaID string, opts ...vwx.RequestOption) (*Media, error)
+	GetTempMediaContext(ctx context.Context, mediaID string, opts ...vwx.RequestOption) (*Media, error)
+}
+
+// CloudFileManager moves files in and out of the cloud storage of the mini program.
+type CloudFileManager interface {
+	BatchDownloadCloudFiles(env string, files []*CloudFile, opts ...vwx.RequestOption) (*CloudFileDownloadResponse, error)
+	BatchDownloadCloudFilesContext(ctx context.Context, env string, files []*CloudFile, opts ...vwx.RequestOption) (*CloudFileDownloadResponse, error)
+	GetCloudFileDownloadURL(env, fileID string, maxAge time.Duration, opts ...vwx.RequestOption) (string, error)
+	GetCloudFileDownloadURLContext(ctx context.Context, env, fileID string, maxAge time.Duration, opts ...vwx.RequestOption) (string, error)
+	GetCloudFileUploadInfo(env, filePath string, opts ...vwx.RequestOption) (*CloudFileUploadResponse, error)
+	GetCloudFileUploadInfoContext(ctx context.Context, env, filePath string, opts ...vwx.RequestOption) (*CloudFileUploadResponse, error)
+	UploadCloudFile(env, filePath string, r io.Reader, opts ...vwx.RequestOption) (string, error)
+	UploadCloudFileContext(ctx context.Context, env, filePath string, r io.Reader, opts ...vwx.RequestOption) (string, error)
+}
+
+// OCRRecognizer recognizes cards and text in images.
+type OCRRecognizer interface {
+	OCRIDCard(mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRIDCardResponse, error)
+	OCRIDCardContext(ctx context.Context, mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRIDCardResponse, error)
+	OCRBankCard(mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRBankCardResponse, error)
+	OCRBankCardContext(ctx context.Context, mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRBankCardResponse, error)
+	OCRPrintedText(filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRPrintedTextResponse, error)
+	OCRPrintedTextContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRPrintedTextResponse, error)
+}
+
+// LiveReplayDownloader retrieves and archives the replays of live rooms.
+type LiveReplayDownloader interface {
+	GetLiveReplay(roomID, start, limit int, opts ...vwx.RequestOption) (*LiveReplayResponse, error)
+	GetLiveReplayContext(ctx context.Context, roomID, start, limit int, opts ...vwx.RequestOption) (*LiveReplayResponse, error)
+	LiveReplays(roomID int, opts ...vwx.RequestOption) *Iterator[*LiveReplay]
+	LiveReplaysContext(ctx context.Context, roomID int, opts ...vwx.RequestOption) *Iterator[*LiveReplay]
+	EachLiveReplay(roomID int, fn func(*LiveReplay) error, opts ...vwx.RequestOption) error
+	EachLiveReplayContext(ctx context.Context, roomID int, fn func(*LiveReplay) error, opts ...vwx.RequestOption) error
+	DownloadLiveReplays(roomID int, sink LiveReplaySink, opts ...vwx.RequestOption) error
+	DownloadLiveReplaysContext(ctx context.Context, roomID int, sink LiveReplaySink, opts ...vwx.RequestOption) error
+}
+
+// LiveRoomLister lists the live rooms.
+type LiveRoomLister interface {
+	GetLiveRooms(start, limit int, opts ...vwx.RequestOption) (*LiveRoomResponse, error)
+	GetLiveRoomsContext(ctx context.Context, start, limit int, opts ...vwx.RequestOption) (*LiveRoomResponse, error)
+	LiveRooms(opts ...vwx.RequestOption) *Iterator[*LiveRoom]
+	LiveRoomsContext(ctx context.Context, opts ...vwx.RequestOption) *Iterator[*LiveRoom]
+}
+
+// FeedbackLister lists the user feedback.
+type FeedbackLister interface {
+	GetFeedback(typ FeedbackType, page, num int, opts ...vwx.RequestOption) (*FeedbackResponse, error)
+	GetFeedbackContext(ctx context.Context, typ FeedbackType, page, num int, opts ...vwx.RequestOption) (*FeedbackResponse, error)
+	Feedbacks(typ FeedbackType, opts ...vwx.RequestOption) *Iterator[*Feedback]
+	FeedbacksContext(ctx context.Context, typ FeedbackType, opts ...vwx.RequestOption) *Iterator[*Feedback]
+}
+
+// PubTemplateLister lists the templates of the public template library.
+type PubTemplateLister interface {
+	GetPubTemplateTitles(categoryIDs []int, start, limit int, opts ...vwx.RequestOption) (*PubTemplateTitleResponse, error)
+	GetPubTemplateTitlesContext(ctx context.Context, categoryIDs []int, start, limit int, opts ...vwx.RequestOption) (*PubTemplateTitleResponse, error)
+	PubTemplateTitles(categoryIDs []int, opts ...vwx.RequestOption) *Iterator[*PubTemplateTitle]
+	PubTemplateTitlesContext(ctx context.Context, categoryIDs []int, opts ...vwx.RequestOption) *Iterator[*PubTemplateTitle]
+}
+
+// UserOnboarder logs new users in with their phone numbers and risk ranks.
+type UserOnboarder interface {
+	GetUserPhoneNumber(code string, opts ...vwx.RequestOption) (*vwxauth.PhoneInfo, error)
+	GetUserPhoneNumberContext(ctx context.Context, code string, opts ...vwx.RequestOption) (*vwxauth.PhoneInfo, error)
+	GetUserRiskRank(req *UserRiskRankRequest, opts ...vwx.RequestOption) (*UserRiskRankResponse, error)
+	GetUserRiskRankContext(ctx context.Context, req *UserRiskRankRequest, opts ...vwx.RequestOption) (*UserRiskRankResponse, error)
+	Login(req *LoginRequest, opts ...vwx.RequestOption) (*OnboardingResult, error)
+	LoginContext(ctx context.Context, req *LoginRequest, opts ...vwx.RequestOption) (*OnboardingResult, error)
+}
+
+// AccessTokenRefresher discards the cached access token and fetches a fresh one.
+type AccessTokenRefresher interface {
+	ForceRefreshAccessToken() (string, error)
+	ForceRefreshAccessTokenContext(ctx context.Context) (string, error)
+}
+
+// SecretFreeService is the restricted service of secret-free clients, all operations of which
+// use the access tokens supplied by the token source.
+type SecretFreeService interface {
+	QRCodeGenerator
+	UnlimitedQRCodeGenerator
+	WxaCodeGenerator
+	SubscribeMessageSender
+	URLLinkGenerator
+	URLSchemeGenerator
+	ShortLinkGenerator
+	ContentChecker
+	ImgChecker
+	MediaUploader
+	MediaDownloader
+	CloudFileManager
+	OCRRecognizer
+	LiveReplayDownloader
+}
+
+var (
+	_ QRCodeGenerator          = (*Service)(nil)
+	_ WxaCodeGenerator         = (*Service)(nil)
+	_ UnlimitedQRCodeGenerator = (*Service)(nil)
+	_ SubscribeMessageSender   = (*Service)(nil)
+	_ URLLinkGenerator         = (*Service)(nil)
+	_ URLSchemeGenerator       = (*Service)(nil)
+	_ ShortLinkGenerator       = (*Service)(nil)
+	_ MsgChecker               = (*Service)(nil)
+	_ MediaChecker             = (*Service)(nil)
+	_ ContentChecker           = (*Service)(nil)
+	_ ImgChecker               = (*Service)(nil)
+	_ MediaUploader            = (*Service)(nil)
+	_ MediaDownloader          = (*Service)(nil)
+	_ CloudFileManager         = (*Service)(nil)
+	_ OCRRecognizer            = (*Service)(nil)
+	_ LiveReplayDownloader     = (*Service)(nil)
+	_ LiveRoomLister           = (*Service)(nil)
+	_ FeedbackLister           = (*Service)(nil)
+	_ PubTemplateLister        = (*Service)(nil)
+	_ UserOnboarder            = (*Service)(nil)
+	_ AccessTokenRefresher     = (*Service)(nil)
+	_ SecretFreeService        = (*Service)(nil)
+
+	_ SubscribeMessageSender = (*TemplateValidator)(nil)
+)
@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"iter"
+)
+
+// page is a page of a list api: the items from the requested offset and the total count of the list.
+type page[T any] struct {
+	items []T
+	total int
+}
+
+// Iterator iterates the items of a list api page by page, the offsets and page numbers of the
+// requests are kept by the iterator. Next fetches the next page once the current one is consumed
+// and stops at the first error, which is returned by Err:
+//
+//	it := svc.LiveRooms()
+//	for it.Next() {
+//		room := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		// the iteration stopped at a failed page
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, offset int) (*page[T], error)
+
+	items  []T
+	index  int
+	offset int
+	last   bool
+	item   T
+	err    error
+}
+
+// newIterator returns an iterator fetching the pages from the offsets by fetch.
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, offset int) (*page[T], error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch}
+}
+
+// Next advances the iterator to the next item, it returns false when the list is exhausted,
+// a page fails or the context is done.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.index >= len(it.items) {
+		if it.last {
+			return false
+		}
+
+		p, err := it.fetch(it.ctx, it.offset)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items, it.index = p.items, 0
+		it.offset += len(p.items)
+		it.last = len(p.items) == 0 || it.offset >= p.total
+	}
+
+	it.item = it.items[it.index]
+	it.index++
+
+	return true
+}
+
+// Item returns the current item, valid after Next returned true.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error stopping the iteration, nil if the list is exhausted.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All returns the remaining items as a sequence for range loops, the error stopping the iteration
+// is yielded as the last element.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestIterator(t *testing.T) {
+	list := []int{1, 2, 3, 4, 5}
+
+	var offsets []int
+	fetch := func(_ context.Context, offset int) (*page[int], error) {
+		offsets = append(offsets, offset)
+		return &page[int]{items: list[offset:min(offset+2, len(list))], total: len(list)}, nil
+	}
+
+	var items []int
+	it := newIterator(context.Background(), fetch)
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, list, items)
+	assert.Equal(t, []int{0, 2, 4}, offsets)
+	assert.False(t, it.Next())
+
+	// a failed page stops the iteration
+	pageErr := errors.New("page error")
+	it = newIterator(context.Background(), func(ctx context.Context, offset int) (*page[int], error) {
+		if offset > 0 {
+			return nil, pageErr
+		}
+		return fetch(ctx, offset)
+	})
+	items = nil
+	for item, err := range it.All() {
+		if err != nil {
+			assert.ErrorIs(t, err, pageErr)
+			break
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2}, items)
+
+	// the iteration stops once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	it = newIterator(ctx, fetch)
+	assert.True(t, it.Next())
+	cancel()
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
+
+func TestListIterators(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	server.SetResponse("/wxa/business/getliveinfo", map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"total":   2,
+		"room_info": []map[string]any{
+			{"name": "room 1", "roomid": 1, "live_status": 101},
+			{"name": "room 2", "roomid": 2, "live_status": 103},
+		},
+	})
+
+	var rooms []int
+	it := svc.LiveRooms()
+	for it.Next() {
+		rooms = append(rooms, it.Item().RoomID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []int{1, 2}, rooms)
+
+	server.SetResponse("/wxaapi/feedback/list", map[string]any{
+		"errcode":   0,
+		"errmsg":    "ok",
+		"total_num": 1,
+		"list":      []map[string]any{{"record_id": 10, "content": "crash on start", "type": 2}},
+	})
+
+	feedbacks := svc.Feedbacks(FeedbackCrash)
+	assert.True(t, feedbacks.Next())
+	assert.Equal(t, "crash on start", feedbacks.Item().Content)
+	assert.False(t, feedbacks.Next())
+	assert.NoError(t, feedbacks.Err())
+
+	requests := server.Requests("/wxaapi/feedback/list")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "2", requests[0].Query.Get("type"))
+	assert.Equal(t, "1", requests[0].Query.Get("page"))
+
+	server.SetError("/wxaapi/newtmpl/getpubtemplatetitles", 40001, "invalid credential")
+	titles := svc.PubTemplateTitles([]int{2, 616})
+	assert.False(t, titles.Next())
+	assert.True(t, vwx.IsErrCode(titles.Err(), 40001))
+	assert.Equal(t, "2,616", server.Requests("/wxaapi/newtmpl/getpubtemplatetitles")[0].Query.Get("ids"))
+}
@@ -0,0 +1,286 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getLiveInfoURL = "https://api.weixin.qq.com/wxa/business/getliveinfo?access_token=%s"
+
+	liveReplayPageSize = 100
+	liveRoomPageSize   = 100
+)
+
+// LiveRoom represents a live room of the mini program.
+type LiveRoom struct {
+	Name       string `json:"name"`        // 直播间名称
+	RoomID     int    `json:"roomid"`      // 直播间 id
+	CoverImg   string `json:"cover_img"`   // 直播间背景图链接
+	ShareImg   string `json:"share_img"`   // 直播间分享图链接
+	LiveStatus int    `json:"live_status"` // 直播间状态，101 直播中，102 未开始，103 已结束，104 禁播，105 暂停，106 异常，107 已过期
+	StartTime  int64  `json:"start_time"`  // 直播计划开始时间，秒级时间戳
+	EndTime    int64  `json:"end_time"`    // 直播计划结束时间，秒级时间戳
+	AnchorName string `json:"anchor_name"` // 主播名
+	LiveType   int    `json:"live_type"`   // 直播类型，1 推流，0 手机直播
+}
+
+// LiveRoomResponse represents the response of getting the live rooms.
+type LiveRoomResponse struct {
+	ErrCode  int         `json:"errcode"`
+	ErrMsg   string      `json:"errmsg"`
+	RoomInfo []*LiveRoom `json:"room_info"` // 直播间列表
+	Total    int         `json:"total"`     // 直播间总数
+}
+
+// GetLiveRooms retrieves a page of the live rooms of the mini program.
+func (c *Service) GetLiveRooms(start, limit int, opts ...vwx.RequestOption) (*LiveRoomResponse, error) {
+	return c.GetLiveRoomsContext(context.Background(), start, limit, opts...)
+}
+
+// GetLiveRoomsContext is GetLiveRooms with the given context.
+func (c *Service) GetLiveRoomsContext(ctx context.Context, start, limit int, opts ...vwx.RequestOption) (*LiveRoomResponse, error) {
+	call := c.call("get live rooms", getLiveInfoURL, opts)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[LiveRoomResponse](ctx, call, map[string]any{
+		"start": start,
+		"limit": limit,
+	})
+}
+
+// LiveRooms iterates all live rooms of the mini program.
+func (c *Service) LiveRooms(opts ...vwx.RequestOption) *Iterator[*LiveRoom] {
+	return c.LiveRoomsContext(context.Background(), opts...)
+}
+
+// LiveRoomsContext is LiveRooms with the given context.
+func (c *Service) LiveRoomsContext(ctx context.Context, opts ...vwx.RequestOption) *Iterator[*LiveRoom] {
+	return newIterator(ctx, func(ctx context.Context, offset int) (*page[*LiveRoom], error) {
+		resp, err := c.GetLiveRoomsContext(ctx, offset, liveRoomPageSize, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &page[*LiveRoom]{items: resp.RoomInfo, total: resp.Total}, nil
+	})
+}
+
+// LiveReplay represents a replay segment of a live room.
+type LiveReplay struct {
+	ExpireTime string `json:"expire_time"` // 回放视频 url 过期时间
+	CreateTime string `json:"create_time"` // 回放视频创建时间
+	MediaURL   string `json:"media_url"`   // 回放视频链接
+}
+
+// LiveReplayResponse represents the response of getting the replays of a live room.
+type LiveReplayResponse struct {
+	ErrCode    int           `json:"errcode"`
+	ErrMsg     string        `json:"errmsg"`
+	LiveReplay []*LiveReplay `json:"live_replay"` // 回放视频片段
+	Total      int           `json:"total"`       // 回放视频片段总数
+}
+
+// GetLiveReplay retrieves a page of the replay segments of the live room.
+func (c *Service) GetLiveReplay(roomID, start, limit int, opts ...vwx.RequestOption) (*LiveReplayResponse, error) {
+	return c.GetLiveReplayContext(context.Background(), roomID, start, limit, opts...)
+}
+
+// GetLiveReplayContext is GetLiveReplay with the given context.
+func (c *Service) GetLiveReplayContext(ctx context.Context, roomID, start, limit int, opts ...vwx.RequestOption) (*LiveReplayResponse, error) {
+	call := c.call("get live replay", getLiveInfoURL, opts)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[LiveReplayResponse](ctx, call, map[string]any{
+		"action":  "get_replay",
+		"room_id": roomID,
+		"start":   start,
+		"limit":   limit,
+	})
+}
+
+// LiveReplays iterates all replay segments of the live room.
+func (c *Service) LiveReplays(roomID int, opts ...vwx.RequestOption) *Iterator[*LiveReplay] {
+	return c.LiveReplaysContext(context.Background(), roomID, opts...)
+}
+
+// LiveReplaysContext is LiveReplays with the given context.
+func (c *Service) LiveReplaysContext(ctx context.Context, roomID int, opts ...vwx.RequestOption) *Iterator[*LiveReplay] {
+	return newIterator(ctx, func(ctx context.Context, offset int) (*page[*LiveReplay], error) {
+		resp, err := c.GetLiveReplayContext(ctx, roomID, offset, liveReplayPageSize, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &page[*LiveReplay]{items: resp.LiveReplay, total: resp.Total}, nil
+	})
+}
+
+// EachLiveReplay iterates all replay segments of the live room page by page,
+// the iteration stops at the first error returned by fn.
+func (c *Service) EachLiveReplay(roomID int, fn func(*LiveReplay) error, opts ...vwx.RequestOption) error {
+	return c.EachLiveReplayContext(context.Background(), roomID, fn, opts...)
+}
+
+// EachLiveReplayContext is EachLiveReplay with the given context.
+func (c *Service) EachLiveReplayContext(ctx context.Context, roomID int, fn func(*LiveReplay) error, opts ...vwx.RequestOption) error {
+	it := c.LiveReplaysContext(ctx, roomID, opts...)
+	for it.Next() {
+		if err := fn(it.Item()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// LiveReplaySink stores the downloaded replay media, e.g. to files or object storage.
+type LiveReplaySink interface {
+	// Offset returns the bytes already stored for the replay, used to resume partial downloads.
+	Offset(replay *LiveReplay) (int64, error)
+	// Writer returns the writer storing the replay media from the offset.
+	Writer(replay *LiveReplay, offset int64) (io.WriteCloser, error)
+}
+
+// DownloadLiveReplays streams the media of all replay segments of the live room to the sink,
+// partially downloaded segments are resumed with http range requests when supported.
+func (c *Service) DownloadLiveReplays(roomID int, sink LiveReplaySink, opts ...vwx.RequestOption) error {
+	return c.DownloadLiveReplaysContext(context.Background(), roomID, sink, opts...)
+}
+
+// DownloadLiveReplaysContext is DownloadLiveReplays with the given context.
+func (c *Service) DownloadLiveReplaysContext(ctx context.Context, roomID int, sink LiveReplaySink, opts ...vwx.RequestOption) error {
+	return c.EachLiveReplayContext(ctx, roomID, func(replay *LiveReplay) error {
+		if err := c.downloadLiveReplay(ctx, replay, sink); err != nil {
+			return fmt.Errorf("download live replay %s error: %w", replay.MediaURL, err)
+		}
+		return nil
+	}, opts...)
+}
+
+func (c *Service) downloadLiveReplay(ctx context.Context, replay *LiveReplay, sink LiveReplaySink) error {
+	offset, err := sink.Offset(replay)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, replay.MediaURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusRequestedRangeNotSatisfiable:
+		// already downloaded completely
+		return nil
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// range not supported, download from the beginning
+		offset = 0
+	default:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	c.client.Logger.Infof("download live replay | url: %s | offset: %d", replay.MediaURL, offset)
+
+	w, err := sink.Writer(replay, offset)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// FileLiveReplaySink stores the replay media as files in a directory, the file name is
+// derived from the media url so that repeated downloads resume the same file.
+type FileLiveReplaySink struct {
+	Dir string
+}
+
+// Path returns the file path of the replay.
+func (s *FileLiveReplaySink) Path(replay *LiveReplay) string {
+	name := replay.MediaURL
+	ext := ".mp4"
+	if u, err := url.Parse(replay.MediaURL); err == nil {
+		name = u.Host + u.Path
+		if e := path.Ext(u.Path); e != "" {
+			ext = e
+		}
+	}
+
+	sum := sha1.Sum([]byte(name))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:8])+ext)
+}
+
+// Offset implements LiveReplaySink.
+func (s *FileLiveReplaySink) Offset(replay *LiveReplay) (int64, error) {
+	info, err := os.Stat(s.Path(replay))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Writer implements LiveReplaySink.
+func (s *FileLiveReplaySink) Writer(replay *LiveReplay, offset int64) (io.WriteCloser, error) {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	return os.OpenFile(s.Path(replay), flag, 0o644)
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestDownloadLiveReplays(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	first := bytes.Repeat([]byte("a"), 1000)
+	second := bytes.Repeat([]byte("b"), 2000)
+	server.SetRawResponse("/replay/1.mp4", "video/mp4", first)
+	server.SetRawResponse("/replay/2.mp4", "video/mp4", second)
+	server.SetResponse("/wxa/business/getliveinfo", map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"total":   2,
+		"live_replay": []map[string]any{
+			{"create_time": "2024-01-01T10:00:00+08:00", "media_url": "https://vod.example.com/replay/1.mp4"},
+			{"create_time": "2024-01-01T10:30:00+08:00", "media_url": "https://vod.example.com/replay/2.mp4"},
+		},
+	})
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	sink := &FileLiveReplaySink{Dir: t.TempDir()}
+
+	// the second replay is partially downloaded
+	secondReplay := &LiveReplay{MediaURL: "https://vod.example.com/replay/2.mp4"}
+	assert.NoError(t, os.WriteFile(sink.Path(secondReplay), second[:500], 0o644))
+
+	assert.NoError(t, svc.DownloadLiveReplays(1, sink))
+
+	data, err := os.ReadFile(sink.Path(&LiveReplay{MediaURL: "https://vod.example.com/replay/1.mp4"}))
+	assert.NoError(t, err)
+	assert.Equal(t, first, data)
+
+	data, err = os.ReadFile(sink.Path(secondReplay))
+	assert.NoError(t, err)
+	assert.Equal(t, second, data)
+
+	// downloading again keeps the complete files
+	assert.NoError(t, svc.DownloadLiveReplays(1, sink))
+	data, _ = os.ReadFile(sink.Path(secondReplay))
+	assert.Equal(t, second, data)
+	assert.Len(t, server.Requests("/wxa/business/getliveinfo"), 2)
+
+	server.SetError("/wxa/business/getliveinfo", 300001, "room not found")
+	assert.Error(t, svc.DownloadLiveReplays(1, sink))
+}
@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	getTempMediaURL = "https://api.weixin.qq.com/cgi-bin/media/get?access_token=%s&media_id=%s"
+
+	// sniffLen is the number of leading bytes used to detect the content type, see http.DetectContentType.
+	sniffLen = 512
+)
+
+// ErrNotMedia is returned when a binary endpoint responds json without errcode.
+var ErrNotMedia = errors.New("response is not media")
+
+// Media is the binary body of a WeChat API (e.g. QR code, temporary media) streamed from the
+// response, the caller must Close it. Error responses in json are detected before the Media is
+// returned, so the body can be passed through to a http.ResponseWriter without buffering.
+type Media struct {
+	ContentType   string // 内容类型，响应未声明或为通用类型时根据内容检测
+	ContentLength int64  // 内容长度，未知时为-1
+	Filename      string // 文件名，取自Content-Disposition，可能为空
+
+	reader *bufio.Reader
+	body   io.Closer
+}
+
+// Read reads the body of the media.
+func (m *Media) Read(p []byte) (int, error) {
+	return m.reader.Read(p)
+}
+
+// WriteTo writes the body of the media to w.
+func (m *Media) WriteTo(w io.Writer) (int64, error) {
+	return m.reader.WriteTo(w)
+}
+
+// Close closes the body of the media.
+func (m *Media) Close() error {
+	return m.body.Close()
+}
+
+// WriteResponse writes the media to the response writer with its content type, length and file
+// name, then closes the media.
+func (m *Media) WriteResponse(w http.ResponseWriter) error {
+	defer m.body.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", m.ContentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	if m.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(m.ContentLength, 10))
+	}
+
+	if m.Filename != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": m.Filename}))
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	_, err := m.WriteTo(w)
+
+	return err
+}
+
+// openMedia opens the media of the response of the api, json error responses are returned as the
+// WeChat error. The response body is closed on errors.
+func openMedia(resp *http.Response, apiURL, requestID string) (*Media, error) {
+	reader := bufio.NewReaderSize(resp.Body, sniffLen)
+
+	head, err := reader.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") || strings.HasPrefix(contentType, "text/plain") {
+		contentType = http.DetectContentType(head)
+	}
+
+	// errors are answered in json, sometimes declared as the media type
+	trimmed := bytes.TrimSpace(head)
+	if strings.Contains(contentType, "json") ||
+		(strings.HasPrefix(contentType, "text/plain") && bytes.HasPrefix(trimmed, []byte("{"))) ||
+		bytes.HasPrefix(trimmed, []byte(`{"errcode"`)) {
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("read response error: %w", err)
+		}
+
+		var result struct {
+			ErrCode int    `json:"errcode"`
+			ErrMsg  string `json:"errmsg"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil || result.ErrCode == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotMedia, body)
+		}
+
+		return nil, wechatError(apiURL, result.ErrCode, result.ErrMsg, requestID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	media := &Media{
+		ContentType:   contentType,
+		ContentLength: resp.ContentLength,
+		reader:        reader,
+		body:          resp.Body,
+	}
+
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		media.Filename = params["filename"]
+	}
+
+	return media, nil
+}
+
+// openLimitedMedia opens the media of the response of the api like openMedia, limiting the media and the
+// json error responses to the max response size of the client.
+func (c *Service) openLimitedMedia(resp *http.Response, apiURL, requestID string) (*Media, error) {
+	limited := *resp
+	limited.Body = struct {
+		io.Reader
+		io.Closer
+	}{c.client.LimitResponse(resp), resp.Body}
+
+	return openMedia(&limited, apiURL, requestID)
+}
+
+// GenerateQRCodeMedia generates the QR code of the scene and page like GenerateQRCode, streaming
+// the image instead of reading it into memory, the caller must Close the media. Responses other than
+// images or larger than the max response size of the client are errors.
+func (c *Service) GenerateQRCodeMedia(scene, page string, opts ...vwx.RequestOption) (*Media, error) {
+	return c.GenerateQRCodeMediaContext(context.Background(), scene, page, opts...)
+}
+
+// GenerateQRCodeMediaContext is GenerateQRCodeMedia with the given context.
+func (c *Service) GenerateQRCodeMediaContext(ctx context.Context, scene, page string, opts ...vwx.RequestOption) (*Media, error) {
+	resp, requestID, err := c.postQRCode(ctx, &QRCodeUnlimitedRequest{Scene: scene, Page: page}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.openImage(resp, generateCodeUnlimitURL, requestID)
+}
+
+// GetTempMedia downloads the temporary media of the media id, e.g. images sent by users to the
+// customer service, the caller must Close the media. Media larger than the max response size of the
+// client fail with vwx.ErrResponseTooLarge.
+func (c *Service) GetTempMedia(mediaID string, opts ...vwx.RequestOption) (*Media, error) {
+	return c.GetTempMediaContext(context.Background(), mediaID, opts...)
+}
+
+// GetTempMediaContext is GetTempMedia with the given context.
+func (c *Service) GetTempMediaContext(ctx context.Context, mediaID string, opts ...vwx.RequestOption) (*Media, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := c.accessToken(ctx, getTempMediaURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("get access token error: %w", err)
+	}
+
+	c.client.Logger.Infof("get temp media | request_id: %s | media_id: %s", requestID, mediaID)
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+
+	resp, err := c.client.Get(vwx.Idempotent(ctx), fmt.Sprintf(getTempMediaURL, accessToken, url.QueryEscape(mediaID)))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("send request error: %w", err)
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return c.openLimitedMedia(resp, getTempMediaURL, requestID)
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestOpenMedia(t *testing.T) {
+	response := func(contentType, body string) *http.Response {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": []string{contentType}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}
+	}
+
+	// the content type of generic binary responses is detected
+	media, err := openMedia(response("application/octet-stream", "\x89PNG\r\n\x1a\nimage"), generateCodeUnlimitURL, "rid")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", media.ContentType)
+	assert.Equal(t, int64(13), media.ContentLength)
+
+	body, err := io.ReadAll(media)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x89PNG\r\n\x1a\nimage", string(body))
+
+	// large bodies are streamed beyond the sniffed bytes
+	large := "\xff\xd8\xff" + strings.Repeat("x", 4096)
+	media, err = openMedia(response("", large), generateCodeUnlimitURL, "rid")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", media.ContentType)
+
+	var sb strings.Builder
+	n, err := media.WriteTo(&sb)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(large)), n)
+	assert.Equal(t, large, sb.String())
+
+	// json errors declared as text are detected
+	_, err = openMedia(response("text/plain", `{"errcode":45009,"errmsg":"quota"}`), generateCodeUnlimitURL, "rid")
+	var limited *RateLimitedError
+	assert.ErrorAs(t, err, &limited)
+
+	_, err = openMedia(response("application/json", `{"errcode":0}`), generateCodeUnlimitURL, "rid")
+	assert.ErrorIs(t, err, ErrNotMedia)
+}
+
+func TestMediaProxy(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	upload, err := svc.UploadTempMedia("a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+
+	media, err := svc.GetTempMedia(upload.MediaID)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", media.ContentType)
+	assert.Equal(t, upload.MediaID+".png", media.Filename)
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, media.WriteResponse(w))
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, vwxmock.PNGHeader, w.Body.Bytes())
+
+	// json errors are returned before anything is passed through
+	_, err = svc.GetTempMedia("unknown")
+	var apiErr *vwx.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 40007, apiErr.Code)
+
+	media, err = svc.GenerateQRCodeMedia("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", media.ContentType)
+	code, err := io.ReadAll(media)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, code)
+	assert.NoError(t, media.Close())
+
+	server.SetError("/wxa/getwxacodeunlimit", 40097, "invalid args")
+	_, err = svc.GenerateQRCodeMedia("scene", "pages/index")
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 40097, apiErr.Code)
+
+	// the media and the json errors are limited to the max response size
+	limitedSvc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithMaxResponseSize(4)))
+
+	_, err = limitedSvc.GetTempMedia(upload.MediaID)
+	assert.ErrorIs(t, err, vwx.ErrResponseTooLarge)
+
+	_, err = limitedSvc.GenerateQRCodeMedia("scene", "pages/index")
+	assert.ErrorIs(t, err, vwx.ErrResponseTooLarge)
+
+	server.SetError("/cgi-bin/media/get", 40007, "invalid media_id")
+	_, err = limitedSvc.GetTempMedia(upload.MediaID)
+	assert.ErrorIs(t, err, vwx.ErrResponseTooLarge)
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	uploadTempMediaURL = "https://api.weixin.qq.com/cgi-bin/media/upload?access_token=%s&type=image"
+)
+
+// UploadMediaResponse represents the response from uploading temporary media.
+type UploadMediaResponse struct {
+	Type      string `json:"type"`       // 文件类型
+	MediaID   string `json:"media_id"`   // 媒体文件上传后，获取标识，3天内有效
+	CreatedAt int64  `json:"created_at"` // 媒体文件上传时间戳
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+}
+
+// UploadTempMedia uploads the image read from r as temporary media for customer service
+// messages, the image is streamed to WeChat without buffering.
+// filename: file name with extension, e.g. card.jpg
+func (c *Service) UploadTempMedia(filename string, r io.Reader, opts ...vwx.RequestOption) (*UploadMediaResponse, error) {
+	return c.UploadTempMediaContext(context.Background(), filename, r, opts...)
+}
+
+// UploadTempMediaContext is UploadTempMedia with the given context.
+func (c *Service) UploadTempMediaContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*UploadMediaResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := c.accessToken(ctx, uploadTempMediaURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("get access token error: %w", err)
+	}
+
+	url := fmt.Sprintf(uploadTempMediaURL, accessToken)
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+	defer cancel()
+
+	var response UploadMediaResponse
+	if err := c.postMultipart(ctx, "upload temp media", requestID, url, "media", filename, r, &response); err != nil {
+		return nil, err
+	}
+
+	if response.ErrCode != 0 {
+		return &response, wechatError(uploadTempMediaURL, response.ErrCode, response.ErrMsg, requestID)
+	}
+
+	return &response, nil
+}
@@ -19,17 +19,22 @@ package vwxa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
+	"encoding/xml"
 	"fmt"
-	"io"
-	"net/http"
+	"time"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+	"github.com/vogo/vwx/vwxpush"
 )
 
 const (
 	mediaCheckAsyncURL = "https://api.weixin.qq.com/wxa/media_check_async?access_token=%s"
+
+	// MediaCheckEvent is the event of the pushes of the media check results.
+	MediaCheckEvent = "wxa_media_check"
 )
 
 const (
@@ -64,32 +69,33 @@ type MediaViolationCheckAsyncResponse struct {
 
 // MediaViolationCheckCallbackResult represents the callback result data structure for asynchronous detection.
 type MediaViolationCheckCallbackResult struct {
-	ToUserName   string                             `json:"ToUserName"`   // 小程序的username
-	FromUserName string                             `json:"FromUserName"` // 平台推送服务UserName
-	CreateTime   int64                              `json:"CreateTime"`   // 发送时间
-	MsgType      string                             `json:"MsgType"`      // 默认为：event
-	Event        string                             `json:"Event"`        // 默认为：wxa_media_check
-	AppID        string                             `json:"appid"`        // 小程序的appid
-	TraceID      string                             `json:"trace_id"`     // 任务id
-	Version      int                                `json:"version"`      // 可用于区分接口版本
-	ErrCode      int                                `json:"errcode"`      // 错误码，仅当该值为0时，结果有效
-	Result       *MediaViolationCheckResult         `json:"result"`       // 综合结果
-	Detail       []*MediaViolationCheckDetailResult `json:"detail"`       // 详细检测结果
+	ToUserName   string                             `json:"ToUserName" xml:"ToUserName"`     // 小程序的username
+	FromUserName string                             `json:"FromUserName" xml:"FromUserName"` // 平台推送服务UserName
+	CreateTime   int64                              `json:"CreateTime" xml:"CreateTime"`     // 发送时间
+	MsgType      string                             `json:"MsgType" xml:"MsgType"`           // 默认为：event
+	Event        string                             `json:"Event" xml:"Event"`               // 默认为：wxa_media_check
+	AppID        string                             `json:"appid" xml:"appid"`               // 小程序的appid
+	TraceID      string                             `json:"trace_id" xml:"trace_id"`         // 任务id
+	Version      int                                `json:"version" xml:"version"`           // 可用于区分接口版本
+	ErrCode      int                                `json:"errcode" xml:"errcode"`           // 错误码，仅当该值为0时，结果有效
+	ErrMsg       string                             `json:"errmsg" xml:"errmsg"`             // 错误信息
+	Result       *MediaViolationCheckResult         `json:"result" xml:"result"`             // 综合结果
+	Detail       []*MediaViolationCheckDetailResult `json:"detail" xml:"detail"`             // 详细检测结果
 }
 
 // MediaViolationCheckResult represents the comprehensive detection result.
 type MediaViolationCheckResult struct {
-	Suggest string `json:"suggest"` // 建议，有risky、pass、review三种值
-	Label   int    `json:"label"`   // 命中标签枚举值，100 正常；20001 时政；20002 色情；20006 违法犯罪；21000 其他
+	Suggest string `json:"suggest" xml:"suggest"` // 建议，有risky、pass、review三种值
+	Label   int    `json:"label" xml:"label"`     // 命中标签枚举值，100 正常；20001 时政；20002 色情；20006 违法犯罪；21000 其他
 }
 
 // MediaViolationCheckDetailResult represents the detailed detection result.
 type MediaViolationCheckDetailResult struct {
-	Strategy string `json:"strategy"` // 策略类型
-	ErrCode  int    `json:"errcode"`  // 错误码，仅当该值为0时，该项结果有效
-	Suggest  string `json:"suggest"`  // 建议，有risky、pass、review三种值
-	Label    int    `json:"label"`    // 命中标签枚举值，100 正常；20001 时政；20002 色情；20006 违法犯罪；21000 其他
-	Prob     int    `json:"prob"`     // 0-100，代表置信度，越高代表越有可能属于当前返回的标签（label）
+	Strategy string `json:"strategy" xml:"strategy"` // 策略类型
+	ErrCode  int    `json:"errcode" xml:"errcode"`   // 错误码，仅当该值为0时，该项结果有效
+	Suggest  string `json:"suggest" xml:"suggest"`   // 建议，有risky、pass、review三种值
+	Label    int    `json:"label" xml:"label"`       // 命中标签枚举值，100 正常；20001 时政；20002 色情；20006 违法犯罪；21000 其他
+	Prob     int    `json:"prob" xml:"prob"`         // 0-100，代表置信度，越高代表越有可能属于当前返回的标签（label）
 }
 
 // MediaViolationInfo represents information about content violation.
@@ -106,14 +112,12 @@ type MediaViolationInfo struct {
 // scene: Scene enumeration value (1 profile, 2 comment, 3 forum, 4 social log)
 // openID: User's openid (user must have accessed the mini program within the last two hours)
 // Rate limit: single appId call limit is 2000 times/minute, 200,000 times/day; file size limit: single file size not exceeding 10M
-func (c *Service) MediaViolationCheckAsync(mediaURL string, mediaType, scene int, openID string) (*MediaViolationCheckAsyncResponse, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
-	if err != nil {
-		return nil, fmt.Errorf("get access token error: %v", err)
-	}
-
-	url := fmt.Sprintf(mediaCheckAsyncURL, accessToken)
+func (c *Service) MediaViolationCheckAsync(mediaURL string, mediaType, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return c.MediaViolationCheckAsyncContext(context.Background(), mediaURL, mediaType, scene, openID, opts...)
+}
 
+// MediaViolationCheckAsyncContext is MediaViolationCheckAsync with the given context.
+func (c *Service) MediaViolationCheckAsyncContext(ctx context.Context, mediaURL string, mediaType, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
 	request := &MediaViolationCheckAsyncRequest{
 		MediaURL:  mediaURL,
 		MediaType: mediaType,
@@ -122,54 +126,112 @@ func (c *Service) MediaViolationCheckAsync(mediaURL string, mediaType, scene int
 		OpenID:    openID,
 	}
 
-	data, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request error: %v", err)
+	return wxapi.PostJSON[MediaViolationCheckAsyncResponse](ctx, c.call("media check async", mediaCheckAsyncURL, opts), request)
+}
+
+// ParseMediaCheckCallback parses the asynchronous callback result of multimedia content security detection,
+// the format (json or xml) is detected from the data.
+func (c *Service) ParseMediaCheckCallback(callbackData []byte) (*MediaViolationCheckCallbackResult, error) {
+	return UnmarshalMediaCheckCallback("", callbackData)
+}
+
+// UnmarshalMediaCheckCallback parses the media check callback in the data type configured for
+// the push receiver (json or xml), the format is detected from the data if dataType is empty.
+func UnmarshalMediaCheckCallback(dataType string, data []byte) (*MediaViolationCheckCallbackResult, error) {
+	if dataType == "" {
+		dataType = "json"
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+			dataType = "xml"
+		}
 	}
 
-	vlog.Infof("media check async | req: %s", string(data))
+	var result MediaViolationCheckCallbackResult
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	var err error
+	if dataType == "xml" {
+		err = xml.Unmarshal(data, &result)
+	} else {
+		err = json.Unmarshal(data, &result)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("send request error: %v", err)
+		return nil, fmt.Errorf("unmarshal callback data error: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
+
+	return &result, nil
+}
+
+// MediaCheckEventHandler adapts the handler of normalized events to the handler of the media check
+// pushes, the payload of the events is the *MediaViolationCheckCallbackResult. Register it on the
+// dispatcher for the MediaCheckEvent.
+func MediaCheckEventHandler(handler vwx.EventHandler) vwxpush.MessageHandler {
+	return func(appID string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+		result, err := UnmarshalMediaCheckCallback("", data)
+		if err != nil {
+			return nil, err
 		}
-	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response error: %v", err)
+		if result.AppID != "" {
+			appID = result.AppID
+		}
+
+		return nil, handler(baseInfo.Context(context.Background()), &vwx.Event{
+			Source:     vwx.EventSourceMediaCheck,
+			AppID:      appID,
+			Kind:       MediaCheckEvent,
+			CreateTime: time.Unix(result.CreateTime, 0),
+			RequestID:  baseInfo.RequestID,
+			Payload:    result,
+			Raw:        data,
+		})
 	}
+}
+
+// CheckMediaViolation determines whether multimedia content violates regulations and returns violation description.
+// The decision is emitted to the moderation sink if set.
+func (c *Service) CheckMediaViolation(result *MediaViolationCheckCallbackResult) *MediaViolationInfo {
+	violationInfo := c.mediaViolationInfo(result)
 
-	vlog.Infof("media check async | resp: %s", string(body))
+	c.emitModerationRecord(&ModerationRecord{
+		Kind:     ModerationKindMedia,
+		TraceID:  result.TraceID,
+		Labels:   mediaViolationLabels(result),
+		Suggest:  violationInfo.Suggest,
+		Decision: moderationDecision(violationInfo),
+		Reason:   violationInfo.Reason,
+	})
 
-	var response MediaViolationCheckAsyncResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("unmarshal response error: %v", err)
+	return violationInfo
+}
+
+// mediaViolationLabels returns the labels hit by the comprehensive and the not passed detail results.
+func mediaViolationLabels(result *MediaViolationCheckCallbackResult) []int {
+	var labels []int
+
+	if result.Result != nil {
+		labels = appendLabel(labels, result.Result.Label)
 	}
 
-	if response.ErrCode != 0 {
-		return &response, errors.New(response.ErrMsg)
+	for _, detail := range result.Detail {
+		if detail.ErrCode == 0 && detail.Suggest != ViolationSuggestPass {
+			labels = appendLabel(labels, detail.Label)
+		}
 	}
 
-	return &response, nil
+	return labels
 }
 
-// ParseMediaCheckCallback parses the asynchronous callback result of multimedia content security detection.
-func (c *Service) ParseMediaCheckCallback(callbackData []byte) (*MediaViolationCheckCallbackResult, error) {
-	var result MediaViolationCheckCallbackResult
-	if err := json.Unmarshal(callbackData, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal callback data error: %v", err)
+// appendLabel appends the label if not in the labels yet.
+func appendLabel(labels []int, label int) []int {
+	for _, l := range labels {
+		if l == label {
+			return labels
+		}
 	}
 
-	return &result, nil
+	return append(labels, label)
 }
 
-// CheckMediaViolation determines whether multimedia content violates regulations and returns violation description.
-func (c *Service) CheckMediaViolation(result *MediaViolationCheckCallbackResult) *MediaViolationInfo {
+func (c *Service) mediaViolationInfo(result *MediaViolationCheckCallbackResult) *MediaViolationInfo {
 	violationInfo := &MediaViolationInfo{
 		IsViolation: false,
 		Reason:      "内容正常",
@@ -237,11 +299,21 @@ func (c *Service) getLabelDescription(label int) string {
 }
 
 // CheckImageAsync is a convenient method for asynchronous image content security detection.
-func (c *Service) CheckImageAsync(imageURL string, scene int, openID string) (*MediaViolationCheckAsyncResponse, error) {
-	return c.MediaViolationCheckAsync(imageURL, 2, scene, openID)
+func (c *Service) CheckImageAsync(imageURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return c.CheckImageAsyncContext(context.Background(), imageURL, scene, openID, opts...)
+}
+
+// CheckImageAsyncContext is CheckImageAsync with the given context.
+func (c *Service) CheckImageAsyncContext(ctx context.Context, imageURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return c.MediaViolationCheckAsyncContext(ctx, imageURL, 2, scene, openID, opts...)
 }
 
 // CheckAudioAsync is a convenient method for asynchronous audio content security detection.
-func (c *Service) CheckAudioAsync(audioURL string, scene int, openID string) (*MediaViolationCheckAsyncResponse, error) {
-	return c.MediaViolationCheckAsync(audioURL, 1, scene, openID)
+func (c *Service) CheckAudioAsync(audioURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return c.CheckAudioAsyncContext(context.Background(), audioURL, scene, openID, opts...)
+}
+
+// CheckAudioAsyncContext is CheckAudioAsync with the given context.
+func (c *Service) CheckAudioAsyncContext(ctx context.Context, audioURL string, scene int, openID string, opts ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return c.MediaViolationCheckAsyncContext(ctx, audioURL, 1, scene, openID, opts...)
 }
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestUnmarshalMediaCheckCallback(t *testing.T) {
+	xmlData := `<xml>
+<ToUserName><![CDATA[gh_38cc49f9733b]]></ToUserName>
+<FromUserName><![CDATA[oH1fu0FdHqpToe2T6gBj0WyB8iS1]]></FromUserName>
+<CreateTime>1626959646</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[wxa_media_check]]></Event>
+<appid><![CDATA[wx8f16a5e2bf1e8f72]]></appid>
+<trace_id><![CDATA[60f96f1d-3845297a-1976a3ae]]></trace_id>
+<version>2</version>
+<detail><strategy><![CDATA[content_model]]></strategy><errcode>0</errcode><suggest><![CDATA[pass]]></suggest><label>100</label><prob>90</prob></detail>
+<detail><strategy><![CDATA[keyword]]></strategy><errcode>0</errcode><suggest><![CDATA[risky]]></suggest><label>20002</label><prob>95</prob></detail>
+<errcode>0</errcode>
+<errmsg><![CDATA[ok]]></errmsg>
+<result><suggest><![CDATA[risky]]></suggest><label>20002</label></result>
+</xml>`
+
+	jsonData := `{"ToUserName":"gh_38cc49f9733b","FromUserName":"oH1fu0FdHqpToe2T6gBj0WyB8iS1","CreateTime":1626959646,
+"MsgType":"event","Event":"wxa_media_check","appid":"wx8f16a5e2bf1e8f72","trace_id":"60f96f1d-3845297a-1976a3ae","version":2,
+"detail":[{"strategy":"content_model","errcode":0,"suggest":"pass","label":100,"prob":90},
+{"strategy":"keyword","errcode":0,"suggest":"risky","label":20002,"prob":95}],
+"errcode":0,"errmsg":"ok","result":{"suggest":"risky","label":20002}}`
+
+	svc := &Service{}
+
+	for _, tt := range []struct {
+		name     string
+		dataType string
+		data     string
+	}{
+		{name: "xml", dataType: "xml", data: xmlData},
+		{name: "json", dataType: "json", data: jsonData},
+		{name: "detect xml", data: xmlData},
+		{name: "detect json", data: jsonData},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := UnmarshalMediaCheckCallback(tt.dataType, []byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, "60f96f1d-3845297a-1976a3ae", result.TraceID)
+			assert.Equal(t, "wx8f16a5e2bf1e8f72", result.AppID)
+			assert.Equal(t, 2, result.Version)
+			assert.Equal(t, "ok", result.ErrMsg)
+			assert.Len(t, result.Detail, 2)
+			assert.Equal(t, 95, result.Detail[1].Prob)
+			assert.Equal(t, ViolationSuggestRisky, result.Result.Suggest)
+			assert.True(t, svc.CheckMediaViolation(result).IsViolation)
+		})
+	}
+
+	_, err := svc.ParseMediaCheckCallback([]byte(xmlData))
+	assert.NoError(t, err)
+
+	_, err = UnmarshalMediaCheckCallback("json", []byte(xmlData))
+	assert.Error(t, err)
+}
+
+func TestMediaCheckEventHandler(t *testing.T) {
+	data := []byte(`{"ToUserName":"gh_38cc49f9733b","CreateTime":1626959646,"MsgType":"event","Event":"wxa_media_check",
+"appid":"wx8f16a5e2bf1e8f72","trace_id":"60f96f1d-3845297a-1976a3ae","version":2,"errcode":0,"errmsg":"ok",
+"result":{"suggest":"pass","label":100}}`)
+
+	var event *vwx.Event
+	handler := MediaCheckEventHandler(func(_ context.Context, e *vwx.Event) error {
+		event = e
+		return nil
+	})
+
+	d := vwxpush.NewDispatcher()
+	d.HandleEvent(MediaCheckEvent, handler)
+
+	resp, err := d.Dispatch("", &vwxpush.PushBaseInfo{MsgType: "event", Event: "wxa_media_check", RequestID: "rid"}, data)
+	assert.NoError(t, err)
+	assert.Empty(t, resp)
+	assert.Equal(t, vwx.EventSourceMediaCheck, event.Source)
+	assert.Equal(t, "wx8f16a5e2bf1e8f72", event.AppID)
+	assert.Equal(t, MediaCheckEvent, event.Kind)
+	assert.Equal(t, "rid", event.RequestID)
+	assert.Equal(t, int64(1626959646), event.CreateTime.Unix())
+	assert.Equal(t, "60f96f1d-3845297a-1976a3ae", event.Payload.(*MediaViolationCheckCallbackResult).TraceID)
+
+	_, err = handler("", &vwxpush.PushBaseInfo{}, []byte("{"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,367 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	defaultModerationTimeout = 30 * time.Minute
+)
+
+// ModerationItem represents a piece of user generated content to be moderated,
+// combining text and media urls.
+type ModerationItem struct {
+	ID        string   // 业务方内容ID，原样返回在审核结论中
+	OpenID    string   // 用户的openid（用户需在近两小时访问过小程序）
+	Scene     int      // 场景枚举值（1 资料；2 评论；3 论坛；4 社交日志）
+	Text      string   // 要检测的文本内容
+	ImageURLs []string // 要检测的图片url
+	AudioURLs []string // 要检测的音频url
+}
+
+// ModerationVerdict represents the aggregated moderation result of a content item.
+type ModerationVerdict struct {
+	ItemID       string                         `json:"item_id"`       // 业务方内容ID
+	IsViolation  bool                           `json:"is_violation"`  // 是否违规
+	Suggest      string                         `json:"suggest"`       // 综合建议，有risky、pass、review三种值
+	Label        int                            `json:"label"`         // 命中标签
+	Reason       string                         `json:"reason"`        // 违规原因
+	TimedOut     bool                           `json:"timed_out"`     // 是否有媒体检测结果未在超时时间内回调
+	TextSafe     bool                           `json:"text_safe"`     // 文本检测是否通过
+	MediaResults map[string]*MediaViolationInfo `json:"media_results"` // 媒体检测结果，key为媒体url
+}
+
+// ModerationPipeline orchestrates text and media checks of content items,
+// correlates asynchronous media check callbacks by trace_id and emits a single
+// aggregated verdict per item.
+type ModerationPipeline struct {
+	svc ContentChecker
+
+	Timeout   time.Duration             // 等待媒体检测回调的超时时间
+	OnVerdict func(*ModerationVerdict)  // 审核结论回调
+	Verdicts  chan<- *ModerationVerdict // 审核结论通道，非阻塞发送，通道满时丢弃结论
+	Logger    vwx.Logger                // 日志，默认vlog
+
+	mu      sync.Mutex
+	tasks   map[string]*moderationTask // item id -> task
+	traceTo map[string]*moderationTask // trace id -> task
+}
+
+type moderationTask struct {
+	verdict    *ModerationVerdict
+	pending    map[string]string // trace id -> media url
+	submitting bool              // 媒体检测是否仍在提交中
+	timer      *time.Timer
+}
+
+// NewModerationPipeline creates a new moderation pipeline on top of the given content checker.
+func NewModerationPipeline(svc ContentChecker, options ...func(*ModerationPipeline)) *ModerationPipeline {
+	p := &ModerationPipeline{
+		svc:     svc,
+		Timeout: defaultModerationTimeout,
+		tasks:   make(map[string]*moderationTask),
+		traceTo: make(map[string]*moderationTask),
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// WithModerationTimeout sets how long to wait for media check callbacks before emitting the verdict.
+func WithModerationTimeout(timeout time.Duration) func(*ModerationPipeline) {
+	return func(p *ModerationPipeline) {
+		p.Timeout = timeout
+	}
+}
+
+// WithVerdictHandler sets the callback invoked with each aggregated verdict.
+func WithVerdictHandler(handler func(*ModerationVerdict)) func(*ModerationPipeline) {
+	return func(p *ModerationPipeline) {
+		p.OnVerdict = handler
+	}
+}
+
+// WithVerdictChannel sets the channel receiving each aggregated verdict.
+// Verdicts are sent without blocking the callback handling, so the channel should be
+// buffered and drained, a verdict is dropped with a warning log if the channel is full.
+func WithVerdictChannel(ch chan<- *ModerationVerdict) func(*ModerationPipeline) {
+	return func(p *ModerationPipeline) {
+		p.Verdicts = ch
+	}
+}
+
+// Submit checks the text synchronously and submits all media urls for asynchronous detection.
+// The verdict is emitted immediately if the item has no media, otherwise after all media
+// callbacks have been handled by HandleCallback or the timeout is reached.
+func (p *ModerationPipeline) Submit(item *ModerationItem) error {
+	if item.ID == "" {
+		return fmt.Errorf("moderation item id is empty")
+	}
+
+	verdict := &ModerationVerdict{
+		ItemID:       item.ID,
+		Suggest:      ViolationSuggestPass,
+		Label:        100,
+		Reason:       "内容正常",
+		TextSafe:     true,
+		MediaResults: make(map[string]*MediaViolationInfo),
+	}
+
+	task, err := p.reserve(verdict)
+	if err != nil {
+		return err
+	}
+
+	if item.Text != "" {
+		safe, err := p.svc.IsMsgContentSafe(item.Text)
+		if err != nil {
+			p.release(task)
+			return fmt.Errorf("check text error: %w", err)
+		}
+
+		p.mu.Lock()
+		verdict.TextSafe = safe
+		if !safe {
+			mergeModerationResult(verdict, ViolationSuggestRisky, 0, "文本内容可能潜在风险")
+		}
+		p.mu.Unlock()
+	}
+
+	submit := func(mediaURL string, mediaType int) error {
+		resp, err := p.svc.MediaViolationCheckAsync(mediaURL, mediaType, item.Scene, item.OpenID)
+		if err != nil {
+			return fmt.Errorf("check media %s error: %w", mediaURL, err)
+		}
+		p.addTrace(task, resp.TraceID, mediaURL)
+		return nil
+	}
+
+	for _, imageURL := range item.ImageURLs {
+		if err := submit(imageURL, ViolationMediaTypeImage); err != nil {
+			p.release(task)
+			return err
+		}
+	}
+
+	for _, audioURL := range item.AudioURLs {
+		if err := submit(audioURL, ViolationMediaTypeAudio); err != nil {
+			p.release(task)
+			return err
+		}
+	}
+
+	if p.start(task) {
+		p.emit(verdict)
+	}
+
+	return nil
+}
+
+// HandleCallback correlates a media check callback with its submitted item.
+// It returns false if the trace_id does not belong to any item in progress.
+func (p *ModerationPipeline) HandleCallback(result *MediaViolationCheckCallbackResult) bool {
+	p.mu.Lock()
+	task, ok := p.traceTo[result.TraceID]
+	if !ok {
+		p.mu.Unlock()
+		return false
+	}
+
+	delete(p.traceTo, result.TraceID)
+	mediaURL := task.pending[result.TraceID]
+	delete(task.pending, result.TraceID)
+
+	info := p.svc.CheckMediaViolation(result)
+	task.verdict.MediaResults[mediaURL] = info
+	if info.IsViolation {
+		suggest := info.Suggest
+		if result.ErrCode != 0 {
+			suggest = ViolationSuggestReview
+		}
+		mergeModerationResult(task.verdict, suggest, info.Label, info.Reason)
+	}
+
+	done := len(task.pending) == 0 && !task.submitting
+	if done {
+		p.untrack(task)
+	}
+	p.mu.Unlock()
+
+	if done {
+		p.emit(task.verdict)
+	}
+
+	return true
+}
+
+// Pending returns the number of items waiting for media check callbacks.
+func (p *ModerationPipeline) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.tasks)
+}
+
+// reserve registers the item before any check is submitted, so that concurrent
+// submissions of the same item are rejected.
+func (p *ModerationPipeline) reserve(verdict *ModerationVerdict) (*moderationTask, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.tasks[verdict.ItemID]; exists {
+		return nil, fmt.Errorf("moderation item %s is already in progress", verdict.ItemID)
+	}
+
+	task := &moderationTask{
+		verdict:    verdict,
+		pending:    make(map[string]string),
+		submitting: true,
+	}
+	p.tasks[verdict.ItemID] = task
+
+	return task, nil
+}
+
+// addTrace correlates a submitted media check with the task immediately,
+// callbacks may arrive before the remaining media are submitted.
+func (p *ModerationPipeline) addTrace(task *moderationTask, traceID, mediaURL string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	task.pending[traceID] = mediaURL
+	p.traceTo[traceID] = task
+}
+
+// release drops a task failed to submit, callbacks of its submitted media are ignored.
+func (p *ModerationPipeline) release(task *moderationTask) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.untrack(task)
+}
+
+// start marks all media of the task submitted and starts waiting for the pending callbacks,
+// it returns true if the task is already done and the verdict should be emitted.
+func (p *ModerationPipeline) start(task *moderationTask) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	task.submitting = false
+	if len(task.pending) == 0 {
+		p.untrack(task)
+		return true
+	}
+
+	p.arm(task)
+
+	return false
+}
+
+// arm starts the timeout timer of the task, the caller must hold the lock.
+func (p *ModerationPipeline) arm(task *moderationTask) {
+	if p.Timeout > 0 {
+		task.timer = time.AfterFunc(p.Timeout, func() {
+			p.expire(task)
+		})
+	}
+}
+
+// untrack removes the task from the pipeline, the caller must hold the lock.
+func (p *ModerationPipeline) untrack(task *moderationTask) {
+	if task.timer != nil {
+		task.timer.Stop()
+	}
+
+	delete(p.tasks, task.verdict.ItemID)
+	for traceID := range task.pending {
+		delete(p.traceTo, traceID)
+	}
+}
+
+func (p *ModerationPipeline) logger() vwx.Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+func (p *ModerationPipeline) expire(task *moderationTask) {
+	p.mu.Lock()
+	if _, ok := p.tasks[task.verdict.ItemID]; !ok {
+		p.mu.Unlock()
+		return
+	}
+
+	p.logger().Warnf("moderation timeout | item: %s | pending: %d", task.verdict.ItemID, len(task.pending))
+
+	p.untrack(task)
+	task.verdict.TimedOut = true
+	mergeModerationResult(task.verdict, ViolationSuggestReview, 0, "媒体检测结果超时未返回，需要人工审核")
+	p.mu.Unlock()
+
+	p.emit(task.verdict)
+}
+
+func (p *ModerationPipeline) emit(verdict *ModerationVerdict) {
+	p.logger().Infof("moderation verdict | item: %s | suggest: %s | label: %d | reason: %s",
+		verdict.ItemID, verdict.Suggest, verdict.Label, verdict.Reason)
+
+	if p.OnVerdict != nil {
+		p.OnVerdict(verdict)
+	}
+
+	if p.Verdicts != nil {
+		select {
+		case p.Verdicts <- verdict:
+		default:
+			p.logger().Warnf("moderation verdict dropped, channel is full | item: %s", verdict.ItemID)
+		}
+	}
+}
+
+// mergeModerationResult keeps the most severe suggestion in the verdict.
+func mergeModerationResult(verdict *ModerationVerdict, suggest string, label int, reason string) {
+	if suggestSeverity(suggest) <= suggestSeverity(verdict.Suggest) {
+		return
+	}
+
+	verdict.Suggest = suggest
+	verdict.Label = label
+	verdict.Reason = reason
+	verdict.IsViolation = suggest != ViolationSuggestPass
+}
+
+func suggestSeverity(suggest string) int {
+	switch suggest {
+	case ViolationSuggestRisky:
+		return 2
+	case ViolationSuggestReview:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	ModerationKindText  = "text"  // 文本检测
+	ModerationKindMedia = "media" // 图片或音频异步检测
+
+	ModerationDecisionPass   = "pass"   // 放行
+	ModerationDecisionReview = "review" // 转人工审核
+	ModerationDecisionReject = "reject" // 拦截
+)
+
+// ModerationRecord is the structured record of a moderation decision, kept as the audit trail
+// of content compliance.
+type ModerationRecord struct {
+	Time        time.Time `json:"time"`                   // 审核时间
+	Kind        string    `json:"kind"`                   // 检测类型，text或media
+	RequestID   string    `json:"request_id,omitempty"`   // 文本检测调用的关联 id
+	TraceID     string    `json:"trace_id,omitempty"`     // 媒体检测任务id
+	ContentHash string    `json:"content_hash,omitempty"` // 文本内容的sha256，不记录原文
+	Labels      []int     `json:"labels,omitempty"`       // 命中标签
+	Suggest     string    `json:"suggest"`                // 微信建议，有risky、pass、review三种值
+	Decision    string    `json:"decision"`               // 审核决定，有pass、review、reject三种值
+	Reason      string    `json:"reason,omitempty"`       // 决定原因
+}
+
+// ModerationSink receives the moderation records, e.g. to export them for audit.
+type ModerationSink interface {
+	WriteModerationRecord(record *ModerationRecord) error
+}
+
+// ModerationSinkFunc adapts a function to a ModerationSink.
+type ModerationSinkFunc func(record *ModerationRecord) error
+
+// WriteModerationRecord calls f(record).
+func (f ModerationSinkFunc) WriteModerationRecord(record *ModerationRecord) error {
+	return f(record)
+}
+
+// JSONLinesModerationSink writes the moderation records as JSON lines, suitable for audit export.
+type JSONLinesModerationSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLinesModerationSink creates a sink writing JSON lines to w.
+func NewJSONLinesModerationSink(w io.Writer) *JSONLinesModerationSink {
+	return &JSONLinesModerationSink{enc: json.NewEncoder(w)}
+}
+
+// WriteModerationRecord writes the record as a JSON line.
+func (s *JSONLinesModerationSink) WriteModerationRecord(record *ModerationRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enc.Encode(record)
+}
+
+// WithModerationSink emits the records of the decisions made by IsMsgContentSafe and
+// CheckMediaViolation to the sink.
+func WithModerationSink(sink ModerationSink) func(*Service) {
+	return func(s *Service) {
+		s.moderationSink = sink
+	}
+}
+
+// emitModerationRecord writes the record to the moderation sink if set, sink errors are logged
+// and never fail the moderation.
+func (c *Service) emitModerationRecord(record *ModerationRecord) {
+	if c.moderationSink == nil {
+		return
+	}
+
+	record.Time = time.Now()
+
+	if err := c.moderationSink.WriteModerationRecord(record); err != nil {
+		c.client.Logger.Errorf("write moderation record error | kind: %s | request_id: %s | trace_id: %s | err: %v",
+			record.Kind, record.RequestID, record.TraceID, err)
+	}
+}
+
+// moderationDecision returns the decision of the violation info.
+func moderationDecision(info *MediaViolationInfo) string {
+	switch {
+	case !info.IsViolation:
+		return ModerationDecisionPass
+	case info.Suggest == ViolationSuggestReview:
+		return ModerationDecisionReview
+	default:
+		return ModerationDecisionReject
+	}
+}
+
+// contentHash returns the hex sha256 of the content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestModerationSink(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)),
+		WithModerationSink(NewJSONLinesModerationSink(&buf)))
+
+	safe, err := svc.IsMsgContentSafe("hello")
+	assert.NoError(t, err)
+	assert.True(t, safe)
+
+	server.SetError("/wxa/msg_sec_check", 87014, "risky content")
+	safe, err = svc.IsMsgContentSafe("bad words")
+	assert.NoError(t, err)
+	assert.False(t, safe)
+
+	svc.CheckMediaViolation(&MediaViolationCheckCallbackResult{
+		TraceID: "trace-1",
+		Result:  &MediaViolationCheckResult{Suggest: ViolationSuggestReview, Label: 20001},
+		Detail: []*MediaViolationCheckDetailResult{
+			{Strategy: "content_model", Suggest: ViolationSuggestPass, Label: 100},
+			{Strategy: "keyword", Suggest: ViolationSuggestReview, Label: 20006},
+		},
+	})
+
+	var records []*ModerationRecord
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record ModerationRecord
+		assert.NoError(t, dec.Decode(&record))
+		records = append(records, &record)
+	}
+
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, ModerationKindText, records[0].Kind)
+	assert.Equal(t, ModerationDecisionPass, records[0].Decision)
+	assert.Equal(t, contentHash("hello"), records[0].ContentHash)
+	assert.NotEmpty(t, records[0].RequestID)
+	assert.False(t, records[0].Time.IsZero())
+
+	assert.Equal(t, ViolationSuggestRisky, records[1].Suggest)
+	assert.Equal(t, ModerationDecisionReject, records[1].Decision)
+
+	assert.Equal(t, ModerationKindMedia, records[2].Kind)
+	assert.Equal(t, "trace-1", records[2].TraceID)
+	assert.Equal(t, []int{20001, 20006}, records[2].Labels)
+	assert.Equal(t, ModerationDecisionReview, records[2].Decision)
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+type fakeMediaChecker struct {
+	ContentChecker
+	submit func(mediaURL string) (*MediaViolationCheckAsyncResponse, error)
+}
+
+func (f *fakeMediaChecker) MediaViolationCheckAsync(mediaURL string, _, _ int, _ string, _ ...vwx.RequestOption) (*MediaViolationCheckAsyncResponse, error) {
+	return f.submit(mediaURL)
+}
+
+// newFakeMediaChecker returns a checker accepting all media, the trace id of a media is its url
+// prefixed by trace-.
+func newFakeMediaChecker() *fakeMediaChecker {
+	return &fakeMediaChecker{
+		ContentChecker: NewService(vwx.NewClient("appid", "secret")),
+		submit: func(mediaURL string) (*MediaViolationCheckAsyncResponse, error) {
+			return &MediaViolationCheckAsyncResponse{TraceID: "trace-" + mediaURL}, nil
+		},
+	}
+}
+
+func TestModerationPipelineAggregate(t *testing.T) {
+	verdicts := make(chan *ModerationVerdict, 1)
+	p := NewModerationPipeline(newFakeMediaChecker(), WithVerdictChannel(verdicts))
+
+	assert.NoError(t, p.Submit(&ModerationItem{ID: "item-1", ImageURLs: []string{"1.jpg", "2.jpg"}}))
+	assert.Equal(t, 1, p.Pending())
+
+	assert.False(t, p.HandleCallback(&MediaViolationCheckCallbackResult{TraceID: "unknown"}))
+
+	assert.True(t, p.HandleCallback(&MediaViolationCheckCallbackResult{
+		TraceID: "trace-1.jpg",
+		Result:  &MediaViolationCheckResult{Suggest: ViolationSuggestReview, Label: 20002},
+	}))
+	assert.Len(t, verdicts, 0)
+
+	assert.True(t, p.HandleCallback(&MediaViolationCheckCallbackResult{
+		TraceID: "trace-2.jpg",
+		Result:  &MediaViolationCheckResult{Suggest: ViolationSuggestRisky, Label: 20001},
+	}))
+
+	verdict := <-verdicts
+	assert.Equal(t, "item-1", verdict.ItemID)
+	assert.True(t, verdict.IsViolation)
+	assert.Equal(t, ViolationSuggestRisky, verdict.Suggest)
+	assert.Equal(t, 20001, verdict.Label)
+	assert.Len(t, verdict.MediaResults, 2)
+	assert.Equal(t, 0, p.Pending())
+}
+
+func TestModerationPipelineTimeout(t *testing.T) {
+	verdicts := make(chan *ModerationVerdict, 1)
+	p := NewModerationPipeline(newFakeMediaChecker(),
+		WithVerdictChannel(verdicts),
+		WithModerationTimeout(10*time.Millisecond),
+	)
+
+	assert.NoError(t, p.Submit(&ModerationItem{ID: "item-2", AudioURLs: []string{"3.mp3"}}))
+
+	select {
+	case verdict := <-verdicts:
+		assert.True(t, verdict.TimedOut)
+		assert.Equal(t, ViolationSuggestReview, verdict.Suggest)
+	case <-time.After(time.Second):
+		t.Fatal("expected verdict after timeout")
+	}
+
+	assert.False(t, p.HandleCallback(&MediaViolationCheckCallbackResult{TraceID: "trace-3.mp3"}))
+}
+
+func TestModerationPipelineSubmit(t *testing.T) {
+	checker := newFakeMediaChecker()
+	verdicts := make(chan *ModerationVerdict, 1)
+	p := NewModerationPipeline(checker, WithVerdictChannel(verdicts))
+
+	// the callback of the first media arrives before the second one is submitted
+	checker.submit = func(mediaURL string) (*MediaViolationCheckAsyncResponse, error) {
+		assert.Error(t, p.Submit(&ModerationItem{ID: "item-3"}))
+
+		if mediaURL == "b.jpg" {
+			assert.True(t, p.HandleCallback(&MediaViolationCheckCallbackResult{
+				TraceID: "trace-a.jpg",
+				Result:  &MediaViolationCheckResult{Suggest: ViolationSuggestPass, Label: 100},
+			}))
+		}
+		return &MediaViolationCheckAsyncResponse{TraceID: "trace-" + mediaURL}, nil
+	}
+
+	assert.NoError(t, p.Submit(&ModerationItem{ID: "item-3", ImageURLs: []string{"a.jpg", "b.jpg"}}))
+	assert.Len(t, verdicts, 0)
+	assert.True(t, p.HandleCallback(&MediaViolationCheckCallbackResult{
+		TraceID: "trace-b.jpg",
+		Result:  &MediaViolationCheckResult{Suggest: ViolationSuggestPass, Label: 100},
+	}))
+
+	verdict := <-verdicts
+	assert.Len(t, verdict.MediaResults, 2)
+	assert.Equal(t, 0, p.Pending())
+
+	// a failed submission releases the item and its submitted media
+	checker.submit = func(mediaURL string) (*MediaViolationCheckAsyncResponse, error) {
+		if mediaURL == "d.mp3" {
+			return nil, assert.AnError
+		}
+		return &MediaViolationCheckAsyncResponse{TraceID: "trace-" + mediaURL}, nil
+	}
+
+	assert.Error(t, p.Submit(&ModerationItem{ID: "item-4", ImageURLs: []string{"c.jpg"}, AudioURLs: []string{"d.mp3"}}))
+	assert.Equal(t, 0, p.Pending())
+	assert.False(t, p.HandleCallback(&MediaViolationCheckCallbackResult{TraceID: "trace-c.jpg"}))
+}
+
+func TestModerationPipelineVerdictDropped(t *testing.T) {
+	svc := NewService(vwx.NewClient("appid", "secret"))
+	p := NewModerationPipeline(svc, WithVerdictChannel(make(chan *ModerationVerdict)))
+
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, p.Submit(&ModerationItem{ID: "item-5"}))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("emit blocked on the verdict channel")
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+// ContentVerdict is the suggestion of WeChat on the checked content.
+type ContentVerdict string
+
+const (
+	ContentVerdictPass   ContentVerdict = ViolationSuggestPass   // 内容正常
+	ContentVerdictReview ContentVerdict = ViolationSuggestReview // 需人工审核
+	ContentVerdictRisky  ContentVerdict = ViolationSuggestRisky  // 内容违规
+)
+
+// MsgContentCheckRequest represents a request of the text security check v2.
+type MsgContentCheckRequest struct {
+	Content   string `json:"content"`             // 需检测的文本内容，文本字数的上限为2500字
+	Version   int    `json:"version"`             // 接口版本号，2.0版本为固定值2
+	Scene     int    `json:"scene"`               // 场景枚举值（1 资料；2 评论；3 论坛；4 社交日志）
+	OpenID    string `json:"openid"`              // 用户的openid（用户需在近两小时访问过小程序）
+	Title     string `json:"title,omitempty"`     // 文本标题
+	Nickname  string `json:"nickname,omitempty"`  // 用户昵称
+	Signature string `json:"signature,omitempty"` // 个性签名，仅在资料类场景有效
+}
+
+// MsgContentCheckDetail represents a detailed result of the text security check v2.
+type MsgContentCheckDetail struct {
+	Strategy string `json:"strategy"`          // 策略类型
+	ErrCode  int    `json:"errcode"`           // 错误码，仅当该值为0时，该项结果有效
+	Suggest  string `json:"suggest"`           // 建议，有risky、pass、review三种值
+	Label    int    `json:"label"`             // 命中标签枚举值
+	Keyword  string `json:"keyword,omitempty"` // 命中的自定义关键词
+	Prob     int    `json:"prob"`              // 0-100，代表置信度
+}
+
+// MsgContentCheckResponse represents the response of the text security check v2.
+type MsgContentCheckResponse struct {
+	ErrCode int                        `json:"errcode"`  // 错误码
+	ErrMsg  string                     `json:"errmsg"`   // 错误信息
+	TraceID string                     `json:"trace_id"` // 唯一请求标识
+	Result  *MediaViolationCheckResult `json:"result"`   // 综合结果
+	Detail  []*MsgContentCheckDetail   `json:"detail"`   // 详细检测结果
+}
+
+// MsgContentVerdict is the typed result of the text security check, so that content to be
+// reviewed can be routed to human moderation instead of being rejected.
+type MsgContentVerdict struct {
+	Verdict ContentVerdict           `json:"verdict"`  // 综合建议
+	Label   int                      `json:"label"`    // 命中标签
+	Labels  []int                    `json:"labels"`   // 未通过的详细结果命中的标签
+	Reason  string                   `json:"reason"`   // 原因说明
+	TraceID string                   `json:"trace_id"` // 唯一请求标识
+	Detail  []*MsgContentCheckDetail `json:"detail"`   // 详细检测结果
+}
+
+// CheckMsgContent checks the text with the security check v2, returning the verdict with the
+// labels and details of the check. The decision is emitted to the moderation sink if set.
+func (c *Service) CheckMsgContent(req *MsgContentCheckRequest, opts ...vwx.RequestOption) (*MsgContentVerdict, error) {
+	return c.CheckMsgContentContext(context.Background(), req, opts...)
+}
+
+// CheckMsgContentContext is CheckMsgContent with the given context.
+func (c *Service) CheckMsgContentContext(ctx context.Context, req *MsgContentCheckRequest, opts ...vwx.RequestOption) (*MsgContentVerdict, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	req.Version = 2
+
+	call := c.call("msg sec check v2", msgSecCheckURL, opts)
+	call.Idempotent = true
+
+	response, err := wxapi.PostJSON[MsgContentCheckResponse](ctx, call, req)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := c.msgContentVerdict(response)
+
+	record := &ModerationRecord{
+		Kind:        ModerationKindText,
+		RequestID:   requestID,
+		TraceID:     verdict.TraceID,
+		ContentHash: contentHash(req.Content),
+		Suggest:     string(verdict.Verdict),
+		Decision:    ModerationDecisionPass,
+		Reason:      verdict.Reason,
+	}
+	if verdict.Label != 0 {
+		record.Labels = appendLabel(record.Labels, verdict.Label)
+	}
+	for _, label := range verdict.Labels {
+		record.Labels = appendLabel(record.Labels, label)
+	}
+	switch verdict.Verdict {
+	case ContentVerdictReview:
+		record.Decision = ModerationDecisionReview
+	case ContentVerdictRisky:
+		record.Decision = ModerationDecisionReject
+	}
+	c.emitModerationRecord(record)
+
+	return verdict, nil
+}
+
+func (c *Service) msgContentVerdict(response *MsgContentCheckResponse) *MsgContentVerdict {
+	verdict := &MsgContentVerdict{
+		Verdict: ContentVerdictPass,
+		TraceID: response.TraceID,
+		Detail:  response.Detail,
+	}
+
+	if response.Result != nil {
+		verdict.Verdict = ContentVerdict(response.Result.Suggest)
+		verdict.Label = response.Result.Label
+	}
+
+	for _, detail := range response.Detail {
+		if detail.ErrCode == 0 && detail.Suggest != ViolationSuggestPass {
+			verdict.Labels = append(verdict.Labels, detail.Label)
+		}
+	}
+
+	switch verdict.Verdict {
+	case ContentVerdictRisky:
+		verdict.Reason = c.getLabelDescription(verdict.Label)
+	case ContentVerdictReview:
+		verdict.Reason = fmt.Sprintf("内容需要人工审核：%s", c.getLabelDescription(verdict.Label))
+	default:
+		verdict.Reason = "内容正常"
+	}
+
+	return verdict
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestCheckMsgContent(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	var records []*ModerationRecord
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)),
+		WithModerationSink(ModerationSinkFunc(func(record *ModerationRecord) error {
+			records = append(records, record)
+			return nil
+		})))
+
+	server.SetResponse("/wxa/msg_sec_check", map[string]any{
+		"errcode":  0,
+		"errmsg":   "ok",
+		"trace_id": "trace-1",
+		"result":   map[string]any{"suggest": "review", "label": 20001},
+		"detail": []map[string]any{
+			{"strategy": "content_model", "errcode": 0, "suggest": "review", "label": 20001, "prob": 60},
+			{"strategy": "keyword", "errcode": 0, "suggest": "pass", "label": 100},
+		},
+	})
+
+	verdict, err := svc.CheckMsgContent(&MsgContentCheckRequest{Content: "hello", Scene: ViolationSceneComment, OpenID: "openid"})
+	assert.NoError(t, err)
+	assert.Equal(t, ContentVerdictReview, verdict.Verdict)
+	assert.Equal(t, 20001, verdict.Label)
+	assert.Equal(t, []int{20001}, verdict.Labels)
+	assert.Equal(t, "trace-1", verdict.TraceID)
+	assert.Len(t, verdict.Detail, 2)
+
+	requests := server.Requests("/wxa/msg_sec_check")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, `{"content":"hello","version":2,"scene":2,"openid":"openid"}`, string(requests[0].Body))
+
+	assert.Len(t, records, 1)
+	assert.Equal(t, ModerationDecisionReview, records[0].Decision)
+	assert.Equal(t, []int{20001}, records[0].Labels)
+
+	server.SetError("/wxa/msg_sec_check", 40003, "invalid openid")
+	_, err = svc.CheckMsgContent(&MsgContentCheckRequest{Content: "hello", Scene: ViolationSceneComment, OpenID: "bad"})
+	assert.True(t, vwx.IsErrCode(err, 40003))
+}
@@ -18,14 +18,10 @@
 package vwxa
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"net/http"
+	"context"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
@@ -49,63 +45,58 @@ type MsgViolationCheckResponse struct {
 // - Media news user article and comment content detection
 // - Game user uploaded material detection, etc.
 // Rate limit: single appId call limit is 4000 times/minute, 2,000,000 times/day
-func (c *Service) MsgViolationCheck(content string) (*MsgViolationCheckResponse, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
-	if err != nil {
-		return nil, fmt.Errorf("get access token error: %v", err)
-	}
-
-	url := fmt.Sprintf(msgSecCheckURL, accessToken)
-
-	request := &MsgViolationCheckRequest{
-		Content: content,
-	}
-
-	data, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request error: %v", err)
-	}
-
-	vlog.Infof("msg sec check | req: %s", string(data))
+func (c *Service) MsgViolationCheck(content string, opts ...vwx.RequestOption) (*MsgViolationCheckResponse, error) {
+	return c.MsgViolationCheckContext(context.Background(), content, opts...)
+}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
-	if err != nil {
-		return nil, fmt.Errorf("send request error: %v", err)
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
+// MsgViolationCheckContext is MsgViolationCheck with the given context.
+func (c *Service) MsgViolationCheckContext(ctx context.Context, content string, opts ...vwx.RequestOption) (*MsgViolationCheckResponse, error) {
+	call := c.call("msg sec check", msgSecCheckURL, opts)
+	call.Idempotent = true
+	call.Error = func(apiURL string, errCode int, errMsg, requestID string) error {
+		// 根据微信文档，errcode为0表示内容正常，87014表示内容可能潜在风险
+		if errCode == vwx.ErrCodeRiskyContent {
+			return nil
 		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response error: %v", err)
-	}
-
-	vlog.Infof("msg sec check | resp: %s", string(body))
-
-	var response MsgViolationCheckResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("unmarshal response error: %v", err)
-	}
-
-	// 根据微信文档，errcode为0表示内容正常，87014表示内容可能潜在风险
-	if response.ErrCode != 0 && response.ErrCode != 87014 {
-		return &response, errors.New(response.ErrMsg)
+		return wechatError(apiURL, errCode, errMsg, requestID)
 	}
 
-	return &response, nil
+	return wxapi.PostJSON[MsgViolationCheckResponse](ctx, call, &MsgViolationCheckRequest{Content: content})
 }
 
 // IsMsgContentSafe is a convenient method to check if content is safe.
 // Returns true if content is safe, false if content may have risks.
-func (c *Service) IsMsgContentSafe(content string) (bool, error) {
-	response, err := c.MsgViolationCheck(content)
+func (c *Service) IsMsgContentSafe(content string, opts ...vwx.RequestOption) (bool, error) {
+	return c.IsMsgContentSafeContext(context.Background(), content, opts...)
+}
+
+// IsMsgContentSafeContext is IsMsgContentSafe with the given context.
+// The decision is emitted to the moderation sink if set.
+func (c *Service) IsMsgContentSafeContext(ctx context.Context, content string, opts ...vwx.RequestOption) (bool, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	response, err := c.MsgViolationCheckContext(ctx, content, opts...)
 	if err != nil {
 		return false, err
 	}
 
 	// errcode为0表示内容正常
-	return response.ErrCode == 0, nil
+	safe := response.ErrCode == 0
+
+	record := &ModerationRecord{
+		Kind:        ModerationKindText,
+		RequestID:   requestID,
+		ContentHash: contentHash(content),
+		Suggest:     ViolationSuggestPass,
+		Decision:    ModerationDecisionPass,
+	}
+	if !safe {
+		record.Suggest = ViolationSuggestRisky
+		record.Decision = ModerationDecisionReject
+		record.Reason = response.ErrMsg
+	}
+	c.emitModerationRecord(record)
+
+	return safe, nil
 }
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"github.com/vogo/vwx"
+)
+
+// MaxMediaSize is the max size of media uploaded from io.Reader.
+const MaxMediaSize = 10 << 20
+
+// ErrMediaTooLarge is returned when the media read from io.Reader exceeds MaxMediaSize.
+var ErrMediaTooLarge = errors.New("media exceeds 10MB limit")
+
+// postMultipart streams the media from the reader as a multipart file field to the url,
+// without buffering it in memory, and unmarshals the json response into v.
+// The upload is aborted with ErrMediaTooLarge once the media exceeds MaxMediaSize.
+func (c *Service) postMultipart(ctx context.Context, name, requestID, url, field, filename string, r io.Reader, v any) error {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := writer.CreateFormFile(field, filename)
+		if err == nil {
+			var size int64
+			size, err = io.Copy(part, io.LimitReader(r, MaxMediaSize+1))
+			if err == nil && size > MaxMediaSize {
+				err = ErrMediaTooLarge
+			}
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	c.client.Logger.Infof("%s | request_id: %s | filename: %s", name, requestID, filename)
+
+	resp, err := c.client.Post(ctx, url, writer.FormDataContentType(), pr)
+	// unblock the writer goroutine if the request ended before reading the whole body
+	_ = pr.Close()
+	if err != nil {
+		if errors.Is(err, ErrMediaTooLarge) {
+			return ErrMediaTooLarge
+		}
+		return fmt.Errorf("send request error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	body, err := c.client.ReadResponse(resp, vwx.JSONContentTypes...)
+	if err != nil {
+		return fmt.Errorf("read response error: %w", err)
+	}
+
+	c.client.Logger.Infof("%s | request_id: %s | resp: %s", name, requestID, body)
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal response error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestMultipartUpload(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	safe, err := svc.IsImageSafe("a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.True(t, safe)
+
+	requests := server.Requests("/wxa/img_sec_check")
+	assert.Len(t, requests, 1)
+	assert.True(t, bytes.Contains(requests[0].Body, vwxmock.PNGHeader))
+
+	server.SetError("/wxa/img_sec_check", 87014, "risky content")
+	safe, err = svc.IsImageSafe("a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.False(t, safe)
+
+	media, err := svc.UploadTempMedia("a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, media.MediaID)
+
+	_, err = svc.UploadTempMedia("a.png", io.LimitReader(zeroReader{}, MaxMediaSize+1))
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+
+	server.SetResponse("/cv/ocr/comm", map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"items":   []map[string]any{{"text": "hello"}},
+	})
+	text, err := svc.OCRPrintedText("a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", text.Items[0].Text)
+
+	server.SetResponse("/cv/ocr/idcard", map[string]any{"errcode": 101000, "errmsg": "invalid image url or image data"})
+	_, err = svc.OCRIDCard(OCRModePhoto, "a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.Error(t, err)
+}
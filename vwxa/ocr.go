@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	ocrIDCardURL      = "https://api.weixin.qq.com/cv/ocr/idcard?type=%s&access_token=%s"
+	ocrBankCardURL    = "https://api.weixin.qq.com/cv/ocr/bankcard?type=%s&access_token=%s"
+	ocrPrintedTextURL = "https://api.weixin.qq.com/cv/ocr/comm?access_token=%s"
+)
+
+// OCRMode represents the image mode of OCR.
+type OCRMode string
+
+const (
+	OCRModePhoto OCRMode = "photo" // 拍照模式
+	OCRModeScan  OCRMode = "scan"  // 扫描模式
+)
+
+// OCRIDCardResponse represents the response of ID card OCR.
+type OCRIDCardResponse struct {
+	Type        string `json:"type"`        // 正面或背面，Front / Back
+	Name        string `json:"name"`        // 正面返回，姓名
+	ID          string `json:"id"`          // 正面返回，身份证号
+	Addr        string `json:"addr"`        // 正面返回，地址
+	Gender      string `json:"gender"`      // 正面返回，性别
+	Nationality string `json:"nationality"` // 正面返回，民族
+	ValidDate   string `json:"valid_date"`  // 背面返回，有效期
+	ErrCode     int    `json:"errcode"`
+	ErrMsg      string `json:"errmsg"`
+}
+
+// OCRBankCardResponse represents the response of bank card OCR.
+type OCRBankCardResponse struct {
+	Number  string `json:"number"` // 银行卡号
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// OCRPoint represents a point of the recognized text position.
+type OCRPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// OCRTextItem represents a recognized line of text.
+type OCRTextItem struct {
+	Text string `json:"text"` // 识别的文本
+	Pos  struct {
+		LeftTop     OCRPoint `json:"left_top"`
+		RightTop    OCRPoint `json:"right_top"`
+		RightBottom OCRPoint `json:"right_bottom"`
+		LeftBottom  OCRPoint `json:"left_bottom"`
+	} `json:"pos"` // 文本位置
+}
+
+// OCRPrintedTextResponse represents the response of printed text OCR.
+type OCRPrintedTextResponse struct {
+	Items   []*OCRTextItem `json:"items"` // 识别结果
+	ImgSize struct {
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"img_size"` // 图片大小
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// OCRIDCard recognizes the ID card in the image read from r.
+func (c *Service) OCRIDCard(mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRIDCardResponse, error) {
+	return c.OCRIDCardContext(context.Background(), mode, filename, r, opts...)
+}
+
+// OCRIDCardContext is OCRIDCard with the given context.
+func (c *Service) OCRIDCardContext(ctx context.Context, mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRIDCardResponse, error) {
+	var response OCRIDCardResponse
+	requestID, err := c.ocr(ctx, "ocr idcard", func(accessToken string) string {
+		return fmt.Sprintf(ocrIDCardURL, mode, accessToken)
+	}, filename, r, &response, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ErrCode != 0 {
+		return &response, wechatError(ocrIDCardURL, response.ErrCode, response.ErrMsg, requestID)
+	}
+
+	return &response, nil
+}
+
+// OCRBankCard recognizes the bank card in the image read from r.
+func (c *Service) OCRBankCard(mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRBankCardResponse, error) {
+	return c.OCRBankCardContext(context.Background(), mode, filename, r, opts...)
+}
+
+// OCRBankCardContext is OCRBankCard with the given context.
+func (c *Service) OCRBankCardContext(ctx context.Context, mode OCRMode, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRBankCardResponse, error) {
+	var response OCRBankCardResponse
+	requestID, err := c.ocr(ctx, "ocr bankcard", func(accessToken string) string {
+		return fmt.Sprintf(ocrBankCardURL, mode, accessToken)
+	}, filename, r, &response, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ErrCode != 0 {
+		return &response, wechatError(ocrBankCardURL, response.ErrCode, response.ErrMsg, requestID)
+	}
+
+	return &response, nil
+}
+
+// OCRPrintedText recognizes the printed text in the image read from r.
+func (c *Service) OCRPrintedText(filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRPrintedTextResponse, error) {
+	return c.OCRPrintedTextContext(context.Background(), filename, r, opts...)
+}
+
+// OCRPrintedTextContext is OCRPrintedText with the given context.
+func (c *Service) OCRPrintedTextContext(ctx context.Context, filename string, r io.Reader, opts ...vwx.RequestOption) (*OCRPrintedTextResponse, error) {
+	var response OCRPrintedTextResponse
+	requestID, err := c.ocr(ctx, "ocr printed text", func(accessToken string) string {
+		return fmt.Sprintf(ocrPrintedTextURL, accessToken)
+	}, filename, r, &response, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ErrCode != 0 {
+		return &response, wechatError(ocrPrintedTextURL, response.ErrCode, response.ErrMsg, requestID)
+	}
+
+	return &response, nil
+}
+
+// ocr streams the image to the OCR endpoint and unmarshals the response into v,
+// it returns the request id of the call.
+func (c *Service) ocr(ctx context.Context, name string, buildURL func(accessToken string) string, filename string, r io.Reader, v any, opts []vwx.RequestOption) (string, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	// the url without the access token tells the endpoint group of the call
+	accessToken, err := c.accessToken(ctx, buildURL(""), opts)
+	if err != nil {
+		return requestID, fmt.Errorf("get access token error: %w", err)
+	}
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+	defer cancel()
+
+	return requestID, c.postMultipart(ctx, name, requestID, buildURL(accessToken), "img", filename, r, v)
+}
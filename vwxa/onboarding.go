@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+	"github.com/vogo/vwx/vwxauth"
+)
+
+const (
+	getUserPhoneNumberURL = "https://api.weixin.qq.com/wxa/business/getuserphonenumber?access_token=%s"
+	getUserRiskRankURL    = "https://api.weixin.qq.com/wxa/getuserriskrank?access_token=%s"
+)
+
+// Scenes of the user risk rank query.
+const (
+	RiskSceneRegister  = 0 // 注册
+	RiskSceneMarketing = 1 // 营销作弊
+)
+
+// PhoneNumberResponse represents the response of exchanging a phone code for the phone number.
+type PhoneNumberResponse struct {
+	ErrCode   int                `json:"errcode"`
+	ErrMsg    string             `json:"errmsg"`
+	PhoneInfo *vwxauth.PhoneInfo `json:"phone_info"` // 用户手机号信息
+}
+
+// UserRiskRankRequest represents a request to query the risk rank of a user.
+type UserRiskRankRequest struct {
+	AppID        string `json:"appid"`                   // 小程序appid
+	OpenID       string `json:"openid"`                  // 用户的openid
+	Scene        int    `json:"scene"`                   // 场景值，0:注册，1:营销作弊
+	MobileNo     string `json:"mobile_no,omitempty"`     // 用户手机号
+	ClientIP     string `json:"client_ip"`               // 用户访问源ip
+	EmailAddress string `json:"email_address,omitempty"` // 用户邮箱地址
+	ExtendedInfo string `json:"extended_info,omitempty"` // 额外补充信息
+	IsTest       bool   `json:"is_test,omitempty"`       // 是否为测试调用，测试调用不计入风控统计
+}
+
+// UserRiskRankResponse represents the response of querying the risk rank of a user.
+type UserRiskRankResponse struct {
+	ErrCode  int    `json:"errcode"`
+	ErrMsg   string `json:"errmsg"`
+	RiskRank int    `json:"risk_rank"` // 用户风险等级，0~4，数值越大风险越高
+	UnionID  int64  `json:"unoin_id"`  // 唯一请求标识，标记单次请求
+}
+
+// LoginRequest represents the login of a new user of the Mini Program.
+type LoginRequest struct {
+	Code      string // wx.login 返回的登录凭证
+	PhoneCode string // 手机号快速验证组件返回的动态令牌，为空时不获取手机号
+	ClientIP  string // 用户访问源ip，用于风险等级查询
+	RiskScene int    // 风险等级查询场景值，默认为注册
+}
+
+// OnboardingResult is the result of the login of a new user.
+type OnboardingResult struct {
+	OpenID     string             // 用户唯一标识
+	UnionID    string             // 用户在开放平台的唯一标识符
+	SessionKey string             // 会话密钥
+	Phone      *vwxauth.PhoneInfo // 用户手机号信息，未传入 PhoneCode 时为空
+	RiskRank   int                // 用户风险等级，0~4，查询失败时为 -1
+	RiskErr    error              // 风险等级查询的错误，查询失败不影响登录
+}
+
+// GetUserPhoneNumber exchanges the code of the phone number button for the phone number of the user.
+func (c *Service) GetUserPhoneNumber(code string, opts ...vwx.RequestOption) (*vwxauth.PhoneInfo, error) {
+	return c.GetUserPhoneNumberContext(context.Background(), code, opts...)
+}
+
+// GetUserPhoneNumberContext is GetUserPhoneNumber with the given context.
+func (c *Service) GetUserPhoneNumberContext(ctx context.Context, code string, opts ...vwx.RequestOption) (*vwxauth.PhoneInfo, error) {
+	resp, err := wxapi.PostJSON[PhoneNumberResponse](ctx, c.call("get user phone number", getUserPhoneNumberURL, opts), map[string]string{"code": code})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.PhoneInfo == nil {
+		return nil, errors.New("get user phone number: no phone info returned")
+	}
+
+	return resp.PhoneInfo, nil
+}
+
+// GetUserRiskRank queries the risk rank of the user, the appid is filled with the client app if empty.
+func (c *Service) GetUserRiskRank(req *UserRiskRankRequest, opts ...vwx.RequestOption) (*UserRiskRankResponse, error) {
+	return c.GetUserRiskRankContext(context.Background(), req, opts...)
+}
+
+// GetUserRiskRankContext is GetUserRiskRank with the given context.
+func (c *Service) GetUserRiskRankContext(ctx context.Context, req *UserRiskRankRequest, opts ...vwx.RequestOption) (*UserRiskRankResponse, error) {
+	if req.AppID == "" {
+		withAppID := *req
+		withAppID.AppID = c.client.AppID
+		req = &withAppID
+	}
+
+	return wxapi.PostJSON[UserRiskRankResponse](ctx, c.call("get user risk rank", getUserRiskRankURL, opts), req)
+}
+
+// Login runs the login of a new user: it exchanges the login code for the session, the phone code
+// for the phone number if given, and queries the risk rank of the user. The login fails if the
+// session or the phone number can't be got, a failed risk rank query is reported in RiskErr.
+func (c *Service) Login(req *LoginRequest, opts ...vwx.RequestOption) (*OnboardingResult, error) {
+	return c.LoginContext(context.Background(), req, opts...)
+}
+
+// LoginContext is Login with the given context.
+func (c *Service) LoginContext(ctx context.Context, req *LoginRequest, opts ...vwx.RequestOption) (*OnboardingResult, error) {
+	session, err := c.authSvc.GetSessionKeyContext(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OnboardingResult{
+		OpenID:     session.OpenID,
+		UnionID:    session.UnionID,
+		SessionKey: session.SessionKey,
+		RiskRank:   -1,
+	}
+
+	riskReq := &UserRiskRankRequest{
+		OpenID:   session.OpenID,
+		Scene:    req.RiskScene,
+		ClientIP: req.ClientIP,
+	}
+
+	if req.PhoneCode != "" {
+		if result.Phone, err = c.GetUserPhoneNumberContext(ctx, req.PhoneCode, opts...); err != nil {
+			return nil, err
+		}
+
+		riskReq.MobileNo = result.Phone.PurePhoneNumber
+	}
+
+	risk, err := c.GetUserRiskRankContext(ctx, riskReq, opts...)
+	if err != nil {
+		c.client.Logger.Warnf("get user risk rank failed | request_id: %s | openid: %s | err: %v",
+			vwx.RequestIDFromContext(ctx), session.OpenID, err)
+		result.RiskErr = err
+
+		return result, nil
+	}
+
+	result.RiskRank = risk.RiskRank
+
+	return result, nil
+}
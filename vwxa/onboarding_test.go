@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestLogin(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	result, err := svc.Login(&LoginRequest{Code: "code1", PhoneCode: "phone-code", ClientIP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.OpenIDPrefix+"code1", result.OpenID)
+	assert.Equal(t, vwxmock.SessionKey, result.SessionKey)
+	assert.Equal(t, vwxmock.PurePhoneNumber, result.Phone.PurePhoneNumber)
+	assert.Equal(t, 0, result.RiskRank)
+	assert.NoError(t, result.RiskErr)
+
+	requests := server.Requests("/wxa/getuserriskrank")
+	assert.Len(t, requests, 1)
+	assert.JSONEq(t, `{"appid":"appid","openid":"mock-openid-code1","scene":0,"mobile_no":"13800138000","client_ip":"10.0.0.1"}`,
+		string(requests[0].Body))
+
+	// the phone number is optional
+	server.SetResponse("/wxa/getuserriskrank", map[string]any{"errcode": 0, "errmsg": "ok", "risk_rank": 3})
+
+	result, err = svc.Login(&LoginRequest{Code: "code2", ClientIP: "10.0.0.1", RiskScene: RiskSceneMarketing})
+	assert.NoError(t, err)
+	assert.Nil(t, result.Phone)
+	assert.Equal(t, 3, result.RiskRank)
+	assert.Len(t, server.Requests("/wxa/business/getuserphonenumber"), 1)
+
+	// a failed risk rank query doesn't fail the login
+	server.SetError("/wxa/getuserriskrank", 61010, "user is not registered")
+
+	result, err = svc.Login(&LoginRequest{Code: "code3", ClientIP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, -1, result.RiskRank)
+	assert.True(t, vwx.IsErrCode(result.RiskErr, 61010))
+
+	// while a failed session or phone number does
+	_, err = svc.Login(&LoginRequest{Code: "", ClientIP: "10.0.0.1"})
+	assert.True(t, vwx.IsErrCode(err, 40029))
+
+	server.SetError("/wxa/business/getuserphonenumber", 40029, "code 无效")
+
+	_, err = svc.Login(&LoginRequest{Code: "code4", PhoneCode: "expired", ClientIP: "10.0.0.1"})
+	assert.True(t, vwx.IsErrCode(err, 40029))
+}
+
+func TestLoginDedicatedTokens(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.AcceptAccessToken("sensitive-token")
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithEndpointTokenSource(vwx.StaticTokenSource("sensitive-token"), append(vwx.PhoneEndpoints, vwx.RiskEndpoints...)...))
+
+	_, err := NewService(client).Login(&LoginRequest{Code: "code1", PhoneCode: "phone-code", ClientIP: "10.0.0.1"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "sensitive-token", server.Requests("/wxa/business/getuserphonenumber")[0].Query.Get("access_token"))
+	assert.Equal(t, "sensitive-token", server.Requests("/wxa/getuserriskrank")[0].Query.Get("access_token"))
+}
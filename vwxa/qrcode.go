@@ -19,47 +19,282 @@ package vwxa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
 )
 
 const (
 	generateCodeUnlimitURL = "https://api.weixin.qq.com/wxa/getwxacodeunlimit?access_token="
+	getWxaCodeURL          = "https://api.weixin.qq.com/wxa/getwxacode?access_token="
+	createWxaQRCodeURL     = "https://api.weixin.qq.com/cgi-bin/wxaapp/createwxaqrcode?access_token="
+
+	// MaxWxaCodePathLength is the max length of the page path of the limited QR codes.
+	MaxWxaCodePathLength = 1024
+
+	// MaxWxaQRCodePathLength is the max length of the page path of the classic QR codes.
+	MaxWxaQRCodePathLength = 128
+
+	// ErrCodeQRCodeTransient is returned by getwxacodeunlimit intermittently, e.g. during WeChat deployments.
+	ErrCodeQRCodeTransient = 85074
 )
 
+// defaultQRCodeRetryableCodes are the errcodes of the QR code apis retried besides system busy,
+// parameter errors (e.g. 40097, 41030) fail permanently and are not retried.
+var defaultQRCodeRetryableCodes = []int{ErrCodeQRCodeTransient}
+
+// WithQRCodeRetryableCodes replaces the errcodes of the QR code apis retried by the retry policy
+// of the client besides system busy, 85074 by default. Calls exceeding the quota are never retried.
+func WithQRCodeRetryableCodes(codes ...int) func(*Service) {
+	return func(s *Service) {
+		s.qrCodeRetryableCodes = codes
+	}
+}
+
 // GenerateQRCode generates QR code for WeChat Mini Program with specified scene and page.
-func (c *Service) GenerateQRCode(scene, page string) ([]byte, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
+func (c *Service) GenerateQRCode(scene, page string, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GenerateQRCodeContext(context.Background(), scene, page, opts...)
+}
+
+// GenerateQRCodeContext is GenerateQRCode with the given context.
+func (c *Service) GenerateQRCodeContext(ctx context.Context, scene, page string, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GenerateUnlimitedQRCodeContext(ctx, &QRCodeUnlimitedRequest{Scene: scene, Page: page}, opts...)
+}
+
+// LineColor is the rgb color of the lines of a QR code.
+type LineColor struct {
+	R int `json:"r"`
+	G int `json:"g"`
+	B int `json:"b"`
+}
+
+// QRCodeUnlimitedRequest represents a request to generate an unlimited QR code of a scene.
+type QRCodeUnlimitedRequest struct {
+	Scene      string     `json:"scene"`                 // 场景值，最大32个可见字符
+	Page       string     `json:"page,omitempty"`        // 已发布小程序的页面，根路径前不要填加/，默认主页
+	CheckPath  *bool      `json:"check_path,omitempty"`  // 是否检查page是否存在，默认取环境配置
+	EnvVersion string     `json:"env_version,omitempty"` // 要打开的小程序版本：release、trial、develop，默认取环境配置
+	Width      int        `json:"width,omitempty"`       // 二维码的宽度，单位px，最小280px，最大1280px，默认430
+	AutoColor  bool       `json:"auto_color,omitempty"`  // 自动配置线条颜色，为true时line_color无效
+	LineColor  *LineColor `json:"line_color,omitempty"`  // auto_color为false时生效，默认黑色
+	IsHyaline  bool       `json:"is_hyaline,omitempty"`  // 是否需要透明底色
+}
+
+// GenerateUnlimitedQRCode generates the unlimited QR code of the request, check_path and the env
+// version default to the env profile of the call.
+func (c *Service) GenerateUnlimitedQRCode(req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GenerateUnlimitedQRCodeContext(context.Background(), req, opts...)
+}
+
+// GenerateUnlimitedQRCodeContext is GenerateUnlimitedQRCode with the given context.
+func (c *Service) GenerateUnlimitedQRCodeContext(ctx context.Context, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) ([]byte, error) {
+	resp, requestID, err := c.postQRCode(ctx, req, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	url := generateCodeUnlimitURL + accessToken
+	return c.readImage(resp, generateCodeUnlimitURL, requestID)
+}
+
+// GenerateQRCodeTo generates the unlimited QR code of the request like GenerateUnlimitedQRCode,
+// streaming the image to w instead of buffering it, e.g. to an http response or a file. It returns
+// the bytes written, nothing is written if WeChat answers an error.
+func (c *Service) GenerateQRCodeTo(w io.Writer, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) (int64, error) {
+	return c.GenerateQRCodeToContext(context.Background(), w, req, opts...)
+}
 
-	params := map[string]interface{}{
-		"scene":       scene,
-		"page":        page,
-		"check_path":  false,
-		"env_version": c.client.EnvVersion,
+// GenerateQRCodeToContext is GenerateQRCodeTo with the given context.
+func (c *Service) GenerateQRCodeToContext(ctx context.Context, w io.Writer, req *QRCodeUnlimitedRequest, opts ...vwx.RequestOption) (int64, error) {
+	resp, requestID, err := c.postQRCode(ctx, req, opts)
+	if err != nil {
+		return 0, err
 	}
 
-	jsonData, err := json.Marshal(params)
+	media, err := c.openImage(resp, generateCodeUnlimitURL, requestID)
+	if err != nil {
+		return 0, err
+	}
+	defer c.closeImage(media)
+
+	return io.Copy(w, media)
+}
+
+// WxaCodeRequest represents a request to generate a limited QR code of a page path, at most
+// 100,000 codes can be generated for an app in total.
+type WxaCodeRequest struct {
+	Path       string     `json:"path"`                  // 扫码进入的小程序页面路径，可带参数，最大长度1024个字符
+	Width      int        `json:"width,omitempty"`       // 二维码的宽度，单位px，最小280px，最大1280px，默认430
+	AutoColor  bool       `json:"auto_color,omitempty"`  // 自动配置线条颜色，为true时line_color无效
+	LineColor  *LineColor `json:"line_color,omitempty"`  // auto_color为false时生效，默认黑色
+	IsHyaline  bool       `json:"is_hyaline,omitempty"`  // 是否需要透明底色
+	EnvVersion string     `json:"env_version,omitempty"` // 要打开的小程序版本：release、trial、develop，默认取环境配置
+}
+
+// GetWxaCode generates the limited QR code of the page path with the default style.
+func (c *Service) GetWxaCode(path string, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GetWxaCodeContext(context.Background(), path, opts...)
+}
+
+// GetWxaCodeContext is GetWxaCode with the given context.
+func (c *Service) GetWxaCodeContext(ctx context.Context, path string, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GenerateWxaCodeContext(ctx, &WxaCodeRequest{Path: path}, opts...)
+}
+
+// GenerateWxaCode generates the limited QR code of the request, the env version defaults to the
+// env profile of the call.
+func (c *Service) GenerateWxaCode(req *WxaCodeRequest, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.GenerateWxaCodeContext(context.Background(), req, opts...)
+}
+
+// GenerateWxaCodeContext is GenerateWxaCode with the given context.
+func (c *Service) GenerateWxaCodeContext(ctx context.Context, req *WxaCodeRequest, opts ...vwx.RequestOption) ([]byte, error) {
+	if req.Path == "" || len(req.Path) > MaxWxaCodePathLength {
+		return nil, fmt.Errorf("wxa code path must be 1 to %d characters, got %d", MaxWxaCodePathLength, len(req.Path))
+	}
+
+	if req.EnvVersion == "" {
+		withEnv := *req
+		withEnv.EnvVersion = c.envProfile(opts).EnvVersion
+		req = &withEnv
+	}
+
+	resp, requestID, err := c.postImage(ctx, "generate wxa code", getWxaCodeURL, req, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	return c.readImage(resp, getWxaCodeURL, requestID)
+}
+
+// CreateWxaQRCode generates the classic square QR code of the page path, sharing the quota of
+// 100,000 codes with the limited QR codes, width 0 keeps the default 430px.
+func (c *Service) CreateWxaQRCode(path string, width int, opts ...vwx.RequestOption) ([]byte, error) {
+	return c.CreateWxaQRCodeContext(context.Background(), path, width, opts...)
+}
+
+// CreateWxaQRCodeContext is CreateWxaQRCode with the given context.
+func (c *Service) CreateWxaQRCodeContext(ctx context.Context, path string, width int, opts ...vwx.RequestOption) ([]byte, error) {
+	if path == "" || len(path) > MaxWxaQRCodePathLength {
+		return nil, fmt.Errorf("wxa qrcode path must be 1 to %d characters, got %d", MaxWxaQRCodePathLength, len(path))
+	}
+
+	params := map[string]any{"path": path}
+	if width > 0 {
+		params["width"] = width
+	}
+
+	resp, requestID, err := c.postImage(ctx, "create wxa qrcode", createWxaQRCodeURL, params, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
+
+	return c.readImage(resp, createWxaQRCodeURL, requestID)
+}
+
+// readImage reads the image of the response of the api and closes its body.
+func (c *Service) readImage(resp *http.Response, apiURL, requestID string) ([]byte, error) {
+	media, err := c.openImage(resp, apiURL, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeImage(media)
+
+	return io.ReadAll(media)
+}
+
+// openImage opens the image of the response of the api limited to the max response size, the
+// caller closes the media. Errors are answered in json, maybe declared as an image or without
+// content type, they are detected by the content and returned as the WeChat error instead of the
+// image, the body is closed then.
+func (c *Service) openImage(resp *http.Response, apiURL, requestID string) (*Media, error) {
+	media, err := c.openLimitedMedia(resp, apiURL, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(media.ContentType, "image/") {
+		var endpoint string
+		if resp.Request != nil {
+			endpoint = resp.Request.URL.Path
+		}
+
+		c.closeImage(media)
+
+		return nil, &vwx.ResponseError{
+			Endpoint:    endpoint,
+			StatusCode:  resp.StatusCode,
+			ContentType: media.ContentType,
+			Err:         vwx.ErrUnexpectedContentType,
 		}
-	}()
+	}
+
+	return media, nil
+}
+
+// closeImage closes the image media, logging the close error.
+func (c *Service) closeImage(media *Media) {
+	if err := media.Close(); err != nil {
+		c.client.Logger.Errorf("failed to close response body | err: %v", err)
+	}
+}
+
+// postQRCode requests the unlimited QR code of the request, the caller closes the response body.
+// The call options are applied until the body is closed.
+func (c *Service) postQRCode(ctx context.Context, req *QRCodeUnlimitedRequest, opts []vwx.RequestOption) (*http.Response, string, error) {
+	profile := c.envProfile(opts)
+
+	params := *req
+	if params.CheckPath == nil {
+		params.CheckPath = &profile.CheckPath
+	}
+
+	if params.EnvVersion == "" {
+		params.EnvVersion = profile.EnvVersion
+	}
+
+	return c.postImage(ctx, "generate qrcode", generateCodeUnlimitURL, &params, opts)
+}
+
+// postImage posts the params to the image api, the caller closes the response body.
+// The call options are applied until the body is closed.
+func (c *Service) postImage(ctx context.Context, name, apiURL string, params any, opts []vwx.RequestOption) (*http.Response, string, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := c.accessToken(ctx, apiURL, opts)
+	if err != nil {
+		return nil, requestID, err
+	}
+
+	url := apiURL + accessToken
+
+	jsonData, err := json.Marshal(params)
+	if err != nil {
+		return nil, requestID, err
+	}
+
+	c.client.Logger.Infof("%s | request_id: %s | req: %s", name, requestID, jsonData)
+
+	codes := c.qrCodeRetryableCodes
+	if codes == nil {
+		codes = defaultQRCodeRetryableCodes
+	}
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+
+	resp, err := c.client.Post(vwx.ContextWithRetryableCodes(vwx.Idempotent(ctx), codes...), url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		cancel()
+		return nil, requestID, err
+	}
+
+	// the timeout of the call options bounds the read of the image by the caller
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 
-	return io.ReadAll(resp.Body)
+	return resp, requestID, nil
 }
@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/vogo/vwx"
+)
+
+// QRCodeStorage is the object storage where generated QR codes are uploaded,
+// the implementation (e.g. S3, OSS) signs requests with the caller's credentials.
+type QRCodeStorage interface {
+	// Lookup returns the url of the object stored under the key, or empty if it doesn't exist.
+	Lookup(ctx context.Context, key string) (string, error)
+	// Upload stores the data under the key and returns its url.
+	Upload(ctx context.Context, key string, data []byte, contentType string) (string, error)
+}
+
+// QRCodeAssetManager generates offline QR codes, uploads them to object storage and
+// returns stable urls, deduplicating by the hash of scene and page.
+type QRCodeAssetManager struct {
+	generator QRCodeGenerator
+	storage   QRCodeStorage
+
+	KeyPrefix string     // 对象存储 key 前缀，建议包含 appid 和环境，如 "qrcode/wx123/release/"
+	Logger    vwx.Logger // 日志，默认vlog
+
+	mu   sync.Mutex
+	urls map[string]string // key -> url
+}
+
+func (m *QRCodeAssetManager) logger() vwx.Logger {
+	if m.Logger != nil {
+		return m.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+// NewQRCodeAssetManager creates a QR code asset manager.
+func NewQRCodeAssetManager(generator QRCodeGenerator, storage QRCodeStorage, options ...func(*QRCodeAssetManager)) *QRCodeAssetManager {
+	m := &QRCodeAssetManager{
+		generator: generator,
+		storage:   storage,
+		KeyPrefix: "qrcode/",
+		urls:      make(map[string]string),
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// WithQRCodeKeyPrefix sets the object key prefix of the QR codes.
+func WithQRCodeKeyPrefix(prefix string) func(*QRCodeAssetManager) {
+	return func(m *QRCodeAssetManager) {
+		m.KeyPrefix = prefix
+	}
+}
+
+// Key returns the stable object key of the QR code of the scene and page.
+func (m *QRCodeAssetManager) Key(scene, page string) string {
+	sum := sha256.Sum256([]byte(page + "\n" + scene))
+	return m.KeyPrefix + hex.EncodeToString(sum[:16]) + ".png"
+}
+
+// URL returns the url of the QR code of the scene and page, the QR code is generated
+// and uploaded only if it doesn't exist in the storage yet.
+//
+// Concurrent calls for the same scene may generate the QR code more than once,
+// which is harmless since they upload the same content to the same key.
+func (m *QRCodeAssetManager) URL(scene, page string) (string, error) {
+	key := m.Key(scene, page)
+
+	m.mu.Lock()
+	url, ok := m.urls[key]
+	m.mu.Unlock()
+	if ok {
+		return url, nil
+	}
+
+	ctx := context.Background()
+
+	url, err := m.storage.Lookup(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("lookup qrcode %s error: %w", key, err)
+	}
+
+	if url == "" {
+		data, err := m.generator.GenerateQRCode(scene, page)
+		if err != nil {
+			return "", fmt.Errorf("generate qrcode error: %w", err)
+		}
+
+		contentType := http.DetectContentType(data)
+		if !strings.HasPrefix(contentType, "image/") {
+			return "", fmt.Errorf("generate qrcode error: unexpected content: %s", data)
+		}
+
+		url, err = m.storage.Upload(ctx, key, data, contentType)
+		if err != nil {
+			return "", fmt.Errorf("upload qrcode %s error: %w", key, err)
+		}
+
+		m.logger().Infof("upload qrcode | scene: %s | page: %s | key: %s | url: %s", scene, page, key, url)
+	}
+
+	m.mu.Lock()
+	m.urls[key] = url
+	m.mu.Unlock()
+
+	return url, nil
+}
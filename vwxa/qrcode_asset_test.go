@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+type memQRCodeStorage struct {
+	objects map[string][]byte
+	uploads int
+}
+
+func (s *memQRCodeStorage) Lookup(_ context.Context, key string) (string, error) {
+	if _, ok := s.objects[key]; ok {
+		return "https://cdn.example.com/" + key, nil
+	}
+	return "", nil
+}
+
+func (s *memQRCodeStorage) Upload(_ context.Context, key string, data []byte, _ string) (string, error) {
+	s.uploads++
+	s.objects[key] = data
+	return "https://cdn.example.com/" + key, nil
+}
+
+type fakeQRCodeGenerator struct {
+	data  []byte
+	err   error
+	calls int
+}
+
+func (g *fakeQRCodeGenerator) GenerateQRCode(scene, page string, opts ...vwx.RequestOption) ([]byte, error) {
+	return g.GenerateQRCodeContext(context.Background(), scene, page, opts...)
+}
+
+func (g *fakeQRCodeGenerator) GenerateQRCodeContext(context.Context, string, string, ...vwx.RequestOption) ([]byte, error) {
+	g.calls++
+	return g.data, g.err
+}
+
+func TestQRCodeAssetManager(t *testing.T) {
+	storage := &memQRCodeStorage{objects: make(map[string][]byte)}
+	generator := &fakeQRCodeGenerator{data: []byte("\x89PNG\r\n\x1a\nqrcode")}
+	m := NewQRCodeAssetManager(generator, storage, WithQRCodeKeyPrefix("qr/"))
+
+	url, err := m.URL("id=1", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/"+m.Key("id=1", "pages/index"), url)
+	assert.Contains(t, url, "/qr/")
+
+	again, err := m.URL("id=1", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, url, again)
+	assert.Equal(t, 1, generator.calls)
+	assert.Equal(t, 1, storage.uploads)
+
+	// already uploaded by another instance
+	other := NewQRCodeAssetManager(generator, storage, WithQRCodeKeyPrefix("qr/"))
+	_, err = other.URL("id=1", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, generator.calls)
+
+	assert.NotEqual(t, m.Key("id=1", "pages/index"), m.Key("id=2", "pages/index"))
+
+	generator.data = []byte(`{"errcode":40001,"errmsg":"invalid credential"}`)
+	_, err = m.URL("id=2", "pages/index")
+	assert.Error(t, err)
+
+	generator.err = errors.New("network error")
+	_, err = m.URL("id=3", "pages/index")
+	assert.Error(t, err)
+	assert.Equal(t, 1, storage.uploads)
+}
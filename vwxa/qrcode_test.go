@@ -0,0 +1,232 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestGenerateWxaCode(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	image, err := svc.GetWxaCode("pages/index?id=1")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+
+	image, err = svc.GenerateWxaCode(&WxaCodeRequest{
+		Path:      "pages/index?id=2",
+		Width:     280,
+		LineColor: &LineColor{R: 255},
+		IsHyaline: true,
+	}, vwx.WithEnv("trial"))
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+
+	requests := server.Requests("/wxa/getwxacode")
+	assert.Len(t, requests, 2)
+	assert.JSONEq(t, `{"path":"pages/index?id=1","env_version":"release"}`, string(requests[0].Body))
+	assert.JSONEq(t, `{"path":"pages/index?id=2","width":280,"line_color":{"r":255,"g":0,"b":0},"is_hyaline":true,"env_version":"trial"}`,
+		string(requests[1].Body))
+
+	// the path is checked before the call
+	_, err = svc.GetWxaCode(strings.Repeat("a", MaxWxaCodePathLength+1))
+	assert.Error(t, err)
+	assert.Len(t, server.Requests("/wxa/getwxacode"), 2)
+
+	// errors are never taken as the image
+	_, err = svc.GenerateWxaCode(&WxaCodeRequest{Path: "pages/index", Width: 100})
+	assert.True(t, vwx.IsErrCode(err, 40097))
+}
+
+func TestCreateWxaQRCode(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	image, err := svc.CreateWxaQRCode("pages/index?id=1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+
+	_, err = svc.CreateWxaQRCode("pages/index?id=2", 430)
+	assert.NoError(t, err)
+
+	requests := server.Requests("/cgi-bin/wxaapp/createwxaqrcode")
+	assert.Len(t, requests, 2)
+	assert.JSONEq(t, `{"path":"pages/index?id=1"}`, string(requests[0].Body))
+	assert.JSONEq(t, `{"path":"pages/index?id=2","width":430}`, string(requests[1].Body))
+
+	_, err = svc.CreateWxaQRCode(strings.Repeat("a", MaxWxaQRCodePathLength+1), 0)
+	assert.Error(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/wxaapp/createwxaqrcode"), 2)
+}
+
+func TestGenerateUnlimitedQRCode(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	checkPath := true
+	image, err := svc.GenerateUnlimitedQRCode(&QRCodeUnlimitedRequest{
+		Scene:     "id=1",
+		Page:      "pages/index",
+		CheckPath: &checkPath,
+		Width:     1280,
+		AutoColor: true,
+		IsHyaline: true,
+	}, vwx.WithEnv(vwx.EnvTrial))
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+
+	_, err = svc.GenerateUnlimitedQRCode(&QRCodeUnlimitedRequest{
+		Scene:      "id=2",
+		EnvVersion: vwx.EnvDevelop,
+		LineColor:  &LineColor{B: 255},
+	})
+	assert.NoError(t, err)
+
+	requests := server.Requests("/wxa/getwxacodeunlimit")
+	assert.Len(t, requests, 2)
+	assert.JSONEq(t, `{"scene":"id=1","page":"pages/index","check_path":true,"env_version":"trial","width":1280,"auto_color":true,"is_hyaline":true}`,
+		string(requests[0].Body))
+	assert.JSONEq(t, `{"scene":"id=2","check_path":false,"env_version":"develop","line_color":{"r":0,"g":0,"b":255}}`,
+		string(requests[1].Body))
+}
+
+func TestGenerateQRCodeTo(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	var buf bytes.Buffer
+	n, err := svc.GenerateQRCodeTo(&buf, &QRCodeUnlimitedRequest{Scene: "id=1", Page: "pages/index"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(vwxmock.PNGHeader)), n)
+	assert.Equal(t, vwxmock.PNGHeader, buf.Bytes())
+	assert.JSONEq(t, `{"scene":"id=1","page":"pages/index","check_path":false,"env_version":"release"}`,
+		string(server.Requests("/wxa/getwxacodeunlimit")[0].Body))
+
+	// nothing is written for errors
+	buf.Reset()
+	server.SetRawResponse("/wxa/getwxacodeunlimit", "image/jpeg", []byte(`{"errcode":41030,"errmsg":"invalid page"}`))
+	n, err = svc.GenerateQRCodeTo(&buf, &QRCodeUnlimitedRequest{Scene: "id=1", Page: "pages/missing"})
+	assert.True(t, vwx.IsErrCode(err, vwx.ErrCodeInvalidPage))
+	assert.Zero(t, n)
+	assert.Zero(t, buf.Len())
+}
+
+func TestQRCodeErrorPayload(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	payload := []byte(`{"errcode":41030,"errmsg":"invalid page rid: 6543a1b2-1c2d3e4f-5a6b7c8d"}`)
+
+	// json errors are detected whatever the declared content type
+	for _, contentType := range []string{"application/json", "text/plain", "image/jpeg", ""} {
+		server.SetRawResponse("/wxa/getwxacodeunlimit", contentType, payload)
+
+		image, err := svc.GenerateQRCode("scene", "pages/missing")
+		assert.Nil(t, image, contentType)
+
+		apiErr, ok := vwx.AsAPIError(err)
+		assert.True(t, ok, contentType)
+		assert.Equal(t, vwx.ErrCodeInvalidPage, apiErr.Code)
+		assert.Equal(t, "/wxa/getwxacodeunlimit", apiErr.Endpoint)
+		assert.Equal(t, "6543a1b2-1c2d3e4f-5a6b7c8d", apiErr.RID)
+	}
+
+	// rate limits keep their retry hint
+	server.SetRawResponse("/wxa/getwxacodeunlimit", "application/json", []byte(`{"errcode":45009,"errmsg":"quota"}`))
+	_, err := svc.GenerateQRCode("scene", "pages/index")
+	var rateLimited *RateLimitedError
+	assert.True(t, errors.As(err, &rateLimited))
+
+	// other non-image content is rejected
+	server.SetRawResponse("/wxa/getwxacodeunlimit", "text/html", []byte("<html>bad gateway</html>"))
+	_, err = svc.GenerateQRCode("scene", "pages/index")
+	assert.ErrorIs(t, err, vwx.ErrUnexpectedContentType)
+
+	// images without content type are sniffed
+	server.SetRawResponse("/wxa/getwxacodeunlimit", "", vwxmock.PNGHeader)
+	image, err := svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+}
+
+func TestQRCodeRetry(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	var failures sync.Map
+	failOnce := func(req *http.Request, next vwx.RoundTrip) (*http.Response, error) {
+		if req.URL.Path != "/wxa/getwxacodeunlimit" {
+			return next(req)
+		}
+
+		code, _ := failures.LoadAndDelete("code")
+		if code == nil {
+			return next(req)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"errcode":%d,"errmsg":"failed"}`, code))),
+			Request:    req,
+		}, nil
+	}
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithInterceptor(failOnce),
+		vwx.WithRetryPolicy(&vwx.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	svc := NewService(client)
+
+	failures.Store("code", ErrCodeQRCodeTransient)
+	code, err := svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(code, vwxmock.PNGHeader))
+
+	// parameter errors are not retried, the json answered is returned as the WeChat error
+	failures.Store("code", 40097)
+	_, err = svc.GenerateQRCode("scene", "pages/index")
+	assert.True(t, vwx.IsErrCode(err, 40097))
+
+	svc = NewService(client, WithQRCodeRetryableCodes(40097))
+	failures.Store("code", 40097)
+	code, err = svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(code, vwxmock.PNGHeader))
+}
@@ -18,18 +18,74 @@
 package vwxa
 
 import (
+	"context"
+	"errors"
+
 	"github.com/vogo/vwx"
 	"github.com/vogo/vwx/vwxauth"
 )
 
 type Service struct {
-	client  *vwx.Client
-	authSvc *vwxauth.Service
+	client         *vwx.Client
+	authSvc        *vwxauth.Service
+	moderationSink ModerationSink
+
+	qrCodeRetryableCodes []int
+	responseCaptureLimit int
 }
 
-func NewService(client *vwx.Client) *Service {
-	return &Service{
+func NewService(client *vwx.Client, options ...func(*Service)) *Service {
+	s := &Service{
 		client:  client,
 		authSvc: vwxauth.NewService(client),
 	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// NewSecretFreeService creates a service for edge deployments on a client created by
+// vwx.NewSecretFreeClient, only the operations using the supplied access tokens are exposed.
+func NewSecretFreeService(client *vwx.Client) (SecretFreeService, error) {
+	if !client.SecretFree() {
+		return nil, errors.New("secret-free service requires a client without app secret")
+	}
+
+	if client.TokenProvider() == nil {
+		return nil, errors.New("secret-free service requires a token source or an access token provider")
+	}
+
+	return NewService(client), nil
+}
+
+// accessToken returns the access token of the api url overridden by the call options, supplied by the
+// token source of its endpoint group, or acquired by the client.
+func (c *Service) accessToken(ctx context.Context, apiURL string, opts []vwx.RequestOption) (string, error) {
+	if o := vwx.NewRequestOptions(opts...); o.AccessToken != "" {
+		return o.AccessToken, nil
+	}
+
+	if ts := c.client.TokenSourceFor(apiURL); ts != nil {
+		return ts.Token(ctx)
+	}
+
+	return c.authSvc.GetAccessTokenContext(ctx)
+}
+
+// envProfile returns the env profile switched by the call options, or the profile of the client env.
+func (c *Service) envProfile(opts []vwx.RequestOption) *vwx.EnvProfile {
+	return c.client.EnvProfile(vwx.NewRequestOptions(opts...).Env)
+}
+
+// ForceRefreshAccessToken discards the cached access token, fetches a fresh one, caches and returns it.
+func (c *Service) ForceRefreshAccessToken() (string, error) {
+	return c.ForceRefreshAccessTokenContext(context.Background())
+}
+
+// ForceRefreshAccessTokenContext is ForceRefreshAccessToken with the given context.
+func (c *Service) ForceRefreshAccessTokenContext(ctx context.Context) (string, error) {
+	return c.authSvc.ForceRefreshAccessTokenContext(ctx)
 }
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestSecretFreeClient(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewSecretFreeClient("appid", vwx.StaticTokenSource(vwxmock.AccessToken), vwxmock.WithServer(server))
+
+	svc, err := NewSecretFreeService(client)
+	assert.NoError(t, err)
+
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.Empty(t, server.Requests("/cgi-bin/token"))
+
+	_, err = vwxauth.NewService(client).GetSessionKey("code")
+	assert.ErrorIs(t, err, vwx.ErrAppSecretRequired)
+	assert.Empty(t, server.Requests("/sns/jscode2session"))
+
+	_, err = NewSecretFreeService(vwx.NewClient("appid", "secret"))
+	assert.Error(t, err)
+
+	_, err = NewSecretFreeService(vwx.NewSecretFreeClient("appid", nil))
+	assert.Error(t, err)
+}
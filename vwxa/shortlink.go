@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	generateShortLinkURL = "https://api.weixin.qq.com/wxa/genwxashortlink?access_token=%s"
+
+	// MaxShortLinkPageURLLength is the max length of the page url of a Short Link.
+	MaxShortLinkPageURLLength = 1024
+)
+
+// ShortLinkRequest represents the request of generating a Short Link.
+type ShortLinkRequest struct {
+	PageURL     string `json:"page_url"`               // 通过 Short Link 进入的小程序页面路径，必须是已经发布的小程序存在的页面，可携带 query，最大1024个字符
+	PageTitle   string `json:"page_title,omitempty"`   // 页面标题，不能包含违法信息，超过20字符会用... 截断代替
+	IsPermanent bool   `json:"is_permanent,omitempty"` // 生成的 Short Link 类型，短期有效：false，永久有效：true
+}
+
+// ShortLinkResponse represents the response of generating a Short Link.
+type ShortLinkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Link    string `json:"link"` // 生成的小程序 Short Link
+}
+
+// GenerateShortLink generates a Short Link (短链) of the page opening the mini program from WeChat
+// chats, titles over 20 characters are truncated by WeChat. Permanent links are limited to 100,000 per app while temporary ones expire in 30 days.
+func (c *Service) GenerateShortLink(pageURL, pageTitle string, isPermanent bool, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateShortLinkContext(context.Background(), pageURL, pageTitle, isPermanent, opts...)
+}
+
+// GenerateShortLinkContext is GenerateShortLink with the given context.
+func (c *Service) GenerateShortLinkContext(ctx context.Context, pageURL, pageTitle string, isPermanent bool, opts ...vwx.RequestOption) (string, error) {
+	if pageURL == "" || len(pageURL) > MaxShortLinkPageURLLength {
+		return "", fmt.Errorf("short link page url must be 1 to %d characters, got %d", MaxShortLinkPageURLLength, len(pageURL))
+	}
+
+	resp, err := wxapi.PostJSON[ShortLinkResponse](ctx, c.call("generate short link", generateShortLinkURL, opts), &ShortLinkRequest{
+		PageURL:     pageURL,
+		PageTitle:   pageTitle,
+		IsPermanent: isPermanent,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Link, nil
+}
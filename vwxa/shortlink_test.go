@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestGenerateShortLink(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	link, err := svc.GenerateShortLink("pages/goods/index?id=42", "商品详情", true)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, "#小程序://"))
+
+	requests := server.Requests("/wxa/genwxashortlink")
+	assert.Len(t, requests, 1)
+	assert.JSONEq(t, `{"page_url":"pages/goods/index?id=42","page_title":"商品详情","is_permanent":true}`, string(requests[0].Body))
+
+	_, err = svc.GenerateShortLink("pages/index", "", false)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"page_url":"pages/index"}`, string(server.Requests("/wxa/genwxashortlink")[1].Body))
+
+	_, err = svc.GenerateShortLink("", "", false)
+	assert.Error(t, err)
+	assert.Len(t, server.Requests("/wxa/genwxashortlink"), 2)
+}
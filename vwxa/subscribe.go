@@ -18,14 +18,12 @@
 package vwxa
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"net/http"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
@@ -54,52 +52,42 @@ type SubscribeMessageResponse struct {
 }
 
 // SendSubscribeMessage sends a subscribe message to the specified user.
-func (c *Service) SendSubscribeMessage(request *SubscribeMessageRequest) (*SubscribeMessageResponse, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
-	if err != nil {
-		return nil, fmt.Errorf("get access token error: %v", err)
-	}
+func (c *Service) SendSubscribeMessage(request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return c.SendSubscribeMessageContext(context.Background(), request, opts...)
+}
 
-	url := fmt.Sprintf(subscribeMessageSendURL, accessToken)
+// SendSubscribeMessageContext is SendSubscribeMessage with the given context.
+func (c *Service) SendSubscribeMessageContext(ctx context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
 
-	data, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request error: %v", err)
+	// the env switched by the call options decides the version opened from the message
+	if env := vwx.NewRequestOptions(opts...).Env; env != "" && request.MiniProgramState == "" {
+		switched := *request
+		switched.MiniProgramState = c.client.EnvProfile(env).MiniProgramState
+		request = &switched
 	}
 
-	vlog.Infof("send subscribe message | req: %s", string(data))
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
-	if err != nil {
-		return nil, fmt.Errorf("send request error: %v", err)
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
+	if c.client.DryRun {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request error: %w", err)
 		}
-	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response error: %v", err)
+		c.client.Logger.Infof("dry run, skip send subscribe message | request_id: %s | req: %s", requestID, data)
+		return &SubscribeMessageResponse{ErrCode: 0, ErrMsg: "ok"}, nil
 	}
 
-	vlog.Infof("send subscribe message | resp: %s", string(body))
-
-	var response SubscribeMessageResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("unmarshal response error: %v", err)
-	}
-
-	if response.ErrCode != 0 {
-		return &response, errors.New(response.ErrMsg)
-	}
-
-	return &response, nil
+	return wxapi.PostJSON[SubscribeMessageResponse](ctx, c.call("send subscribe message", subscribeMessageSendURL, opts), request)
 }
 
 // SendSubscribeMessageSimple is a convenient method to send a subscribe message with simple data.
-func (c *Service) SendSubscribeMessageSimple(openID, templateID, page string, data map[string]string) (*SubscribeMessageResponse, error) {
+func (c *Service) SendSubscribeMessageSimple(openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return c.SendSubscribeMessageSimpleContext(context.Background(), openID, templateID, page, data, opts...)
+}
+
+// SendSubscribeMessageSimpleContext is SendSubscribeMessageSimple with the given context.
+func (c *Service) SendSubscribeMessageSimpleContext(ctx context.Context, openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
 	// 构建数据项
 	dataItems := make(map[string]*SubscribeMessageDataItem)
 	for k, v := range data {
@@ -115,5 +103,5 @@ func (c *Service) SendSubscribeMessageSimple(openID, templateID, page string, da
 	}
 
 	// 发送请求
-	return c.SendSubscribeMessage(request)
+	return c.SendSubscribeMessageContext(ctx, request, opts...)
 }
@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	SubscribeLangZhCN = "zh_CN" // 简体中文
+	SubscribeLangEnUS = "en_US" // 英文
+	SubscribeLangZhHK = "zh_HK" // 繁体中文（香港）
+	SubscribeLangZhTW = "zh_TW" // 繁体中文（台湾）
+)
+
+// UserLangStore stores the preferred language of users, e.g. reported by wx.getSystemInfo.
+type UserLangStore interface {
+	// GetLang returns the language of the user, empty if unknown.
+	GetLang(ctx context.Context, openID string) (string, error)
+	// SetLang stores the language of the user.
+	SetLang(ctx context.Context, openID, lang string) error
+}
+
+// MemoryUserLangStore is an in-memory UserLangStore, suitable for a single instance.
+type MemoryUserLangStore struct {
+	mu    sync.RWMutex
+	langs map[string]string
+}
+
+// NewMemoryUserLangStore creates an in-memory user language store.
+func NewMemoryUserLangStore() *MemoryUserLangStore {
+	return &MemoryUserLangStore{langs: make(map[string]string)}
+}
+
+// GetLang returns the language of the user, empty if unknown.
+func (m *MemoryUserLangStore) GetLang(_ context.Context, openID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.langs[openID], nil
+}
+
+// SetLang stores the language of the user.
+func (m *MemoryUserLangStore) SetLang(_ context.Context, openID, lang string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.langs[openID] = lang
+
+	return nil
+}
+
+// LocalizedSubscribeMessage is a subscribe message with the data values of each language.
+type LocalizedSubscribeMessage struct {
+	ToUser           string                       // 接收者（用户）的 openid
+	TemplateID       string                       // 所需下发的订阅消息的模板id
+	Page             string                       // 点击模板卡片后的跳转页面
+	MiniProgramState string                       // 跳转小程序类型：developer、trial、formal
+	Data             map[string]map[string]string // 语言 -> 模板关键词 -> 内容
+}
+
+// SubscribeMessageLocalizer sends subscribe messages in the stored language of the user.
+type SubscribeMessageLocalizer struct {
+	sender      SubscribeMessageSender
+	store       UserLangStore
+	DefaultLang string // 用户语言未知或无对应内容时使用的语言，默认为zh_CN
+}
+
+// NewSubscribeMessageLocalizer creates a localizer sending the messages by the sender, which can
+// be the Service, a TemplateValidator, or any other SubscribeMessageSender.
+func NewSubscribeMessageLocalizer(sender SubscribeMessageSender, store UserLangStore, options ...func(*SubscribeMessageLocalizer)) *SubscribeMessageLocalizer {
+	l := &SubscribeMessageLocalizer{
+		sender:      sender,
+		store:       store,
+		DefaultLang: SubscribeLangZhCN,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+// WithDefaultLang sets the language used if the language of the user is unknown or has no data.
+func WithDefaultLang(lang string) func(*SubscribeMessageLocalizer) {
+	return func(l *SubscribeMessageLocalizer) {
+		l.DefaultLang = lang
+	}
+}
+
+// Localize builds the subscribe message request in the language of the user, falling back to
+// the default language if the user language is unknown or not provided by the message.
+func (l *SubscribeMessageLocalizer) Localize(msg *LocalizedSubscribeMessage) (*SubscribeMessageRequest, error) {
+	lang, err := l.store.GetLang(context.Background(), msg.ToUser)
+	if err != nil {
+		return nil, fmt.Errorf("get user lang error: %w", err)
+	}
+
+	values, ok := msg.Data[lang]
+	if !ok {
+		lang = l.DefaultLang
+		values, ok = msg.Data[lang]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("no data of lang %s for template %s", lang, msg.TemplateID)
+	}
+
+	data := make(map[string]*SubscribeMessageDataItem, len(values))
+	for k, v := range values {
+		data[k] = &SubscribeMessageDataItem{Value: v}
+	}
+
+	return &SubscribeMessageRequest{
+		ToUser:           msg.ToUser,
+		TemplateID:       msg.TemplateID,
+		Page:             msg.Page,
+		Data:             data,
+		MiniProgramState: msg.MiniProgramState,
+		Lang:             lang,
+	}, nil
+}
+
+// Send localizes the message in the language of the user and sends it.
+func (l *SubscribeMessageLocalizer) Send(msg *LocalizedSubscribeMessage, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	request, err := l.Localize(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.sender.SendSubscribeMessage(request, opts...)
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+type recordSubscribeSender struct {
+	requests []*SubscribeMessageRequest
+}
+
+func (s *recordSubscribeSender) SendSubscribeMessage(request *SubscribeMessageRequest, _ ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	s.requests = append(s.requests, request)
+	return &SubscribeMessageResponse{}, nil
+}
+
+func (s *recordSubscribeSender) SendSubscribeMessageContext(_ context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return s.SendSubscribeMessage(request, opts...)
+}
+
+func (s *recordSubscribeSender) SendSubscribeMessageSimpleContext(context.Context, string, string, string, map[string]string, ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return &SubscribeMessageResponse{}, nil
+}
+
+func (s *recordSubscribeSender) SendSubscribeMessageSimple(string, string, string, map[string]string, ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return &SubscribeMessageResponse{}, nil
+}
+
+func TestSubscribeMessageLocalizer(t *testing.T) {
+	store := NewMemoryUserLangStore()
+	assert.NoError(t, store.SetLang(context.Background(), "openid-en", SubscribeLangEnUS))
+	assert.NoError(t, store.SetLang(context.Background(), "openid-tw", SubscribeLangZhTW))
+
+	sender := &recordSubscribeSender{}
+	localizer := NewSubscribeMessageLocalizer(sender, store)
+
+	msg := func(openID string) *LocalizedSubscribeMessage {
+		return &LocalizedSubscribeMessage{
+			ToUser:     openID,
+			TemplateID: "tmpl-1",
+			Data: map[string]map[string]string{
+				SubscribeLangZhCN: {"thing1": "订单已发货"},
+				SubscribeLangEnUS: {"thing1": "Order shipped"},
+			},
+		}
+	}
+
+	_, err := localizer.Send(msg("openid-en"))
+	assert.NoError(t, err)
+	_, err = localizer.Send(msg("openid-tw"))
+	assert.NoError(t, err)
+	_, err = localizer.Send(msg("openid-unknown"))
+	assert.NoError(t, err)
+
+	assert.Len(t, sender.requests, 3)
+	assert.Equal(t, SubscribeLangEnUS, sender.requests[0].Lang)
+	assert.Equal(t, "Order shipped", sender.requests[0].Data["thing1"].Value)
+	assert.Equal(t, SubscribeLangZhCN, sender.requests[1].Lang)
+	assert.Equal(t, "订单已发货", sender.requests[1].Data["thing1"].Value)
+	assert.Equal(t, SubscribeLangZhCN, sender.requests[2].Lang)
+
+	localizer = NewSubscribeMessageLocalizer(sender, store, WithDefaultLang(SubscribeLangZhHK))
+	_, err = localizer.Send(msg("openid-unknown"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,348 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	// SubscribeQuotaUnlimited is the quota of long-term templates accepted by the user.
+	SubscribeQuotaUnlimited = -1
+
+	subscribeMsgPopupEvent  = "subscribe_msg_popup_event"
+	subscribeMsgChangeEvent = "subscribe_msg_change_event"
+	subscribeMsgSentEvent   = "subscribe_msg_sent_event"
+
+	errCodeUserRefuse = vwx.ErrCodeUserRefused // 用户拒绝接受消息
+)
+
+// ErrSubscribeQuotaExhausted is returned when the user has no allowance left for the template.
+var ErrSubscribeQuotaExhausted = errors.New("subscribe message quota exhausted")
+
+// SubscribeMsgEventItem represents an item of the subscribe message popup, change or sent events.
+type SubscribeMsgEventItem struct {
+	TemplateID            string `xml:"TemplateId" json:"TemplateId"`                       // 模板id
+	SubscribeStatusString string `xml:"SubscribeStatusString" json:"SubscribeStatusString"` // 订阅结果：accept 接收，reject 拒收，ban 已被后台封禁，filter 已被过滤
+	PopupScene            string `xml:"PopupScene" json:"PopupScene"`                       // 弹框场景：0 小程序页面内，1 支付完成后，2 公众号文章
+	MsgID                 string `xml:"MsgID" json:"MsgID"`                                 // 消息id，仅发送结果事件
+	ErrorCode             string `xml:"ErrorCode" json:"ErrorCode"`                         // 推送结果状态码，0 表示成功，仅发送结果事件
+	ErrorStatus           string `xml:"ErrorStatus" json:"ErrorStatus"`                     // 推送结果状态码对应的含义，仅发送结果事件
+}
+
+type subscribeMsgEventList struct {
+	List []*SubscribeMsgEventItem `xml:"List"`
+}
+
+type subscribeMsgXMLEvent struct {
+	PopupEvent  subscribeMsgEventList `xml:"SubscribeMsgPopupEvent"`
+	ChangeEvent subscribeMsgEventList `xml:"SubscribeMsgChangeEvent"`
+	SentEvent   subscribeMsgEventList `xml:"SubscribeMsgSentEvent"`
+}
+
+// ParseSubscribeMsgEvent parses the items of the subscribe message popup, change or sent event
+// in xml or json format, json events carry a single item as an object or several as an array.
+func ParseSubscribeMsgEvent(data []byte) ([]*SubscribeMsgEventItem, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("<")) {
+		var event subscribeMsgXMLEvent
+		if err := xml.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("unmarshal subscribe message event error: %w", err)
+		}
+
+		items := append(event.PopupEvent.List, event.ChangeEvent.List...)
+		return append(items, event.SentEvent.List...), nil
+	}
+
+	var event struct {
+		List json.RawMessage `json:"List"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal subscribe message event error: %w", err)
+	}
+
+	list := bytes.TrimSpace(event.List)
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	if list[0] == '[' {
+		var items []*SubscribeMsgEventItem
+		if err := json.Unmarshal(list, &items); err != nil {
+			return nil, fmt.Errorf("unmarshal subscribe message event list error: %w", err)
+		}
+		return items, nil
+	}
+
+	var item SubscribeMsgEventItem
+	if err := json.Unmarshal(list, &item); err != nil {
+		return nil, fmt.Errorf("unmarshal subscribe message event list error: %w", err)
+	}
+
+	return []*SubscribeMsgEventItem{&item}, nil
+}
+
+// SubscribeQuotaStore stores the remaining send allowance per user and template.
+type SubscribeQuotaStore interface {
+	// Get returns the quota of the user and template, zero if absent.
+	Get(ctx context.Context, openID, templateID string) (int, error)
+	// Set overwrites the quota of the user and template.
+	Set(ctx context.Context, openID, templateID string, quota int) error
+	// Incr atomically adds delta to the quota and returns the new value.
+	Incr(ctx context.Context, openID, templateID string, delta int) (int, error)
+}
+
+// MemorySubscribeQuotaStore is an in-memory SubscribeQuotaStore, suitable for a single instance.
+type MemorySubscribeQuotaStore struct {
+	mu     sync.Mutex
+	quotas map[string]int
+}
+
+// NewMemorySubscribeQuotaStore creates an in-memory subscribe quota store.
+func NewMemorySubscribeQuotaStore() *MemorySubscribeQuotaStore {
+	return &MemorySubscribeQuotaStore{quotas: make(map[string]int)}
+}
+
+func quotaKey(openID, templateID string) string {
+	return openID + "\x00" + templateID
+}
+
+// Get implements SubscribeQuotaStore.
+func (s *MemorySubscribeQuotaStore) Get(_ context.Context, openID, templateID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.quotas[quotaKey(openID, templateID)], nil
+}
+
+// Set implements SubscribeQuotaStore.
+func (s *MemorySubscribeQuotaStore) Set(_ context.Context, openID, templateID string, quota int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotas[quotaKey(openID, templateID)] = quota
+	return nil
+}
+
+// Incr implements SubscribeQuotaStore.
+func (s *MemorySubscribeQuotaStore) Incr(_ context.Context, openID, templateID string, delta int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := quotaKey(openID, templateID)
+	s.quotas[key] += delta
+	return s.quotas[key], nil
+}
+
+// SubscribeQuotaTracker tracks the per-template send allowances of users by consuming
+// subscribe message push events, so that senders skip users whose quota is exhausted.
+//
+// Each accept of a one-time template grants one send, an accepted long-term template
+// can be sent until the user rejects it. Users who subscribed before the tracker was
+// deployed have no quota recorded.
+type SubscribeQuotaTracker struct {
+	store    SubscribeQuotaStore
+	longTerm map[string]bool
+
+	Logger vwx.Logger // 日志，默认vlog
+}
+
+func (t *SubscribeQuotaTracker) logger() vwx.Logger {
+	if t.Logger != nil {
+		return t.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+// NewSubscribeQuotaTracker creates a subscribe quota tracker on top of the store.
+func NewSubscribeQuotaTracker(store SubscribeQuotaStore, options ...func(*SubscribeQuotaTracker)) *SubscribeQuotaTracker {
+	t := &SubscribeQuotaTracker{
+		store:    store,
+		longTerm: make(map[string]bool),
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	return t
+}
+
+// WithLongTermTemplates marks the templates as long-term subscriptions.
+func WithLongTermTemplates(templateIDs ...string) func(*SubscribeQuotaTracker) {
+	return func(t *SubscribeQuotaTracker) {
+		for _, templateID := range templateIDs {
+			t.longTerm[templateID] = true
+		}
+	}
+}
+
+// Register registers the tracker for the subscribe message events of the dispatcher.
+func (t *SubscribeQuotaTracker) Register(d *vwxpush.Dispatcher) {
+	d.HandleEvent(subscribeMsgPopupEvent, t.HandleEvent)
+	d.HandleEvent(subscribeMsgChangeEvent, t.HandleEvent)
+	d.HandleEvent(subscribeMsgSentEvent, t.HandleEvent)
+}
+
+// HandleEvent updates the quotas from a subscribe message popup, change or sent event,
+// it matches vwxpush.MessageHandler.
+func (t *SubscribeQuotaTracker) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	items, err := ParseSubscribeMsgEvent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	openID := baseInfo.FromUserName
+	event := strings.ToLower(baseInfo.Event)
+
+	for _, item := range items {
+		if err := t.apply(ctx, event, openID, item); err != nil {
+			return nil, fmt.Errorf("update subscribe quota error: %w", err)
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *SubscribeQuotaTracker) apply(ctx context.Context, event, openID string, item *SubscribeMsgEventItem) error {
+	longTerm := t.longTerm[item.TemplateID]
+
+	t.logger().Infof("subscribe message event | event: %s | openid: %s | template: %s | status: %s | errcode: %s",
+		event, openID, item.TemplateID, item.SubscribeStatusString, item.ErrorCode)
+
+	switch event {
+	case subscribeMsgSentEvent:
+		if item.ErrorCode == strconv.Itoa(errCodeUserRefuse) {
+			return t.store.Set(ctx, openID, item.TemplateID, 0)
+		}
+		return nil
+	case subscribeMsgPopupEvent, subscribeMsgChangeEvent:
+	default:
+		return nil
+	}
+
+	switch item.SubscribeStatusString {
+	case "accept":
+		if longTerm {
+			return t.store.Set(ctx, openID, item.TemplateID, SubscribeQuotaUnlimited)
+		}
+		if event == subscribeMsgPopupEvent {
+			_, err := t.store.Incr(ctx, openID, item.TemplateID, 1)
+			return err
+		}
+	case "reject", "ban":
+		// a rejection in the popup doesn't revoke previously accepted one-time sends,
+		// while a rejection in the settings (change event) or a ban revokes all.
+		if longTerm || event == subscribeMsgChangeEvent || item.SubscribeStatusString == "ban" {
+			return t.store.Set(ctx, openID, item.TemplateID, 0)
+		}
+	}
+
+	return nil
+}
+
+// CanSend reports whether the user has allowance left for the template.
+func (t *SubscribeQuotaTracker) CanSend(openID, templateID string) (bool, error) {
+	quota, err := t.store.Get(context.Background(), openID, templateID)
+	if err != nil {
+		return false, err
+	}
+
+	return quota == SubscribeQuotaUnlimited || quota > 0, nil
+}
+
+// Consume consumes one allowance of the template, it returns false if the quota is exhausted.
+func (t *SubscribeQuotaTracker) Consume(openID, templateID string) (bool, error) {
+	ok, _, err := t.consume(context.Background(), openID, templateID)
+	return ok, err
+}
+
+// consume consumes one allowance of the template, charged reports whether a one-time allowance
+// was decremented, which is refunded if the send fails.
+func (t *SubscribeQuotaTracker) consume(ctx context.Context, openID, templateID string) (ok, charged bool, err error) {
+	quota, err := t.store.Get(ctx, openID, templateID)
+	if err != nil {
+		return false, false, err
+	}
+
+	if quota == SubscribeQuotaUnlimited {
+		return true, false, nil
+	}
+
+	if quota <= 0 {
+		return false, false, nil
+	}
+
+	quota, err = t.store.Incr(ctx, openID, templateID, -1)
+	if err != nil {
+		return false, false, err
+	}
+
+	if quota < 0 {
+		// consumed concurrently by another sender
+		return false, false, t.store.Set(ctx, openID, templateID, 0)
+	}
+
+	return true, true, nil
+}
+
+// SendSubscribeMessage consumes one allowance and sends the message, ErrSubscribeQuotaExhausted
+// is returned without calling WeChat if the quota is exhausted. The quota is reset if WeChat
+// reports the user refused (43101), and the allowance is refunded if the send fails otherwise.
+func (t *SubscribeQuotaTracker) SendSubscribeMessage(sender SubscribeMessageSender, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	ctx := context.Background()
+
+	ok, charged, err := t.consume(ctx, request.ToUser, request.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("consume subscribe quota error: %w", err)
+	}
+
+	if !ok {
+		return nil, ErrSubscribeQuotaExhausted
+	}
+
+	resp, err := sender.SendSubscribeMessage(request, opts...)
+
+	switch {
+	case resp != nil && resp.ErrCode == errCodeUserRefuse:
+		if setErr := t.store.Set(ctx, request.ToUser, request.TemplateID, 0); setErr != nil {
+			t.logger().Errorf("failed to reset subscribe quota | openid: %s | template: %s | err: %v",
+				request.ToUser, request.TemplateID, setErr)
+		}
+	case charged && (err != nil || resp == nil || resp.ErrCode != 0):
+		if _, incrErr := t.store.Incr(ctx, request.ToUser, request.TemplateID, 1); incrErr != nil {
+			t.logger().Errorf("failed to refund subscribe quota | openid: %s | template: %s | err: %v",
+				request.ToUser, request.TemplateID, incrErr)
+		}
+	}
+
+	return resp, err
+}
@@ -0,0 +1,141 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+type fakeSubscribeSender struct {
+	errCode int
+	calls   int
+}
+
+func (s *fakeSubscribeSender) SendSubscribeMessage(*SubscribeMessageRequest, ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	s.calls++
+	return &SubscribeMessageResponse{ErrCode: s.errCode}, nil
+}
+
+func (s *fakeSubscribeSender) SendSubscribeMessageContext(_ context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return s.SendSubscribeMessage(request, opts...)
+}
+
+func (s *fakeSubscribeSender) SendSubscribeMessageSimpleContext(context.Context, string, string, string, map[string]string, ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return s.SendSubscribeMessage(nil)
+}
+
+func (s *fakeSubscribeSender) SendSubscribeMessageSimple(string, string, string, map[string]string, ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return s.SendSubscribeMessage(nil)
+}
+
+func TestParseSubscribeMsgEvent(t *testing.T) {
+	xmlData := `<xml><ToUserName><![CDATA[gh_123]]></ToUserName><FromUserName><![CDATA[openid]]></FromUserName>
+<MsgType><![CDATA[event]]></MsgType><Event><![CDATA[subscribe_msg_popup_event]]></Event>
+<SubscribeMsgPopupEvent>
+<List><TemplateId><![CDATA[tmpl-1]]></TemplateId><SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString><PopupScene>0</PopupScene></List>
+<List><TemplateId><![CDATA[tmpl-2]]></TemplateId><SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString><PopupScene>0</PopupScene></List>
+</SubscribeMsgPopupEvent></xml>`
+
+	items, err := ParseSubscribeMsgEvent([]byte(xmlData))
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "tmpl-1", items[0].TemplateID)
+	assert.Equal(t, "reject", items[1].SubscribeStatusString)
+
+	items, err = ParseSubscribeMsgEvent([]byte(`{"Event":"subscribe_msg_popup_event","List":{"TemplateId":"tmpl-1","SubscribeStatusString":"accept","PopupScene":"2"}}`))
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "2", items[0].PopupScene)
+
+	items, err = ParseSubscribeMsgEvent([]byte(`{"Event":"subscribe_msg_sent_event","List":[{"TemplateId":"tmpl-1","MsgID":"1","ErrorCode":"43101","ErrorStatus":"fail"}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "43101", items[0].ErrorCode)
+}
+
+func TestSubscribeQuotaTracker(t *testing.T) {
+	tracker := NewSubscribeQuotaTracker(NewMemorySubscribeQuotaStore(), WithLongTermTemplates("tmpl-long"))
+
+	d := vwxpush.NewDispatcher()
+	tracker.Register(d)
+
+	push := func(event, list string) {
+		baseInfo := &vwxpush.PushBaseInfo{FromUserName: "openid", MsgType: "event", Event: event}
+		_, err := d.Dispatch("", baseInfo, []byte(`{"List":`+list+`}`))
+		assert.NoError(t, err)
+	}
+
+	ok, err := tracker.CanSend("openid", "tmpl-1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	push("subscribe_msg_popup_event", `[{"TemplateId":"tmpl-1","SubscribeStatusString":"accept"},{"TemplateId":"tmpl-long","SubscribeStatusString":"accept"}]`)
+	push("subscribe_msg_popup_event", `{"TemplateId":"tmpl-1","SubscribeStatusString":"accept"}`)
+
+	sender := &fakeSubscribeSender{}
+	request := &SubscribeMessageRequest{ToUser: "openid", TemplateID: "tmpl-1"}
+
+	for i := 0; i < 2; i++ {
+		_, err = tracker.SendSubscribeMessage(sender, request)
+		assert.NoError(t, err)
+	}
+
+	_, err = tracker.SendSubscribeMessage(sender, request)
+	assert.ErrorIs(t, err, ErrSubscribeQuotaExhausted)
+	assert.Equal(t, 2, sender.calls)
+
+	// long-term templates are unlimited until rejected
+	longRequest := &SubscribeMessageRequest{ToUser: "openid", TemplateID: "tmpl-long"}
+	for i := 0; i < 3; i++ {
+		_, err = tracker.SendSubscribeMessage(sender, longRequest)
+		assert.NoError(t, err)
+	}
+
+	push("subscribe_msg_change_event", `{"TemplateId":"tmpl-long","SubscribeStatusString":"reject"}`)
+	ok, _ = tracker.CanSend("openid", "tmpl-long")
+	assert.False(t, ok)
+
+	// popup rejection keeps the accepted one-time allowance
+	push("subscribe_msg_popup_event", `{"TemplateId":"tmpl-1","SubscribeStatusString":"accept"}`)
+	push("subscribe_msg_popup_event", `{"TemplateId":"tmpl-1","SubscribeStatusString":"reject"}`)
+	ok, _ = tracker.CanSend("openid", "tmpl-1")
+	assert.True(t, ok)
+
+	// failed sends other than 43101 refund the allowance
+	sender.errCode = -1
+	_, _ = tracker.SendSubscribeMessage(sender, request)
+	quota, _ := tracker.store.Get(context.Background(), "openid", "tmpl-1")
+	assert.Equal(t, 1, quota)
+
+	// 43101 from WeChat resets the quota
+	sender.errCode = errCodeUserRefuse
+	push("subscribe_msg_popup_event", `{"TemplateId":"tmpl-1","SubscribeStatusString":"accept"}`)
+	_, _ = tracker.SendSubscribeMessage(sender, request)
+	ok, _ = tracker.CanSend("openid", "tmpl-1")
+	assert.False(t, ok)
+
+	push("subscribe_msg_popup_event", `{"TemplateId":"tmpl-1","SubscribeStatusString":"accept"}`)
+	push("subscribe_msg_sent_event", `{"TemplateId":"tmpl-1","ErrorCode":"43101"}`)
+	ok, _ = tracker.CanSend("openid", "tmpl-1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,277 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	subscribeMessageSendEndpoint = "/cgi-bin/message/subscribe/send"
+
+	defaultSubscribeMaxAttempts  = 3
+	defaultSubscribePollInterval = time.Second
+	defaultSubscribeQuotaBackoff = time.Minute
+)
+
+// DefaultSubscribeSendRate is the default rate of the subscribe scheduler, limited per second
+// so that bursts are spread over the minute instead of exhausting the per-minute quota at once.
+var DefaultSubscribeSendRate = vwx.RateLimit{Requests: 20, Per: time.Second}
+
+// SubscribePriority is the lane of a queued subscribe message.
+type SubscribePriority string
+
+const (
+	SubscribeTransactional SubscribePriority = "transactional" // 交易类消息（订单、物流等），优先发送
+	SubscribeMarketing     SubscribePriority = "marketing"     // 营销类消息，交易类消息发完后发送
+)
+
+// subscribeLanes are the lanes in the order they are drained.
+var subscribeLanes = []SubscribePriority{SubscribeTransactional, SubscribeMarketing}
+
+// QueuedSubscribeMessage is a subscribe message waiting in the queue of the scheduler.
+type QueuedSubscribeMessage struct {
+	ID         string                   `json:"id"`          // 消息id
+	Priority   SubscribePriority        `json:"priority"`    // 优先级
+	Request    *SubscribeMessageRequest `json:"request"`     // 订阅消息
+	Attempts   int                      `json:"attempts"`    // 已发送次数
+	EnqueuedAt time.Time                `json:"enqueued_at"` // 入队时间
+}
+
+// SubscribeQueueStore persists the queued subscribe messages per lane, so that they survive restarts.
+// A store is drained by a single scheduler, messages taken by Pop are not persisted any more.
+type SubscribeQueueStore interface {
+	// Push appends the message to the lane of its priority.
+	Push(ctx context.Context, msg *QueuedSubscribeMessage) error
+	// Pop removes and returns the oldest message of the lane, nil if the lane is empty.
+	Pop(ctx context.Context, priority SubscribePriority) (*QueuedSubscribeMessage, error)
+}
+
+// MemorySubscribeQueueStore is an in-memory SubscribeQueueStore, the queued messages are lost on restart.
+type MemorySubscribeQueueStore struct {
+	mu    sync.Mutex
+	lanes map[SubscribePriority][]*QueuedSubscribeMessage
+}
+
+// NewMemorySubscribeQueueStore creates an in-memory subscribe queue store.
+func NewMemorySubscribeQueueStore() *MemorySubscribeQueueStore {
+	return &MemorySubscribeQueueStore{lanes: make(map[SubscribePriority][]*QueuedSubscribeMessage)}
+}
+
+// Push implements SubscribeQueueStore.
+func (m *MemorySubscribeQueueStore) Push(_ context.Context, msg *QueuedSubscribeMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queued := *msg
+	m.lanes[msg.Priority] = append(m.lanes[msg.Priority], &queued)
+
+	return nil
+}
+
+// Pop implements SubscribeQueueStore.
+func (m *MemorySubscribeQueueStore) Pop(_ context.Context, priority SubscribePriority) (*QueuedSubscribeMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lane := m.lanes[priority]
+	if len(lane) == 0 {
+		return nil, nil
+	}
+
+	m.lanes[priority] = lane[1:]
+
+	return lane[0], nil
+}
+
+// SubscribeScheduler queues subscribe messages and sends them at a steady rate, transactional
+// messages before marketing ones. Messages failing with a network error or a busy WeChat are
+// queued again until MaxAttempts, the sending pauses for QuotaBackoff when the quota is reached.
+type SubscribeScheduler struct {
+	sender  SubscribeMessageSender
+	store   SubscribeQueueStore
+	limiter *vwx.RateLimiter
+	notify  chan struct{}
+
+	MaxAttempts  int           // 最大发送次数，默认3次
+	PollInterval time.Duration // 队列为空时检查存储的间隔，默认1秒
+	QuotaBackoff time.Duration // 触发频率限制（45009/45011）后暂停发送的时间，默认1分钟
+	Logger       vwx.Logger    // 日志，默认vlog
+
+	// OnResult is called with the final result of each message, err is nil if it is sent.
+	OnResult func(msg *QueuedSubscribeMessage, resp *SubscribeMessageResponse, err error)
+}
+
+func (s *SubscribeScheduler) logger() vwx.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+// NewSubscribeScheduler creates a subscribe scheduler sending by the sender, an in-memory store is
+// used if the store is nil. The messages are sent at DefaultSubscribeSendRate unless WithSubscribeSendRate.
+func NewSubscribeScheduler(sender SubscribeMessageSender, store SubscribeQueueStore, options ...func(*SubscribeScheduler)) *SubscribeScheduler {
+	if store == nil {
+		store = NewMemorySubscribeQueueStore()
+	}
+
+	s := &SubscribeScheduler{
+		sender:       sender,
+		store:        store,
+		notify:       make(chan struct{}, 1),
+		MaxAttempts:  defaultSubscribeMaxAttempts,
+		PollInterval: defaultSubscribePollInterval,
+		QuotaBackoff: defaultSubscribeQuotaBackoff,
+	}
+
+	WithSubscribeSendRate(DefaultSubscribeSendRate)(s)
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// WithSubscribeSendRate sets the rate the scheduler sends the messages at.
+func WithSubscribeSendRate(rate vwx.RateLimit) func(*SubscribeScheduler) {
+	return func(s *SubscribeScheduler) {
+		s.limiter = vwx.NewRateLimiter(map[string]vwx.RateLimit{subscribeMessageSendEndpoint: rate})
+	}
+}
+
+// WithSubscribeResultHandler sets the handler of the final result of each message.
+func WithSubscribeResultHandler(handler func(msg *QueuedSubscribeMessage, resp *SubscribeMessageResponse, err error)) func(*SubscribeScheduler) {
+	return func(s *SubscribeScheduler) {
+		s.OnResult = handler
+	}
+}
+
+// Enqueue queues the message in the lane of the priority, returning the id of the queued message.
+func (s *SubscribeScheduler) Enqueue(priority SubscribePriority, request *SubscribeMessageRequest) (string, error) {
+	return s.EnqueueContext(context.Background(), priority, request)
+}
+
+// EnqueueContext is Enqueue with the given context.
+func (s *SubscribeScheduler) EnqueueContext(ctx context.Context, priority SubscribePriority, request *SubscribeMessageRequest) (string, error) {
+	msg := &QueuedSubscribeMessage{
+		ID:         vwx.NewRequestID(),
+		Priority:   priority,
+		Request:    request,
+		EnqueuedAt: time.Now(),
+	}
+
+	if err := s.store.Push(ctx, msg); err != nil {
+		return "", fmt.Errorf("push subscribe message error: %w", err)
+	}
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+
+	return msg.ID, nil
+}
+
+// Run sends the queued messages until the context is done, it returns the error of the context.
+func (s *SubscribeScheduler) Run(ctx context.Context) error {
+	for {
+		msg, err := s.next(ctx)
+		if err != nil {
+			s.logger().Errorf("pop subscribe message error | err: %v", err)
+		}
+
+		if msg == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-s.notify:
+			case <-time.After(s.PollInterval):
+			}
+			continue
+		}
+
+		if err := s.limiter.Wait(ctx, subscribeMessageSendEndpoint); err != nil {
+			s.requeue(msg)
+			return err
+		}
+
+		if pause := s.send(ctx, msg); pause > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pause):
+			}
+		}
+	}
+}
+
+// next pops the oldest message of the first non-empty lane.
+func (s *SubscribeScheduler) next(ctx context.Context) (*QueuedSubscribeMessage, error) {
+	for _, priority := range subscribeLanes {
+		msg, err := s.store.Pop(ctx, priority)
+		if err != nil || msg != nil {
+			return msg, err
+		}
+	}
+
+	return nil, nil
+}
+
+// send sends the message, returning how long to pause the sending.
+func (s *SubscribeScheduler) send(ctx context.Context, msg *QueuedSubscribeMessage) time.Duration {
+	msg.Attempts++
+
+	resp, err := s.sender.SendSubscribeMessageContext(ctx, msg.Request)
+	if err != nil {
+		apiErr, isAPIErr := vwx.AsAPIError(err)
+
+		if isAPIErr && apiErr.IsRateLimited() {
+			s.logger().Warnf("subscribe quota reached, pause sending | id: %s | backoff: %s", msg.ID, s.QuotaBackoff)
+			// the quota rejection is not counted as an attempt
+			msg.Attempts--
+			s.requeue(msg)
+			return s.QuotaBackoff
+		}
+
+		if (!isAPIErr || apiErr.IsSystemBusy()) && msg.Attempts < s.MaxAttempts && ctx.Err() == nil {
+			s.logger().Warnf("send subscribe message failed, queue again | id: %s | attempts: %d | err: %v", msg.ID, msg.Attempts, err)
+			s.requeue(msg)
+			return 0
+		}
+	}
+
+	if s.OnResult != nil {
+		s.OnResult(msg, resp, err)
+	}
+
+	return 0
+}
+
+// requeue puts the message back to the end of its lane.
+func (s *SubscribeScheduler) requeue(msg *QueuedSubscribeMessage) {
+	if err := s.store.Push(context.Background(), msg); err != nil {
+		s.logger().Errorf("requeue subscribe message error | id: %s | err: %v", msg.ID, err)
+	}
+}
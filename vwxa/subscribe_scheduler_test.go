@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestSubscribeScheduler(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	results := make(chan *QueuedSubscribeMessage, 10)
+	errs := make(map[string]error)
+	scheduler := NewSubscribeScheduler(svc, nil,
+		WithSubscribeSendRate(vwx.RateLimit{Requests: 100, Per: time.Second}),
+		WithSubscribeResultHandler(func(msg *QueuedSubscribeMessage, _ *SubscribeMessageResponse, err error) {
+			errs[msg.Request.ToUser] = err
+			results <- msg
+		}))
+	scheduler.MaxAttempts = 2
+
+	for _, openID := range []string{"marketing-1", "marketing-2"} {
+		_, err := scheduler.Enqueue(SubscribeMarketing, &SubscribeMessageRequest{ToUser: openID, TemplateID: "tmpl"})
+		assert.NoError(t, err)
+	}
+	_, err := scheduler.Enqueue(SubscribeTransactional, &SubscribeMessageRequest{ToUser: "order-1", TemplateID: "tmpl"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	for range 3 {
+		<-results
+	}
+
+	// transactional messages are sent first
+	var sent []string
+	for _, request := range server.Requests("/cgi-bin/message/subscribe/send") {
+		var req SubscribeMessageRequest
+		assert.NoError(t, json.Unmarshal(request.Body, &req))
+		sent = append(sent, req.ToUser)
+	}
+	assert.Equal(t, []string{"order-1", "marketing-1", "marketing-2"}, sent)
+	assert.NoError(t, errs["order-1"])
+
+	// busy failures are sent again up to MaxAttempts
+	server.SetError("/cgi-bin/message/subscribe/send", -1, "system error")
+	_, err = scheduler.Enqueue(SubscribeTransactional, &SubscribeMessageRequest{ToUser: "order-2", TemplateID: "tmpl"})
+	assert.NoError(t, err)
+
+	msg := <-results
+	assert.Equal(t, 2, msg.Attempts)
+	assert.True(t, vwx.IsErrCode(errs["order-2"], vwx.ErrCodeSystemBusy))
+	assert.Len(t, server.Requests("/cgi-bin/message/subscribe/send"), 5)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestMemorySubscribeQueueStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySubscribeQueueStore()
+
+	msg, err := store.Pop(ctx, SubscribeMarketing)
+	assert.NoError(t, err)
+	assert.Nil(t, msg)
+
+	assert.NoError(t, store.Push(ctx, &QueuedSubscribeMessage{ID: "1", Priority: SubscribeMarketing}))
+	assert.NoError(t, store.Push(ctx, &QueuedSubscribeMessage{ID: "2", Priority: SubscribeMarketing}))
+
+	msg, _ = store.Pop(ctx, SubscribeTransactional)
+	assert.Nil(t, msg)
+
+	msg, _ = store.Pop(ctx, SubscribeMarketing)
+	assert.Equal(t, "1", msg.ID)
+	msg, _ = store.Pop(ctx, SubscribeMarketing)
+	assert.Equal(t, "2", msg.ID)
+}
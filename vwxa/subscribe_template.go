@@ -0,0 +1,230 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/tmpl"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getSubscribeTemplatesURL = "https://api.weixin.qq.com/wxaapi/newtmpl/gettemplate?access_token=%s"
+	getPubTemplateTitlesURL  = "https://api.weixin.qq.com/wxaapi/newtmpl/getpubtemplatetitles?access_token=%s&ids=%s&start=%d&limit=%d"
+
+	// pubTemplateTitlePageSize is the max limit of the public template titles api.
+	pubTemplateTitlePageSize = 30
+
+	defaultTemplateCacheTTL = 10 * time.Minute
+)
+
+// SubscribeTemplate represents a subscribe message template added to the account.
+type SubscribeTemplate struct {
+	PriTmplID string `json:"priTmplId"` // 添加至账号下的模板 id
+	Title     string `json:"title"`     // 模板标题
+	Content   string `json:"content"`   // 模板内容
+	Example   string `json:"example"`   // 模板内容示例
+	Type      int    `json:"type"`      // 模板类型，2 为一次性订阅，3 为长期订阅
+}
+
+// Keywords returns the data keys (e.g. thing1, time2) declared in the template content.
+func (t *SubscribeTemplate) Keywords() []string {
+	return tmpl.Keywords(t.Content)
+}
+
+// SubscribeTemplateListResponse represents the response of the template list API.
+type SubscribeTemplateListResponse struct {
+	ErrCode int                  `json:"errcode"`
+	ErrMsg  string               `json:"errmsg"`
+	Data    []*SubscribeTemplate `json:"data"`
+}
+
+// GetSubscribeTemplates retrieves the subscribe message templates added to the account.
+func (c *Service) GetSubscribeTemplates(opts ...vwx.RequestOption) (*SubscribeTemplateListResponse, error) {
+	return c.GetSubscribeTemplatesContext(context.Background(), opts...)
+}
+
+// GetSubscribeTemplatesContext is GetSubscribeTemplates with the given context.
+func (c *Service) GetSubscribeTemplatesContext(ctx context.Context, opts ...vwx.RequestOption) (*SubscribeTemplateListResponse, error) {
+	call := c.call("get subscribe templates", getSubscribeTemplatesURL, opts)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[SubscribeTemplateListResponse](ctx, call)
+}
+
+// PubTemplateTitle represents a template of the public template library.
+type PubTemplateTitle struct {
+	TID        int    `json:"tid"`        // 模版标题 id
+	Title      string `json:"title"`      // 模版标题
+	Type       int    `json:"type"`       // 模版类型，2 为一次性订阅，3 为长期订阅
+	CategoryID string `json:"categoryId"` // 模版所属类目 id
+}
+
+// PubTemplateTitleResponse represents the response of the public template titles API.
+type PubTemplateTitleResponse struct {
+	ErrCode int                 `json:"errcode"`
+	ErrMsg  string              `json:"errmsg"`
+	Count   int                 `json:"count"` // 模版标题总数
+	Data    []*PubTemplateTitle `json:"data"`  // 模版标题列表
+}
+
+// GetPubTemplateTitles retrieves a page of the public template titles of the categories,
+// the limit is at most 30.
+func (c *Service) GetPubTemplateTitles(categoryIDs []int, start, limit int, opts ...vwx.RequestOption) (*PubTemplateTitleResponse, error) {
+	return c.GetPubTemplateTitlesContext(context.Background(), categoryIDs, start, limit, opts...)
+}
+
+// GetPubTemplateTitlesContext is GetPubTemplateTitles with the given context.
+func (c *Service) GetPubTemplateTitlesContext(ctx context.Context, categoryIDs []int, start, limit int, opts ...vwx.RequestOption) (*PubTemplateTitleResponse, error) {
+	ids := make([]string, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	call := c.call("get pub template titles", getPubTemplateTitlesURL, opts, url.QueryEscape(strings.Join(ids, ",")), start, limit)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[PubTemplateTitleResponse](ctx, call)
+}
+
+// PubTemplateTitles iterates all public template titles of the categories.
+func (c *Service) PubTemplateTitles(categoryIDs []int, opts ...vwx.RequestOption) *Iterator[*PubTemplateTitle] {
+	return c.PubTemplateTitlesContext(context.Background(), categoryIDs, opts...)
+}
+
+// PubTemplateTitlesContext is PubTemplateTitles with the given context.
+func (c *Service) PubTemplateTitlesContext(ctx context.Context, categoryIDs []int, opts ...vwx.RequestOption) *Iterator[*PubTemplateTitle] {
+	return newIterator(ctx, func(ctx context.Context, offset int) (*page[*PubTemplateTitle], error) {
+		resp, err := c.GetPubTemplateTitlesContext(ctx, categoryIDs, offset, pubTemplateTitlePageSize, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &page[*PubTemplateTitle]{items: resp.Data, total: resp.Count}, nil
+	})
+}
+
+// TemplateValidationError describes the mismatches between a subscribe message and its template.
+type TemplateValidationError = tmpl.ValidationError
+
+// TemplateValidator verifies subscribe message data against the live template definitions
+// before sending, the definitions are cached for TTL.
+type TemplateValidator struct {
+	svc *Service
+	TTL time.Duration
+
+	cache tmpl.Cache[*SubscribeTemplate]
+}
+
+// NewTemplateValidator creates a template validator on top of the given service.
+func NewTemplateValidator(svc *Service) *TemplateValidator {
+	return &TemplateValidator{
+		svc: svc,
+		TTL: defaultTemplateCacheTTL,
+	}
+}
+
+// Template returns the cached definition of the template, fetching the template list
+// if the cache is expired or the template is unknown. Templates still not found after a
+// fetch are not fetched again until the cache expires.
+func (v *TemplateValidator) Template(templateID string) (*SubscribeTemplate, error) {
+	return v.TemplateContext(context.Background(), templateID)
+}
+
+// TemplateContext is Template with the given context, the call options apply to the fetch of
+// the template list. Concurrent lookups share a single fetch.
+func (v *TemplateValidator) TemplateContext(ctx context.Context, templateID string, opts ...vwx.RequestOption) (*SubscribeTemplate, error) {
+	return v.cache.Get(ctx, templateID, v.TTL, func(ctx context.Context) (map[string]*SubscribeTemplate, error) {
+		resp, err := v.svc.GetSubscribeTemplatesContext(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		templates := make(map[string]*SubscribeTemplate, len(resp.Data))
+		for _, t := range resp.Data {
+			templates[t.PriTmplID] = t
+		}
+
+		return templates, nil
+	})
+}
+
+// Validate verifies the message data matches the template keywords exactly and
+// each value satisfies the constraint of its keyword type.
+func (v *TemplateValidator) Validate(request *SubscribeMessageRequest) error {
+	return v.ValidateContext(context.Background(), request)
+}
+
+// ValidateContext is Validate with the given context, the call options apply to the fetch of
+// the template list.
+func (v *TemplateValidator) ValidateContext(ctx context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) error {
+	t, err := v.TemplateContext(ctx, request.TemplateID, opts...)
+	if err != nil {
+		return err
+	}
+
+	return ValidateSubscribeMessageData(t, request.Data)
+}
+
+// SendSubscribeMessage validates the message and sends it if valid.
+func (v *TemplateValidator) SendSubscribeMessage(request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return v.SendSubscribeMessageContext(context.Background(), request, opts...)
+}
+
+// SendSubscribeMessageContext is SendSubscribeMessage with the given context, the context and the
+// call options apply to the fetch of the template list as well.
+func (v *TemplateValidator) SendSubscribeMessageContext(ctx context.Context, request *SubscribeMessageRequest, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	if err := v.ValidateContext(ctx, request, opts...); err != nil {
+		return nil, err
+	}
+
+	return v.svc.SendSubscribeMessageContext(ctx, request, opts...)
+}
+
+// SendSubscribeMessageSimple validates the message with simple data and sends it if valid.
+func (v *TemplateValidator) SendSubscribeMessageSimple(openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	return v.SendSubscribeMessageSimpleContext(context.Background(), openID, templateID, page, data, opts...)
+}
+
+// SendSubscribeMessageSimpleContext is SendSubscribeMessageSimple with the given context.
+func (v *TemplateValidator) SendSubscribeMessageSimpleContext(ctx context.Context, openID, templateID, page string, data map[string]string, opts ...vwx.RequestOption) (*SubscribeMessageResponse, error) {
+	dataItems := make(map[string]*SubscribeMessageDataItem, len(data))
+	for k, val := range data {
+		dataItems[k] = &SubscribeMessageDataItem{Value: val}
+	}
+
+	return v.SendSubscribeMessageContext(ctx, &SubscribeMessageRequest{
+		ToUser:     openID,
+		TemplateID: templateID,
+		Page:       page,
+		Data:       dataItems,
+	}, opts...)
+}
+
+// ValidateSubscribeMessageData verifies the message data against the template definition.
+func ValidateSubscribeMessageData(t *SubscribeTemplate, data map[string]*SubscribeMessageDataItem) error {
+	return tmpl.Validate(t.PriTmplID, t.Content, data, func(item *SubscribeMessageDataItem) string {
+		return item.Value
+	})
+}
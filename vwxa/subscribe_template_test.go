@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestValidateSubscribeMessageData(t *testing.T) {
+	tmpl := &SubscribeTemplate{
+		PriTmplID: "tmpl-1",
+		Content:   "商品名称:{{thing1.DATA}}\n订单号:{{character_string2.DATA}}\n金额:{{amount3.DATA}}\n",
+	}
+
+	assert.Equal(t, []string{"thing1", "character_string2", "amount3"}, tmpl.Keywords())
+
+	err := ValidateSubscribeMessageData(tmpl, map[string]*SubscribeMessageDataItem{
+		"thing1":            {Value: "咖啡"},
+		"character_string2": {Value: "NO-20240101"},
+		"amount3":           {Value: "¥12.50"},
+	})
+	assert.NoError(t, err)
+
+	err = ValidateSubscribeMessageData(tmpl, map[string]*SubscribeMessageDataItem{
+		"thing1":            {Value: "这是一个非常非常非常非常非常非常长的商品名称"},
+		"character_string2": {Value: "订单号"},
+		"time4":             {Value: "2024-01-01 12:00"},
+	})
+
+	var validationErr *TemplateValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"amount3"}, validationErr.Missing)
+	assert.Equal(t, []string{"time4"}, validationErr.Extra)
+	assert.Contains(t, validationErr.Invalid, "thing1")
+	assert.Contains(t, validationErr.Invalid, "character_string2")
+}
+
+func TestTemplateValidatorCache(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.SetResponse("/wxaapi/newtmpl/gettemplate", map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"data":    []map[string]any{{"priTmplId": "tmpl-1", "title": "订单通知", "content": "商品名称:{{thing1.DATA}}\n", "type": 2}},
+	})
+
+	v := NewTemplateValidator(NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server))))
+
+	tmpl, err := v.Template("tmpl-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "订单通知", tmpl.Title)
+
+	// an unknown template is fetched once until the cache expires
+	for range 3 {
+		_, err = v.Template("tmpl-typo")
+		assert.Error(t, err)
+	}
+	assert.Len(t, server.Requests("/wxaapi/newtmpl/gettemplate"), 2)
+
+	_, err = v.Template("tmpl-1")
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/wxaapi/newtmpl/gettemplate"), 2)
+
+	// the cached definitions expire at once
+	v.TTL = 0
+
+	_, err = v.Template("tmpl-typo")
+	assert.Error(t, err)
+	assert.Len(t, server.Requests("/wxaapi/newtmpl/gettemplate"), 3)
+}
+
+func TestTemplateValidatorFetch(t *testing.T) {
+	var (
+		hits    atomic.Int32
+		token   atomic.Value
+		arrived = make(chan struct{}, 3)
+		release = make(chan struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		token.Store(r.URL.Query().Get("access_token"))
+		arrived <- struct{}{}
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","data":[{"priTmplId":"tmpl-1","content":"{{thing1.DATA}}"}]}`))
+	}))
+	defer server.Close()
+
+	v := NewTemplateValidator(NewService(vwx.NewClient("appid", "secret", vwx.WithBaseURL(server.URL))))
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tmpl, err := v.TemplateContext(context.Background(), "tmpl-1", vwx.WithAccessToken("authorizer-token"))
+			assert.NoError(t, err)
+			assert.Equal(t, "tmpl-1", tmpl.PriTmplID)
+		}()
+	}
+
+	<-arrived
+
+	// the lock is not held during the fetch, a caller whose context is done returns at once
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := v.TemplateContext(ctx, "tmpl-1")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+
+	// the concurrent lookups share a single fetch made with the call options
+	assert.Equal(t, int32(1), hits.Load())
+	assert.Equal(t, "authorizer-token", token.Load())
+}
+
+func TestTemplateValidator(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.SetResponse("/wxaapi/newtmpl/gettemplate", map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"data": []map[string]any{
+			{"priTmplId": "tmpl-1", "title": "订单通知", "content": "商品:{{thing1.DATA}}\n时间:{{time2.DATA}}\n", "type": 2},
+		},
+	})
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	validator := NewTemplateValidator(svc)
+
+	_, err := validator.SendSubscribeMessageSimple("openid", "tmpl-1", "", map[string]string{"thing1": "咖啡"})
+	assert.Error(t, err)
+	assert.Empty(t, server.Requests("/cgi-bin/message/subscribe/send"))
+
+	_, err = validator.SendSubscribeMessageSimple("openid", "tmpl-1", "", map[string]string{"thing1": "咖啡", "time2": "12:00"})
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/message/subscribe/send"), 1)
+	assert.Len(t, server.Requests("/wxaapi/newtmpl/gettemplate"), 1)
+
+	_, err = validator.SendSubscribeMessageSimple("openid", "tmpl-2", "", map[string]string{"thing1": "咖啡"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestDryRun(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithDryRun(true))
+	svc := NewService(client)
+
+	resp, err := svc.SendSubscribeMessageSimple("openid", "template", "", map[string]string{"thing1": "hi"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.ErrCode)
+	assert.Empty(t, server.Requests("/cgi-bin/message/subscribe/send"))
+	assert.Empty(t, server.Requests("/cgi-bin/token"))
+}
@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	tcbBatchDownloadFileURL = "https://api.weixin.qq.com/tcb/batchdownloadfile?access_token=%s"
+	tcbUploadFileURL        = "https://api.weixin.qq.com/tcb/uploadfile?access_token=%s"
+
+	// MaxCloudFileBatch is the max number of files of a batch download.
+	MaxCloudFileBatch = 50
+)
+
+// ErrCloudEnvRequired is returned when neither the call nor the env profile specifies the cloud env.
+var ErrCloudEnvRequired = errors.New("cloud env is required")
+
+// CloudFile is a file of a batch download.
+type CloudFile struct {
+	FileID string `json:"fileid"`  // 云文件id，如cloud://env.xxx/a.png
+	MaxAge int64  `json:"max_age"` // 下载链接有效期，单位秒
+}
+
+// CloudFileDownload is the download url of a file.
+type CloudFileDownload struct {
+	FileID      string `json:"fileid"`       // 云文件id
+	DownloadURL string `json:"download_url"` // 临时下载链接
+	Status      int    `json:"status"`       // 状态码，0为成功
+	ErrMsg      string `json:"errmsg"`       // 错误信息
+}
+
+// CloudFileDownloadResponse represents the response of a batch download.
+type CloudFileDownloadResponse struct {
+	ErrCode  int                  `json:"errcode"`
+	ErrMsg   string               `json:"errmsg"`
+	FileList []*CloudFileDownload `json:"file_list"` // 文件列表
+}
+
+// CloudFileUploadResponse represents the upload credential of a file.
+type CloudFileUploadResponse struct {
+	ErrCode       int    `json:"errcode"`
+	ErrMsg        string `json:"errmsg"`
+	URL           string `json:"url"`           // 上传url
+	Token         string `json:"token"`         // x-cos-security-token
+	Authorization string `json:"authorization"` // 上传签名，作为Signature字段
+	FileID        string `json:"file_id"`       // 云文件id
+	COSFileID     string `json:"cos_file_id"`   // x-cos-meta-fileid
+}
+
+// cloudEnv returns the env, or the cloud env of the env profile switched by the call options.
+func (c *Service) cloudEnv(env string, opts []vwx.RequestOption) (string, error) {
+	if env == "" {
+		env = c.envProfile(opts).CloudEnv
+	}
+
+	if env == "" {
+		return "", ErrCloudEnvRequired
+	}
+
+	return env, nil
+}
+
+// BatchDownloadCloudFiles exchanges the cloud file ids for temporary download urls, up to
+// MaxCloudFileBatch files a call. An empty env uses the CloudEnv of the env profile. Files failing
+// individually are reported by their Status.
+func (c *Service) BatchDownloadCloudFiles(env string, files []*CloudFile, opts ...vwx.RequestOption) (*CloudFileDownloadResponse, error) {
+	return c.BatchDownloadCloudFilesContext(context.Background(), env, files, opts...)
+}
+
+// BatchDownloadCloudFilesContext is BatchDownloadCloudFiles with the given context.
+func (c *Service) BatchDownloadCloudFilesContext(ctx context.Context, env string, files []*CloudFile, opts ...vwx.RequestOption) (*CloudFileDownloadResponse, error) {
+	if len(files) > MaxCloudFileBatch {
+		return nil, fmt.Errorf("batch download at most %d files, got %d", MaxCloudFileBatch, len(files))
+	}
+
+	env, err := c.cloudEnv(env, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	call := c.call("batch download cloud files", tcbBatchDownloadFileURL, opts)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[CloudFileDownloadResponse](ctx, call, map[string]any{"env": env, "file_list": files})
+}
+
+// GetCloudFileDownloadURL returns the temporary download url of the cloud file valid for maxAge.
+func (c *Service) GetCloudFileDownloadURL(env, fileID string, maxAge time.Duration, opts ...vwx.RequestOption) (string, error) {
+	return c.GetCloudFileDownloadURLContext(context.Background(), env, fileID, maxAge, opts...)
+}
+
+// GetCloudFileDownloadURLContext is GetCloudFileDownloadURL with the given context.
+func (c *Service) GetCloudFileDownloadURLContext(ctx context.Context, env, fileID string, maxAge time.Duration, opts ...vwx.RequestOption) (string, error) {
+	resp, err := c.BatchDownloadCloudFilesContext(ctx, env, []*CloudFile{{FileID: fileID, MaxAge: int64(maxAge.Seconds())}}, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.FileList) == 0 {
+		return "", fmt.Errorf("cloud file %s not returned", fileID)
+	}
+
+	file := resp.FileList[0]
+	if file.Status != 0 {
+		return "", fmt.Errorf("download cloud file %s failed: %d %s", fileID, file.Status, file.ErrMsg)
+	}
+
+	return file.DownloadURL, nil
+}
+
+// GetCloudFileUploadInfo returns the credential to upload a file to the path of the cloud storage,
+// see UploadCloudFile to upload with it.
+func (c *Service) GetCloudFileUploadInfo(env, filePath string, opts ...vwx.RequestOption) (*CloudFileUploadResponse, error) {
+	return c.GetCloudFileUploadInfoContext(context.Background(), env, filePath, opts...)
+}
+
+// GetCloudFileUploadInfoContext is GetCloudFileUploadInfo with the given context.
+func (c *Service) GetCloudFileUploadInfoContext(ctx context.Context, env, filePath string, opts ...vwx.RequestOption) (*CloudFileUploadResponse, error) {
+	env, err := c.cloudEnv(env, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the response carries the upload credential, it is not logged
+	call := c.call("get cloud file upload info", tcbUploadFileURL, opts)
+	call.Summary = fmt.Sprintf("env: %s | path: %s", env, filePath)
+
+	return wxapi.PostJSON[CloudFileUploadResponse](ctx, call, map[string]string{"env": env, "path": filePath})
+}
+
+// UploadCloudFile uploads the file read from r to the path of the cloud storage and returns its
+// cloud file id, the file is streamed to the storage without buffering.
+func (c *Service) UploadCloudFile(env, filePath string, r io.Reader, opts ...vwx.RequestOption) (string, error) {
+	return c.UploadCloudFileContext(context.Background(), env, filePath, r, opts...)
+}
+
+// UploadCloudFileContext is UploadCloudFile with the given context.
+func (c *Service) UploadCloudFileContext(ctx context.Context, env, filePath string, r io.Reader, opts ...vwx.RequestOption) (string, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	info, err := c.GetCloudFileUploadInfoContext(ctx, env, filePath, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	fields := [][2]string{
+		{"key", filePath},
+		{"Signature", info.Authorization},
+		{"x-cos-security-token", info.Token},
+		{"x-cos-meta-fileid", info.COSFileID},
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		var err error
+		for _, field := range fields {
+			if err = writer.WriteField(field[0], field[1]); err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			var part io.Writer
+			if part, err = writer.CreateFormFile("file", path.Base(filePath)); err == nil {
+				_, err = io.Copy(part, r)
+			}
+		}
+
+		if err == nil {
+			err = writer.Close()
+		}
+
+		_ = pw.CloseWithError(err)
+	}()
+
+	c.client.Logger.Infof("upload cloud file | request_id: %s | path: %s | file_id: %s", requestID, filePath, info.FileID)
+
+	ctx, cancel := vwx.NewRequestOptions(opts...).Context(ctx)
+	defer cancel()
+
+	resp, err := c.client.Post(ctx, info.URL, writer.FormDataContentType(), pr)
+	// unblock the writer goroutine if the request ended before reading the whole body
+	_ = pr.Close()
+	if err != nil {
+		return "", fmt.Errorf("upload cloud file error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("upload cloud file error: status %d %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return info.FileID, nil
+}
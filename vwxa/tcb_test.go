@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxa
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestCloudFiles(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithEnvProfile(vwx.EnvRelease, &vwx.EnvProfile{CloudEnv: "prod-1a2b"}))
+	svc := NewService(client)
+
+	fileID, err := svc.UploadCloudFile("", "avatars/a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.Equal(t, "cloud://prod-1a2b/avatars/a.png", fileID)
+
+	data, ok := server.CloudFile(fileID)
+	assert.True(t, ok)
+	assert.Equal(t, vwxmock.PNGHeader, data)
+
+	url, err := svc.GetCloudFileDownloadURL("", fileID, time.Hour)
+	assert.NoError(t, err)
+	assert.Contains(t, url, "avatars/a.png")
+
+	resp, err := svc.BatchDownloadCloudFiles("prod-1a2b", []*CloudFile{
+		{FileID: fileID, MaxAge: 60},
+		{FileID: "cloud://prod-1a2b/missing.png", MaxAge: 60},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.FileList, 2)
+	assert.Equal(t, 0, resp.FileList[0].Status)
+	assert.NotEqual(t, 0, resp.FileList[1].Status)
+
+	_, err = svc.GetCloudFileDownloadURL("", "cloud://prod-1a2b/missing.png", time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server))).
+		GetCloudFileDownloadURL("", fileID, time.Hour)
+	assert.ErrorIs(t, err, ErrCloudEnvRequired)
+}
@@ -18,18 +18,16 @@
 package vwxa
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"io"
-	"net/http"
+	"context"
 	"time"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
-	generateURLLinkURL = "https://api.weixin.qq.com/wxa/generate_urllink?access_token="
+	generateURLLinkURL = "https://api.weixin.qq.com/wxa/generate_urllink?access_token=%s"
+	queryURLLinkURL    = "https://api.weixin.qq.com/wxa/query_urllink?access_token=%s"
 )
 
 // URLLinkRequest represents the request parameters for generating URL Link.
@@ -59,80 +57,37 @@ type URLLinkResponse struct {
 	URLLink string `json:"url_link"`
 }
 
-func (c *Service) marshalRequest(req *URLLinkRequest) ([]byte, error) {
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encoder.SetEscapeHTML(false)
-	if jsonErr := encoder.Encode(req); jsonErr != nil {
-		return nil, jsonErr
-	}
-	// Remove the trailing newline added by Encode
-	jsonData := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
-
-	return jsonData, nil
-}
-
 // GenerateURLLink generates a URL Link for WeChat Mini Program.
 // 获取小程序 URL Link，适用于短信、邮件、网页、微信内等拉起小程序的业务场景
-func (c *Service) GenerateURLLink(req *URLLinkRequest) (*URLLinkResponse, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
-	if err != nil {
-		return nil, err
-	}
-
-	url := generateURLLinkURL + accessToken
+func (c *Service) GenerateURLLink(req *URLLinkRequest, opts ...vwx.RequestOption) (*URLLinkResponse, error) {
+	return c.GenerateURLLinkContext(context.Background(), req, opts...)
+}
 
+// GenerateURLLinkContext is GenerateURLLink with the given context.
+func (c *Service) GenerateURLLinkContext(ctx context.Context, req *URLLinkRequest, opts ...vwx.RequestOption) (*URLLinkResponse, error) {
 	// Set default env_version if not provided
 	if req.EnvVersion == nil {
-		envVersion := c.client.EnvVersion
+		envVersion := c.envProfile(opts).EnvVersion
 		req.EnvVersion = &envVersion
 	}
 
-	jsonData, err := c.marshalRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	vlog.Infof("generate urllink | req: %s", string(jsonData))
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	vlog.Infof("generate urllink | resp: %s", string(body))
-
-	var result URLLinkResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
-	}
-
-	if result.ErrCode != 0 {
-		return nil, errors.New(result.ErrMsg)
-	}
-
-	return &result, nil
+	return wxapi.PostJSON[URLLinkResponse](ctx, c.call("generate urllink", generateURLLinkURL, opts), req)
 }
 
 // GenerateSimpleURLLink generates a simple URL Link with basic parameters.
 // 简化版本的URL Link生成，只需要提供基本参数
-func (c *Service) GenerateSimpleURLLink(path, query string) (string, error) {
+func (c *Service) GenerateSimpleURLLink(path, query string, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateSimpleURLLinkContext(context.Background(), path, query, opts...)
+}
+
+// GenerateSimpleURLLinkContext is GenerateSimpleURLLink with the given context.
+func (c *Service) GenerateSimpleURLLinkContext(ctx context.Context, path, query string, opts ...vwx.RequestOption) (string, error) {
 	req := &URLLinkRequest{
 		Path:  &path,
 		Query: &query,
 	}
 
-	resp, err := c.GenerateURLLink(req)
+	resp, err := c.GenerateURLLinkContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -142,7 +97,12 @@ func (c *Service) GenerateSimpleURLLink(path, query string) (string, error) {
 
 // GenerateExpirableURLLink generates a URL Link with expiration time.
 // 生成带有过期时间的URL Link
-func (c *Service) GenerateExpirableURLLink(path, query string, expireTime time.Time) (string, error) {
+func (c *Service) GenerateExpirableURLLink(path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateExpirableURLLinkContext(context.Background(), path, query, expireTime, opts...)
+}
+
+// GenerateExpirableURLLinkContext is GenerateExpirableURLLink with the given context.
+func (c *Service) GenerateExpirableURLLinkContext(ctx context.Context, path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error) {
 	expireType := 0
 	expireTimeUnix := expireTime.Unix()
 	req := &URLLinkRequest{
@@ -152,7 +112,7 @@ func (c *Service) GenerateExpirableURLLink(path, query string, expireTime time.T
 		ExpireTime: &expireTimeUnix,
 	}
 
-	resp, err := c.GenerateURLLink(req)
+	resp, err := c.GenerateURLLinkContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -162,7 +122,12 @@ func (c *Service) GenerateExpirableURLLink(path, query string, expireTime time.T
 
 // GenerateIntervalURLLink generates a URL Link with expiration interval in days.
 // 生成带有失效间隔天数的URL Link
-func (c *Service) GenerateIntervalURLLink(path, query string, expireIntervalDays int) (string, error) {
+func (c *Service) GenerateIntervalURLLink(path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateIntervalURLLinkContext(context.Background(), path, query, expireIntervalDays, opts...)
+}
+
+// GenerateIntervalURLLinkContext is GenerateIntervalURLLink with the given context.
+func (c *Service) GenerateIntervalURLLinkContext(ctx context.Context, path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error) {
 	expireType := 1
 	req := &URLLinkRequest{
 		Path:           &path,
@@ -171,10 +136,57 @@ func (c *Service) GenerateIntervalURLLink(path, query string, expireIntervalDays
 		ExpireInterval: &expireIntervalDays,
 	}
 
-	resp, err := c.GenerateURLLink(req)
+	resp, err := c.GenerateURLLinkContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
 
 	return resp.URLLink, nil
 }
+
+// URLLinkInfo represents the target and the validity of a URL Link.
+type URLLinkInfo struct {
+	AppID      string     `json:"appid"`                // 小程序 appid
+	Path       string     `json:"path"`                 // 小程序页面路径
+	Query      string     `json:"query"`                // 小程序页面query
+	CreateTime int64      `json:"create_time"`          // 创建时间，为 Unix 时间戳
+	ExpireTime int64      `json:"expire_time"`          // 到期失效时间，为 Unix 时间戳，0 表示永久生效
+	EnvVersion string     `json:"env_version"`          // 要打开的小程序版本
+	CloudBase  *CloudBase `json:"cloud_base,omitempty"` // 云开发静态网站配置
+}
+
+// CreatedAt returns the creation time of the link.
+func (i *URLLinkInfo) CreatedAt() time.Time {
+	return time.Unix(i.CreateTime, 0)
+}
+
+// ExpiresAt returns the expiry of the link, the zero time if it never expires.
+func (i *URLLinkInfo) ExpiresAt() time.Time {
+	if i.ExpireTime == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(i.ExpireTime, 0)
+}
+
+// URLLinkQueryResponse represents the response of querying a URL Link.
+type URLLinkQueryResponse struct {
+	ErrCode     int             `json:"errcode"`
+	ErrMsg      string          `json:"errmsg"`
+	URLLinkInfo *URLLinkInfo    `json:"url_link_info"` // url_link 配置
+	QuotaInfo   *LinkVisitQuota `json:"quota_info"`    // 访问配额
+}
+
+// QueryURLLink queries the target path and query, the creation time and the expiry of a URL Link
+// generated earlier.
+func (c *Service) QueryURLLink(urlLink string, opts ...vwx.RequestOption) (*URLLinkQueryResponse, error) {
+	return c.QueryURLLinkContext(context.Background(), urlLink, opts...)
+}
+
+// QueryURLLinkContext is QueryURLLink with the given context.
+func (c *Service) QueryURLLinkContext(ctx context.Context, urlLink string, opts ...vwx.RequestOption) (*URLLinkQueryResponse, error) {
+	call := c.call("query urllink", queryURLLinkURL, opts)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[URLLinkQueryResponse](ctx, call, map[string]string{"url_link": urlLink})
+}
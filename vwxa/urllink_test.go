@@ -18,11 +18,15 @@
 package vwxa
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+	"github.com/vogo/vwx/vwxmock"
 )
 
 func TestLinkRequest(t *testing.T) {
@@ -33,10 +37,7 @@ func TestLinkRequest(t *testing.T) {
 		Query: &query,
 	}
 
-	c := vwx.NewClient("appid", "secret")
-	svc := NewService(c)
-
-	body, err := svc.marshalRequest(req)
+	body, err := wxapi.EncodeJSON(req)
 	if err != nil {
 		t.Fatalf("failed to marshal request: %v", err)
 	}
@@ -47,17 +48,43 @@ func TestLinkRequest(t *testing.T) {
 }
 
 func TestGenerateExpirableURLLinkWithTimeType(t *testing.T) {
-	c := vwx.NewClient("test_appid", "test_secret")
-	svc := NewService(c)
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
 
-	// Test that the function accepts time.Time parameter
 	expireTime := time.Now().Add(24 * time.Hour)
 
-	// This would normally make an HTTP request, but we're just testing the parameter type
-	// In a real test environment, you'd mock the HTTP client
-	_, err := svc.GenerateExpirableURLLink("/pages/test", "param=value", expireTime)
+	link, err := svc.GenerateExpirableURLLink("/pages/test", "param=value", expireTime)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, "https://wxaurl.cn/"))
+
+	requests := server.Requests("/wxa/generate_urllink")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), fmt.Sprintf(`"expire_time":%d`, expireTime.Unix()))
+}
+
+func TestQueryURLLink(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	expireTime := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	link, err := svc.GenerateExpirableURLLink("/pages/goods", "id=42", expireTime)
+	assert.NoError(t, err)
+
+	resp, err := svc.QueryURLLink(link)
+	assert.NoError(t, err)
+	assert.Equal(t, "/pages/goods", resp.URLLinkInfo.Path)
+	assert.Equal(t, "id=42", resp.URLLinkInfo.Query)
+	assert.Equal(t, vwx.EnvRelease, resp.URLLinkInfo.EnvVersion)
+	assert.True(t, expireTime.Equal(resp.URLLinkInfo.ExpiresAt()))
+	assert.False(t, resp.URLLinkInfo.CreatedAt().IsZero())
+	assert.Positive(t, resp.QuotaInfo.RemainVisitQuota)
+	assert.JSONEq(t, fmt.Sprintf(`{"url_link":%q}`, link), string(server.Requests("/wxa/query_urllink")[0].Body))
 
-	// We expect an error because we don't have valid credentials, but the important thing
-	// is that the function accepts time.Time parameter without compilation errors
-	assert.Error(t, err) // This will fail due to invalid credentials, which is expected
+	_, err = svc.QueryURLLink("https://wxaurl.cn/unknown")
+	assert.True(t, vwx.IsErrCode(err, 40097))
 }
@@ -18,18 +18,23 @@
 package vwxa
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"io"
-	"net/http"
+	"context"
+	"net/url"
 	"time"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
-	generateURLSchemeURL = "https://api.weixin.qq.com/wxa/generatescheme?access_token="
+	generateURLSchemeURL = "https://api.weixin.qq.com/wxa/generatescheme?access_token=%s"
+	queryURLSchemeURL    = "https://api.weixin.qq.com/wxa/queryscheme?access_token=%s"
+)
+
+// 查询的scheme码类型
+const (
+	URLSchemeQueryEncrypted = 0 // 加密scheme码，如 weixin://dl/business/?t=XTSkBZlzqmn
+	URLSchemeQueryPlaintext = 1 // 明文scheme码，如 weixin://dl/business/?appid=APPID&path=PATH
 )
 
 // URLSchemeRequest represents the request parameters for generating URL Scheme.
@@ -55,72 +60,89 @@ type URLSchemeResponse struct {
 	OpenLink string `json:"openlink"`
 }
 
-// GenerateURLScheme generates a URL Scheme for WeChat Mini Program.
-// 获取小程序scheme码，适用于短信、邮件、外部网页、微信内等拉起小程序的业务场景
-func (c *Service) GenerateURLScheme(req *URLSchemeRequest) (*URLSchemeResponse, error) {
-	accessToken, err := c.authSvc.GetAccessToken()
-	if err != nil {
-		return nil, err
-	}
-
-	url := generateURLSchemeURL + accessToken
+// URLSchemeInfo represents the target and the validity of a URL Scheme.
+type URLSchemeInfo struct {
+	AppID      string `json:"appid"`       // 小程序 appid
+	Path       string `json:"path"`        // 小程序页面路径
+	Query      string `json:"query"`       // 小程序页面query
+	CreateTime int64  `json:"create_time"` // 创建时间，为 Unix 时间戳
+	ExpireTime int64  `json:"expire_time"` // 到期失效时间，为 Unix 时间戳，0 表示永久生效
+	EnvVersion string `json:"env_version"` // 要打开的小程序版本
+}
 
-	// Set default env_version if not provided
-	if req.JumpWxa != nil && req.JumpWxa.EnvVersion == "" {
-		req.JumpWxa.EnvVersion = c.client.EnvVersion
-	}
+// CreatedAt returns the creation time of the scheme.
+func (i *URLSchemeInfo) CreatedAt() time.Time {
+	return time.Unix(i.CreateTime, 0)
+}
 
-	jsonData, err := c.marshalURLSchemeRequest(req)
-	if err != nil {
-		return nil, err
+// ExpiresAt returns the expiry of the scheme, the zero time if it never expires.
+func (i *URLSchemeInfo) ExpiresAt() time.Time {
+	if i.ExpireTime == 0 {
+		return time.Time{}
 	}
 
-	vlog.Infof("generate url scheme | req: %s", string(jsonData))
+	return time.Unix(i.ExpireTime, 0)
+}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
+// LinkVisitQuota represents the daily visit quota of the URL Schemes and URL Links of the mini program.
+type LinkVisitQuota struct {
+	RemainVisitQuota int64 `json:"remain_visit_quota"` // 今日剩余访问次数
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// URLSchemeQueryResponse represents the response of querying a URL Scheme.
+type URLSchemeQueryResponse struct {
+	ErrCode    int             `json:"errcode"`
+	ErrMsg     string          `json:"errmsg"`
+	SchemeInfo *URLSchemeInfo  `json:"scheme_info"` // scheme码配置
+	QuotaInfo  *LinkVisitQuota `json:"quota_info"`  // 访问配额
+}
 
-	vlog.Infof("generate url scheme | resp: %s", string(body))
+// QueryURLScheme queries the target path and query, the creation time and the expiry of a URL
+// Scheme generated earlier. Plaintext schemes carrying the appid are queried as such, others as
+// encrypted schemes.
+func (c *Service) QueryURLScheme(scheme string, opts ...vwx.RequestOption) (*URLSchemeQueryResponse, error) {
+	return c.QueryURLSchemeContext(context.Background(), scheme, opts...)
+}
 
-	var result URLSchemeResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+// QueryURLSchemeContext is QueryURLScheme with the given context.
+func (c *Service) QueryURLSchemeContext(ctx context.Context, scheme string, opts ...vwx.RequestOption) (*URLSchemeQueryResponse, error) {
+	queryType := URLSchemeQueryEncrypted
+	if u, err := url.Parse(scheme); err == nil && u.Query().Has("appid") {
+		queryType = URLSchemeQueryPlaintext
 	}
 
-	if result.ErrCode != 0 {
-		return nil, errors.New(result.ErrMsg)
-	}
+	call := c.call("query url scheme", queryURLSchemeURL, opts)
+	call.Idempotent = true
 
-	return &result, nil
+	return wxapi.PostJSON[URLSchemeQueryResponse](ctx, call, map[string]any{
+		"scheme":     scheme,
+		"query_type": queryType,
+	})
+}
+
+// GenerateURLScheme generates a URL Scheme for WeChat Mini Program.
+// 获取小程序scheme码，适用于短信、邮件、外部网页、微信内等拉起小程序的业务场景
+func (c *Service) GenerateURLScheme(req *URLSchemeRequest, opts ...vwx.RequestOption) (*URLSchemeResponse, error) {
+	return c.GenerateURLSchemeContext(context.Background(), req, opts...)
 }
 
-func (c *Service) marshalURLSchemeRequest(req *URLSchemeRequest) ([]byte, error) {
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encoder.SetEscapeHTML(false)
-	if jsonErr := encoder.Encode(req); jsonErr != nil {
-		return nil, jsonErr
+// GenerateURLSchemeContext is GenerateURLScheme with the given context.
+func (c *Service) GenerateURLSchemeContext(ctx context.Context, req *URLSchemeRequest, opts ...vwx.RequestOption) (*URLSchemeResponse, error) {
+	// Set default env_version if not provided
+	if req.JumpWxa != nil && req.JumpWxa.EnvVersion == "" {
+		req.JumpWxa.EnvVersion = c.envProfile(opts).EnvVersion
 	}
-	// Remove the trailing newline added by Encode
-	jsonData := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
 
-	return jsonData, nil
+	return wxapi.PostJSON[URLSchemeResponse](ctx, c.call("generate url scheme", generateURLSchemeURL, opts), req)
 }
 
 // GenerateSimpleURLScheme generates a simple URL Scheme with path and query.
-func (c *Service) GenerateSimpleURLScheme(path, query string) (string, error) {
+func (c *Service) GenerateSimpleURLScheme(path, query string, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateSimpleURLSchemeContext(context.Background(), path, query, opts...)
+}
+
+// GenerateSimpleURLSchemeContext is GenerateSimpleURLScheme with the given context.
+func (c *Service) GenerateSimpleURLSchemeContext(ctx context.Context, path, query string, opts ...vwx.RequestOption) (string, error) {
 	isExpire := false
 	req := &URLSchemeRequest{
 		JumpWxa: &JumpWxa{
@@ -130,7 +152,7 @@ func (c *Service) GenerateSimpleURLScheme(path, query string) (string, error) {
 		IsExpire: &isExpire, // 永久有效
 	}
 
-	resp, err := c.GenerateURLScheme(req)
+	resp, err := c.GenerateURLSchemeContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -139,7 +161,12 @@ func (c *Service) GenerateSimpleURLScheme(path, query string) (string, error) {
 }
 
 // GenerateExpirableURLScheme generates a URL Scheme that expires at a specific time.
-func (c *Service) GenerateExpirableURLScheme(path, query string, expireTime time.Time) (string, error) {
+func (c *Service) GenerateExpirableURLScheme(path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateExpirableURLSchemeContext(context.Background(), path, query, expireTime, opts...)
+}
+
+// GenerateExpirableURLSchemeContext is GenerateExpirableURLScheme with the given context.
+func (c *Service) GenerateExpirableURLSchemeContext(ctx context.Context, path, query string, expireTime time.Time, opts ...vwx.RequestOption) (string, error) {
 	isExpire := true
 	expireType := 0
 	expireUnix := expireTime.Unix()
@@ -153,7 +180,7 @@ func (c *Service) GenerateExpirableURLScheme(path, query string, expireTime time
 		ExpireTime: &expireUnix,
 	}
 
-	resp, err := c.GenerateURLScheme(req)
+	resp, err := c.GenerateURLSchemeContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -162,7 +189,12 @@ func (c *Service) GenerateExpirableURLScheme(path, query string, expireTime time
 }
 
 // GenerateIntervalURLScheme generates a URL Scheme that expires after a specific number of days.
-func (c *Service) GenerateIntervalURLScheme(path, query string, expireIntervalDays int) (string, error) {
+func (c *Service) GenerateIntervalURLScheme(path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error) {
+	return c.GenerateIntervalURLSchemeContext(context.Background(), path, query, expireIntervalDays, opts...)
+}
+
+// GenerateIntervalURLSchemeContext is GenerateIntervalURLScheme with the given context.
+func (c *Service) GenerateIntervalURLSchemeContext(ctx context.Context, path, query string, expireIntervalDays int, opts ...vwx.RequestOption) (string, error) {
 	isExpire := true
 	expireType := 1
 	req := &URLSchemeRequest{
@@ -175,7 +207,7 @@ func (c *Service) GenerateIntervalURLScheme(path, query string, expireIntervalDa
 		ExpireInterval: &expireIntervalDays,
 	}
 
-	resp, err := c.GenerateURLScheme(req)
+	resp, err := c.GenerateURLSchemeContext(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -18,11 +18,15 @@
 package vwxa
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+	"github.com/vogo/vwx/vwxmock"
 )
 
 func TestURLSchemeRequest(t *testing.T) {
@@ -36,10 +40,7 @@ func TestURLSchemeRequest(t *testing.T) {
 		IsExpire: &isExpire,
 	}
 
-	c := vwx.NewClient("appid", "secret")
-	svc := NewService(c)
-
-	body, err := svc.marshalURLSchemeRequest(req)
+	body, err := wxapi.EncodeJSON(req)
 	if err != nil {
 		t.Fatalf("failed to marshal request: %v", err)
 	}
@@ -65,10 +66,7 @@ func TestURLSchemeRequestWithExpire(t *testing.T) {
 		ExpireTime: &expireTime,
 	}
 
-	c := vwx.NewClient("appid", "secret")
-	svc := NewService(c)
-
-	body, err := svc.marshalURLSchemeRequest(req)
+	body, err := wxapi.EncodeJSON(req)
 	if err != nil {
 		t.Fatalf("failed to marshal request: %v", err)
 	}
@@ -94,10 +92,7 @@ func TestURLSchemeRequestWithInterval(t *testing.T) {
 		ExpireInterval: &expireInterval,
 	}
 
-	c := vwx.NewClient("appid", "secret")
-	svc := NewService(c)
-
-	body, err := svc.marshalURLSchemeRequest(req)
+	body, err := wxapi.EncodeJSON(req)
 	if err != nil {
 		t.Fatalf("failed to marshal request: %v", err)
 	}
@@ -109,17 +104,44 @@ func TestURLSchemeRequestWithInterval(t *testing.T) {
 }
 
 func TestGenerateExpirableURLSchemeWithTimeType(t *testing.T) {
-	c := vwx.NewClient("test_appid", "test_secret")
-	svc := NewService(c)
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
 
-	// Test that the function accepts time.Time parameter
 	expireTime := time.Now().Add(24 * time.Hour)
 
-	// This would normally make an HTTP request, but we're just testing the parameter type
-	// In a real test environment, you'd mock the HTTP client
-	_, err := svc.GenerateExpirableURLScheme("/pages/test", "param=value", expireTime)
+	link, err := svc.GenerateExpirableURLScheme("/pages/test", "param=value", expireTime)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, "weixin://dl/business/"))
+
+	requests := server.Requests("/wxa/generatescheme")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), fmt.Sprintf(`"expire_time":%d`, expireTime.Unix()))
+}
+
+func TestQueryURLScheme(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	link, err := svc.GenerateIntervalURLScheme("/pages/goods", "id=42", 7, vwx.WithEnv(vwx.EnvTrial))
+	assert.NoError(t, err)
+
+	resp, err := svc.QueryURLScheme(link)
+	assert.NoError(t, err)
+	assert.Equal(t, "/pages/goods", resp.SchemeInfo.Path)
+	assert.Equal(t, "id=42", resp.SchemeInfo.Query)
+	assert.Equal(t, vwx.EnvTrial, resp.SchemeInfo.EnvVersion)
+	assert.Equal(t, 7*24*time.Hour, resp.SchemeInfo.ExpiresAt().Sub(resp.SchemeInfo.CreatedAt()))
+	assert.Positive(t, resp.QuotaInfo.RemainVisitQuota)
+
+	requests := server.Requests("/wxa/queryscheme")
+	assert.JSONEq(t, fmt.Sprintf(`{"scheme":%q,"query_type":0}`, link), string(requests[0].Body))
 
-	// We expect an error because we don't have valid credentials, but the important thing
-	// is that the function accepts time.Time parameter without compilation errors
-	assert.Error(t, err) // This will fail due to invalid credentials, which is expected
+	// plaintext schemes are queried as such
+	_, err = svc.QueryURLScheme("weixin://dl/business/?appid=appid&path=pages/index")
+	assert.True(t, vwx.IsErrCode(err, 40097))
+	assert.Contains(t, string(server.Requests("/wxa/queryscheme")[1].Body), `"query_type":1`)
 }
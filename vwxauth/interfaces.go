@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxauth
+
+import (
+	"context"
+
+	"github.com/vogo/vwx"
+)
+
+// AccessTokenGetter retrieves the access token of the app.
+type AccessTokenGetter interface {
+	GetAccessToken() (string, error)
+	GetAccessTokenContext(ctx context.Context) (string, error)
+	RefreshAccessToken(ctx context.Context, staleToken string) (string, error)
+	ForceRefreshAccessToken() (string, error)
+	ForceRefreshAccessTokenContext(ctx context.Context) (string, error)
+}
+
+// SessionKeyGetter exchanges an authorization code for the session key.
+type SessionKeyGetter interface {
+	GetSessionKey(code string) (*SessionResponse, error)
+	GetSessionKeyContext(ctx context.Context, code string) (*SessionResponse, error)
+}
+
+// PhoneDecrypter decrypts phone numbers from WeChat Mini Program.
+type PhoneDecrypter interface {
+	ParsePhoneEncryptedData(data []byte) (*PhoneInfo, *SessionResponse, error)
+	ParsePhoneEncryptedDataContext(ctx context.Context, data []byte) (*PhoneInfo, *SessionResponse, error)
+	DecryptPhoneNumber(sessionKey, encryptedData, iv string) (*PhoneInfo, error)
+}
+
+var (
+	_ AccessTokenGetter = (*Service)(nil)
+	_ SessionKeyGetter  = (*Service)(nil)
+	_ PhoneDecrypter    = (*Service)(nil)
+	_ vwx.TokenSource   = (*Service)(nil)
+)
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxauth
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+// Metrics of the access token reported to the vwx.MetricsCollector of the client, labeled by appid.
+const (
+	// MetricTokenCacheHits counts the access tokens served from the cache.
+	MetricTokenCacheHits = "vwx_token_cache_hits_total"
+	// MetricTokenCacheMisses counts the access tokens not found in the cache, labeled by reason:
+	// "miss", or "no_cache" if the client has no CacheProvider and every call burns the token quota.
+	MetricTokenCacheMisses = "vwx_token_cache_misses_total"
+	// MetricTokenRefreshDuration observes the duration of fetching access tokens from WeChat, labeled by result.
+	MetricTokenRefreshDuration = "vwx_token_refresh_duration_seconds"
+	// MetricTokenRefreshFailures counts the failed access token fetches, labeled by errcode.
+	MetricTokenRefreshFailures = "vwx_token_refresh_failures_total"
+	// MetricTokenExpiresIn is the validity in seconds of the last fetched access token.
+	MetricTokenExpiresIn = "vwx_token_expires_in_seconds"
+)
+
+func (c *Service) incCounter(name string, labels map[string]string) {
+	if c.client.Metrics == nil {
+		return
+	}
+
+	labels["appid"] = c.client.AppID
+	c.client.Metrics.IncCounter(name, labels)
+}
+
+// observeTokenRefresh reports a fetch of access token from WeChat.
+func (c *Service) observeTokenRefresh(start time.Time, expiresIn int, err error) {
+	metrics := c.client.Metrics
+	if metrics == nil {
+		return
+	}
+
+	appID := c.client.AppID
+
+	if err != nil {
+		errCode := "unknown"
+		if apiErr, ok := vwx.AsAPIError(err); ok {
+			errCode = strconv.Itoa(apiErr.Code)
+		}
+
+		metrics.ObserveDuration(MetricTokenRefreshDuration, time.Since(start), map[string]string{"appid": appID, "result": "error"})
+		metrics.IncCounter(MetricTokenRefreshFailures, map[string]string{"appid": appID, "errcode": errCode})
+		return
+	}
+
+	metrics.ObserveDuration(MetricTokenRefreshDuration, time.Since(start), map[string]string{"appid": appID, "result": "ok"})
+	metrics.SetGauge(MetricTokenExpiresIn, float64(expiresIn), map[string]string{"appid": appID})
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxauth_test
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+type recordMetrics struct {
+	mu        sync.Mutex
+	counters  map[string]int
+	gauges    map[string]float64
+	durations map[string]int
+}
+
+func newRecordMetrics() *recordMetrics {
+	return &recordMetrics{counters: map[string]int{}, gauges: map[string]float64{}, durations: map[string]int{}}
+}
+
+func metricKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+
+	return name + "{" + strings.Join(keys, ",") + "}"
+}
+
+func (m *recordMetrics) IncCounter(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)]++
+}
+
+func (m *recordMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[metricKey(name, labels)] = value
+}
+
+func (m *recordMetrics) ObserveDuration(name string, _ time.Duration, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations[metricKey(name, labels)]++
+}
+
+func TestTokenMetrics(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	metrics := newRecordMetrics()
+	svc := vwxauth.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithMetricsCollector(metrics)))
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.GetAccessToken()
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, metrics.counters["vwx_token_cache_misses_total{appid=appid,reason=miss}"])
+	assert.Equal(t, 2, metrics.counters["vwx_token_cache_hits_total{appid=appid}"])
+	assert.Equal(t, 1, metrics.durations["vwx_token_refresh_duration_seconds{appid=appid,result=ok}"])
+	assert.Equal(t, float64(7200), metrics.gauges["vwx_token_expires_in_seconds{appid=appid}"])
+
+	// a client without cache fetches tokens on every call
+	server.SetError("/cgi-bin/token", 40013, "invalid appid")
+	svc = vwxauth.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithMetricsCollector(metrics), vwx.WithCacheProvider(nil)))
+
+	_, err := svc.GetAccessToken()
+	assert.Error(t, err)
+	assert.Equal(t, 1, metrics.counters["vwx_token_cache_misses_total{appid=appid,reason=no_cache}"])
+	assert.Equal(t, 1, metrics.counters["vwx_token_refresh_failures_total{appid=appid,errcode=40013}"])
+}
@@ -18,14 +18,13 @@
 package vwxauth
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"runtime/debug"
-
-	"github.com/vogo/vogo/vlog"
 )
 
 // PhoneEncryptedData represents the encrypted phone data from WeChat Mini Program.
@@ -44,6 +43,11 @@ type PhoneInfo struct {
 
 // ParsePhoneEncryptedData parses and decrypts phone encrypted data from WeChat Mini Program.
 func (c *Service) ParsePhoneEncryptedData(data []byte) (*PhoneInfo, *SessionResponse, error) {
+	return c.ParsePhoneEncryptedDataContext(context.Background(), data)
+}
+
+// ParsePhoneEncryptedDataContext is ParsePhoneEncryptedData with the given context.
+func (c *Service) ParsePhoneEncryptedDataContext(ctx context.Context, data []byte) (*PhoneInfo, *SessionResponse, error) {
 	var encData PhoneEncryptedData
 	err := json.Unmarshal(data, &encData)
 	if err != nil {
@@ -54,7 +58,7 @@ func (c *Service) ParsePhoneEncryptedData(data []byte) (*PhoneInfo, *SessionResp
 		return nil, nil, fmt.Errorf("code or encryptedData or iv is empty")
 	}
 
-	sessionInfo, err := c.GetSessionKey(encData.Code)
+	sessionInfo, err := c.GetSessionKeyContext(ctx, encData.Code)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -71,12 +75,12 @@ func (c *Service) ParsePhoneEncryptedData(data []byte) (*PhoneInfo, *SessionResp
 func (c *Service) DecryptPhoneNumber(sessionKey, encryptedData, iv string) (_info *PhoneInfo, _err error) {
 	defer func() {
 		if err := recover(); err != nil {
-			vlog.Errorf("failed to decrypt phone number | err: %v | stack: %s", err, debug.Stack())
+			c.client.Logger.Errorf("failed to decrypt phone number | err: %v | stack: %s", err, debug.Stack())
 			_err = fmt.Errorf("decrypt phone number error: %v", err)
 		}
 	}()
 
-	vlog.Infof("decrypt phone number | sessionKey: %s | encryptedData: %s | iv: %s",
+	c.client.Logger.Infof("decrypt phone number | sessionKey: %s | encryptedData: %s | iv: %s",
 		sessionKey, encryptedData, iv)
 
 	key, err := base64.StdEncoding.DecodeString(sessionKey)
@@ -105,7 +109,7 @@ func (c *Service) DecryptPhoneNumber(sessionKey, encryptedData, iv string) (_inf
 	// 处理 PKCS#7 填充
 	cipherText = pkcs7Unpad(cipherText)
 	if cipherText == nil {
-		vlog.Errorf("failed to decrypt phone number | err: unpad failed")
+		c.client.Logger.Errorf("failed to decrypt phone number | err: unpad failed")
 		return nil, fmt.Errorf("unpad failed")
 	}
 
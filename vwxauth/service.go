@@ -17,12 +17,25 @@
 
 package vwxauth
 
-import "github.com/vogo/vwx"
+import (
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/token"
+)
 
 type Service struct {
 	client *vwx.Client
+	tokens *token.Manager
 }
 
+// NewService creates the auth service, it installs the access token refresher of the client
+// if not set, so that calls failing with an invalidated token are resent with a new one.
 func NewService(client *vwx.Client) *Service {
-	return &Service{client: client}
+	s := &Service{client: client}
+	s.tokens = s.newTokenManager()
+
+	if client.TokenRefresher == nil {
+		client.TokenRefresher = s.RefreshAccessToken
+	}
+
+	return s
 }
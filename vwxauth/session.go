@@ -18,11 +18,11 @@
 package vwxauth
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
@@ -40,28 +40,27 @@ type SessionResponse struct {
 
 // GetSessionKey retrieves session key from WeChat using authorization code.
 func (c *Service) GetSessionKey(code string) (*SessionResponse, error) {
-	vlog.Infof("get session key | appid: %s | code: %s", c.client.AppID, code)
-
-	url := fmt.Sprintf(jsCode2SessionURL, c.client.AppID, c.client.AppSecret, code)
+	return c.GetSessionKeyContext(context.Background(), code)
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+// GetSessionKeyContext is GetSessionKey with the given context.
+func (c *Service) GetSessionKeyContext(ctx context.Context, code string) (*SessionResponse, error) {
+	if c.client.SecretFree() {
+		return nil, vwx.ErrAppSecretRequired
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
 
-	var result SessionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	call := &wxapi.Call{
+		Client:  c.client,
+		Name:    "get session key",
+		URL:     jsCode2SessionURL,
+		Args:    []any{c.client.AppID, c.client.AppSecret, code},
+		Summary: fmt.Sprintf("appid: %s | code: %s", c.client.AppID, code),
 	}
 
-	if result.ErrCode != 0 {
-		return nil, fmt.Errorf("wechat error: %d %s", result.ErrCode, result.ErrMsg)
+	result, err := wxapi.GetJSON[SessionResponse](ctx, call)
+	if err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return result, nil
 }
@@ -18,51 +18,156 @@
 package vwxauth
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/token"
 )
 
 const (
 	accessTokenURL = "https://api.weixin.qq.com/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s"
+	stableTokenURL = "https://api.weixin.qq.com/cgi-bin/stable_token"
 )
 
+// stableTokenRequest represents the request of the stable_token endpoint.
+type stableTokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	AppID        string `json:"appid"`
+	Secret       string `json:"secret"`
+	ForceRefresh bool   `json:"force_refresh"` // 是否强制刷新，强制刷新会使之前的token失效，每天限用20次
+}
+
 func (c *Service) cacheKeyAccessToken() string {
 	return c.client.CacheKeyPrefix + "vwxa:access_token:" + c.client.AppID
 }
 
+// newTokenManager returns the manager of the access token of the client.
+func (c *Service) newTokenManager() *token.Manager {
+	return &token.Manager{
+		Client:   c.client,
+		Name:     "access token",
+		CacheKey: c.cacheKeyAccessToken(),
+		Fetch:    c.requestAccessToken,
+		OnCacheHit: func() {
+			c.incCounter(MetricTokenCacheHits, map[string]string{})
+		},
+		OnCacheMiss: func(reason string) {
+			c.incCounter(MetricTokenCacheMisses, map[string]string{"reason": reason})
+		},
+		OnFetch: c.observeTokenRefresh,
+	}
+}
+
 // GetAccessToken retrieves access token from WeChat API with caching support.
 func (c *Service) GetAccessToken() (string, error) {
-	if c.client.CacheProvider != nil {
-		cachedToken := c.client.CacheProvider.Get(context.Background(), c.cacheKeyAccessToken())
-		if cachedToken != "" {
-			return cachedToken, nil
-		}
+	return c.GetAccessTokenContext(context.Background())
+}
+
+// GetAccessTokenContext is GetAccessToken with the given context.
+func (c *Service) GetAccessTokenContext(ctx context.Context) (string, error) {
+	if p := c.client.TokenProvider(); p != nil {
+		return p.AccessToken(ctx)
+	}
+
+	if c.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return c.tokens.Get(ctx)
+}
+
+// Token is GetAccessTokenContext, making the service the token source of the endpoint groups of
+// another client served by this app, see vwx.WithEndpointTokenSource.
+func (c *Service) Token(ctx context.Context) (string, error) {
+	return c.GetAccessTokenContext(ctx)
+}
+
+// RefreshAccessToken replaces the stale access token invalidated by WeChat with a new one. The cached
+// token is returned if it was already refreshed by another call, tokens of an external provider are
+// refreshed by the provider. Stable tokens are fetched with force_refresh, which is limited to 20 times a day.
+func (c *Service) RefreshAccessToken(ctx context.Context, staleToken string) (string, error) {
+	if p := c.client.TokenProvider(); p != nil {
+		return p.RefreshAccessToken(ctx, staleToken)
+	}
+
+	if c.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return c.tokens.Refresh(ctx, staleToken)
+}
+
+// ForceRefreshAccessToken discards the cached access token, fetches a fresh one, caches and returns it.
+// Stable tokens are fetched with force_refresh, which invalidates the previous token and is limited
+// to 20 times a day.
+func (c *Service) ForceRefreshAccessToken() (string, error) {
+	return c.ForceRefreshAccessTokenContext(context.Background())
+}
+
+// ForceRefreshAccessTokenContext is ForceRefreshAccessToken with the given context.
+func (c *Service) ForceRefreshAccessTokenContext(ctx context.Context) (string, error) {
+	if p := c.client.TokenProvider(); p != nil {
+		return p.RefreshAccessToken(ctx, "")
 	}
 
-	url := fmt.Sprintf(accessTokenURL, c.client.AppID, c.client.AppSecret)
+	if c.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return c.tokens.ForceRefresh(ctx)
+}
 
-	resp, err := http.Get(url)
+// requestAccessToken requests a new access token from WeChat, the token is returned with its
+// validity in seconds.
+func (c *Service) requestAccessToken(ctx context.Context, forceRefresh bool) (string, int, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	c.client.Logger.Infof("get access token | request_id: %s | appid: %s | stable: %t | force_refresh: %t",
+		requestID, c.client.AppID, c.client.StableToken, forceRefresh)
+
+	var (
+		apiURL string
+		resp   *http.Response
+		err    error
+	)
+
+	if c.client.StableToken {
+		apiURL = stableTokenURL
+
+		data, marshalErr := json.Marshal(&stableTokenRequest{
+			GrantType:    "client_credential",
+			AppID:        c.client.AppID,
+			Secret:       c.client.AppSecret,
+			ForceRefresh: forceRefresh,
+		})
+		if marshalErr != nil {
+			return "", 0, fmt.Errorf("marshal request error: %w", marshalErr)
+		}
+
+		// a forced refresh invalidates the previous token, it must not be sent twice
+		if !forceRefresh {
+			ctx = vwx.Idempotent(ctx)
+		}
+
+		resp, err = c.client.Post(ctx, apiURL, "application/json", bytes.NewReader(data))
+	} else {
+		apiURL = accessTokenURL
+		resp, err = c.client.Get(vwx.Idempotent(ctx), fmt.Sprintf(accessTokenURL, c.client.AppID, c.client.AppSecret))
+	}
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
+			c.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
 		}
 	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
 	var result struct {
 		AccessToken string `json:"access_token"`
 		ExpiresIn   int    `json:"expires_in"`
@@ -70,22 +175,17 @@ func (c *Service) GetAccessToken() (string, error) {
 		ErrMsg      string `json:"errmsg"`
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
+	if err := vwx.CheckContentType(resp, vwx.JSONContentTypes...); err != nil {
+		return "", 0, err
 	}
 
-	if result.ErrCode != 0 {
-		return "", errors.New(result.ErrMsg)
+	if err := json.NewDecoder(c.client.LimitResponse(resp)).Decode(&result); err != nil {
+		return "", 0, err
 	}
 
-	// cache access token
-	if c.client.CacheProvider != nil {
-		expireTime := time.Duration(result.ExpiresIn-300) * time.Second
-		if err := c.client.CacheProvider.Set(context.Background(),
-			c.cacheKeyAccessToken(), result.AccessToken, expireTime); err != nil {
-			vlog.Errorf("failed to set access token to cache | err: %v", err)
-		}
+	if result.ErrCode != 0 {
+		return "", 0, vwx.NewAPIError(apiURL, result.ErrCode, result.ErrMsg, requestID)
 	}
 
-	return result.AccessToken, nil
+	return result.AccessToken, result.ExpiresIn, nil
 }
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxauth_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+type delayTransport struct {
+	base  http.RoundTripper
+	delay time.Duration
+}
+
+func (t *delayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	time.Sleep(t.delay)
+	return t.base.RoundTrip(req)
+}
+
+func TestConcurrentAccessTokenFetch(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwx.WithHTTPClient(&http.Client{
+		Transport: &delayTransport{base: server.HTTPClient().Transport, delay: 50 * time.Millisecond},
+	}))
+	authSvc := vwxauth.NewService(client)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := authSvc.GetAccessToken()
+			assert.NoError(t, err)
+			assert.Equal(t, vwxmock.AccessToken, token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, server.Requests("/cgi-bin/token"), 1)
+}
+
+type mapCache map[string]string
+
+func (m mapCache) Get(_ context.Context, key string) string {
+	return m[key]
+}
+
+func (m mapCache) Set(_ context.Context, key, value string, _ time.Duration) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapCache) Delete(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestStableToken(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	cache := mapCache{}
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server),
+		vwx.WithCacheProvider(cache), vwx.WithStableToken(true))
+
+	token, err := vwxauth.NewService(client).GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, token)
+
+	requests := server.Requests("/cgi-bin/stable_token")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, `{"grant_type":"client_credential","appid":"appid","secret":"secret","force_refresh":false}`, string(requests[0].Body))
+	assert.Empty(t, server.Requests("/cgi-bin/token"))
+
+	// an invalidated stable token is refreshed with force_refresh
+	server.AccessToken = "rotated-token"
+	_, err = vwxa.NewService(client).GenerateSimpleURLLink("/pages/index", "")
+	assert.NoError(t, err)
+
+	requests = server.Requests("/cgi-bin/stable_token")
+	assert.Len(t, requests, 2)
+	assert.Contains(t, string(requests[1].Body), `"force_refresh":true`)
+	assert.Equal(t, "rotated-token", cache["vwxa:access_token:appid"])
+}
+
+func TestSharedAccessTokenRefresh(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	// the instances share the cache and the lock, e.g. the redis cache
+	cache := vwx.NewMemoryCache()
+	locker := vwx.NewMemoryLocker()
+
+	var wg sync.WaitGroup
+	for range 3 {
+		client := vwx.NewClient("appid", "secret", vwx.WithCacheProvider(cache), vwx.WithLocker(locker),
+			vwx.WithHTTPClient(&http.Client{
+				Transport: &delayTransport{base: server.HTTPClient().Transport, delay: 50 * time.Millisecond},
+			}))
+		authSvc := vwxauth.NewService(client)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := authSvc.GetAccessToken()
+			assert.NoError(t, err)
+			assert.Equal(t, vwxmock.AccessToken, token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, server.Requests("/cgi-bin/token"), 1)
+}
+
+// rotatingProvider issues a stale token first, then the token of the mock server once refreshed.
+type rotatingProvider struct {
+	mu     sync.Mutex
+	token  string
+	stales []string
+}
+
+func (p *rotatingProvider) AccessToken(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.token, nil
+}
+
+func (p *rotatingProvider) RefreshAccessToken(_ context.Context, staleToken string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stales = append(p.stales, staleToken)
+	p.token = vwxmock.AccessToken
+
+	return p.token, nil
+}
+
+func TestAccessTokenProvider(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	provider := &rotatingProvider{token: "stale-token"}
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithAccessTokenProvider(provider))
+
+	// the stale token is refreshed by the provider and the call is sent once more
+	_, err := vwxa.NewService(client).GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stale-token"}, provider.stales)
+
+	_, err = vwxmp.NewService(client).GetMenu()
+	assert.NoError(t, err)
+
+	token, err := vwxauth.NewService(client).ForceRefreshAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, token)
+	assert.Equal(t, []string{"stale-token", ""}, provider.stales)
+
+	// the SDK never fetches tokens itself even with the AppSecret
+	assert.Empty(t, server.Requests("/cgi-bin/token"))
+	assert.Empty(t, server.Requests("/cgi-bin/stable_token"))
+
+	// the provider takes precedence over the token source
+	client = vwx.NewSecretFreeClient("appid", vwx.StaticTokenSource("source-token"),
+		vwxmock.WithServer(server), vwx.WithAccessTokenProvider(provider))
+
+	svc, err := vwxa.NewSecretFreeService(client)
+	assert.NoError(t, err)
+
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+}
+
+func TestForceRefreshAccessToken(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	var deleted []string
+	cache := &recordDeleteCache{mapCache: mapCache{"vwxa:access_token:appid": "bad-token"}, deleted: &deleted}
+	svc := vwxauth.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithCacheProvider(cache)))
+
+	token, err := svc.ForceRefreshAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, token)
+	assert.Equal(t, []string{"vwxa:access_token:appid"}, deleted)
+	assert.Equal(t, vwxmock.AccessToken, cache.mapCache["vwxa:access_token:appid"])
+	assert.Len(t, server.Requests("/cgi-bin/token"), 1)
+}
+
+type recordDeleteCache struct {
+	mapCache
+	deleted *[]string
+}
+
+func (c *recordDeleteCache) Delete(ctx context.Context, key string) error {
+	*c.deleted = append(*c.deleted, key)
+	return c.mapCache.Delete(ctx, key)
+}
+
+func TestRefreshInvalidatedAccessToken(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	cache := mapCache{"vwxa:access_token:appid": "stale-token"}
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithCacheProvider(cache))
+	svc := vwxa.NewService(client)
+
+	_, err := svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, cache["vwxa:access_token:appid"])
+	assert.Len(t, server.Requests("/cgi-bin/token"), 1)
+
+	requests := server.Requests("/wxa/generate_urllink")
+	assert.Len(t, requests, 2)
+	assert.Equal(t, requests[0].Body, requests[1].Body)
+
+	// the token invalidated on the server is refreshed once, other failures are returned as is
+	server.AccessToken = "rotated-token"
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated-token", cache["vwxa:access_token:appid"])
+
+	server.SetError("/wxa/generate_urllink", 40001, "invalid credential")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.True(t, vwx.IsErrCode(err, vwx.ErrCodeInvalidCredential))
+	assert.Len(t, server.Requests("/cgi-bin/token"), 3)
+
+	// the overridden token is not owned by the client, it is never replaced by the token of the client
+	server.ClearError("/wxa/generate_urllink")
+	sent := len(server.Requests("/wxa/generate_urllink"))
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1", vwx.WithAccessToken("authorizer-token"))
+	assert.True(t, vwx.IsErrCode(err, vwx.ErrCodeInvalidCredential))
+	assert.Len(t, server.Requests("/cgi-bin/token"), 3)
+
+	requests = server.Requests("/wxa/generate_urllink")[sent:]
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "authorizer-token", requests[0].Query.Get("access_token"))
+}
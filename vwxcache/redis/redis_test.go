@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx/vwxcache/redis"
+)
+
+func TestCache(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	cache, err := redis.NewFromURL("redis://"+server.Addr(), redis.WithKeyPrefix("vwx:"))
+	assert.NoError(t, err)
+	defer func() { _ = cache.Close() }()
+
+	ctx := context.Background()
+
+	assert.Empty(t, cache.Get(ctx, "token"))
+	assert.NoError(t, cache.Set(ctx, "token", "value", time.Minute))
+	assert.Equal(t, "value", cache.Get(ctx, "token"))
+
+	stored, err := server.Get("vwx:token")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", stored)
+	assert.Equal(t, time.Minute, server.TTL("vwx:token"))
+
+	server.FastForward(time.Minute)
+	assert.Empty(t, cache.Get(ctx, "token"))
+
+	assert.NoError(t, cache.Set(ctx, "ticket", "value", 0))
+	assert.Equal(t, time.Duration(0), server.TTL("vwx:ticket"))
+
+	assert.NoError(t, cache.Delete(ctx, "ticket"))
+	assert.Empty(t, cache.Get(ctx, "ticket"))
+
+	_, err = redis.NewFromURL("invalid://localhost")
+	assert.Error(t, err)
+}
+
+func TestLocker(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	instance1, err := redis.NewFromURL("redis://"+server.Addr(), redis.WithKeyPrefix("vwx:"))
+	assert.NoError(t, err)
+	defer func() { _ = instance1.Close() }()
+
+	instance2, err := redis.NewFromURL("redis://" + server.Addr())
+	assert.NoError(t, err)
+	defer func() { _ = instance2.Close() }()
+
+	ctx := context.Background()
+
+	locked, err := instance1.TryLock(ctx, "lock", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	assert.Equal(t, time.Minute, server.TTL("vwx:lock"))
+
+	locked, err = instance2.TryLock(ctx, "vwx:lock", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	// the lock is only released by its holder
+	assert.NoError(t, instance2.Unlock(ctx, "vwx:lock"))
+	assert.True(t, server.Exists("vwx:lock"))
+
+	assert.NoError(t, instance1.Unlock(ctx, "lock"))
+	assert.False(t, server.Exists("vwx:lock"))
+
+	locked, err = instance2.TryLock(ctx, "vwx:lock", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+}
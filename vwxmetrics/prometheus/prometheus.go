@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package prometheus exports the metrics of the SDK to Prometheus, it is both a
+// vwx.MetricsHook observing the API calls and a vwx.MetricsCollector of the SDK metrics.
+package prometheus
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/vogo/vwx"
+)
+
+// Names of the API call metrics.
+const (
+	MetricAPIRequests        = "vwx_api_requests_total"
+	MetricAPIRequestDuration = "vwx_api_request_duration_seconds"
+	MetricAPIQuotaRejections = "vwx_api_quota_rejections_total"
+	MetricAPIInflight        = "vwx_api_requests_in_flight"
+)
+
+// Collector is a prometheus.Collector exporting the API call metrics and the SDK metrics,
+// register it on a prometheus.Registerer and set it on the client by vwx.WithMetricsHook
+// and vwx.WithMetricsCollector.
+type Collector struct {
+	buckets []float64
+
+	requests        *prom.CounterVec
+	requestDuration *prom.HistogramVec
+	quotaRejections *prom.CounterVec
+	inflight        *prom.GaugeVec
+
+	mu         sync.RWMutex
+	counters   map[string]*prom.CounterVec
+	gauges     map[string]*prom.GaugeVec
+	histograms map[string]*prom.HistogramVec
+}
+
+var (
+	_ prom.Collector       = (*Collector)(nil)
+	_ vwx.MetricsHook      = (*Collector)(nil)
+	_ vwx.MetricsCollector = (*Collector)(nil)
+)
+
+// New creates a collector.
+func New(options ...func(*Collector)) *Collector {
+	c := &Collector{
+		buckets:    prom.DefBuckets,
+		counters:   make(map[string]*prom.CounterVec),
+		gauges:     make(map[string]*prom.GaugeVec),
+		histograms: make(map[string]*prom.HistogramVec),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.requests = prom.NewCounterVec(prom.CounterOpts{
+		Name: MetricAPIRequests,
+		Help: "Calls to the WeChat APIs by endpoint and errcode.",
+	}, []string{"appid", "endpoint", "errcode"})
+
+	c.requestDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Name:    MetricAPIRequestDuration,
+		Help:    "Latency of the calls to the WeChat APIs, including retries.",
+		Buckets: c.buckets,
+	}, []string{"appid", "endpoint"})
+
+	c.quotaRejections = prom.NewCounterVec(prom.CounterOpts{
+		Name: MetricAPIQuotaRejections,
+		Help: "Calls to the WeChat APIs rejected for the api quota (errcode 45009) or frequency limit (errcode 45011).",
+	}, []string{"appid", "endpoint", "errcode"})
+
+	c.inflight = prom.NewGaugeVec(prom.GaugeOpts{
+		Name: MetricAPIInflight,
+		Help: "Calls to the WeChat APIs in flight.",
+	}, []string{"appid"})
+
+	return c
+}
+
+// WithBuckets sets the buckets in seconds of the duration histograms, prometheus.DefBuckets by default.
+func WithBuckets(buckets ...float64) func(*Collector) {
+	return func(c *Collector) {
+		c.buckets = buckets
+	}
+}
+
+// OnRequest counts the call in flight.
+func (c *Collector) OnRequest(_ context.Context, call *vwx.APICall) {
+	c.inflight.WithLabelValues(call.AppID).Inc()
+}
+
+// OnResponse records the result and the latency of the call.
+func (c *Collector) OnResponse(_ context.Context, call *vwx.APICall) {
+	c.inflight.WithLabelValues(call.AppID).Dec()
+
+	code := errCodeLabel(call)
+
+	c.requests.WithLabelValues(call.AppID, call.Endpoint, code).Inc()
+	c.requestDuration.WithLabelValues(call.AppID, call.Endpoint).Observe(call.Duration.Seconds())
+
+	switch call.ErrCode {
+	case vwx.ErrCodeDailyQuotaLimit, vwx.ErrCodeMinuteQuotaLimit:
+		c.quotaRejections.WithLabelValues(call.AppID, call.Endpoint, code).Inc()
+	}
+}
+
+// errCodeLabel is the errcode of the call, "error" for request errors and "http_<status>" for
+// error responses without errcode.
+func errCodeLabel(call *vwx.APICall) string {
+	switch {
+	case call.Err != nil:
+		return "error"
+	case call.ErrCode != 0:
+		return strconv.Itoa(call.ErrCode)
+	case call.Status >= 400:
+		return "http_" + strconv.Itoa(call.Status)
+	default:
+		return "0"
+	}
+}
+
+// IncCounter increments the counter by one.
+func (c *Collector) IncCounter(name string, labels map[string]string) {
+	keys, values := splitLabels(labels)
+
+	vec := getOrCreate(&c.mu, c.counters, name, func() *prom.CounterVec {
+		return prom.NewCounterVec(prom.CounterOpts{Name: name, Help: help(name)}, keys)
+	})
+
+	vec.WithLabelValues(values...).Inc()
+}
+
+// SetGauge sets the gauge to the value.
+func (c *Collector) SetGauge(name string, value float64, labels map[string]string) {
+	keys, values := splitLabels(labels)
+
+	vec := getOrCreate(&c.mu, c.gauges, name, func() *prom.GaugeVec {
+		return prom.NewGaugeVec(prom.GaugeOpts{Name: name, Help: help(name)}, keys)
+	})
+
+	vec.WithLabelValues(values...).Set(value)
+}
+
+// ObserveDuration records the duration in the histogram.
+func (c *Collector) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	keys, values := splitLabels(labels)
+
+	vec := getOrCreate(&c.mu, c.histograms, name, func() *prom.HistogramVec {
+		return prom.NewHistogramVec(prom.HistogramOpts{Name: name, Help: help(name), Buckets: c.buckets}, keys)
+	})
+
+	vec.WithLabelValues(values...).Observe(d.Seconds())
+}
+
+// Describe sends the descriptors of the API call metrics, the SDK metrics are created on first use
+// and left undescribed.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.quotaRejections.Describe(ch)
+	c.inflight.Describe(ch)
+}
+
+// Collect sends the API call metrics and the SDK metrics.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.quotaRejections.Collect(ch)
+	c.inflight.Collect(ch)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, vec := range c.counters {
+		vec.Collect(ch)
+	}
+
+	for _, vec := range c.gauges {
+		vec.Collect(ch)
+	}
+
+	for _, vec := range c.histograms {
+		vec.Collect(ch)
+	}
+}
+
+// getOrCreate returns the vec of the name, creating it on first use. The label keys of a name are
+// fixed by its first use.
+func getOrCreate[V any](mu *sync.RWMutex, vecs map[string]V, name string, create func() V) V {
+	mu.RLock()
+	vec, ok := vecs[name]
+	mu.RUnlock()
+
+	if ok {
+		return vec
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if vec, ok = vecs[name]; !ok {
+		vec = create()
+		vecs[name] = vec
+	}
+
+	return vec
+}
+
+// splitLabels splits the labels into the keys and values sorted by key.
+func splitLabels(labels map[string]string) ([]string, []string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = labels[key]
+	}
+
+	return keys, values
+}
+
+// help describes the SDK metric of the name, e.g. "vwx token cache hits total".
+func help(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+func TestCollector(t *testing.T) {
+	collector := New(WithBuckets(0.1, 1))
+
+	registry := prom.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	ctx := context.Background()
+	call := func(errCode, status int, err error) {
+		c := &vwx.APICall{AppID: "appid", Endpoint: "/wxa/getwxacodeunlimit", Duration: 50 * time.Millisecond,
+			Status: status, ErrCode: errCode, Err: err}
+		collector.OnRequest(ctx, c)
+		collector.OnResponse(ctx, c)
+	}
+
+	call(0, 200, nil)
+	call(0, 200, nil)
+	call(vwx.ErrCodeMinuteQuotaLimit, 200, nil)
+	call(0, 502, nil)
+	call(0, 0, errors.New("timeout"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.requests.WithLabelValues("appid", "/wxa/getwxacodeunlimit", "0")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("appid", "/wxa/getwxacodeunlimit", "45011")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("appid", "/wxa/getwxacodeunlimit", "http_502")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("appid", "/wxa/getwxacodeunlimit", "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.quotaRejections.WithLabelValues("appid", "/wxa/getwxacodeunlimit", "45011")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(collector.inflight.WithLabelValues("appid")))
+
+	// the sdk metrics are created on first use
+	collector.IncCounter("vwx_token_cache_misses_total", map[string]string{"appid": "appid", "reason": "miss"})
+	collector.SetGauge("vwx_token_expires_in_seconds", 7200, map[string]string{"appid": "appid"})
+	collector.ObserveDuration("vwx_token_refresh_duration_seconds", 200*time.Millisecond, map[string]string{"appid": "appid", "result": "ok"})
+
+	expected := `
+# HELP vwx_token_cache_misses_total vwx token cache misses total
+# TYPE vwx_token_cache_misses_total counter
+vwx_token_cache_misses_total{appid="appid",reason="miss"} 1
+# HELP vwx_token_expires_in_seconds vwx token expires in seconds
+# TYPE vwx_token_expires_in_seconds gauge
+vwx_token_expires_in_seconds{appid="appid"} 7200
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"vwx_token_cache_misses_total", "vwx_token_expires_in_seconds"))
+
+	count, err := testutil.GatherAndCount(registry, "vwx_token_refresh_duration_seconds", MetricAPIRequestDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,308 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmock
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vogo/vwx"
+)
+
+const redacted = "REDACTED"
+
+// RecorderMode represents the working mode of the Recorder.
+type RecorderMode int
+
+const (
+	// ModeReplay replays responses from golden files, and fails if a fixture is missing.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends requests to the real WeChat APIs and records the responses to golden files.
+	ModeRecord
+	// ModeAuto replays existing fixtures and records the missing ones.
+	ModeAuto
+)
+
+// DefaultRedactKeys are the query parameters and json fields redacted from fixtures.
+var DefaultRedactKeys = []string{
+	"access_token", "secret", "appsecret", "session_key", "refresh_token", "js_code", "code", "ticket",
+}
+
+// Fixture is the content of a golden file.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is the redacted request of a fixture.
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// FixtureResponse is the redacted response of a fixture.
+type FixtureResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Base64      bool   `json:"base64,omitempty"` // whether the body is base64 encoded binary data
+}
+
+// Recorder is a VCR-style http.RoundTripper recording WeChat responses to golden files
+// and replaying them, with secrets redacted.
+//
+// Fixtures are matched by method, path, redacted query and redacted body, so requests
+// carrying volatile values (e.g. timestamps) should be avoided in replayed tests.
+type Recorder struct {
+	Mode       RecorderMode
+	Dir        string            // golden files directory
+	Transport  http.RoundTripper // transport to send real requests, default http.DefaultTransport
+	RedactKeys []string          // query parameters and json fields to redact
+}
+
+// NewRecorder creates a recorder storing golden files in dir.
+func NewRecorder(dir string, mode RecorderMode) *Recorder {
+	return &Recorder{
+		Mode:       mode,
+		Dir:        dir,
+		RedactKeys: DefaultRedactKeys,
+	}
+}
+
+// WithRecorder returns a client option sending all requests through the recorder.
+func WithRecorder(r *Recorder) func(*vwx.Client) {
+	return vwx.WithHTTPClient(&http.Client{Transport: r})
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	fixtureReq := FixtureRequest{
+		Method: req.Method,
+		URL:    r.redactURL(req),
+		Body:   string(r.redactJSON(reqBody)),
+	}
+	file := r.fixturePath(&fixtureReq)
+
+	if r.Mode != ModeRecord {
+		fixture, err := loadFixture(file)
+		if err == nil {
+			return fixture.Response.toHTTPResponse(req)
+		}
+
+		if r.Mode == ModeReplay || !os.IsNotExist(err) {
+			return nil, fmt.Errorf("load fixture %s for %s %s error: %w", file, fixtureReq.Method, fixtureReq.URL, err)
+		}
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{
+		Request: fixtureReq,
+		Response: FixtureResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+	}
+
+	if utf8.Valid(respBody) {
+		fixture.Response.Body = string(r.redactJSON(respBody))
+	} else {
+		fixture.Response.Body = base64.StdEncoding.EncodeToString(respBody)
+		fixture.Response.Base64 = true
+	}
+
+	if err := saveFixture(file, fixture); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	return resp, nil
+}
+
+func (r *Recorder) fixturePath(req *FixtureRequest) string {
+	h := sha1.New()
+	h.Write([]byte(req.Method + " " + req.URL + "\n" + req.Body))
+	sum := hex.EncodeToString(h.Sum(nil))[:12]
+
+	path := req.URL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.ReplaceAll(path, "/", "_")
+
+	return filepath.Join(r.Dir, fmt.Sprintf("%s_%s_%s.json", strings.ToLower(req.Method), path, sum))
+}
+
+func (r *Recorder) redactURL(req *http.Request) string {
+	u := *req.URL
+	query := u.Query()
+	for _, key := range r.RedactKeys {
+		if query.Has(key) {
+			query.Set(key, redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// redactJSON replaces the values of the redact keys in json data,
+// non-json data is returned as is.
+func (r *Recorder) redactJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+
+	r.redactValue(v)
+
+	redactedData, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+
+	return redactedData
+}
+
+func (r *Recorder) redactValue(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if r.isRedactKey(k) {
+				val[k] = redacted
+				continue
+			}
+			r.redactValue(item)
+		}
+	case []any:
+		for _, item := range val {
+			r.redactValue(item)
+		}
+	}
+}
+
+func (r *Recorder) isRedactKey(key string) bool {
+	for _, k := range r.RedactKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FixtureResponse) toHTTPResponse(req *http.Request) (*http.Response, error) {
+	body := []byte(f.Body)
+	if f.Base64 {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(f.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decode fixture body error: %w", err)
+		}
+	}
+
+	header := make(http.Header)
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func loadFixture(file string) (*Fixture, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, err
+	}
+
+	return &fixture, nil
+}
+
+func saveFixture(file string, fixture *Fixture) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
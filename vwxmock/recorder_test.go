@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmock_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	server := vwxmock.NewServer()
+
+	recorder := vwxmock.NewRecorder(dir, vwxmock.ModeRecord)
+	recorder.Transport = server.HTTPClient().Transport
+
+	svc := vwxa.NewService(vwx.NewClient("appid", "secret", vwxmock.WithRecorder(recorder)))
+	recordedLink, err := svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+
+	recordedImage, err := svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+
+	server.Close()
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		assert.NoError(t, err)
+		assert.False(t, strings.Contains(string(data), vwxmock.AccessToken), "fixture %s contains access token", file.Name())
+		assert.False(t, strings.Contains(string(data), "secret=secret"), "fixture %s contains app secret", file.Name())
+	}
+
+	replayer := vwxmock.NewRecorder(dir, vwxmock.ModeReplay)
+	svc = vwxa.NewService(vwx.NewClient("appid", "secret", vwxmock.WithRecorder(replayer)))
+
+	link, err := svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.Equal(t, recordedLink, link)
+
+	image, err := svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, recordedImage, image)
+
+	_, err = svc.GenerateSimpleURLLink("/pages/other", "")
+	assert.Error(t, err)
+}
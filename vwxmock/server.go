@@ -0,0 +1,1901 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxmock provides an httptest based fake WeChat API server,
+// so that integration tests don't hit the real WeChat APIs.
+package vwxmock
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	// AccessToken is the default access token issued by the mock server.
+	AccessToken = "mock-access-token"
+	// SessionKey is the base64 encoded session key issued by jscode2session.
+	SessionKey = "MDEyMzQ1Njc4OWFiY2RlZg=="
+	// OpenIDPrefix is prepended to the js code to build the openid returned by jscode2session
+	// and the web OAuth access token API.
+	OpenIDPrefix = "mock-openid-"
+	// OAuthAccessTokenPrefix is the prefix of the web OAuth access tokens issued by the mock server.
+	OAuthAccessTokenPrefix = "mock-oauth-access-token-"
+	// RefreshTokenPrefix is prepended to the openid to build the web OAuth refresh token.
+	RefreshTokenPrefix = "mock-refresh-token-"
+	// JSAPITicketPrefix is the prefix of the jsapi tickets issued by the mock server.
+	JSAPITicketPrefix = "mock-jsapi-ticket-"
+	// ChangedOpenIDPrefix is prepended to the original openid to build the openid converted by changeopenid.
+	ChangedOpenIDPrefix = "mock-changed-"
+	// ComponentAccessToken is the component access token issued to third-party platforms by the mock server.
+	ComponentAccessToken = "mock-component-access-token"
+	// AuthorizerRefreshTokenPrefix is prepended to the authorizer appid to build its refresh token.
+	AuthorizerRefreshTokenPrefix = "mock-authorizer-refresh-token-"
+	// ExternalUserIDPrefix is prepended to the member userid to build the external contact listed for it.
+	ExternalUserIDPrefix = "mock-external-"
+	// FollowUserID is the WeCom member following the external contacts and owning the group chats.
+	FollowUserID = "mock-follow-user"
+	// WorkMsgIDPrefix is the prefix of the WeCom app message ids recalled by the mock server.
+	WorkMsgIDPrefix = "mock-msgid-"
+	// WorkAgentID is the agentid of the WeCom app reported by the message statistics.
+	WorkAgentID = 1000002
+	// PurePhoneNumber is the phone number without country code returned by getuserphonenumber.
+	PurePhoneNumber = "13800138000"
+)
+
+// PNGHeader is the leading bytes of the fake QR code image returned by the mock server.
+var PNGHeader = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// CloudUploadPath is the path of the cloud storage upload url returned by the mock server.
+const CloudUploadPath = "/mock-cos/upload"
+
+// BillDownloadPath is the path of the download urls of the bills applied on the mock server.
+const BillDownloadPath = "/v3/billdownload/file"
+
+// QuotaDailyLimit is the daily quota of every api reported by the mock server, the quota can be
+// cleared 10 times.
+const QuotaDailyLimit = 100000
+
+// CallbackIPList is the ip list of the WeChat servers pushing messages returned by the mock server.
+var CallbackIPList = []string{"101.226.62.77", "101.226.62.78", "101.226.62.79"}
+
+// KFAccounts are the customer service accounts of the mock server, all online unless set by SetOnlineKFs.
+var KFAccounts = []string{"kf1@mock_account", "kf2@mock_account", "kf3@mock_account"}
+
+// APIDomainIPList is the ip list of the api domain returned by the mock server.
+var APIDomainIPList = []string{"101.89.47.18", "101.91.34.103"}
+
+// Request represents a request received by the mock server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake WeChat API server.
+type Server struct {
+	*httptest.Server
+
+	AccessToken string
+	ExpiresIn   int
+
+	// PayPrivateKey signs the responses of the WeChat Pay apis with PayPublicKeyID as serial if set.
+	PayPrivateKey  *rsa.PrivateKey
+	PayPublicKeyID string
+
+	// PublishingPolls is the number of freepublish/get polls reporting a publish task in progress
+	// before it succeeds.
+	PublishingPolls int
+
+	mu        sync.Mutex
+	errors    map[string]*apiError
+	responses map[string]any
+	raw       map[string]*rawResponse
+	requests  map[string][]*Request
+	media     map[string]bool
+	publishes map[string]int
+	masses    map[string]int64
+	files     map[string][]byte
+	menu      json.RawMessage
+	tokens    map[string]bool
+	quotaBase map[string]int
+	clears    int
+	rids      map[string]*ridCall
+	onlineKFs []string
+	kfSession map[string]*kfSession // openid -> session
+
+	authorizers []string
+	options     map[string]string
+	combines    map[string]*combineOrder
+	bills       map[string][]byte
+	comments    map[int64]int
+	msgRecords  []*msgRecord
+	links       map[string]*miniLink // scheme openlink or url link -> link
+	seq         atomic.Int64
+}
+
+// miniLink is a URL Scheme or URL Link generated on the mock server.
+type miniLink struct {
+	Path       string
+	Query      string
+	CreateTime int64
+	ExpireTime int64
+	EnvVersion string
+}
+
+// info returns the jump target and the validity of the link answered by the query apis.
+func (l *miniLink) info() map[string]any {
+	return map[string]any{
+		"appid":       "appid",
+		"path":        l.Path,
+		"query":       l.Query,
+		"create_time": l.CreateTime,
+		"expire_time": l.ExpireTime,
+		"env_version": l.EnvVersion,
+	}
+}
+
+// kfSession is the customer service session of a user.
+type kfSession struct {
+	account    string
+	createTime int64
+}
+
+// ridCall is the call answered with an error carrying a rid.
+type ridCall struct {
+	request  *Request
+	response *apiError
+	time     time.Time
+}
+
+type rawResponse struct {
+	contentType string
+	body        []byte
+}
+
+type apiError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// NewServer starts a new mock server, the caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		AccessToken: AccessToken,
+		ExpiresIn:   7200,
+		errors:      make(map[string]*apiError),
+		responses:   make(map[string]any),
+		raw:         make(map[string]*rawResponse),
+		requests:    make(map[string][]*Request),
+		media:       make(map[string]bool),
+		publishes:   make(map[string]int),
+		masses:      make(map[string]int64),
+		files:       make(map[string][]byte),
+		tokens:      make(map[string]bool),
+		quotaBase:   make(map[string]int),
+		rids:        make(map[string]*ridCall),
+		onlineKFs:   KFAccounts,
+		kfSession:   make(map[string]*kfSession),
+		options:     make(map[string]string),
+		combines:    make(map[string]*combineOrder),
+		bills:       make(map[string][]byte),
+		comments:    make(map[int64]int),
+		links:       make(map[string]*miniLink),
+
+		PublishingPolls: 1,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cgi-bin/token", s.handleToken)
+	mux.HandleFunc("/cgi-bin/stable_token", s.handleToken)
+	mux.HandleFunc("/sns/jscode2session", s.handleJsCode2Session)
+	mux.HandleFunc("/wxa/business/getuserphonenumber", s.withAccessToken(s.handleGetUserPhoneNumber))
+	mux.HandleFunc("/wxa/getuserriskrank", s.withAccessToken(s.handleGetUserRiskRank))
+	mux.HandleFunc("/sns/oauth2/access_token", s.handleOAuthAccessToken)
+	mux.HandleFunc("/sns/oauth2/refresh_token", s.handleOAuthRefreshToken)
+	mux.HandleFunc("/sns/userinfo", s.handleUserInfo)
+	mux.HandleFunc("/cgi-bin/media/upload", s.withAccessToken(s.handleMediaUpload))
+	mux.HandleFunc("/cgi-bin/media/get", s.withAccessToken(s.handleMediaGet))
+	mux.HandleFunc("/cgi-bin/media/uploadimg", s.withAccessToken(s.handleUploadImg))
+	mux.HandleFunc("/cgi-bin/menu/create", s.withAccessToken(s.handleMenuCreate))
+	mux.HandleFunc("/cgi-bin/menu/get", s.withAccessToken(s.handleMenuGet))
+	mux.HandleFunc("/cgi-bin/menu/delete", s.withAccessToken(s.handleMenuDelete))
+	mux.HandleFunc("/cgi-bin/ticket/getticket", s.withAccessToken(s.handleJSAPITicket))
+	mux.HandleFunc("/cgi-bin/qrcode/create", s.withAccessToken(s.handleQRCodeCreate))
+	mux.HandleFunc("/cgi-bin/user/info", s.withAccessToken(s.handleSubscriberInfo))
+	mux.HandleFunc("/cgi-bin/changeopenid", s.withAccessToken(s.handleChangeOpenID))
+	mux.HandleFunc("/cgi-bin/freepublish/submit", s.withAccessToken(s.handleFreePublishSubmit))
+	mux.HandleFunc("/cgi-bin/freepublish/get", s.withAccessToken(s.handleFreePublishGet))
+	mux.HandleFunc("/cgi-bin/message/mass/sendall", s.withAccessToken(s.handleMassSend))
+	mux.HandleFunc("/cgi-bin/message/mass/send", s.withAccessToken(s.handleMassSend))
+	mux.HandleFunc("/cgi-bin/message/mass/get", s.withAccessToken(s.handleMassGet))
+	mux.HandleFunc("/cgi-bin/message/custom/send", s.withAccessToken(s.handleCustomSend))
+	mux.HandleFunc("/cgi-bin/message/subscribe/send", s.withAccessToken(s.handleSubscribeSend))
+	mux.HandleFunc("/cgi-bin/message/template/send", s.withAccessToken(s.handleSubscribeSend))
+	mux.HandleFunc("/cgi-bin/comment/list", s.withAccessToken(s.handleCommentList))
+	mux.HandleFunc("/customservice/msgrecord/getmsglist", s.withAccessToken(s.handleMsgRecordList))
+	mux.HandleFunc("/cgi-bin/clear_quota", s.withAccessToken(s.handleClearQuota))
+	mux.HandleFunc("/cgi-bin/openapi/quota/get", s.withAccessToken(s.handleQuotaGet))
+	mux.HandleFunc("/cgi-bin/openapi/rid/get", s.withAccessToken(s.handleRIDGet))
+	mux.HandleFunc("/cgi-bin/customservice/getkflist", s.withAccessToken(s.handleKFList))
+	mux.HandleFunc("/cgi-bin/customservice/getonlinekflist", s.withAccessToken(s.handleOnlineKFList))
+	mux.HandleFunc("/customservice/kfsession/create", s.withAccessToken(s.handleKFSessionCreate))
+	mux.HandleFunc("/customservice/kfsession/close", s.withAccessToken(s.handleKFSessionClose))
+	mux.HandleFunc("/customservice/kfsession/getsession", s.withAccessToken(s.handleKFSessionGet))
+	mux.HandleFunc("/customservice/kfsession/getsessionlist", s.withAccessToken(s.handleKFSessionList))
+	mux.HandleFunc("/customservice/kfsession/getwaitcase", s.withAccessToken(s.handleKFWaitCase))
+	mux.HandleFunc("/cgi-bin/account/getaccountbasicinfo", s.withAccessToken(s.handleAccountBasicInfo))
+	mux.HandleFunc("/cgi-bin/getcallbackip", s.withAccessToken(s.handleIPList(CallbackIPList)))
+	mux.HandleFunc("/cgi-bin/get_api_domain_ip", s.withAccessToken(s.handleIPList(APIDomainIPList)))
+	mux.HandleFunc("/cgi-bin/callback/check", s.withAccessToken(s.handleCallbackCheck))
+	mux.HandleFunc("/wxa/generate_urllink", s.withAccessToken(s.handleGenerateURLLink))
+	mux.HandleFunc("/wxa/query_urllink", s.withAccessToken(s.handleQueryURLLink))
+	mux.HandleFunc("/wxa/genwxashortlink", s.withAccessToken(s.handleGenerateShortLink))
+	mux.HandleFunc("/wxa/generatescheme", s.withAccessToken(s.handleGenerateScheme))
+	mux.HandleFunc("/wxa/queryscheme", s.withAccessToken(s.handleQueryScheme))
+	mux.HandleFunc("/wxa/msg_sec_check", s.withAccessToken(s.handleOK))
+	mux.HandleFunc("/wxa/img_sec_check", s.withAccessToken(s.handleMediaOK))
+	mux.HandleFunc("/wxa/media_check_async", s.withAccessToken(s.handleMediaCheckAsync))
+	mux.HandleFunc("/tcb/batchdownloadfile", s.withAccessToken(s.handleCloudDownload))
+	mux.HandleFunc("/tcb/uploadfile", s.withAccessToken(s.handleCloudUploadInfo))
+	mux.HandleFunc(CloudUploadPath, s.handleCloudUpload)
+	mux.HandleFunc("/wxa/getwxacodeunlimit", s.withAccessToken(s.handleQRCode))
+	mux.HandleFunc("/wxa/getwxacode", s.withAccessToken(s.handleWxaCode))
+	mux.HandleFunc("/cgi-bin/wxaapp/createwxaqrcode", s.withAccessToken(s.handleWxaCode))
+	mux.HandleFunc("/cgi-bin/component/api_component_token", s.handleComponentToken)
+	mux.HandleFunc("/cgi-bin/gettoken", s.handleToken)
+	mux.HandleFunc("/v3/pay/transactions/jsapi", s.withPaySignature(s.handleJSAPICreate))
+	mux.HandleFunc("/v3/combine-transactions/jsapi", s.withPaySignature(s.handleCombineCreate))
+	mux.HandleFunc("/v3/combine-transactions/native", s.withPaySignature(s.handleCombineCreate))
+	mux.HandleFunc("/v3/combine-transactions/out-trade-no/", s.withPaySignature(s.handleCombineOrder))
+	mux.HandleFunc("/v3/bill/tradebill", s.withPaySignature(s.handleBillApply))
+	mux.HandleFunc("/v3/bill/fundflowbill", s.withPaySignature(s.handleBillApply))
+	mux.HandleFunc(BillDownloadPath, s.withPaySignature(s.handleBillDownload))
+	mux.HandleFunc("/cgi-bin/externalcontact/get_follow_user_list", s.withAccessToken(s.handleFollowUserList))
+	mux.HandleFunc("/cgi-bin/externalcontact/list", s.withAccessToken(s.handleExternalContactList))
+	mux.HandleFunc("/cgi-bin/externalcontact/get", s.withAccessToken(s.handleExternalContactGet))
+	mux.HandleFunc("/cgi-bin/externalcontact/add_contact_way", s.withAccessToken(s.handleAddContactWay))
+	mux.HandleFunc("/cgi-bin/externalcontact/del_contact_way", s.withAccessToken(s.handleOK))
+	mux.HandleFunc("/cgi-bin/externalcontact/groupchat/list", s.withAccessToken(s.handleGroupChatList))
+	mux.HandleFunc("/cgi-bin/externalcontact/groupchat/get", s.withAccessToken(s.handleGroupChatGet))
+	mux.HandleFunc("/cgi-bin/message/recall", s.withAccessToken(s.handleMessageRecall))
+	mux.HandleFunc("/cgi-bin/message/get_statistics", s.withAccessToken(s.handleMessageStatistics))
+	mux.HandleFunc("/cgi-bin/component/api_get_authorizer_list", s.withComponentAccessToken(s.handleAuthorizerList))
+	mux.HandleFunc("/cgi-bin/component/api_get_authorizer_option", s.withComponentAccessToken(s.handleAuthorizerOptionGet))
+	mux.HandleFunc("/cgi-bin/component/api_set_authorizer_option", s.withComponentAccessToken(s.handleAuthorizerOptionSet))
+
+	s.Server = httptest.NewServer(s.record(mux))
+
+	return s
+}
+
+// SetError makes the endpoint of the given path return the errcode and errmsg.
+func (s *Server) SetError(path string, errCode int, errMsg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors[path] = &apiError{ErrCode: errCode, ErrMsg: errMsg}
+}
+
+// ClearError restores the normal response of the endpoint of the given path.
+func (s *Server) ClearError(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.errors, path)
+}
+
+// SetResponse makes the endpoint of the given path respond the json encoded value,
+// it also serves paths not emulated by the mock server.
+func (s *Server) SetResponse(path string, v any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = v
+}
+
+// SetRawResponse makes the path respond the body with the content type, e.g. to serve
+// files downloaded by the SDK since all hosts are routed to the mock server.
+func (s *Server) SetRawResponse(path, contentType string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.raw[path] = &rawResponse{contentType: contentType, body: body}
+}
+
+// AcceptAccessToken makes the apis accept the access token besides AccessToken, e.g. the token of
+// a dedicated app serving a group of endpoints.
+func (s *Server) AcceptAccessToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token] = true
+}
+
+// Requests returns the requests received by the endpoint of the given path.
+func (s *Server) Requests(path string) []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Request(nil), s.requests[path]...)
+}
+
+// ExpireMedia expires all uploaded temporary media, so that sending them returns 40007.
+func (s *Server) ExpireMedia() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.media = make(map[string]bool)
+}
+
+// AddAuthorizers authorizes the accounts of the appids to the third-party platform.
+func (s *Server) AddAuthorizers(appIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authorizers = append(s.authorizers, appIDs...)
+}
+
+// SetOnlineKFs sets the customer service accounts online.
+func (s *Server) SetOnlineKFs(accounts ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onlineKFs = accounts
+}
+
+// HTTPClient returns a http client which sends all requests to the mock server
+// whatever the request host is.
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+
+	return &http.Client{
+		Transport: &rewriteTransport{
+			target: target,
+			base:   s.Client().Transport,
+		},
+	}
+}
+
+// WithServer returns a client option pointing all modules at the mock server.
+func WithServer(s *Server) func(*vwx.Client) {
+	return vwx.WithHTTPClient(s.HTTPClient())
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+
+	return t.base.RoundTrip(r)
+}
+
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		request := &Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		}
+
+		s.mu.Lock()
+		s.requests[r.URL.Path] = append(s.requests[r.URL.Path], request)
+		apiErr := s.errors[r.URL.Path]
+		if apiErr != nil {
+			// the rids of the errmsgs set by SetError can be looked up by openapi/rid/get
+			if rid := vwx.ParseRID(apiErr.ErrMsg); rid != "" {
+				s.rids[rid] = &ridCall{request: request, response: apiErr, time: time.Now()}
+			}
+		}
+		response, hasResponse := s.responses[r.URL.Path]
+		raw := s.raw[r.URL.Path]
+		s.mu.Unlock()
+
+		if raw != nil {
+			// ServeContent supports range requests
+			w.Header().Set("Content-Type", raw.contentType)
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(raw.body))
+			return
+		}
+
+		if apiErr != nil {
+			writeJSON(w, apiErr)
+			return
+		}
+
+		if hasResponse {
+			writeJSON(w, response)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) withAccessToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != s.AccessToken && !s.acceptedToken(token) {
+			writeJSON(w, &apiError{ErrCode: 40001, ErrMsg: "invalid credential, access_token is invalid or not latest"})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) acceptedToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[token]
+}
+
+func (s *Server) withComponentAccessToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("component_access_token") != ComponentAccessToken {
+			writeJSON(w, &apiError{ErrCode: 40001, ErrMsg: "invalid credential, access_token is invalid or not latest"})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"access_token": s.AccessToken,
+		"expires_in":   s.ExpiresIn,
+	})
+}
+
+func (s *Server) handleJsCode2Session(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("js_code")
+	if code == "" {
+		writeJSON(w, &apiError{ErrCode: 40029, ErrMsg: "invalid code"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"openid":      OpenIDPrefix + code,
+		"session_key": SessionKey,
+	})
+}
+
+func (s *Server) handleGetUserPhoneNumber(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		writeJSON(w, &apiError{ErrCode: 40029, ErrMsg: "code 无效"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"phone_info": map[string]any{
+			"phoneNumber":     PurePhoneNumber,
+			"purePhoneNumber": PurePhoneNumber,
+			"countryCode":     "86",
+		},
+	})
+}
+
+func (s *Server) handleGetUserRiskRank(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AppID    string `json:"appid"`
+		OpenID   string `json:"openid"`
+		ClientIP string `json:"client_ip"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AppID == "" || req.OpenID == "" || req.ClientIP == "" {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "risk_rank": 0, "unoin_id": s.seq.Add(1)})
+}
+
+func (s *Server) handleOAuthAccessToken(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		writeJSON(w, &apiError{ErrCode: 40029, ErrMsg: "invalid code"})
+		return
+	}
+
+	s.writeOAuthToken(w, OpenIDPrefix+code)
+}
+
+func (s *Server) handleOAuthRefreshToken(w http.ResponseWriter, r *http.Request) {
+	openID, ok := strings.CutPrefix(r.URL.Query().Get("refresh_token"), RefreshTokenPrefix)
+	if !ok {
+		writeJSON(w, &apiError{ErrCode: 40030, ErrMsg: "invalid refresh_token"})
+		return
+	}
+
+	s.writeOAuthToken(w, openID)
+}
+
+func (s *Server) writeOAuthToken(w http.ResponseWriter, openID string) {
+	writeJSON(w, map[string]any{
+		"access_token":  fmt.Sprintf("%s%d", OAuthAccessTokenPrefix, s.seq.Add(1)),
+		"expires_in":    s.ExpiresIn,
+		"refresh_token": RefreshTokenPrefix + openID,
+		"openid":        openID,
+		"scope":         "snsapi_userinfo",
+	})
+}
+
+func (s *Server) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !strings.HasPrefix(query.Get("access_token"), OAuthAccessTokenPrefix) {
+		writeJSON(w, &apiError{ErrCode: 40001, ErrMsg: "invalid credential, access_token is invalid or not latest"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"openid":   query.Get("openid"),
+		"nickname": "mock-" + query.Get("openid"),
+	})
+}
+
+func (s *Server) handleMediaUpload(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("media")
+	if err != nil {
+		writeJSON(w, &apiError{ErrCode: 41005, ErrMsg: "media data missing"})
+		return
+	}
+	_ = file.Close()
+
+	mediaID := fmt.Sprintf("mock-media-%d", s.seq.Add(1))
+
+	s.mu.Lock()
+	s.media[mediaID] = true
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"type":       r.URL.Query().Get("type"),
+		"media_id":   mediaID,
+		"created_at": time.Now().Unix(),
+	})
+}
+
+func (s *Server) handleMediaGet(w http.ResponseWriter, r *http.Request) {
+	mediaID := r.URL.Query().Get("media_id")
+
+	s.mu.Lock()
+	ok := s.media[mediaID]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, &apiError{ErrCode: 40007, ErrMsg: "invalid media_id"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+mediaID+`.png"`)
+	_, _ = w.Write(PNGHeader)
+}
+
+func (s *Server) handleUploadImg(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("media")
+	if err != nil {
+		writeJSON(w, &apiError{ErrCode: 41005, ErrMsg: "media data missing"})
+		return
+	}
+	_ = file.Close()
+
+	writeJSON(w, map[string]any{
+		"url": fmt.Sprintf("http://mmbiz.qpic.cn/mmbiz_png/mock%d/0", s.seq.Add(1)),
+	})
+}
+
+func (s *Server) handleCustomSend(w http.ResponseWriter, r *http.Request) {
+	var msg struct {
+		Image *struct {
+			MediaID string `json:"media_id"`
+		} `json:"image"`
+		Voice *struct {
+			MediaID string `json:"media_id"`
+		} `json:"voice"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	mediaID := ""
+	if msg.Image != nil {
+		mediaID = msg.Image.MediaID
+	} else if msg.Voice != nil {
+		mediaID = msg.Voice.MediaID
+	}
+
+	if mediaID != "" {
+		s.mu.Lock()
+		valid := s.media[mediaID]
+		s.mu.Unlock()
+
+		if !valid {
+			writeJSON(w, &apiError{ErrCode: 40007, ErrMsg: "invalid media_id"})
+			return
+		}
+	}
+
+	s.handleOK(w, r)
+}
+
+func (s *Server) handleMenuCreate(w http.ResponseWriter, r *http.Request) {
+	var menu json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&menu); err != nil {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	s.mu.Lock()
+	s.menu = menu
+	s.mu.Unlock()
+
+	s.handleOK(w, r)
+}
+
+func (s *Server) handleMenuGet(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	menu := s.menu
+	s.mu.Unlock()
+
+	if menu == nil {
+		writeJSON(w, &apiError{ErrCode: 46003, ErrMsg: "menu no exist"})
+		return
+	}
+
+	writeJSON(w, map[string]any{"menu": menu})
+}
+
+func (s *Server) handleMenuDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.menu = nil
+	s.mu.Unlock()
+
+	s.handleOK(w, r)
+}
+
+func (s *Server) handleJSAPITicket(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"errcode":    0,
+		"errmsg":     "ok",
+		"ticket":     fmt.Sprintf("%s%d", JSAPITicketPrefix, s.seq.Add(1)),
+		"expires_in": 7200,
+	})
+}
+
+func (s *Server) handleQRCodeCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ExpireSeconds int `json:"expire_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	seq := s.seq.Add(1)
+	writeJSON(w, map[string]any{
+		"ticket":         fmt.Sprintf("mock-ticket-%d", seq),
+		"expire_seconds": req.ExpireSeconds,
+		"url":            fmt.Sprintf("http://weixin.qq.com/q/mock%d", seq),
+	})
+}
+
+func (s *Server) handleChangeOpenID(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OpenIDList []string `json:"openid_list"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.OpenIDList) > 100 {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	results := make([]map[string]string, 0, len(req.OpenIDList))
+	for _, openID := range req.OpenIDList {
+		results = append(results, map[string]string{
+			"ori_openid": openID,
+			"new_openid": ChangedOpenIDPrefix + openID,
+			"err_msg":    "ok",
+		})
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode":     0,
+		"errmsg":      "ok",
+		"result_list": results,
+	})
+}
+
+func (s *Server) handleSubscriberInfo(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	writeJSON(w, map[string]any{
+		"subscribe":       1,
+		"openid":          query.Get("openid"),
+		"language":        query.Get("lang"),
+		"subscribe_time":  s.seq.Add(1),
+		"subscribe_scene": "ADD_SCENE_QR_CODE",
+	})
+}
+
+// CloudFile returns the content of the file uploaded to the cloud storage by the cloud file id.
+func (s *Server) CloudFile(fileID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[fileID]
+	return data, ok
+}
+
+func (s *Server) handleCloudDownload(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Env      string `json:"env"`
+		FileList []struct {
+			FileID string `json:"fileid"`
+			MaxAge int64  `json:"max_age"`
+		} `json:"file_list"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Env == "" {
+		writeJSON(w, &apiError{ErrCode: -501001, ErrMsg: "env not found"})
+		return
+	}
+
+	files := make([]map[string]any, 0, len(req.FileList))
+	for _, file := range req.FileList {
+		_, ok := s.CloudFile(file.FileID)
+		if !ok {
+			files = append(files, map[string]any{"fileid": file.FileID, "status": -503003, "errmsg": "file not exist"})
+			continue
+		}
+
+		files = append(files, map[string]any{
+			"fileid":       file.FileID,
+			"download_url": fmt.Sprintf("https://%s.tcb.qcloud.la/%s?sign=mock&t=%d", req.Env, strings.TrimPrefix(file.FileID, "cloud://"), file.MaxAge),
+			"status":       0,
+			"errmsg":       "ok",
+		})
+	}
+
+	writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "file_list": files})
+}
+
+func (s *Server) handleCloudUploadInfo(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Env  string `json:"env"`
+		Path string `json:"path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Env == "" || req.Path == "" {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode":       0,
+		"errmsg":        "ok",
+		"url":           "https://cos.ap-shanghai.myqcloud.com" + CloudUploadPath,
+		"token":         "mock-cos-token",
+		"authorization": "mock-signature",
+		"file_id":       "cloud://" + req.Env + "/" + req.Path,
+		"cos_file_id":   "mock-cos:" + req.Env + "/" + req.Path,
+	})
+}
+
+func (s *Server) handleCloudUpload(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil || r.FormValue("Signature") != "mock-signature" || r.FormValue("x-cos-security-token") != "mock-cos-token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+		return
+	}
+	defer file.Close()
+
+	data, _ := io.ReadAll(file)
+	fileID := "cloud://" + strings.TrimPrefix(r.FormValue("x-cos-meta-fileid"), "mock-cos:")
+
+	s.mu.Lock()
+	s.files[fileID] = data
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleMassSend(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MsgType     string `json:"msgtype"`
+		ClientMsgID string `json:"clientmsgid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MsgType == "" {
+		writeJSON(w, &apiError{ErrCode: 40004, ErrMsg: "invalid media type"})
+		return
+	}
+
+	s.mu.Lock()
+	msgID, exists := s.masses[req.ClientMsgID]
+	if !exists {
+		msgID = 1000000000 + s.seq.Add(1)
+		if req.ClientMsgID != "" {
+			s.masses[req.ClientMsgID] = msgID
+		}
+	}
+	s.mu.Unlock()
+
+	if exists {
+		writeJSON(w, map[string]any{"errcode": 45065, "errmsg": "clientmsgid exist", "msg_id": msgID})
+		return
+	}
+
+	writeJSON(w, map[string]any{"errcode": 0, "errmsg": "send job submission success", "msg_id": msgID, "msg_data_id": msgID})
+}
+
+func (s *Server) handleMassGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MsgID int64 `json:"msg_id"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	writeJSON(w, map[string]any{"msg_id": req.MsgID, "msg_status": "SEND_SUCCESS"})
+}
+
+func (s *Server) handleFreePublishSubmit(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MediaID string `json:"media_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MediaID == "" {
+		writeJSON(w, &apiError{ErrCode: 40007, ErrMsg: "invalid media_id"})
+		return
+	}
+
+	publishID := fmt.Sprintf("mock-publish-%d", s.seq.Add(1))
+
+	s.mu.Lock()
+	s.publishes[publishID] = 0
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"errcode":     0,
+		"errmsg":      "ok",
+		"publish_id":  publishID,
+		"msg_data_id": s.seq.Add(1),
+	})
+}
+
+func (s *Server) handleFreePublishGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PublishID string `json:"publish_id"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	polls, ok := s.publishes[req.PublishID]
+	s.publishes[req.PublishID] = polls + 1
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, &apiError{ErrCode: 53600, ErrMsg: "invalid publish_id"})
+		return
+	}
+
+	if polls < s.PublishingPolls {
+		writeJSON(w, map[string]any{"publish_id": req.PublishID, "publish_status": 1})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"publish_id":     req.PublishID,
+		"publish_status": 0,
+		"article_id":     "mock-article-" + req.PublishID,
+		"article_detail": map[string]any{
+			"count": 1,
+			"item":  []map[string]any{{"idx": 1, "article_url": "https://mp.weixin.qq.com/s/" + req.PublishID}},
+		},
+		"fail_idx": []int{},
+	})
+}
+
+func (s *Server) handleSubscribeSend(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"msgid":   s.seq.Add(1),
+	})
+}
+
+func (s *Server) handleGenerateURLLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path           string `json:"path"`
+		Query          string `json:"query"`
+		ExpireType     int    `json:"expire_type"`
+		ExpireTime     int64  `json:"expire_time"`
+		ExpireInterval int    `json:"expire_interval"`
+		EnvVersion     string `json:"env_version"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	link := &miniLink{Path: req.Path, Query: req.Query, CreateTime: time.Now().Unix(), EnvVersion: req.EnvVersion}
+	if link.EnvVersion == "" {
+		link.EnvVersion = "release"
+	}
+
+	link.ExpireTime = req.ExpireTime
+	if req.ExpireType == 1 {
+		link.ExpireTime = link.CreateTime + int64(req.ExpireInterval)*24*3600
+	}
+
+	urlLink := fmt.Sprintf("https://wxaurl.cn/mock%d", s.seq.Add(1))
+
+	s.mu.Lock()
+	s.links[urlLink] = link
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"errcode":  0,
+		"errmsg":   "ok",
+		"url_link": urlLink,
+	})
+}
+
+func (s *Server) handleQueryURLLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URLLink string `json:"url_link"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	link, ok := s.links[req.URLLink]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode":       0,
+		"errmsg":        "ok",
+		"url_link_info": link.info(),
+		"quota_info":    map[string]any{"remain_visit_quota": 1000000},
+	})
+}
+
+func (s *Server) handleGenerateShortLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PageURL string `json:"page_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PageURL == "" {
+		writeJSON(w, &apiError{ErrCode: 40066, ErrMsg: "invalid url"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"link":    fmt.Sprintf("#小程序://mock/mock%d", s.seq.Add(1)),
+	})
+}
+
+func (s *Server) handleGenerateScheme(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		JumpWxa *struct {
+			Path       string `json:"path"`
+			Query      string `json:"query"`
+			EnvVersion string `json:"env_version"`
+		} `json:"jump_wxa"`
+		IsExpire       bool  `json:"is_expire"`
+		ExpireType     int   `json:"expire_type"`
+		ExpireTime     int64 `json:"expire_time"`
+		ExpireInterval int   `json:"expire_interval"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	scheme := &miniLink{CreateTime: time.Now().Unix(), EnvVersion: "release"}
+	if req.JumpWxa != nil {
+		scheme.Path, scheme.Query = req.JumpWxa.Path, req.JumpWxa.Query
+		if req.JumpWxa.EnvVersion != "" {
+			scheme.EnvVersion = req.JumpWxa.EnvVersion
+		}
+	}
+
+	if req.IsExpire {
+		scheme.ExpireTime = req.ExpireTime
+		if req.ExpireType == 1 {
+			scheme.ExpireTime = scheme.CreateTime + int64(req.ExpireInterval)*24*3600
+		}
+	}
+
+	openLink := fmt.Sprintf("weixin://dl/business/?t=mock%d", s.seq.Add(1))
+
+	s.mu.Lock()
+	s.links[openLink] = scheme
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"errcode":  0,
+		"errmsg":   "ok",
+		"openlink": openLink,
+	})
+}
+
+func (s *Server) handleQueryScheme(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Scheme string `json:"scheme"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	scheme, ok := s.links[req.Scheme]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"errcode":     0,
+		"errmsg":      "ok",
+		"scheme_info": scheme.info(),
+		"quota_info":  map[string]any{"remain_visit_quota": 1000000},
+	})
+}
+
+func (s *Server) handleMediaCheckAsync(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"errcode":  0,
+		"errmsg":   "ok",
+		"trace_id": fmt.Sprintf("mock-trace-%d", s.seq.Add(1)),
+	})
+}
+
+func (s *Server) handleQRCode(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(PNGHeader)
+}
+
+func (s *Server) handleWxaCode(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path  string `json:"path"`
+		Width int    `json:"width"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Path == "" {
+		writeJSON(w, &apiError{ErrCode: 41030, ErrMsg: "invalid page"})
+		return
+	}
+
+	if req.Width != 0 && (req.Width < 280 || req.Width > 1280) {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	s.handleQRCode(w, r)
+}
+
+func (s *Server) handleMediaOK(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("media")
+	if err != nil {
+		writeJSON(w, &apiError{ErrCode: 41005, ErrMsg: "media data missing"})
+		return
+	}
+	_ = file.Close()
+
+	s.handleOK(w, r)
+}
+
+func (s *Server) handleMessageRecall(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MsgID string `json:"msgid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !strings.HasPrefix(req.MsgID, WorkMsgIDPrefix) {
+		writeJSON(w, &apiError{ErrCode: 40008, ErrMsg: "invalid message type"})
+		return
+	}
+
+	writeJSON(w, &apiError{ErrCode: 0, ErrMsg: "ok"})
+}
+
+func (s *Server) handleMessageStatistics(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TimeType int `json:"time_type"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"statistics": []map[string]any{
+			{"agentid": WorkAgentID, "app_name": "mock-app", "count": 100 + req.TimeType},
+		},
+	})
+}
+
+func (s *Server) handleOK(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, &apiError{ErrCode: 0, ErrMsg: "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) handleComponentToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ComponentVerifyTicket string `json:"component_verify_ticket"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ComponentVerifyTicket == "" {
+		writeJSON(w, &apiError{ErrCode: 61006, ErrMsg: "component ticket is invalid"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"component_access_token": ComponentAccessToken,
+		"expires_in":             s.ExpiresIn,
+	})
+}
+
+func (s *Server) handleAuthorizerList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Offset int `json:"offset"`
+		Count  int `json:"count"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Count > 500 {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	s.mu.Lock()
+	authorizers := s.authorizers
+	s.mu.Unlock()
+
+	list := make([]map[string]any, 0, req.Count)
+	for i := req.Offset; i < len(authorizers) && i < req.Offset+req.Count; i++ {
+		list = append(list, map[string]any{
+			"authorizer_appid": authorizers[i],
+			"refresh_token":    AuthorizerRefreshTokenPrefix + authorizers[i],
+			"auth_time":        1700000000 + i,
+		})
+	}
+
+	writeJSON(w, map[string]any{
+		"total_count": len(authorizers),
+		"list":        list,
+	})
+}
+
+func (s *Server) handleAuthorizerOptionGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AuthorizerAppID string `json:"authorizer_appid"`
+		OptionName      string `json:"option_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	s.mu.Lock()
+	value, ok := s.options[req.AuthorizerAppID+"/"+req.OptionName]
+	s.mu.Unlock()
+
+	if !ok {
+		value = "0"
+	}
+
+	writeJSON(w, map[string]any{
+		"authorizer_appid": req.AuthorizerAppID,
+		"option_name":      req.OptionName,
+		"option_value":     value,
+	})
+}
+
+func (s *Server) handleAuthorizerOptionSet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AuthorizerAppID string `json:"authorizer_appid"`
+		OptionName      string `json:"option_name"`
+		OptionValue     string `json:"option_value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OptionValue == "" {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	s.mu.Lock()
+	s.options[req.AuthorizerAppID+"/"+req.OptionName] = req.OptionValue
+	s.mu.Unlock()
+
+	s.handleOK(w, r)
+}
+
+func (s *Server) handleFollowUserList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"follow_user": []string{FollowUserID},
+	})
+}
+
+func (s *Server) handleExternalContactList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"external_userid": []string{ExternalUserIDPrefix + r.URL.Query().Get("userid")},
+	})
+}
+
+func (s *Server) handleExternalContactGet(w http.ResponseWriter, r *http.Request) {
+	externalUserID := r.URL.Query().Get("external_userid")
+
+	writeJSON(w, map[string]any{
+		"external_contact": map[string]any{
+			"external_userid": externalUserID,
+			"name":            "mock-" + externalUserID,
+			"type":            1,
+		},
+		"follow_user": []map[string]any{
+			{"userid": FollowUserID, "createtime": s.seq.Add(1), "add_way": 1},
+		},
+	})
+}
+
+func (s *Server) handleAddContactWay(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Scene int `json:"scene"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	response := map[string]any{"config_id": fmt.Sprintf("mock-config-%d", s.seq.Add(1))}
+	if req.Scene == 2 {
+		response["qr_code"] = fmt.Sprintf("https://p.qpic.cn/wwhead/mock%d", s.seq.Add(1))
+	}
+
+	writeJSON(w, response)
+}
+
+// mockGroupChats is the number of group chats listed by the mock server.
+const mockGroupChats = 3
+
+func (s *Server) handleGroupChatList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Cursor string `json:"cursor"`
+		Limit  int    `json:"limit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Limit < 1 || req.Limit > 1000 {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	var offset int
+	_, _ = fmt.Sscan(req.Cursor, &offset)
+
+	list := make([]map[string]any, 0, req.Limit)
+	for i := offset; i < mockGroupChats && i < offset+req.Limit; i++ {
+		list = append(list, map[string]any{"chat_id": fmt.Sprintf("mock-chat-%d", i), "status": 0})
+	}
+
+	response := map[string]any{"group_chat_list": list}
+	if next := offset + len(list); next < mockGroupChats {
+		response["next_cursor"] = fmt.Sprint(next)
+	}
+
+	writeJSON(w, response)
+}
+
+func (s *Server) handleGroupChatGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChatID   string `json:"chat_id"`
+		NeedName int    `json:"need_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChatID == "" {
+		writeJSON(w, &apiError{ErrCode: 47001, ErrMsg: "data format error"})
+		return
+	}
+
+	member := map[string]any{"userid": FollowUserID, "type": 1, "join_scene": 1}
+	if req.NeedName == 1 {
+		member["name"] = "mock-name"
+	}
+
+	writeJSON(w, map[string]any{
+		"group_chat": map[string]any{
+			"chat_id":     req.ChatID,
+			"name":        "mock-group",
+			"owner":       FollowUserID,
+			"member_list": []map[string]any{member},
+			"admin_list":  []map[string]any{},
+		},
+	})
+}
+
+// combineOrder is a combined payment created on the mock server.
+type combineOrder struct {
+	tradeType string
+	request   map[string]any
+	closed    bool
+}
+
+// payError is the error of the WeChat Pay apis.
+type payError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *Server) withPaySignature(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "WECHATPAY2-SHA256-RSA2048 ") {
+			s.writePayJSON(w, http.StatusUnauthorized, &payError{Code: "SIGN_ERROR", Message: "authorization missing"})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// writePayJSON writes the WeChat Pay response of the status, signed by PayPrivateKey if set.
+func (s *Server) writePayJSON(w http.ResponseWriter, status int, v any) {
+	var body []byte
+	if v != nil {
+		body, _ = json.Marshal(v)
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	if s.PayPrivateKey != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := fmt.Sprintf("mock-nonce-%d", s.seq.Add(1))
+		sum := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + string(body) + "\n"))
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, s.PayPrivateKey, crypto.SHA256, sum[:])
+
+		w.Header().Set("Wechatpay-Timestamp", timestamp)
+		w.Header().Set("Wechatpay-Nonce", nonce)
+		w.Header().Set("Wechatpay-Signature", base64.StdEncoding.EncodeToString(signature))
+		w.Header().Set("Wechatpay-Serial", s.PayPublicKeyID)
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) handleCombineCreate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writePayJSON(w, http.StatusBadRequest, &payError{Code: "PARAM_ERROR", Message: "invalid json"})
+		return
+	}
+
+	combineOutTradeNo, _ := req["combine_out_trade_no"].(string)
+	tradeType := strings.ToUpper(strings.TrimPrefix(r.URL.Path, "/v3/combine-transactions/"))
+
+	s.mu.Lock()
+	s.combines[combineOutTradeNo] = &combineOrder{tradeType: tradeType, request: req}
+	s.mu.Unlock()
+
+	if tradeType == "NATIVE" {
+		s.writePayJSON(w, http.StatusOK, map[string]any{"code_url": "weixin://wxpay/bizpayurl/up?pr=mock" + combineOutTradeNo})
+		return
+	}
+
+	s.writePayJSON(w, http.StatusOK, map[string]any{"prepay_id": "mock-prepay-" + combineOutTradeNo})
+}
+
+func (s *Server) handleJSAPICreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OutTradeNo string `json:"out_trade_no"`
+		Payer      struct {
+			OpenID string `json:"openid"`
+		} `json:"payer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Payer.OpenID == "" {
+		s.writePayJSON(w, http.StatusBadRequest, &payError{Code: "PARAM_ERROR", Message: "invalid request"})
+		return
+	}
+
+	s.writePayJSON(w, http.StatusOK, map[string]any{"prepay_id": "mock-prepay-" + req.OutTradeNo})
+}
+
+func (s *Server) handleCombineOrder(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v3/combine-transactions/out-trade-no/")
+	combineOutTradeNo, action, _ := strings.Cut(path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.combines[combineOutTradeNo]
+	if !ok {
+		s.writePayJSON(w, http.StatusNotFound, &payError{Code: "ORDER_NOT_EXIST", Message: "order not exist"})
+		return
+	}
+
+	if action == "close" {
+		order.closed = true
+		s.writePayJSON(w, http.StatusNoContent, nil)
+		return
+	}
+
+	state := "NOTPAY"
+	if order.closed {
+		state = "CLOSED"
+	}
+
+	subOrders, _ := order.request["sub_orders"].([]any)
+	states := make([]map[string]any, 0, len(subOrders))
+	for _, subOrder := range subOrders {
+		fields, _ := subOrder.(map[string]any)
+		states = append(states, map[string]any{
+			"mchid":        fields["mchid"],
+			"trade_type":   order.tradeType,
+			"trade_state":  state,
+			"attach":       fields["attach"],
+			"out_trade_no": fields["out_trade_no"],
+			"amount":       fields["amount"],
+		})
+	}
+
+	s.writePayJSON(w, http.StatusOK, map[string]any{
+		"combine_appid":        order.request["combine_appid"],
+		"combine_mchid":        order.request["combine_mchid"],
+		"combine_out_trade_no": combineOutTradeNo,
+		"sub_orders":           states,
+		"combine_payer_info":   order.request["combine_payer_info"],
+	})
+}
+
+// SetBill sets the bill content of the date, served as both the trade and the fund flow bill.
+func (s *Server) SetBill(billDate string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bills[billDate] = content
+}
+
+func (s *Server) handleBillApply(w http.ResponseWriter, r *http.Request) {
+	billDate := r.URL.Query().Get("bill_date")
+
+	s.mu.Lock()
+	content, ok := s.bills[billDate]
+	s.mu.Unlock()
+
+	if !ok {
+		s.writePayJSON(w, http.StatusBadRequest, &payError{Code: "NO_STATEMENT_EXIST", Message: "bill not exist"})
+		return
+	}
+
+	query := url.Values{"token": {billDate}}
+	if r.URL.Query().Get("tar_type") == "GZIP" {
+		query.Set("tartype", "gzip")
+	}
+
+	sum := sha1.Sum(content)
+	s.writePayJSON(w, http.StatusOK, map[string]any{
+		"hash_type":    "SHA1",
+		"hash_value":   hex.EncodeToString(sum[:]),
+		"download_url": "https://api.mch.weixin.qq.com" + BillDownloadPath + "?" + query.Encode(),
+	})
+}
+
+func (s *Server) handleBillDownload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	content, ok := s.bills[r.URL.Query().Get("token")]
+	s.mu.Unlock()
+
+	if !ok {
+		s.writePayJSON(w, http.StatusBadRequest, &payError{Code: "NO_STATEMENT_EXIST", Message: "bill not exist"})
+		return
+	}
+
+	if r.URL.Query().Get("tartype") != "gzip" {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(content)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	gz := gzip.NewWriter(w)
+	_, _ = gz.Write(content)
+	_ = gz.Close()
+}
+
+// SetComments sets the number of the comments of the articles of the mass send.
+func (s *Server) SetComments(msgDataID int64, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.comments[msgDataID] = count
+}
+
+func (s *Server) handleCommentList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MsgDataID int64 `json:"msg_data_id"`
+		Begin     int   `json:"begin"`
+		Count     int   `json:"count"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Count <= 0 || req.Count > 50 {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	s.mu.Lock()
+	total := s.comments[req.MsgDataID]
+	s.mu.Unlock()
+
+	comments := make([]map[string]any, 0, req.Count)
+	for i := req.Begin; i < min(req.Begin+req.Count, total); i++ {
+		comments = append(comments, map[string]any{
+			"user_comment_id": i + 1,
+			"openid":          fmt.Sprintf("%s%d", OpenIDPrefix, i),
+			"create_time":     1700000000 + i,
+			"content":         fmt.Sprintf("mock-comment-%d", i),
+			"comment_type":    i % 2,
+		})
+	}
+
+	writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "total": total, "comment": comments})
+}
+
+// msgRecord is a customer service message record of the mock server.
+type msgRecord struct {
+	openID string
+	time   int64
+}
+
+// AddMsgRecords adds the customer service message records of the user at the times.
+func (s *Server) AddMsgRecords(openID string, times ...time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range times {
+		s.msgRecords = append(s.msgRecords, &msgRecord{openID: openID, time: t.Unix()})
+	}
+}
+
+func (s *Server) handleMsgRecordList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartTime int64 `json:"starttime"`
+		EndTime   int64 `json:"endtime"`
+		MsgID     int64 `json:"msgid"`
+		Number    int   `json:"number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MsgID < 1 || req.Number <= 0 ||
+		req.EndTime <= req.StartTime || req.EndTime-req.StartTime > 86400 {
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	s.mu.Lock()
+	var records []*msgRecord
+	for _, record := range s.msgRecords {
+		if record.time >= req.StartTime && record.time <= req.EndTime {
+			records = append(records, record)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].time < records[j].time
+	})
+
+	list := make([]map[string]any, 0, req.Number)
+	for i := int(req.MsgID - 1); i < min(int(req.MsgID-1)+req.Number, len(records)); i++ {
+		list = append(list, map[string]any{
+			"openid":   records[i].openID,
+			"opercode": 2002,
+			"text":     fmt.Sprintf("mock-record-%d", i),
+			"time":     records[i].time,
+			"worker":   "kf2001@mock",
+		})
+	}
+
+	writeJSON(w, map[string]any{"recordlist": list, "number": len(list), "msgid": req.MsgID + int64(len(list))})
+}
+
+func (s *Server) handleClearQuota(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AppID string `json:"appid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AppID == "" {
+		writeJSON(w, &apiError{ErrCode: 41002, ErrMsg: "appid missing"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clears >= 10 {
+		writeJSON(w, &apiError{ErrCode: 48006, ErrMsg: "forbid to clear quota because of reaching the limit"})
+		return
+	}
+
+	s.clears++
+	for path, requests := range s.requests {
+		s.quotaBase[path] = len(requests)
+	}
+
+	writeJSON(w, &apiError{ErrCode: 0, ErrMsg: "ok"})
+}
+
+func (s *Server) handleQuotaGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CgiPath string `json:"cgi_path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !strings.HasPrefix(req.CgiPath, "/") {
+		writeJSON(w, &apiError{ErrCode: 76021, ErrMsg: "cgi_path not found, please check"})
+		return
+	}
+
+	s.mu.Lock()
+	used := len(s.requests[req.CgiPath]) - s.quotaBase[req.CgiPath]
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"quota": map[string]any{
+			"daily_limit": QuotaDailyLimit,
+			"used":        used,
+			"remain":      QuotaDailyLimit - used,
+		},
+		"rate_limit": map[string]any{"call_count": 100, "refresh_second": 60},
+	})
+}
+
+func (s *Server) handleKFList(w http.ResponseWriter, _ *http.Request) {
+	list := make([]map[string]any, 0, len(KFAccounts))
+	for i, account := range KFAccounts {
+		list = append(list, map[string]any{
+			"kf_account": account,
+			"kf_nick":    fmt.Sprintf("kf%d", i+1),
+			"kf_id":      strconv.Itoa(1001 + i),
+		})
+	}
+
+	writeJSON(w, map[string]any{"kf_list": list})
+}
+
+func (s *Server) handleOnlineKFList(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]map[string]any, 0, len(s.onlineKFs))
+	for _, account := range s.onlineKFs {
+		accepted := 0
+		for _, session := range s.kfSession {
+			if session.account == account {
+				accepted++
+			}
+		}
+
+		list = append(list, map[string]any{
+			"kf_account":    account,
+			"status":        1,
+			"kf_id":         strconv.Itoa(1001 + slices.Index(KFAccounts, account)),
+			"accepted_case": accepted,
+		})
+	}
+
+	writeJSON(w, map[string]any{"kf_online_list": list})
+}
+
+// decodeKFSessionRequest decodes the kf account and the openid of a session request.
+func decodeKFSessionRequest(w http.ResponseWriter, r *http.Request) (account, openID string, ok bool) {
+	var req struct {
+		KFAccount string `json:"kf_account"`
+		OpenID    string `json:"openid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OpenID == "" {
+		writeJSON(w, &apiError{ErrCode: 40003, ErrMsg: "invalid openid"})
+		return "", "", false
+	}
+
+	if !slices.Contains(KFAccounts, req.KFAccount) {
+		writeJSON(w, &apiError{ErrCode: 65401, ErrMsg: "invalid kf_account"})
+		return "", "", false
+	}
+
+	return req.KFAccount, req.OpenID, true
+}
+
+func (s *Server) handleKFSessionCreate(w http.ResponseWriter, r *http.Request) {
+	account, openID, ok := decodeKFSessionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.Contains(s.onlineKFs, account) {
+		writeJSON(w, &apiError{ErrCode: 65415, ErrMsg: "kf is not online"})
+		return
+	}
+
+	if session := s.kfSession[openID]; session != nil && session.account != account {
+		writeJSON(w, &apiError{ErrCode: 65414, ErrMsg: "customer is being served by other kf"})
+		return
+	}
+
+	s.kfSession[openID] = &kfSession{account: account, createTime: time.Now().Unix()}
+
+	writeJSON(w, &apiError{ErrCode: 0, ErrMsg: "ok"})
+}
+
+func (s *Server) handleKFSessionClose(w http.ResponseWriter, r *http.Request) {
+	account, openID, ok := decodeKFSessionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session := s.kfSession[openID]; session == nil || session.account != account {
+		writeJSON(w, &apiError{ErrCode: 65413, ErrMsg: "no session for the customer"})
+		return
+	}
+
+	delete(s.kfSession, openID)
+
+	writeJSON(w, &apiError{ErrCode: 0, ErrMsg: "ok"})
+}
+
+func (s *Server) handleKFSessionGet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := map[string]any{"kf_account": "", "createtime": 0}
+	if session := s.kfSession[r.URL.Query().Get("openid")]; session != nil {
+		resp = map[string]any{"kf_account": session.account, "createtime": session.createTime}
+	}
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) handleKFSessionList(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("kf_account")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]map[string]any, 0)
+	for openID, session := range s.kfSession {
+		if session.account == account {
+			list = append(list, map[string]any{"openid": openID, "createtime": session.createTime})
+		}
+	}
+
+	writeJSON(w, map[string]any{"sessionlist": list})
+}
+
+func (s *Server) handleKFWaitCase(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{"count": 0, "waitcaselist": []any{}})
+}
+
+func (s *Server) handleAccountBasicInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"errcode":         0,
+		"errmsg":          "ok",
+		"appid":           r.URL.Query().Get("appid"),
+		"account_type":    2,
+		"principal_type":  1,
+		"principal_name":  "mock principal",
+		"realname_status": 1,
+		"wx_verify_info":  map[string]any{"qualification_verify": true, "naming_verify": true},
+	})
+}
+
+func (s *Server) handleIPList(ipList []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"ip_list": ipList})
+	}
+}
+
+func (s *Server) handleCallbackCheck(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Action        string `json:"action"`
+		CheckOperator string `json:"check_operator"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	switch req.CheckOperator {
+	case "DEFAULT", "CHINANET", "UNICOM", "CAP":
+	default:
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+		return
+	}
+
+	operator := req.CheckOperator
+	if operator == "DEFAULT" {
+		operator = "CHINANET"
+	}
+
+	dns := []map[string]any{{"ip": "127.0.0.1", "real_operator": operator}}
+	ping := []map[string]any{{"ip": "127.0.0.1", "from_operator": operator, "package_loss": "0%", "time": "1.000ms"}}
+
+	switch req.Action {
+	case "all":
+		writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "dns": dns, "ping": ping})
+	case "dns":
+		writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "dns": dns, "ping": []any{}})
+	case "ping":
+		writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok", "dns": []any{}, "ping": ping})
+	default:
+		writeJSON(w, &apiError{ErrCode: 40097, ErrMsg: "invalid args"})
+	}
+}
+
+func (s *Server) handleRIDGet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RID string `json:"rid"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	call := s.rids[req.RID]
+	s.mu.Unlock()
+
+	if call == nil {
+		writeJSON(w, &apiError{ErrCode: 76001, ErrMsg: "rid not found"})
+		return
+	}
+
+	responseBody, _ := json.Marshal(call.response)
+
+	writeJSON(w, map[string]any{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"request": map[string]any{
+			"invoke_time":   call.time.Unix(),
+			"cost_in_ms":    1,
+			"request_url":   call.request.Query.Encode(),
+			"request_body":  string(call.request.Body),
+			"response_body": string(responseBody),
+			"client_ip":     "127.0.0.1",
+		},
+	})
+}
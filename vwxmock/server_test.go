@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmock_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+func TestMockServer(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+
+	authSvc := vwxauth.NewService(client)
+	token, err := authSvc.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.AccessToken, token)
+
+	session, err := authSvc.GetSessionKey("code")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.OpenIDPrefix+"code", session.OpenID)
+	assert.Equal(t, vwxmock.SessionKey, session.SessionKey)
+
+	svc := vwxa.NewService(client)
+
+	link, err := svc.GenerateSimpleURLLink("/pages/index", "a=1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, link)
+
+	requests := server.Requests("/wxa/generate_urllink")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, `{"path":"/pages/index","query":"a=1","env_version":"release"}`, string(requests[0].Body))
+
+	safe, err := svc.IsMsgContentSafe("hello")
+	assert.NoError(t, err)
+	assert.True(t, safe)
+
+	server.SetError("/wxa/msg_sec_check", 87014, "risky content")
+	safe, err = svc.IsMsgContentSafe("hello")
+	assert.NoError(t, err)
+	assert.False(t, safe)
+
+	server.SetError("/cgi-bin/message/subscribe/send", 43101, "user refuse to accept the msg")
+	_, err = svc.SendSubscribeMessageSimple("openid", "template", "", map[string]string{"thing1": "hi"})
+	assert.Error(t, err)
+
+	server.ClearError("/cgi-bin/message/subscribe/send")
+	_, err = svc.SendSubscribeMessageSimple("openid", "template", "", map[string]string{"thing1": "hi"})
+	assert.NoError(t, err)
+
+	image, err := svc.GenerateQRCode("scene", "pages/index")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.PNGHeader, image)
+}
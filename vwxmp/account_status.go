@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getAccountBasicInfoURL = "https://api.weixin.qq.com/cgi-bin/account/getaccountbasicinfo?access_token=%s"
+)
+
+// Real name statuses of the account.
+const (
+	RealNameStatusVerified  = 1 // 实名验证成功
+	RealNameStatusVerifying = 2 // 实名验证中
+	RealNameStatusFailed    = 3 // 实名验证失败
+)
+
+// AccountStatus is the security status of the official account.
+type AccountStatus string
+
+const (
+	// AccountStatusNormal means the account works normally.
+	AccountStatusNormal AccountStatus = "normal"
+	// AccountStatusUnverified means the real name verification of the account is in progress or failed.
+	AccountStatusUnverified AccountStatus = "unverified"
+	// AccountStatusBlocked means the apis of the account are blocked for irregularities (48004).
+	AccountStatusBlocked AccountStatus = "blocked"
+	// AccountStatusRestricted means the account is restricted or frozen (50002).
+	AccountStatusRestricted AccountStatus = "restricted"
+)
+
+// Restricted reports whether the apis of the account fail by the status, e.g. sending messages.
+func (s AccountStatus) Restricted() bool {
+	return s == AccountStatusBlocked || s == AccountStatusRestricted
+}
+
+// AccountStatusOf returns the account status reported by the APIError in the chain of err,
+// false if err doesn't tell the account is blocked or restricted.
+func AccountStatusOf(err error) (AccountStatus, bool) {
+	switch {
+	case vwx.IsErrCode(err, vwx.ErrCodeAPIBlocked):
+		return AccountStatusBlocked, true
+	case vwx.IsErrCode(err, vwx.ErrCodeUserLimited):
+		return AccountStatusRestricted, true
+	default:
+		return "", false
+	}
+}
+
+// WxVerifyInfo is the WeChat verification of the account.
+type WxVerifyInfo struct {
+	QualificationVerify   bool  `json:"qualification_verify"`     // 是否资质认证，若是，拥有微信认证相关的权限
+	NamingVerify          bool  `json:"naming_verify"`            // 是否名称认证
+	AnnualReview          bool  `json:"annual_review"`            // 是否需要年审
+	AnnualReviewBeginTime int64 `json:"annual_review_begin_time"` // 年审开始时间，时间戳
+	AnnualReviewEndTime   int64 `json:"annual_review_end_time"`   // 年审截止时间，时间戳
+}
+
+// AccountBasicInfo represents the basic info of the account.
+type AccountBasicInfo struct {
+	ErrCode        int           `json:"errcode"`
+	ErrMsg         string        `json:"errmsg"`
+	AppID          string        `json:"appid"`           // 帐号appid
+	AccountType    int           `json:"account_type"`    // 帐号类型：1订阅号，2服务号，3小程序
+	PrincipalType  int           `json:"principal_type"`  // 主体类型：0个人，1企业，2媒体，3政府，4其他组织
+	PrincipalName  string        `json:"principal_name"`  // 主体名称
+	Credential     string        `json:"credential"`      // 主体标识
+	RealNameStatus int           `json:"realname_status"` // 实名验证状态：1实名验证成功，2实名验证中，3实名验证失败
+	WxVerifyInfo   *WxVerifyInfo `json:"wx_verify_info"`  // 微信认证信息
+}
+
+// GetAccountBasicInfo returns the basic info of the account, including its real name and WeChat
+// verification status.
+func (s *Service) GetAccountBasicInfo() (*AccountBasicInfo, error) {
+	return s.GetAccountBasicInfoContext(context.Background())
+}
+
+// GetAccountBasicInfoContext is GetAccountBasicInfo with the given context.
+func (s *Service) GetAccountBasicInfoContext(ctx context.Context) (*AccountBasicInfo, error) {
+	call := s.call("get account basic info", getAccountBasicInfoURL)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[AccountBasicInfo](ctx, call)
+}
+
+// GetAccountStatus probes the security status of the account for monitoring, a blocked or
+// restricted account is reported by its status instead of an error, other errors are returned.
+func (s *Service) GetAccountStatus() (AccountStatus, error) {
+	return s.GetAccountStatusContext(context.Background())
+}
+
+// GetAccountStatusContext is GetAccountStatus with the given context.
+func (s *Service) GetAccountStatusContext(ctx context.Context) (AccountStatus, error) {
+	info, err := s.GetAccountBasicInfoContext(ctx)
+	if err != nil {
+		if status, ok := AccountStatusOf(err); ok {
+			return status, nil
+		}
+
+		return "", err
+	}
+
+	if info.RealNameStatus != 0 && info.RealNameStatus != RealNameStatusVerified {
+		return AccountStatusUnverified, nil
+	}
+
+	return AccountStatusNormal, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestAccountStatus(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	info, err := svc.GetAccountBasicInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.RealNameStatusVerified, info.RealNameStatus)
+	assert.True(t, info.WxVerifyInfo.QualificationVerify)
+
+	status, err := svc.GetAccountStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.AccountStatusNormal, status)
+	assert.False(t, status.Restricted())
+
+	server.SetResponse("/cgi-bin/account/getaccountbasicinfo", map[string]any{"errcode": 0, "errmsg": "ok", "realname_status": 2})
+	status, err = svc.GetAccountStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.AccountStatusUnverified, status)
+
+	// blocked and restricted accounts are reported by their status
+	server.SetError("/cgi-bin/account/getaccountbasicinfo", 50002, "user limited")
+	status, err = svc.GetAccountStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.AccountStatusRestricted, status)
+	assert.True(t, status.Restricted())
+
+	server.SetError("/cgi-bin/account/getaccountbasicinfo", 48004, "api forbidden for irregularities")
+	status, err = svc.GetAccountStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.AccountStatusBlocked, status)
+
+	server.SetError("/cgi-bin/account/getaccountbasicinfo", 40013, "invalid appid")
+	_, err = svc.GetAccountStatus()
+	assert.True(t, vwx.IsErrCode(err, 40013))
+
+	// the failures of other apis tell the status too
+	server.SetError("/cgi-bin/message/custom/send", 48004, "api forbidden for irregularities")
+	_, err = svc.SendCustomMessage(&vwxmp.CustomMessageRequest{ToUser: "openid", MsgType: "text", Text: &vwxmp.CustomMessageText{Content: "hello"}})
+	status, ok := vwxmp.AccountStatusOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, vwxmp.AccountStatusBlocked, status)
+
+	_, ok = vwxmp.AccountStatusOf(errors.New("timeout"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,230 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	uploadArticleImageURL = "https://api.weixin.qq.com/cgi-bin/media/uploadimg?access_token=%s"
+
+	// MaxArticleImageSize is the max size of images uploaded by uploadimg.
+	MaxArticleImageSize = 1 << 20
+)
+
+var (
+	articleImageRegexp = regexp.MustCompile(`(?i)(<img\b[^>]*?\s(?:data-)?src\s*=\s*["'])([^"']+)(["'])`)
+
+	wechatImageHosts = []string{"mmbiz.qpic.cn", "mmbiz.qlogo.cn"}
+)
+
+// UploadArticleImageResponse represents the response from uploading an article image.
+type UploadArticleImageResponse struct {
+	URL     string `json:"url"` // 图片在微信 CDN 的 url，可放置在图文消息中使用
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// ArticleMigrationResult represents the result of migrating the images of an article.
+type ArticleMigrationResult struct {
+	Content  string            // 替换为微信 CDN 图片地址后的 HTML
+	Replaced map[string]string // 原图片 url -> 微信 CDN url
+	Failed   map[string]string // 迁移失败的原图片 url -> 失败原因，内容中保留原 url
+}
+
+// UploadArticleImage uploads an image used in article content, only jpg/png within 1MB are supported,
+// the image doesn't count in the material quota.
+// filename: file name with extension, e.g. cover.png
+func (s *Service) UploadArticleImage(filename string, r io.Reader) (string, error) {
+	return s.UploadArticleImageContext(context.Background(), filename, r)
+}
+
+// UploadArticleImageContext is UploadArticleImage with the given context.
+func (s *Service) UploadArticleImageContext(ctx context.Context, filename string, r io.Reader) (string, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := s.authSvc.GetAccessTokenContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get access token error: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("media", filename)
+	if err != nil {
+		return "", fmt.Errorf("create form file error: %w", err)
+	}
+
+	size, err := io.Copy(part, io.LimitReader(r, MaxArticleImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("read image error: %w", err)
+	}
+
+	if size > MaxArticleImageSize {
+		return "", fmt.Errorf("image too large, exceeds %d bytes", MaxArticleImageSize)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("close multipart writer error: %w", err)
+	}
+
+	s.client.Logger.Infof("upload article image | request_id: %s | filename: %s | size: %d", requestID, filename, size)
+
+	resp, err := s.client.Post(ctx, fmt.Sprintf(uploadArticleImageURL, accessToken), writer.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if err := vwx.CheckContentType(resp, vwx.JSONContentTypes...); err != nil {
+		return "", err
+	}
+
+	var result UploadArticleImageResponse
+	if err := json.NewDecoder(s.client.LimitResponse(resp)).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if result.ErrCode != 0 {
+		return "", vwx.NewAPIError(uploadArticleImageURL, result.ErrCode, result.ErrMsg, requestID)
+	}
+
+	return result.URL, nil
+}
+
+// MigrateArticleImages downloads the external images (src and data-src of img tags) of the
+// HTML article content, uploads them via UploadArticleImage and rewrites the content to the
+// WeChat CDN urls, producing content ready for the draft box.
+// Images failing to migrate are kept as is and reported in the result.
+func (s *Service) MigrateArticleImages(content string) *ArticleMigrationResult {
+	return s.MigrateArticleImagesContext(context.Background(), content)
+}
+
+// MigrateArticleImagesContext is MigrateArticleImages with the given context.
+func (s *Service) MigrateArticleImagesContext(ctx context.Context, content string) *ArticleMigrationResult {
+	result := &ArticleMigrationResult{
+		Replaced: make(map[string]string),
+		Failed:   make(map[string]string),
+	}
+
+	for _, match := range articleImageRegexp.FindAllStringSubmatch(content, -1) {
+		imageURL := match[2]
+		if _, ok := result.Replaced[imageURL]; ok {
+			continue
+		}
+		if _, ok := result.Failed[imageURL]; ok {
+			continue
+		}
+		if !isExternalImage(imageURL) {
+			continue
+		}
+
+		cdnURL, err := s.migrateArticleImage(ctx, imageURL)
+		if err != nil {
+			s.client.Logger.Warnf("failed to migrate article image | url: %s | err: %v", imageURL, err)
+			result.Failed[imageURL] = err.Error()
+			continue
+		}
+
+		result.Replaced[imageURL] = cdnURL
+	}
+
+	result.Content = articleImageRegexp.ReplaceAllStringFunc(content, func(tag string) string {
+		match := articleImageRegexp.FindStringSubmatch(tag)
+		if cdnURL, ok := result.Replaced[match[2]]; ok {
+			return match[1] + cdnURL + match[3]
+		}
+		return tag
+	})
+
+	return result
+}
+
+func (s *Service) migrateArticleImage(ctx context.Context, imageURL string) (string, error) {
+	resp, err := s.client.Get(vwx.Idempotent(ctx), imageURL)
+	if err != nil {
+		return "", fmt.Errorf("download image error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download image error: status %d", resp.StatusCode)
+	}
+
+	return s.UploadArticleImageContext(ctx, articleImageFilename(imageURL, resp.Header.Get("Content-Type")), resp.Body)
+}
+
+// isExternalImage reports whether the image url is an http(s) url outside the WeChat CDN.
+func isExternalImage(imageURL string) bool {
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	for _, host := range wechatImageHosts {
+		if u.Hostname() == host || strings.HasSuffix(u.Hostname(), "."+host) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// articleImageFilename builds the upload file name, WeChat detects the image format by its extension.
+func articleImageFilename(imageURL, contentType string) string {
+	name := "image"
+	if u, err := url.Parse(imageURL); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+		name = path.Base(u.Path)
+	}
+
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".jpg", ".jpeg", ".png":
+		return name
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "image/png" {
+		return strings.TrimSuffix(name, path.Ext(name)) + ".png"
+	}
+
+	return strings.TrimSuffix(name, path.Ext(name)) + ".jpg"
+}
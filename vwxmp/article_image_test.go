@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestMigrateArticleImages(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.SetRawResponse("/images/a.png", "image/png", vwxmock.PNGHeader)
+	server.SetRawResponse("/images/b", "image/jpeg", []byte{0xff, 0xd8, 0xff})
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	content := `<p><img src="https://example.com/images/a.png"/></p>` +
+		`<p><img class="x" data-src='https://example.com/images/b' /></p>` +
+		`<p><img src="https://example.com/images/a.png"></p>` +
+		`<p><img src="http://mmbiz.qpic.cn/mmbiz_png/exist/0"></p>` +
+		`<p><img src="https://example.com/images/missing.png"></p>`
+
+	result := svc.MigrateArticleImages(content)
+	assert.Len(t, result.Replaced, 2)
+	assert.Len(t, result.Failed, 1)
+	assert.Contains(t, result.Failed, "https://example.com/images/missing.png")
+	assert.NotContains(t, result.Content, "https://example.com/images/a.png")
+	assert.NotContains(t, result.Content, "https://example.com/images/b")
+	assert.Contains(t, result.Content, `data-src='`+result.Replaced["https://example.com/images/b"]+`'`)
+	assert.Contains(t, result.Content, "http://mmbiz.qpic.cn/mmbiz_png/exist/0")
+	assert.Contains(t, result.Content, "https://example.com/images/missing.png")
+
+	uploads := server.Requests("/cgi-bin/media/uploadimg")
+	assert.Len(t, uploads, 2)
+	assert.Contains(t, string(uploads[1].Body), `filename="b.jpg"`)
+}
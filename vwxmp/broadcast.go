@@ -0,0 +1,509 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	massSendJobFinishEvent = "MASSSENDJOBFINISH"
+
+	// DefaultBroadcastDailyQuota is the daily call limit of the mass send apis.
+	DefaultBroadcastDailyQuota = 100
+)
+
+var (
+	// ErrBroadcastJobNotFound is returned when the broadcast job is not submitted.
+	ErrBroadcastJobNotFound = errors.New("broadcast job not found")
+	// ErrBroadcastQuotaExhausted is returned by RunBroadcast when batches are left pending for the
+	// daily quota, run the job again the next day.
+	ErrBroadcastQuotaExhausted = errors.New("broadcast daily quota exhausted")
+)
+
+// broadcastLocation is the timezone where WeChat resets the daily quotas.
+var broadcastLocation = time.FixedZone("CST", 8*3600)
+
+// BroadcastBatchStatus is the status of a batch of a broadcast job.
+type BroadcastBatchStatus string
+
+const (
+	BroadcastBatchPending  BroadcastBatchStatus = "pending"  // 待群发
+	BroadcastBatchSent     BroadcastBatchStatus = "sent"     // 已提交群发，等待MASSSENDJOBFINISH推送
+	BroadcastBatchFinished BroadcastBatchStatus = "finished" // 已收到群发结果
+	BroadcastBatchFailed   BroadcastBatchStatus = "failed"   // 提交群发失败
+)
+
+// BroadcastBatch is a mass send of a broadcast job, to the users of a tag or up to MaxMassOpenIDs openids.
+type BroadcastBatch struct {
+	Index       int                  `json:"index"`                 // 批次序号，从0开始
+	ToAll       bool                 `json:"to_all,omitempty"`      // 是否群发给全部用户
+	TagID       int64                `json:"tag_id,omitempty"`      // 群发的标签id
+	OpenIDs     []string             `json:"openids,omitempty"`     // 群发的openid列表
+	ClientMsgID string               `json:"clientmsgid"`           // 群发的唯一标识，重试时避免重复群发
+	Status      BroadcastBatchStatus `json:"status"`                // 批次状态
+	MsgID       int64                `json:"msg_id,omitempty"`      // 群发任务的id
+	MsgDataID   int64                `json:"msg_data_id,omitempty"` // 图文消息的数据id
+	Error       string               `json:"error,omitempty"`       // 提交群发失败的原因
+	Result      string               `json:"result,omitempty"`      // 群发结果，如send success、send fail、err(10001)
+	TotalCount  int                  `json:"total_count"`           // 目标用户数
+	FilterCount int                  `json:"filter_count"`          // 过滤后准备发送的用户数
+	SentCount   int                  `json:"sent_count"`            // 发送成功的用户数
+	ErrorCount  int                  `json:"error_count"`           // 发送失败的用户数
+	SentAt      time.Time            `json:"sent_at"`               // 提交群发的时间
+	FinishedAt  time.Time            `json:"finished_at"`           // 收到群发结果的时间
+}
+
+// BroadcastJob is a broadcast of a message split into batches by tag and openid lists.
+type BroadcastJob struct {
+	ID        string            `json:"id"`         // 任务id，作为clientmsgid的前缀，不超过50个字符
+	Message   *MassMessage      `json:"message"`    // 群发的消息
+	Batches   []*BroadcastBatch `json:"batches"`    // 群发批次
+	CreatedAt time.Time         `json:"created_at"` // 创建时间
+}
+
+// NewBroadcastJob creates a job broadcasting the message to the users of the tags and the openids,
+// a tag id 0 broadcasts to all users. The openids are deduplicated and split evenly into batches
+// of at most MaxMassOpenIDs.
+func NewBroadcastJob(id string, msg *MassMessage, tagIDs []int64, openIDs []string) (*BroadcastJob, error) {
+	job := &BroadcastJob{
+		ID:        id,
+		Message:   msg,
+		CreatedAt: time.Now(),
+	}
+
+	for _, tagID := range tagIDs {
+		job.addBatch(&BroadcastBatch{ToAll: tagID == 0, TagID: tagID})
+	}
+
+	seen := make(map[string]bool, len(openIDs))
+	unique := make([]string, 0, len(openIDs))
+	for _, openID := range openIDs {
+		if openID != "" && !seen[openID] {
+			seen[openID] = true
+			unique = append(unique, openID)
+		}
+	}
+
+	if len(unique) == 1 {
+		return nil, errors.New("broadcast by openid requires at least 2 openids")
+	}
+
+	if len(unique) > 0 {
+		count := (len(unique) + MaxMassOpenIDs - 1) / MaxMassOpenIDs
+		for i := range count {
+			job.addBatch(&BroadcastBatch{OpenIDs: unique[len(unique)*i/count : len(unique)*(i+1)/count]})
+		}
+	}
+
+	if len(job.Batches) == 0 {
+		return nil, errors.New("broadcast job has no target")
+	}
+
+	return job, nil
+}
+
+func (j *BroadcastJob) addBatch(batch *BroadcastBatch) {
+	batch.Index = len(j.Batches)
+	batch.ClientMsgID = j.ID + "-" + strconv.Itoa(batch.Index)
+	batch.Status = BroadcastBatchPending
+	j.Batches = append(j.Batches, batch)
+}
+
+// BroadcastReport summarizes a broadcast job.
+type BroadcastReport struct {
+	JobID       string `json:"job_id"`       // 任务id
+	Batches     int    `json:"batches"`      // 批次数
+	Pending     int    `json:"pending"`      // 待群发的批次数
+	Sent        int    `json:"sent"`         // 等待群发结果的批次数
+	Finished    int    `json:"finished"`     // 已收到群发结果的批次数
+	Failed      int    `json:"failed"`       // 提交群发失败的批次数
+	TotalCount  int    `json:"total_count"`  // 目标用户数
+	FilterCount int    `json:"filter_count"` // 过滤后准备发送的用户数
+	SentCount   int    `json:"sent_count"`   // 发送成功的用户数
+	ErrorCount  int    `json:"error_count"`  // 发送失败的用户数
+}
+
+// Done reports whether all batches of the job are finished or failed.
+func (r *BroadcastReport) Done() bool {
+	return r.Pending == 0 && r.Sent == 0
+}
+
+// Report summarizes the batches of the job.
+func (j *BroadcastJob) Report() *BroadcastReport {
+	report := &BroadcastReport{JobID: j.ID, Batches: len(j.Batches)}
+
+	for _, batch := range j.Batches {
+		switch batch.Status {
+		case BroadcastBatchPending:
+			report.Pending++
+		case BroadcastBatchSent:
+			report.Sent++
+		case BroadcastBatchFinished:
+			report.Finished++
+		case BroadcastBatchFailed:
+			report.Failed++
+		}
+
+		report.TotalCount += batch.TotalCount
+		report.FilterCount += batch.FilterCount
+		report.SentCount += batch.SentCount
+		report.ErrorCount += batch.ErrorCount
+	}
+
+	return report
+}
+
+// BroadcastStore persists the broadcast jobs.
+type BroadcastStore interface {
+	// SaveBroadcastJob inserts or updates the job by id.
+	SaveBroadcastJob(ctx context.Context, job *BroadcastJob) error
+	// GetBroadcastJob returns the job of the id, or nil if absent.
+	GetBroadcastJob(ctx context.Context, id string) (*BroadcastJob, error)
+	// FindBroadcastJobByMsgID returns the job with a batch of the msg id, or nil if absent.
+	FindBroadcastJobByMsgID(ctx context.Context, msgID int64) (*BroadcastJob, error)
+	// CountBroadcastBatchesSent counts the batches of all jobs sent since the time.
+	CountBroadcastBatchesSent(ctx context.Context, since time.Time) (int, error)
+}
+
+// MemoryBroadcastStore is an in-memory BroadcastStore, suitable for a single instance and tests.
+type MemoryBroadcastStore struct {
+	mu   sync.RWMutex
+	jobs map[string][]byte
+}
+
+// NewMemoryBroadcastStore creates an in-memory broadcast store.
+func NewMemoryBroadcastStore() *MemoryBroadcastStore {
+	return &MemoryBroadcastStore{jobs: make(map[string][]byte)}
+}
+
+// SaveBroadcastJob implements BroadcastStore.
+func (m *MemoryBroadcastStore) SaveBroadcastJob(_ context.Context, job *BroadcastJob) error {
+	// jobs are kept encoded so that callers never share the batches with the store
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs[job.ID] = data
+	return nil
+}
+
+// GetBroadcastJob implements BroadcastStore.
+func (m *MemoryBroadcastStore) GetBroadcastJob(_ context.Context, id string) (*BroadcastJob, error) {
+	m.mu.RLock()
+	data, ok := m.jobs[id]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return decodeBroadcastJob(data)
+}
+
+// FindBroadcastJobByMsgID implements BroadcastStore.
+func (m *MemoryBroadcastStore) FindBroadcastJobByMsgID(_ context.Context, msgID int64) (*BroadcastJob, error) {
+	jobs, err := m.all()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		for _, batch := range job.Batches {
+			if batch.MsgID == msgID {
+				return job, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// CountBroadcastBatchesSent implements BroadcastStore.
+func (m *MemoryBroadcastStore) CountBroadcastBatchesSent(_ context.Context, since time.Time) (int, error) {
+	jobs, err := m.all()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, job := range jobs {
+		for _, batch := range job.Batches {
+			if !batch.SentAt.IsZero() && !batch.SentAt.Before(since) {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (m *MemoryBroadcastStore) all() ([]*BroadcastJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jobs := make([]*BroadcastJob, 0, len(m.jobs))
+	for _, data := range m.jobs {
+		job, err := decodeBroadcastJob(data)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func decodeBroadcastJob(data []byte) (*BroadcastJob, error) {
+	var job BroadcastJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Broadcaster runs broadcast jobs batch by batch within the daily quota of the mass send apis, and
+// reconciles the MASSSENDJOBFINISH push events into the job reports. It is scheduler friendly: run
+// the jobs periodically until done, each run sends the pending batches the quota allows.
+type Broadcaster struct {
+	sender MassSender
+	store  BroadcastStore
+
+	DailyQuota int        // 每日可群发的批次数，默认100，订阅号每天仅可群发1次
+	Logger     vwx.Logger // 日志，默认vlog
+}
+
+func (b *Broadcaster) logger() vwx.Logger {
+	if b.Logger != nil {
+		return b.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+// NewBroadcaster creates a broadcaster sending the mass messages by the sender.
+func NewBroadcaster(sender MassSender, store BroadcastStore) *Broadcaster {
+	return &Broadcaster{
+		sender:     sender,
+		store:      store,
+		DailyQuota: DefaultBroadcastDailyQuota,
+	}
+}
+
+// SubmitBroadcast saves the job to be run by RunBroadcast.
+func (b *Broadcaster) SubmitBroadcast(job *BroadcastJob) error {
+	return b.store.SaveBroadcastJob(context.Background(), job)
+}
+
+// GetBroadcastJob returns the job with the status of its batches.
+func (b *Broadcaster) GetBroadcastJob(jobID string) (*BroadcastJob, error) {
+	job, err := b.store.GetBroadcastJob(context.Background(), jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil {
+		return nil, ErrBroadcastJobNotFound
+	}
+
+	return job, nil
+}
+
+// GetBroadcastReport returns the report of the job.
+func (b *Broadcaster) GetBroadcastReport(jobID string) (*BroadcastReport, error) {
+	job, err := b.GetBroadcastJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.Report(), nil
+}
+
+// RunBroadcast sends the pending batches of the job, see RunBroadcastContext.
+func (b *Broadcaster) RunBroadcast(jobID string) (*BroadcastReport, error) {
+	return b.RunBroadcastContext(context.Background(), jobID)
+}
+
+// RunBroadcastContext sends the pending batches of the job in order while the daily quota allows,
+// saving the job after each batch. ErrBroadcastQuotaExhausted is returned along with the report if
+// batches are left pending for the quota. Batches rejected for the api quota (45009/45011) are left
+// pending and stop the run, other failures mark the batch failed.
+func (b *Broadcaster) RunBroadcastContext(ctx context.Context, jobID string) (*BroadcastReport, error) {
+	job, err := b.store.GetBroadcastJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("get broadcast job error: %w", err)
+	}
+
+	if job == nil {
+		return nil, ErrBroadcastJobNotFound
+	}
+
+	year, month, day := time.Now().In(broadcastLocation).Date()
+
+	sent, err := b.store.CountBroadcastBatchesSent(ctx, time.Date(year, month, day, 0, 0, 0, 0, broadcastLocation))
+	if err != nil {
+		return nil, fmt.Errorf("count broadcast batches error: %w", err)
+	}
+
+	for _, batch := range job.Batches {
+		if batch.Status != BroadcastBatchPending {
+			continue
+		}
+
+		if sent >= b.DailyQuota {
+			b.logger().Warnf("broadcast daily quota exhausted | job: %s | sent: %d", job.ID, sent)
+			return job.Report(), ErrBroadcastQuotaExhausted
+		}
+
+		if err := b.sendBatch(ctx, job, batch); err != nil {
+			return job.Report(), err
+		}
+
+		sent++
+
+		if err := b.store.SaveBroadcastJob(ctx, job); err != nil {
+			return job.Report(), fmt.Errorf("save broadcast job error: %w", err)
+		}
+	}
+
+	return job.Report(), nil
+}
+
+// sendBatch sends the batch, only errors stopping the run are returned.
+func (b *Broadcaster) sendBatch(ctx context.Context, job *BroadcastJob, batch *BroadcastBatch) error {
+	msg := *job.Message
+	msg.ClientMsgID = batch.ClientMsgID
+
+	var resp *MassSendResponse
+	var err error
+	if len(batch.OpenIDs) > 0 {
+		resp, err = b.sender.SendMassByOpenIDsContext(ctx, batch.OpenIDs, &msg)
+	} else {
+		resp, err = b.sender.SendMassByTagContext(ctx, batch.TagID, &msg)
+	}
+
+	batch.SentAt = time.Now()
+
+	switch {
+	case err == nil, vwx.IsErrCode(err, ErrCodeMassClientMsgIDExists):
+		// a batch sent by an interrupted run is not sent again
+		batch.Status = BroadcastBatchSent
+		batch.MsgID = resp.MsgID
+		batch.MsgDataID = resp.MsgDataID
+		b.logger().Infof("broadcast batch sent | job: %s | batch: %d | msg_id: %d", job.ID, batch.Index, resp.MsgID)
+		return nil
+	case vwx.IsErrCode(err, vwx.ErrCodeDailyQuotaLimit, vwx.ErrCodeMinuteQuotaLimit), ctx.Err() != nil:
+		batch.SentAt = time.Time{}
+		return err
+	default:
+		batch.Status = BroadcastBatchFailed
+		batch.Error = err.Error()
+		b.logger().Errorf("broadcast batch failed | job: %s | batch: %d | err: %v", job.ID, batch.Index, err)
+		return nil
+	}
+}
+
+// Register registers the broadcaster for the MASSSENDJOBFINISH events of the dispatcher.
+func (b *Broadcaster) Register(d *vwxpush.Dispatcher) {
+	d.HandleEvent(massSendJobFinishEvent, b.HandleEvent)
+}
+
+// HandleEvent records the result of a batch from a MASSSENDJOBFINISH push event, it matches
+// vwxpush.MessageHandler. Events of mass sends not run by the broadcaster are ignored.
+func (b *Broadcaster) HandleEvent(_ string, _ *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	event, err := parseMassSendResult(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	job, err := b.store.FindBroadcastJobByMsgID(ctx, int64(event.MsgID))
+	if err != nil {
+		return nil, fmt.Errorf("find broadcast job error: %w", err)
+	}
+
+	if job == nil {
+		b.logger().Warnf("broadcast job not found | msg_id: %d", event.MsgID)
+		return nil, nil
+	}
+
+	for _, batch := range job.Batches {
+		if batch.MsgID != int64(event.MsgID) {
+			continue
+		}
+
+		batch.Status = BroadcastBatchFinished
+		batch.Result = event.Status
+		batch.TotalCount = event.TotalCount
+		batch.FilterCount = event.FilterCount
+		batch.SentCount = event.SentCount
+		batch.ErrorCount = event.ErrorCount
+		batch.FinishedAt = time.Now()
+	}
+
+	if err := b.store.SaveBroadcastJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("save broadcast job error: %w", err)
+	}
+
+	return nil, nil
+}
+
+type massSendResult struct {
+	MsgID       flexInt64 `xml:"MsgID" json:"MsgID"`
+	Status      string    `xml:"Status" json:"Status"`
+	TotalCount  int       `xml:"TotalCount" json:"TotalCount"`
+	FilterCount int       `xml:"FilterCount" json:"FilterCount"`
+	SentCount   int       `xml:"SentCount" json:"SentCount"`
+	ErrorCount  int       `xml:"ErrorCount" json:"ErrorCount"`
+}
+
+func parseMassSendResult(data []byte) (*massSendResult, error) {
+	data = bytes.TrimSpace(data)
+
+	var event massSendResult
+	var err error
+	if bytes.HasPrefix(data, []byte("<")) {
+		err = xml.Unmarshal(data, &event)
+	} else {
+		err = json.Unmarshal(data, &event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal mass send job finish event error: %w", err)
+	}
+
+	event.Status = strings.TrimSpace(event.Status)
+
+	return &event, nil
+}
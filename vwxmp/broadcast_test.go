@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestBroadcast(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	broadcaster := vwxmp.NewBroadcaster(svc, vwxmp.NewMemoryBroadcastStore())
+	broadcaster.DailyQuota = 3
+
+	d := vwxpush.NewDispatcher()
+	broadcaster.Register(d)
+
+	openIDs := make([]string, 25000)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid-%d", i)
+	}
+
+	msg := &vwxmp.MassMessage{MsgType: "mpnews", MPNews: &vwxmp.MassMedia{MediaID: "draft"}}
+	job, err := vwxmp.NewBroadcastJob("job-1", msg, []int64{100}, openIDs)
+	assert.NoError(t, err)
+	assert.Len(t, job.Batches, 4)
+	assert.Len(t, job.Batches[1].OpenIDs, 8333)
+	assert.Len(t, job.Batches[3].OpenIDs, 8334)
+	assert.NoError(t, broadcaster.SubmitBroadcast(job))
+
+	// a batch sent by an interrupted run is recognized by its clientmsgid
+	first, err := svc.SendMassByTag(100, &vwxmp.MassMessage{MsgType: "mpnews", MPNews: msg.MPNews, ClientMsgID: "job-1-0"})
+	assert.NoError(t, err)
+
+	report, err := broadcaster.RunBroadcast("job-1")
+	assert.ErrorIs(t, err, vwxmp.ErrBroadcastQuotaExhausted)
+	assert.Equal(t, 3, report.Sent)
+	assert.Equal(t, 1, report.Pending)
+	assert.Len(t, server.Requests("/cgi-bin/message/mass/sendall"), 2)
+	assert.Len(t, server.Requests("/cgi-bin/message/mass/send"), 2)
+
+	broadcaster.DailyQuota = 4
+	report, err = broadcaster.RunBroadcast("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Sent)
+	assert.False(t, report.Done())
+
+	job, err = broadcaster.GetBroadcastJob("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, first.MsgID, job.Batches[0].MsgID)
+
+	// reconcile the MASSSENDJOBFINISH events
+	baseInfo := &vwxpush.PushBaseInfo{FromUserName: "gh_account", MsgType: "event", Event: "MASSSENDJOBFINISH"}
+	for _, batch := range job.Batches {
+		_, err := d.Dispatch("", baseInfo, []byte(fmt.Sprintf(`<xml><MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[MASSSENDJOBFINISH]]></Event><MsgID>%d</MsgID><Status><![CDATA[send success]]></Status>
+<TotalCount>100</TotalCount><FilterCount>100</FilterCount><SentCount>98</SentCount><ErrorCount>2</ErrorCount></xml>`,
+			batch.MsgID)))
+		assert.NoError(t, err)
+	}
+
+	report, err = broadcaster.GetBroadcastReport("job-1")
+	assert.NoError(t, err)
+	assert.True(t, report.Done())
+	assert.Equal(t, 4, report.Finished)
+	assert.Equal(t, 392, report.SentCount)
+	assert.Equal(t, 8, report.ErrorCount)
+
+	_, err = broadcaster.GetBroadcastReport("job-2")
+	assert.ErrorIs(t, err, vwxmp.ErrBroadcastJobNotFound)
+}
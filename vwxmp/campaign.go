@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	subscribeEvent = "subscribe"
+	scanEvent      = "SCAN"
+
+	// qrScenePrefix prefixes the EventKey of subscribe events from parametric QR codes.
+	qrScenePrefix = "qrscene_"
+)
+
+// CampaignQRCode is the QR code of a campaign scene.
+type CampaignQRCode struct {
+	Scene     string    // 场景值
+	Ticket    string    // 二维码ticket
+	URL       string    // 二维码图片解析后的地址
+	ImageURL  string    // 二维码图片地址
+	ExpiresAt time.Time // 过期时间，永久二维码为零值
+}
+
+// CampaignArrival is a user arriving via the QR code of a campaign scene.
+type CampaignArrival struct {
+	OpenID     string    // 用户openid
+	Scene      string    // 场景值
+	Ticket     string    // 二维码ticket
+	Subscribed bool      // 是否为扫码关注，false表示已关注用户扫码
+	ArrivedAt  time.Time // 扫码时间
+}
+
+// CampaignHandler is called when a user arrives via the QR code of a campaign scene.
+type CampaignHandler func(arrival *CampaignArrival) error
+
+// CampaignTracker creates QR codes of campaign scenes and reports users arriving via them
+// from the subscribe and SCAN push events.
+type CampaignTracker struct {
+	creator QRCodeCreator
+	handler CampaignHandler
+	Expire  time.Duration // 二维码有效期，0为永久二维码，默认为30天
+}
+
+// NewCampaignTracker creates a campaign tracker reporting arrivals to the handler.
+func NewCampaignTracker(creator QRCodeCreator, handler CampaignHandler, options ...func(*CampaignTracker)) *CampaignTracker {
+	t := &CampaignTracker{
+		creator: creator,
+		handler: handler,
+		Expire:  MaxQRCodeExpire,
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	return t
+}
+
+// WithCampaignExpire sets the expire time of the campaign QR codes, zero creates permanent QR codes
+// which are limited to 100000 per account.
+func WithCampaignExpire(expire time.Duration) func(*CampaignTracker) {
+	return func(t *CampaignTracker) {
+		t.Expire = expire
+	}
+}
+
+// CreateScene creates the QR code of the campaign scene.
+func (t *CampaignTracker) CreateScene(scene string) (*CampaignQRCode, error) {
+	if scene == "" || len(scene) > 64 {
+		return nil, fmt.Errorf("campaign scene must be 1-64 bytes: %q", scene)
+	}
+
+	resp, err := t.creator.CreateStrSceneQRCode(scene, t.Expire)
+	if err != nil {
+		return nil, err
+	}
+
+	qrcode := &CampaignQRCode{
+		Scene:    scene,
+		Ticket:   resp.Ticket,
+		URL:      resp.URL,
+		ImageURL: QRCodeImageURL(resp.Ticket),
+	}
+
+	if resp.ExpireSeconds > 0 {
+		qrcode.ExpiresAt = time.Now().Add(time.Duration(resp.ExpireSeconds) * time.Second)
+	}
+
+	return qrcode, nil
+}
+
+// Register registers the tracker for the subscribe and SCAN events of the dispatcher.
+func (t *CampaignTracker) Register(d *vwxpush.Dispatcher) {
+	d.HandleEvent(subscribeEvent, t.HandleEvent)
+	d.HandleEvent(scanEvent, t.HandleEvent)
+}
+
+// HandleEvent reports the arrival of a subscribe or SCAN push event from a parametric QR code,
+// it matches vwxpush.MessageHandler. Subscriptions not from QR codes are ignored.
+func (t *CampaignTracker) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	arrival, err := ParseCampaignArrival(baseInfo, data)
+	if err != nil || arrival == nil {
+		return nil, err
+	}
+
+	return nil, t.handler(arrival)
+}
+
+// ParseCampaignArrival parses the arrival from a subscribe or SCAN push event,
+// nil is returned if the event is not from a parametric QR code.
+func ParseCampaignArrival(baseInfo *vwxpush.PushBaseInfo, data []byte) (*CampaignArrival, error) {
+	var event struct {
+		EventKey string `xml:"EventKey" json:"EventKey"`
+		Ticket   string `xml:"Ticket" json:"Ticket"`
+	}
+
+	if err := unmarshalPushData(data, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal qrcode event error: %w", err)
+	}
+
+	arrival := &CampaignArrival{
+		OpenID:    baseInfo.FromUserName,
+		Ticket:    event.Ticket,
+		ArrivedAt: time.Unix(baseInfo.CreateTime, 0),
+	}
+
+	switch {
+	case strings.EqualFold(baseInfo.Event, subscribeEvent):
+		scene, ok := strings.CutPrefix(event.EventKey, qrScenePrefix)
+		if !ok {
+			return nil, nil
+		}
+		arrival.Scene = scene
+		arrival.Subscribed = true
+	case strings.EqualFold(baseInfo.Event, scanEvent):
+		arrival.Scene = event.EventKey
+	default:
+		return nil, nil
+	}
+
+	return arrival, nil
+}
+
+// unmarshalPushData unmarshals the push message in xml or json format.
+func unmarshalPushData(data []byte, v any) error {
+	if data = bytes.TrimSpace(data); bytes.HasPrefix(data, []byte("<")) {
+		return xml.Unmarshal(data, v)
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestCampaignTracker(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	var arrivals []*vwxmp.CampaignArrival
+	tracker := vwxmp.NewCampaignTracker(svc, func(arrival *vwxmp.CampaignArrival) error {
+		arrivals = append(arrivals, arrival)
+		return nil
+	})
+
+	qrcode, err := tracker.CreateScene("spring-sale")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, qrcode.Ticket)
+	assert.Contains(t, qrcode.ImageURL, qrcode.Ticket)
+	assert.False(t, qrcode.ExpiresAt.IsZero())
+
+	requests := server.Requests("/cgi-bin/qrcode/create")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, `{"expire_seconds":2592000,"action_name":"QR_STR_SCENE","action_info":{"scene":{"scene_str":"spring-sale"}}}`, string(requests[0].Body))
+
+	d := vwxpush.NewDispatcher()
+	tracker.Register(d)
+
+	push := func(event, eventKey string) {
+		baseInfo := &vwxpush.PushBaseInfo{FromUserName: "openid", MsgType: "event", Event: event, CreateTime: 1700000000}
+		_, err := d.Dispatch("", baseInfo, []byte(fmt.Sprintf(`<xml><FromUserName><![CDATA[openid]]></FromUserName>
+<MsgType><![CDATA[event]]></MsgType><Event><![CDATA[%s]]></Event>
+<EventKey><![CDATA[%s]]></EventKey><Ticket><![CDATA[%s]]></Ticket></xml>`, event, eventKey, qrcode.Ticket)))
+		assert.NoError(t, err)
+	}
+
+	push("subscribe", "qrscene_spring-sale")
+	push("SCAN", "spring-sale")
+	push("subscribe", "")
+
+	assert.Len(t, arrivals, 2)
+	assert.Equal(t, "spring-sale", arrivals[0].Scene)
+	assert.True(t, arrivals[0].Subscribed)
+	assert.Equal(t, qrcode.Ticket, arrivals[0].Ticket)
+	assert.Equal(t, "spring-sale", arrivals[1].Scene)
+	assert.False(t, arrivals[1].Subscribed)
+}
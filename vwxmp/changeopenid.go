@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	changeOpenIDURL = "https://api.weixin.qq.com/cgi-bin/changeopenid?access_token=%s"
+
+	// MaxChangeOpenIDBatch is the max number of openids converted by a changeopenid call.
+	MaxChangeOpenIDBatch = 100
+)
+
+// ChangeOpenIDRequest represents the request of converting the openids of the original account
+// to the openids of the migrated account.
+type ChangeOpenIDRequest struct {
+	FromAppID  string   `json:"from_appid"`  // 原账号的appid
+	OpenIDList []string `json:"openid_list"` // 需要转换的openid，即原账号的openid，最多100个
+}
+
+// ChangeOpenIDResult is the conversion result of an openid.
+type ChangeOpenIDResult struct {
+	OriOpenID string `json:"ori_openid"`           // 原账号的openid
+	NewOpenID string `json:"new_openid,omitempty"` // 新账号的openid，转换失败时为空
+	ErrMsg    string `json:"err_msg"`              // 转换结果，ok为成功，ori_openid error为openid不属于原账号
+}
+
+// ChangeOpenIDResponse represents the response of converting openids.
+type ChangeOpenIDResponse struct {
+	ErrCode    int                   `json:"errcode"`
+	ErrMsg     string                `json:"errmsg"`
+	ResultList []*ChangeOpenIDResult `json:"result_list"` // 转换结果
+}
+
+// ChangeOpenID converts at most 100 openids of the original account to the openids of the account
+// migrated to, it must be called by the migrated account within the migration period.
+func (s *Service) ChangeOpenID(fromAppID string, openIDs []string) ([]*ChangeOpenIDResult, error) {
+	return s.ChangeOpenIDContext(context.Background(), fromAppID, openIDs)
+}
+
+// ChangeOpenIDContext is ChangeOpenID with the given context.
+func (s *Service) ChangeOpenIDContext(ctx context.Context, fromAppID string, openIDs []string) ([]*ChangeOpenIDResult, error) {
+	if len(openIDs) > MaxChangeOpenIDBatch {
+		return nil, fmt.Errorf("changeopenid accepts at most %d openids, got %d", MaxChangeOpenIDBatch, len(openIDs))
+	}
+
+	call := s.call("change openid", changeOpenIDURL)
+	call.Idempotent = true
+	call.Summary = fmt.Sprintf("from_appid: %s | count: %d", fromAppID, len(openIDs))
+
+	result, err := wxapi.PostJSON[ChangeOpenIDResponse](ctx, call, &ChangeOpenIDRequest{FromAppID: fromAppID, OpenIDList: openIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ResultList, nil
+}
+
+// MigrateOpenIDs converts the openids of the original account in batches of 100, fn is called with
+// the results of each batch in order so that the identity database can be remapped page by page.
+// The migration stops at the first error of the api or fn.
+func (s *Service) MigrateOpenIDs(fromAppID string, openIDs []string, fn func(results []*ChangeOpenIDResult) error) error {
+	return s.MigrateOpenIDsContext(context.Background(), fromAppID, openIDs, fn)
+}
+
+// MigrateOpenIDsContext is MigrateOpenIDs with the given context.
+func (s *Service) MigrateOpenIDsContext(ctx context.Context, fromAppID string, openIDs []string, fn func(results []*ChangeOpenIDResult) error) error {
+	for start := 0; start < len(openIDs); start += MaxChangeOpenIDBatch {
+		batch := openIDs[start:min(start+MaxChangeOpenIDBatch, len(openIDs))]
+
+		results, err := s.ChangeOpenIDContext(ctx, fromAppID, batch)
+		if err != nil {
+			return fmt.Errorf("change openid batch at %d error: %w", start, err)
+		}
+
+		if err := fn(results); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
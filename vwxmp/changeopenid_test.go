@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestMigrateOpenIDs(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	openIDs := make([]string, 250)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid-%d", i)
+	}
+
+	var results []*vwxmp.ChangeOpenIDResult
+	err := svc.MigrateOpenIDs("old-appid", openIDs, func(batch []*vwxmp.ChangeOpenIDResult) error {
+		results = append(results, batch...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 250)
+	assert.Equal(t, "openid-249", results[249].OriOpenID)
+	assert.Equal(t, vwxmock.ChangedOpenIDPrefix+"openid-249", results[249].NewOpenID)
+	assert.Len(t, server.Requests("/cgi-bin/changeopenid"), 3)
+
+	_, err = svc.ChangeOpenID("old-appid", openIDs)
+	assert.Error(t, err)
+}
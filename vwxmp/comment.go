@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	commentListURL = "https://api.weixin.qq.com/cgi-bin/comment/list?access_token=%s"
+
+	// MaxCommentPageSize is the max number of comments of a page.
+	MaxCommentPageSize = 50
+)
+
+// CommentType selects the comments of an article.
+type CommentType int
+
+const (
+	CommentTypeAll     CommentType = 0 // 全部评论
+	CommentTypeNormal  CommentType = 1 // 普通评论
+	CommentTypeElected CommentType = 2 // 精选评论
+)
+
+// CommentListRequest represents the request of listing a page of the comments of an article.
+type CommentListRequest struct {
+	MsgDataID int64       `json:"msg_data_id"` // 群发返回的msg_data_id
+	Index     int         `json:"index"`       // 多图文时，用来指定第几篇图文，从0开始
+	Begin     int         `json:"begin"`       // 起始位置
+	Count     int         `json:"count"`       // 获取数目，不超过50
+	Type      CommentType `json:"type"`        // 评论类型
+}
+
+// CommentReply is the reply of the author to a comment.
+type CommentReply struct {
+	Content    string `json:"content"`     // 作者回复内容
+	CreateTime int64  `json:"create_time"` // 作者回复时间
+}
+
+// Comment is a comment of an article.
+type Comment struct {
+	UserCommentID int64         `json:"user_comment_id"` // 用户评论id
+	OpenID        string        `json:"openid"`          // 评论用户的openid
+	CreateTime    int64         `json:"create_time"`     // 评论时间
+	Content       string        `json:"content"`         // 评论内容
+	CommentType   int           `json:"comment_type"`    // 是否精选评论，0为非精选，1为精选
+	Reply         *CommentReply `json:"reply,omitempty"` // 作者回复
+
+	MsgDataID int64 `json:"msg_data_id"` // 评论所属群发的msg_data_id，导出时填充
+	Index     int   `json:"index"`       // 评论所属图文的序号，导出时填充
+}
+
+// Elected reports whether the comment is elected by the author.
+func (c *Comment) Elected() bool {
+	return c.CommentType == 1
+}
+
+// CommentListResponse represents a page of the comments of an article.
+type CommentListResponse struct {
+	ErrCode  int        `json:"errcode"`
+	ErrMsg   string     `json:"errmsg"`
+	Total    int        `json:"total"`   // 总数，非comment的size
+	Comments []*Comment `json:"comment"` // 评论列表
+}
+
+// ListComments returns a page of the comments of the article of the mass send.
+func (s *Service) ListComments(req *CommentListRequest) (*CommentListResponse, error) {
+	return s.ListCommentsContext(context.Background(), req)
+}
+
+// ListCommentsContext is ListComments with the given context.
+func (s *Service) ListCommentsContext(ctx context.Context, req *CommentListRequest) (*CommentListResponse, error) {
+	if req.Count <= 0 || req.Count > MaxCommentPageSize {
+		return nil, fmt.Errorf("comment count must be in 1-%d, got %d", MaxCommentPageSize, req.Count)
+	}
+
+	call := s.call("list comments", commentListURL)
+	call.Idempotent = true
+
+	result, err := wxapi.PostJSON[CommentListResponse](ctx, call, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range result.Comments {
+		comment.MsgDataID = req.MsgDataID
+		comment.Index = req.Index
+	}
+
+	return result, nil
+}
+
+// Comments iterates all comments of the article of the mass send page by page, the iteration stops
+// at the first error yielded.
+//
+//	for comment, err := range svc.Comments(msgDataID, 0, vwxmp.CommentTypeAll) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *Service) Comments(msgDataID int64, index int, typ CommentType) iter.Seq2[*Comment, error] {
+	return s.CommentsContext(context.Background(), msgDataID, index, typ)
+}
+
+// CommentsContext is Comments with the given context.
+func (s *Service) CommentsContext(ctx context.Context, msgDataID int64, index int, typ CommentType) iter.Seq2[*Comment, error] {
+	return func(yield func(*Comment, error) bool) {
+		for begin := 0; ; {
+			resp, err := s.ListCommentsContext(ctx, &CommentListRequest{
+				MsgDataID: msgDataID,
+				Index:     index,
+				Begin:     begin,
+				Count:     MaxCommentPageSize,
+				Type:      typ,
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, comment := range resp.Comments {
+				if !yield(comment, nil) {
+					return
+				}
+			}
+
+			begin += len(resp.Comments)
+			if len(resp.Comments) == 0 || begin >= resp.Total {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestInteractionExport(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxmp.NewService(client)
+
+	server.SetComments(1001, 120)
+
+	var comments []*vwxmp.Comment
+	for comment, err := range svc.Comments(1001, 0, vwxmp.CommentTypeAll) {
+		assert.NoError(t, err)
+		comments = append(comments, comment)
+	}
+	assert.Len(t, comments, 120)
+	assert.Equal(t, int64(120), comments[119].UserCommentID)
+	assert.Equal(t, int64(1001), comments[119].MsgDataID)
+	assert.Len(t, server.Requests("/cgi-bin/comment/list"), 3)
+
+	// breaking the loop stops paging
+	for range svc.Comments(1001, 0, vwxmp.CommentTypeAll) {
+		break
+	}
+	assert.Len(t, server.Requests("/cgi-bin/comment/list"), 4)
+
+	server.SetError("/cgi-bin/comment/list", 88000, "without comment privilege")
+	for _, err := range svc.Comments(1001, 0, vwxmp.CommentTypeAll) {
+		assert.True(t, vwx.IsErrCode(err, 88000))
+	}
+	server.ClearError("/cgi-bin/comment/list")
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+
+	times := make([]time.Time, 0, vwxmp.MaxMsgRecordPageSize+1)
+	for i := range vwxmp.MaxMsgRecordPageSize + 1 {
+		times = append(times, start.Add(time.Duration(i)*time.Second))
+	}
+	server.AddMsgRecords("openid-1", times...)
+	server.AddMsgRecords("openid-2", start.Add(36*time.Hour), start.Add(48*time.Hour))
+
+	count := 0
+	var last *vwxmp.MsgRecord
+	for record, err := range svc.MsgRecords(start, start.Add(72*time.Hour)) {
+		assert.NoError(t, err)
+		count++
+		last = record
+	}
+	assert.Equal(t, vwxmp.MaxMsgRecordPageSize+3, count)
+	assert.Equal(t, "openid-2", last.OpenID)
+
+	// 2 pages of the first day and a page of each of the other days
+	assert.Len(t, server.Requests("/customservice/msgrecord/getmsglist"), 4)
+
+	_, err := svc.ListMsgRecords(&vwxmp.MsgRecordListRequest{StartTime: 0, EndTime: 86401, MsgID: 1, Number: 10})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	customMessageSendURL = "https://api.weixin.qq.com/cgi-bin/message/custom/send?access_token=%s"
+
+	// errCodeInvalidMediaID is returned when the media id is invalid or the temporary media is expired.
+	errCodeInvalidMediaID = 40007
+
+	// maxCustomMediaSize is the max size of media sent by SendCustomMediaMessage.
+	maxCustomMediaSize = 10 << 20
+)
+
+// CustomMessageText represents the text content of a customer service message.
+type CustomMessageText struct {
+	Content string `json:"content"` // 文本消息内容
+}
+
+// CustomMessageMedia represents the media content of a customer service message.
+type CustomMessageMedia struct {
+	MediaID string `json:"media_id"` // 发送的媒体文件的媒体ID
+}
+
+// CustomMessageMsgMenuItem represents an option of a menu message.
+type CustomMessageMsgMenuItem struct {
+	ID      string `json:"id"`      // 菜单项id，用户点击后在推送的bizmsgmenuid中返回
+	Content string `json:"content"` // 菜单项显示内容
+}
+
+// CustomMessageMsgMenu represents the menu content of a customer service message.
+type CustomMessageMsgMenu struct {
+	HeadContent string                      `json:"head_content"` // 菜单前的提示内容
+	List        []*CustomMessageMsgMenuItem `json:"list"`         // 菜单项列表
+	TailContent string                      `json:"tail_content"` // 菜单后的提示内容
+}
+
+// CustomMessageRequest represents the request of sending a customer service message.
+type CustomMessageRequest struct {
+	ToUser  string                `json:"touser"`            // 普通用户openid
+	MsgType string                `json:"msgtype"`           // 消息类型，text、image、voice等
+	Text    *CustomMessageText    `json:"text,omitempty"`    // 文本消息
+	Image   *CustomMessageMedia   `json:"image,omitempty"`   // 图片消息
+	Voice   *CustomMessageMedia   `json:"voice,omitempty"`   // 语音消息
+	MsgMenu *CustomMessageMsgMenu `json:"msgmenu,omitempty"` // 菜单消息
+}
+
+// CustomMessageResponse represents the response from sending a customer service message.
+type CustomMessageResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SendCustomMessage sends a customer service message to the user,
+// the user must have interacted with the official account within 48 hours.
+func (s *Service) SendCustomMessage(req *CustomMessageRequest) (*CustomMessageResponse, error) {
+	return s.SendCustomMessageContext(context.Background(), req)
+}
+
+// SendCustomMessageContext is SendCustomMessage with the given context.
+func (s *Service) SendCustomMessageContext(ctx context.Context, req *CustomMessageRequest) (*CustomMessageResponse, error) {
+	if s.client.DryRun {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request error: %w", err)
+		}
+
+		s.client.Logger.Infof("dry run, skip send custom message | request_id: %s | req: %s", vwx.RequestIDOrNew(ctx), data)
+		return &CustomMessageResponse{ErrCode: 0, ErrMsg: "ok"}, nil
+	}
+
+	return wxapi.PostJSON[CustomMessageResponse](ctx, s.call("send custom message", customMessageSendURL), req)
+}
+
+// SendCustomMediaMessage uploads the image or voice as temporary media and sends it
+// as a customer service message in one call. The media is uploaded again and the
+// message resent once if WeChat reports the media id invalid or expired.
+func (s *Service) SendCustomMediaMessage(openID string, mediaType MediaType, filename string, r io.Reader) error {
+	return s.SendCustomMediaMessageContext(context.Background(), openID, mediaType, filename, r)
+}
+
+// SendCustomMediaMessageContext is SendCustomMediaMessage with the given context.
+func (s *Service) SendCustomMediaMessageContext(ctx context.Context, openID string, mediaType MediaType, filename string, r io.Reader) error {
+	if mediaType != MediaTypeImage && mediaType != MediaTypeVoice {
+		return fmt.Errorf("unsupported custom message media type: %s", mediaType)
+	}
+
+	// buffer the media so that it can be uploaded again on media expiry
+	data, err := io.ReadAll(io.LimitReader(r, maxCustomMediaSize+1))
+	if err != nil {
+		return fmt.Errorf("read media error: %w", err)
+	}
+
+	if len(data) > maxCustomMediaSize {
+		return fmt.Errorf("media too large, exceeds %d bytes", maxCustomMediaSize)
+	}
+
+	if s.client.DryRun {
+		s.client.Logger.Infof("dry run, skip send custom media message | openid: %s | type: %s | size: %d", openID, mediaType, len(data))
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		media, err := s.UploadTempMediaContext(ctx, mediaType, filename, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("upload media error: %w", err)
+		}
+
+		req := &CustomMessageRequest{
+			ToUser:  openID,
+			MsgType: string(mediaType),
+		}
+
+		content := &CustomMessageMedia{MediaID: media.MediaID}
+		if mediaType == MediaTypeImage {
+			req.Image = content
+		} else {
+			req.Voice = content
+		}
+
+		resp, err := s.SendCustomMessageContext(ctx, req)
+		if err != nil && attempt == 0 && resp != nil && resp.ErrCode == errCodeInvalidMediaID {
+			s.client.Logger.Warnf("custom message media expired, upload again | openid: %s | media_id: %s", openID, media.MediaID)
+			continue
+		}
+
+		return err
+	}
+}
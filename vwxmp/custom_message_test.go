@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestCustomMediaMessage(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	err := svc.SendCustomMediaMessage("openid", vwxmp.MediaTypeImage, "a.png", bytes.NewReader(vwxmock.PNGHeader))
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/media/upload"), 1)
+	assert.Len(t, server.Requests("/cgi-bin/message/custom/send"), 1)
+
+	// expired media is uploaded again on send failure
+	server.ExpireMedia()
+	_, err = svc.SendCustomMessage(&vwxmp.CustomMessageRequest{
+		ToUser:  "openid",
+		MsgType: "image",
+		Image:   &vwxmp.CustomMessageMedia{MediaID: "mock-media-1"},
+	})
+	assert.Error(t, err)
+
+	server.SetResponse("/cgi-bin/media/upload", map[string]any{"type": "voice", "media_id": "expired-media"})
+	err = svc.SendCustomMediaMessage("openid", vwxmp.MediaTypeVoice, "a.mp3", bytes.NewReader([]byte("voice")))
+	assert.Error(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/media/upload"), 3)
+	assert.Len(t, server.Requests("/cgi-bin/message/custom/send"), 4)
+
+	_, err = svc.SendCustomMessage(&vwxmp.CustomMessageRequest{
+		ToUser:  "openid",
+		MsgType: "text",
+		Text:    &vwxmp.CustomMessageText{Content: "hello"},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	freePublishSubmitURL = "https://api.weixin.qq.com/cgi-bin/freepublish/submit?access_token=%s"
+	freePublishGetURL    = "https://api.weixin.qq.com/cgi-bin/freepublish/get?access_token=%s"
+
+	// defaultPublishPollInterval is the first interval polling the publish status, doubled after each poll.
+	defaultPublishPollInterval = 2 * time.Second
+	// defaultPublishPollMaxInterval caps the interval polling the publish status.
+	defaultPublishPollMaxInterval = 30 * time.Second
+	// defaultPublishWaitTimeout bounds WaitForPublish called without a context.
+	defaultPublishWaitTimeout = 10 * time.Minute
+)
+
+// PublishStatus is the status of a publish task.
+type PublishStatus int
+
+const (
+	PublishSuccess        PublishStatus = 0 // 成功
+	PublishPublishing     PublishStatus = 1 // 发布中
+	PublishOriginalFailed PublishStatus = 2 // 原创失败
+	PublishFailed         PublishStatus = 3 // 常规失败
+	PublishAuditRejected  PublishStatus = 4 // 平台审核不通过
+	PublishUserDeleted    PublishStatus = 5 // 成功后用户删除所有文章
+	PublishBanned         PublishStatus = 6 // 成功后系统封禁所有文章
+)
+
+// ErrPublishFailed is returned by WaitForPublish when the publish task ends without success.
+var ErrPublishFailed = errors.New("publish failed")
+
+// FreePublishSubmitResponse represents the response of submitting a draft to publish.
+type FreePublishSubmitResponse struct {
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+	PublishID string `json:"publish_id"`  // 发布任务的id
+	MsgDataID int64  `json:"msg_data_id"` // 消息的数据ID
+}
+
+// FreePublishArticle is a published article.
+type FreePublishArticle struct {
+	Idx        int    `json:"idx"`         // 文章序号，从1开始
+	ArticleURL string `json:"article_url"` // 图文的永久链接
+}
+
+// FreePublishArticleDetail is the articles of a publish task.
+type FreePublishArticleDetail struct {
+	Count int                   `json:"count"` // 文章数量
+	Item  []*FreePublishArticle `json:"item"`  // 文章列表
+}
+
+// FreePublishStatusResponse represents the status of a publish task.
+type FreePublishStatusResponse struct {
+	ErrCode       int                       `json:"errcode"`
+	ErrMsg        string                    `json:"errmsg"`
+	PublishID     string                    `json:"publish_id"`     // 发布任务id
+	PublishStatus PublishStatus             `json:"publish_status"` // 发布状态
+	ArticleID     string                    `json:"article_id"`     // 发布成功时的图文article_id
+	ArticleDetail *FreePublishArticleDetail `json:"article_detail"` // 发布成功时的文章信息
+	FailIdx       []int                     `json:"fail_idx"`       // 原创审核不通过或常规失败时失败的文章序号
+}
+
+// ArticleURLs returns the permanent urls of the published articles in order.
+func (r *FreePublishStatusResponse) ArticleURLs() []string {
+	if r.ArticleDetail == nil {
+		return nil
+	}
+
+	urls := make([]string, 0, len(r.ArticleDetail.Item))
+	for _, item := range r.ArticleDetail.Item {
+		urls = append(urls, item.ArticleURL)
+	}
+
+	return urls
+}
+
+// WithPublishPollInterval sets the first and the max interval WaitForPublish polls the publish status.
+func WithPublishPollInterval(interval, maxInterval time.Duration) func(*Service) {
+	return func(s *Service) {
+		s.publishPollInterval = interval
+		s.publishPollMaxInterval = maxInterval
+	}
+}
+
+// SubmitPublish submits the draft of the media id to publish, the publish runs asynchronously.
+func (s *Service) SubmitPublish(mediaID string) (*FreePublishSubmitResponse, error) {
+	return s.SubmitPublishContext(context.Background(), mediaID)
+}
+
+// SubmitPublishContext is SubmitPublish with the given context.
+func (s *Service) SubmitPublishContext(ctx context.Context, mediaID string) (*FreePublishSubmitResponse, error) {
+	if s.client.DryRun {
+		s.client.Logger.Infof("dry run, skip submit publish | request_id: %s | media_id: %s", vwx.RequestIDOrNew(ctx), mediaID)
+		return &FreePublishSubmitResponse{ErrCode: 0, ErrMsg: "ok"}, nil
+	}
+
+	return wxapi.PostJSON[FreePublishSubmitResponse](ctx, s.call("submit publish", freePublishSubmitURL), map[string]string{"media_id": mediaID})
+}
+
+// GetPublishStatus returns the status of the publish task.
+func (s *Service) GetPublishStatus(publishID string) (*FreePublishStatusResponse, error) {
+	return s.GetPublishStatusContext(context.Background(), publishID)
+}
+
+// GetPublishStatusContext is GetPublishStatus with the given context.
+func (s *Service) GetPublishStatusContext(ctx context.Context, publishID string) (*FreePublishStatusResponse, error) {
+	call := s.call("get publish status", freePublishGetURL)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[FreePublishStatusResponse](ctx, call, map[string]string{"publish_id": publishID})
+}
+
+// WaitForPublish polls the status of the publish task with backoff until it ends, and returns
+// the final status, whose ArticleURLs are the urls of the published articles. ErrPublishFailed
+// is returned along with the status if the publish fails. It gives up after 10 minutes.
+func (s *Service) WaitForPublish(publishID string) (*FreePublishStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPublishWaitTimeout)
+	defer cancel()
+
+	return s.WaitForPublishContext(ctx, publishID)
+}
+
+// WaitForPublishContext is WaitForPublish with the given context, it polls until the context is done.
+func (s *Service) WaitForPublishContext(ctx context.Context, publishID string) (*FreePublishStatusResponse, error) {
+	// all polls share the request id of the wait
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	interval := s.publishPollInterval
+	if interval <= 0 {
+		interval = defaultPublishPollInterval
+	}
+
+	maxInterval := s.publishPollMaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultPublishPollMaxInterval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for publish %s: %w", publishID, ctx.Err())
+		case <-timer.C:
+		}
+
+		status, err := s.GetPublishStatusContext(ctx, publishID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.PublishStatus {
+		case PublishPublishing:
+			interval = min(interval*2, maxInterval)
+			timer.Reset(interval)
+			continue
+		case PublishSuccess:
+			s.client.Logger.Infof("publish success | request_id: %s | publish_id: %s | article_id: %s", requestID, publishID, status.ArticleID)
+			return status, nil
+		default:
+			s.client.Logger.Warnf("publish failed | request_id: %s | publish_id: %s | status: %d | fail_idx: %v",
+				requestID, publishID, status.PublishStatus, status.FailIdx)
+			return status, fmt.Errorf("%w: publish_id %s status %d", ErrPublishFailed, publishID, status.PublishStatus)
+		}
+	}
+}
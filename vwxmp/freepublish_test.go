@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestWaitForPublish(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+	server.PublishingPolls = 2
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)),
+		vwxmp.WithPublishPollInterval(time.Millisecond, 2*time.Millisecond))
+
+	submitted, err := svc.SubmitPublish("draft-media-id")
+	assert.NoError(t, err)
+
+	status, err := svc.WaitForPublish(submitted.PublishID)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.PublishSuccess, status.PublishStatus)
+	assert.Equal(t, []string{"https://mp.weixin.qq.com/s/" + submitted.PublishID}, status.ArticleURLs())
+	assert.Len(t, server.Requests("/cgi-bin/freepublish/get"), 3)
+
+	server.SetResponse("/cgi-bin/freepublish/get", map[string]any{"publish_id": "failed", "publish_status": 4, "fail_idx": []int{1}})
+
+	status, err = svc.WaitForPublish("failed")
+	assert.ErrorIs(t, err, vwxmp.ErrPublishFailed)
+	assert.Equal(t, []int{1}, status.FailIdx)
+
+	server.SetResponse("/cgi-bin/freepublish/get", map[string]any{"publish_id": "pending", "publish_status": 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = svc.WaitForPublishContext(ctx, "pending")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// drafts are not published in dry run mode
+	dryRun := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server), vwx.WithDryRun(true)))
+	_, err = dryRun.SubmitPublish("draft-media-id")
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/freepublish/submit"), 1)
+}
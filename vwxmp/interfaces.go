@@ -0,0 +1,209 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+)
+
+// OAuthClient performs the WeChat Web (H5) OAuth flow.
+type OAuthClient interface {
+	BuildAuthorizeURL(redirectURI string, scope OAuthScope, state string, forcePopup bool) string
+	GetOAuthAccessToken(code string) (*OAuthAccessTokenResponse, error)
+	GetOAuthAccessTokenContext(ctx context.Context, code string) (*OAuthAccessTokenResponse, error)
+	RefreshOAuthAccessToken(refreshToken string) (*OAuthAccessTokenResponse, error)
+	RefreshOAuthAccessTokenContext(ctx context.Context, refreshToken string) (*OAuthAccessTokenResponse, error)
+	CheckOAuthAccessToken(accessToken, openID string) error
+	CheckOAuthAccessTokenContext(ctx context.Context, accessToken, openID string) error
+}
+
+// UserInfoGetter retrieves the profile of an authorized user.
+type UserInfoGetter interface {
+	GetUserInfo(accessToken, openID string, lang UserInfoLang) (*UserInfoResponse, error)
+	GetUserInfoContext(ctx context.Context, accessToken, openID string, lang UserInfoLang) (*UserInfoResponse, error)
+}
+
+// SubscriberInfoGetter retrieves the basic info of the users of the official account.
+type SubscriberInfoGetter interface {
+	GetSubscriberInfo(openID string, lang UserInfoLang) (*SubscriberInfo, error)
+	GetSubscriberInfoContext(ctx context.Context, openID string, lang UserInfoLang) (*SubscriberInfo, error)
+}
+
+// OAuthTokenManager keeps the OAuth tokens of users and refreshes them transparently.
+type OAuthTokenManager interface {
+	ExchangeOAuthToken(code string) (*OAuthToken, error)
+	ExchangeOAuthTokenContext(ctx context.Context, code string) (*OAuthToken, error)
+	GetOAuthToken(openID string) (*OAuthToken, error)
+	GetOAuthTokenContext(ctx context.Context, openID string) (*OAuthToken, error)
+	GetUserInfoByOpenID(openID string, lang UserInfoLang) (*UserInfoResponse, error)
+	GetUserInfoByOpenIDContext(ctx context.Context, openID string, lang UserInfoLang) (*UserInfoResponse, error)
+}
+
+// ScopeUpgrader upgrades a snsapi_base identity to snsapi_userinfo and merges the profile.
+type ScopeUpgrader interface {
+	BuildScopeUpgradeURL(openID, redirectURI, continueState string) (string, error)
+	BuildScopeUpgradeURLContext(ctx context.Context, openID, redirectURI, continueState string) (string, error)
+	CompleteScopeUpgrade(code, state string, lang UserInfoLang) (*OAuthToken, string, error)
+	CompleteScopeUpgradeContext(ctx context.Context, code, state string, lang UserInfoLang) (*OAuthToken, string, error)
+}
+
+// JSSDKSigner signs the wx.config of the JS-SDK for web pages.
+type JSSDKSigner interface {
+	GetJSAPITicket() (string, error)
+	GetJSAPITicketContext(ctx context.Context) (string, error)
+	RefreshJSAPITicket() (string, error)
+	RefreshJSAPITicketContext(ctx context.Context) (string, error)
+	GetJSSDKConfig(pageURL string) (*JSSDKConfig, error)
+	GetJSSDKConfigContext(ctx context.Context, pageURL string) (*JSSDKConfig, error)
+	InvalidateJSSDKSignatures()
+}
+
+// CustomMessageSender sends customer service messages.
+type CustomMessageSender interface {
+	UploadTempMedia(mediaType MediaType, filename string, r io.Reader) (*UploadMediaResponse, error)
+	UploadTempMediaContext(ctx context.Context, mediaType MediaType, filename string, r io.Reader) (*UploadMediaResponse, error)
+	SendCustomMessage(req *CustomMessageRequest) (*CustomMessageResponse, error)
+	SendCustomMessageContext(ctx context.Context, req *CustomMessageRequest) (*CustomMessageResponse, error)
+	SendCustomMediaMessage(openID string, mediaType MediaType, filename string, r io.Reader) error
+	SendCustomMediaMessageContext(ctx context.Context, openID string, mediaType MediaType, filename string, r io.Reader) error
+}
+
+// ArticleImageMigrator uploads article images and rewrites article content to WeChat CDN urls.
+type ArticleImageMigrator interface {
+	UploadArticleImage(filename string, r io.Reader) (string, error)
+	UploadArticleImageContext(ctx context.Context, filename string, r io.Reader) (string, error)
+	MigrateArticleImages(content string) *ArticleMigrationResult
+	MigrateArticleImagesContext(ctx context.Context, content string) *ArticleMigrationResult
+}
+
+// TemplateMessageSender sends template messages.
+type TemplateMessageSender interface {
+	SendTemplateMessage(req *TemplateMessageRequest) (*TemplateMessageResponse, error)
+	SendTemplateMessageContext(ctx context.Context, req *TemplateMessageRequest) (*TemplateMessageResponse, error)
+}
+
+// MenuManager manages the custom menu declaratively.
+type MenuManager interface {
+	GetMenu() (*Menu, error)
+	GetMenuContext(ctx context.Context) (*Menu, error)
+	CreateMenu(menu *Menu) error
+	CreateMenuContext(ctx context.Context, menu *Menu) error
+	DeleteMenu() error
+	DeleteMenuContext(ctx context.Context) error
+	DiffMenu(desired *Menu) (*MenuDiff, error)
+	DiffMenuContext(ctx context.Context, desired *Menu) (*MenuDiff, error)
+	ApplyMenu(desired *Menu) (*MenuDiff, error)
+	ApplyMenuContext(ctx context.Context, desired *Menu) (*MenuDiff, error)
+}
+
+// QRCodeCreator creates parametric QR codes.
+type QRCodeCreator interface {
+	CreateQRCode(req *QRCodeCreateRequest) (*QRCodeResponse, error)
+	CreateQRCodeContext(ctx context.Context, req *QRCodeCreateRequest) (*QRCodeResponse, error)
+	CreateStrSceneQRCode(scene string, expire time.Duration) (*QRCodeResponse, error)
+	CreateStrSceneQRCodeContext(ctx context.Context, scene string, expire time.Duration) (*QRCodeResponse, error)
+}
+
+// OpenIDMigrator converts the openids of the original account after an account migration.
+type OpenIDMigrator interface {
+	ChangeOpenID(fromAppID string, openIDs []string) ([]*ChangeOpenIDResult, error)
+	ChangeOpenIDContext(ctx context.Context, fromAppID string, openIDs []string) ([]*ChangeOpenIDResult, error)
+	MigrateOpenIDs(fromAppID string, openIDs []string, fn func(results []*ChangeOpenIDResult) error) error
+	MigrateOpenIDsContext(ctx context.Context, fromAppID string, openIDs []string, fn func(results []*ChangeOpenIDResult) error) error
+}
+
+// FreePublisher publishes drafts and waits for the published articles.
+type FreePublisher interface {
+	SubmitPublish(mediaID string) (*FreePublishSubmitResponse, error)
+	SubmitPublishContext(ctx context.Context, mediaID string) (*FreePublishSubmitResponse, error)
+	GetPublishStatus(publishID string) (*FreePublishStatusResponse, error)
+	GetPublishStatusContext(ctx context.Context, publishID string) (*FreePublishStatusResponse, error)
+	WaitForPublish(publishID string) (*FreePublishStatusResponse, error)
+	WaitForPublishContext(ctx context.Context, publishID string) (*FreePublishStatusResponse, error)
+}
+
+// MassSender sends mass messages by tag or openid list.
+type MassSender interface {
+	SendMassByTag(tagID int64, msg *MassMessage) (*MassSendResponse, error)
+	SendMassByTagContext(ctx context.Context, tagID int64, msg *MassMessage) (*MassSendResponse, error)
+	SendMassByOpenIDs(openIDs []string, msg *MassMessage) (*MassSendResponse, error)
+	SendMassByOpenIDsContext(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResponse, error)
+	GetMassStatus(msgID int64) (*MassStatusResponse, error)
+	GetMassStatusContext(ctx context.Context, msgID int64) (*MassStatusResponse, error)
+}
+
+// InteractionExporter pages through the article comments and the customer service message records of the fans.
+type InteractionExporter interface {
+	ListComments(req *CommentListRequest) (*CommentListResponse, error)
+	ListCommentsContext(ctx context.Context, req *CommentListRequest) (*CommentListResponse, error)
+	Comments(msgDataID int64, index int, typ CommentType) iter.Seq2[*Comment, error]
+	CommentsContext(ctx context.Context, msgDataID int64, index int, typ CommentType) iter.Seq2[*Comment, error]
+	ListMsgRecords(req *MsgRecordListRequest) (*MsgRecordListResponse, error)
+	ListMsgRecordsContext(ctx context.Context, req *MsgRecordListRequest) (*MsgRecordListResponse, error)
+	MsgRecords(start, end time.Time) iter.Seq2[*MsgRecord, error]
+	MsgRecordsContext(ctx context.Context, start, end time.Time) iter.Seq2[*MsgRecord, error]
+}
+
+// KFSessionManager manages the customer service accounts and their sessions.
+type KFSessionManager interface {
+	GetKFList() ([]*KFAccount, error)
+	GetKFListContext(ctx context.Context) ([]*KFAccount, error)
+	GetOnlineKFList() ([]*OnlineKF, error)
+	GetOnlineKFListContext(ctx context.Context) ([]*OnlineKF, error)
+	CreateKFSession(kfAccount, openID string) error
+	CreateKFSessionContext(ctx context.Context, kfAccount, openID string) error
+	CloseKFSession(kfAccount, openID string) error
+	CloseKFSessionContext(ctx context.Context, kfAccount, openID string) error
+	GetKFSession(openID string) (*KFSession, error)
+	GetKFSessionContext(ctx context.Context, openID string) (*KFSession, error)
+	GetKFSessionList(kfAccount string) ([]*KFSessionItem, error)
+	GetKFSessionListContext(ctx context.Context, kfAccount string) ([]*KFSessionItem, error)
+	GetKFWaitCase() (*KFWaitCaseResponse, error)
+	GetKFWaitCaseContext(ctx context.Context) (*KFWaitCaseResponse, error)
+}
+
+// AccountStatusChecker checks the security status of the account.
+type AccountStatusChecker interface {
+	GetAccountBasicInfo() (*AccountBasicInfo, error)
+	GetAccountBasicInfoContext(ctx context.Context) (*AccountBasicInfo, error)
+	GetAccountStatus() (AccountStatus, error)
+	GetAccountStatusContext(ctx context.Context) (AccountStatus, error)
+}
+
+var (
+	_ OAuthClient           = (*Service)(nil)
+	_ UserInfoGetter        = (*Service)(nil)
+	_ SubscriberInfoGetter  = (*Service)(nil)
+	_ OAuthTokenManager     = (*Service)(nil)
+	_ ScopeUpgrader         = (*Service)(nil)
+	_ JSSDKSigner           = (*Service)(nil)
+	_ CustomMessageSender   = (*Service)(nil)
+	_ ArticleImageMigrator  = (*Service)(nil)
+	_ TemplateMessageSender = (*Service)(nil)
+	_ MenuManager           = (*Service)(nil)
+	_ QRCodeCreator         = (*Service)(nil)
+	_ OpenIDMigrator        = (*Service)(nil)
+	_ FreePublisher         = (*Service)(nil)
+	_ MassSender            = (*Service)(nil)
+	_ InteractionExporter   = (*Service)(nil)
+	_ AccountStatusChecker  = (*Service)(nil)
+	_ KFSessionManager      = (*Service)(nil)
+)
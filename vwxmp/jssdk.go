@@ -0,0 +1,209 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	jsapiTicketURL = "https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=%s&type=jsapi"
+
+	// MaxJSSDKSignatures is the max number of page urls whose signatures are cached,
+	// the cache is reset when it is full.
+	MaxJSSDKSignatures = 1000
+)
+
+// JSAPITicketResponse represents the response of getting the jsapi ticket.
+type JSAPITicketResponse struct {
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+	Ticket    string `json:"ticket"`     // 用于JS-SDK签名的临时票据
+	ExpiresIn int    `json:"expires_in"` // 有效期，单位秒，一般为7200
+}
+
+// JSSDKConfig is the signed config of wx.config for a page.
+type JSSDKConfig struct {
+	AppID     string `json:"appId"`     // 公众号的唯一标识
+	Timestamp int64  `json:"timestamp"` // 生成签名的时间戳
+	NonceStr  string `json:"nonceStr"`  // 生成签名的随机串
+	Signature string `json:"signature"` // 签名
+	URL       string `json:"url"`       // 签名的页面url，不包含#及其后面部分
+}
+
+// jssdkSignatures caches the configs signed by a jsapi ticket per normalized page url,
+// they are dropped when the ticket changes.
+type jssdkSignatures struct {
+	mu      sync.Mutex
+	ticket  string
+	configs map[string]*JSSDKConfig
+}
+
+// get returns the config of the url signed by the ticket, nil if not cached.
+func (j *jssdkSignatures) get(ticket, pageURL string) *JSSDKConfig {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.ticket != ticket {
+		return nil
+	}
+
+	return j.configs[pageURL]
+}
+
+// put caches the config of the url signed by the ticket.
+func (j *jssdkSignatures) put(ticket string, config *JSSDKConfig) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.ticket != ticket || len(j.configs) >= MaxJSSDKSignatures {
+		j.ticket = ticket
+		j.configs = make(map[string]*JSSDKConfig)
+	}
+
+	j.configs[config.URL] = config
+}
+
+// reset drops all cached configs.
+func (j *jssdkSignatures) reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.ticket = ""
+	j.configs = nil
+}
+
+func (s *Service) cacheKeyJSAPITicket() string {
+	return s.client.CacheKeyPrefix + "vwxmp:jsapi_ticket:" + s.client.AppID
+}
+
+// GetJSAPITicket returns the jsapi ticket signing the JS-SDK configs, it is cached by the cache
+// provider of the client for its lifetime.
+func (s *Service) GetJSAPITicket() (string, error) {
+	return s.GetJSAPITicketContext(context.Background())
+}
+
+// GetJSAPITicketContext is GetJSAPITicket with the given context.
+func (s *Service) GetJSAPITicketContext(ctx context.Context) (string, error) {
+	return s.jsapiTickets.Get(ctx)
+}
+
+// RefreshJSAPITicket discards the cached jsapi ticket and the configs signed by it,
+// then fetches a fresh ticket.
+func (s *Service) RefreshJSAPITicket() (string, error) {
+	return s.RefreshJSAPITicketContext(context.Background())
+}
+
+// RefreshJSAPITicketContext is RefreshJSAPITicket with the given context.
+func (s *Service) RefreshJSAPITicketContext(ctx context.Context) (string, error) {
+	s.jssdk.reset()
+
+	return s.jsapiTickets.ForceRefresh(ctx)
+}
+
+// requestJSAPITicket requests a new jsapi ticket from WeChat.
+func (s *Service) requestJSAPITicket(ctx context.Context, _ bool) (string, int, error) {
+	call := s.call("get jsapi ticket", jsapiTicketURL)
+	call.Idempotent = true
+	call.Summary = "appid: " + s.client.AppID
+
+	result, err := wxapi.GetJSON[JSAPITicketResponse](ctx, call)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return result.Ticket, result.ExpiresIn, nil
+}
+
+// NormalizeJSSDKURL returns the page url signed for wx.config: the fragment is stripped,
+// the query is kept as is.
+func NormalizeJSSDKURL(pageURL string) string {
+	pageURL, _, _ = strings.Cut(pageURL, "#")
+
+	return pageURL
+}
+
+// SignJSSDK returns the wx.config signature of the page url by the jsapi ticket.
+func SignJSSDK(ticket, nonceStr string, timestamp int64, pageURL string) string {
+	// the fields are sorted by name
+	plain := "jsapi_ticket=" + ticket + "&noncestr=" + nonceStr +
+		"&timestamp=" + strconv.FormatInt(timestamp, 10) + "&url=" + pageURL
+
+	sum := sha1.Sum([]byte(plain))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// GetJSSDKConfig returns the signed wx.config of the page url. Configs are cached per normalized
+// url while the jsapi ticket is valid, so that the loads of a single page application sign once
+// and get consistent signatures.
+func (s *Service) GetJSSDKConfig(pageURL string) (*JSSDKConfig, error) {
+	return s.GetJSSDKConfigContext(context.Background(), pageURL)
+}
+
+// GetJSSDKConfigContext is GetJSSDKConfig with the given context.
+func (s *Service) GetJSSDKConfigContext(ctx context.Context, pageURL string) (*JSSDKConfig, error) {
+	ticket, err := s.GetJSAPITicketContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get jsapi ticket error: %w", err)
+	}
+
+	pageURL = NormalizeJSSDKURL(pageURL)
+
+	if config := s.jssdk.get(ticket, pageURL); config != nil {
+		c := *config
+		return &c, nil
+	}
+
+	config := &JSSDKConfig{
+		AppID:     s.client.AppID,
+		Timestamp: time.Now().Unix(),
+		NonceStr:  newNonceStr(),
+		URL:       pageURL,
+	}
+	config.Signature = SignJSSDK(ticket, config.NonceStr, config.Timestamp, pageURL)
+
+	s.jssdk.put(ticket, config)
+
+	c := *config
+	return &c, nil
+}
+
+// InvalidateJSSDKSignatures drops the cached wx.config signatures, they are signed again on the
+// next GetJSSDKConfig. RefreshJSAPITicket invalidates them as well.
+func (s *Service) InvalidateJSSDKSignatures() {
+	s.jssdk.reset()
+}
+
+// newNonceStr returns a random nonce string of the signature.
+func newNonceStr() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestJSSDKConfig(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	config, err := svc.GetJSSDKConfig("https://example.com/app/?from=share#/detail/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "appid", config.AppID)
+	assert.Equal(t, "https://example.com/app/?from=share", config.URL)
+
+	ticket, err := svc.GetJSAPITicket()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ticket, vwxmock.JSAPITicketPrefix))
+	assert.Equal(t, vwxmp.SignJSSDK(ticket, config.NonceStr, config.Timestamp, config.URL), config.Signature)
+
+	// the routes of a single page application share the signature of the page
+	again, err := svc.GetJSSDKConfig("https://example.com/app/?from=share#/list")
+	assert.NoError(t, err)
+	assert.Equal(t, config, again)
+	assert.Len(t, server.Requests("/cgi-bin/ticket/getticket"), 1)
+
+	other, err := svc.GetJSSDKConfig("https://example.com/app/?from=menu")
+	assert.NoError(t, err)
+	assert.NotEqual(t, config.Signature, other.Signature)
+
+	// the signatures are dropped with the refreshed ticket
+	refreshed, err := svc.RefreshJSAPITicket()
+	assert.NoError(t, err)
+	assert.NotEqual(t, ticket, refreshed)
+
+	config, err = svc.GetJSSDKConfig("https://example.com/app/?from=share")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.SignJSSDK(refreshed, config.NonceStr, config.Timestamp, config.URL), config.Signature)
+	assert.Len(t, server.Requests("/cgi-bin/ticket/getticket"), 2)
+}
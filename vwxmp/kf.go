@@ -0,0 +1,220 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getKFListURL        = "https://api.weixin.qq.com/cgi-bin/customservice/getkflist?access_token=%s"
+	getOnlineKFListURL  = "https://api.weixin.qq.com/cgi-bin/customservice/getonlinekflist?access_token=%s"
+	createKFSessionURL  = "https://api.weixin.qq.com/customservice/kfsession/create?access_token=%s"
+	closeKFSessionURL   = "https://api.weixin.qq.com/customservice/kfsession/close?access_token=%s"
+	getKFSessionURL     = "https://api.weixin.qq.com/customservice/kfsession/getsession?access_token=%s&openid=%s"
+	getKFSessionListURL = "https://api.weixin.qq.com/customservice/kfsession/getsessionlist?access_token=%s&kf_account=%s"
+	getKFWaitCaseURL    = "https://api.weixin.qq.com/customservice/kfsession/getwaitcase?access_token=%s"
+)
+
+// KFAccount is a customer service account of the official account.
+type KFAccount struct {
+	KFAccount    string `json:"kf_account"`    // 完整客服帐号，格式为：帐号前缀@公众号微信号
+	KFNick       string `json:"kf_nick"`       // 客服昵称
+	KFID         string `json:"kf_id"`         // 客服编号
+	KFHeadImgURL string `json:"kf_headimgurl"` // 客服头像
+	KFWx         string `json:"kf_wx"`         // 绑定的客服微信号，未绑定时为空
+	InviteWx     string `json:"invite_wx"`     // 已邀请但未接受绑定的微信号
+}
+
+// KFListResponse represents the response of listing the customer service accounts.
+type KFListResponse struct {
+	ErrCode int          `json:"errcode"`
+	ErrMsg  string       `json:"errmsg"`
+	KFList  []*KFAccount `json:"kf_list"` // 客服列表
+}
+
+// OnlineKF is a customer service account online.
+type OnlineKF struct {
+	KFAccount    string `json:"kf_account"`    // 完整客服帐号
+	Status       int    `json:"status"`        // 客服在线状态，目前为：1 web 在线
+	KFID         string `json:"kf_id"`         // 客服编号
+	AcceptedCase int    `json:"accepted_case"` // 客服当前正在接待的会话数
+}
+
+// OnlineKFListResponse represents the response of listing the customer service accounts online.
+type OnlineKFListResponse struct {
+	ErrCode      int         `json:"errcode"`
+	ErrMsg       string      `json:"errmsg"`
+	KFOnlineList []*OnlineKF `json:"kf_online_list"` // 在线客服列表
+}
+
+// KFSessionRequest represents the request of creating or closing a customer service session.
+type KFSessionRequest struct {
+	KFAccount string `json:"kf_account"` // 完整客服帐号
+	OpenID    string `json:"openid"`     // 粉丝的openid
+}
+
+// KFSession represents the customer service session of a user.
+type KFSession struct {
+	ErrCode    int    `json:"errcode"`
+	ErrMsg     string `json:"errmsg"`
+	KFAccount  string `json:"kf_account"` // 正在接待的客服，为空表示没有人在接待
+	CreateTime int64  `json:"createtime"` // 会话接入的时间
+}
+
+// KFSessionItem is a session received by a customer service account.
+type KFSessionItem struct {
+	OpenID     string `json:"openid"`     // 粉丝的openid
+	CreateTime int64  `json:"createtime"` // 会话接入的时间
+}
+
+// KFSessionListResponse represents the response of listing the sessions of a customer service account.
+type KFSessionListResponse struct {
+	ErrCode     int              `json:"errcode"`
+	ErrMsg      string           `json:"errmsg"`
+	SessionList []*KFSessionItem `json:"sessionlist"` // 会话列表
+}
+
+// KFWaitCase is a user waiting for a customer service session.
+type KFWaitCase struct {
+	OpenID     string `json:"openid"`      // 粉丝的openid
+	LatestTime int64  `json:"latest_time"` // 粉丝的最后一条消息的时间
+}
+
+// KFWaitCaseResponse represents the response of listing the users waiting for sessions.
+type KFWaitCaseResponse struct {
+	ErrCode      int           `json:"errcode"`
+	ErrMsg       string        `json:"errmsg"`
+	Count        int           `json:"count"`        // 未接入会话数量
+	WaitCaseList []*KFWaitCase `json:"waitcaselist"` // 未接入会话列表，最多返回100条数据，按照来访顺序
+}
+
+// GetKFList lists the customer service accounts of the official account.
+func (s *Service) GetKFList() ([]*KFAccount, error) {
+	return s.GetKFListContext(context.Background())
+}
+
+// GetKFListContext is GetKFList with the given context.
+func (s *Service) GetKFListContext(ctx context.Context) ([]*KFAccount, error) {
+	call := s.call("get kf list", getKFListURL)
+	call.Idempotent = true
+
+	resp, err := wxapi.GetJSON[KFListResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.KFList, nil
+}
+
+// GetOnlineKFList lists the customer service accounts online with their accepted sessions.
+func (s *Service) GetOnlineKFList() ([]*OnlineKF, error) {
+	return s.GetOnlineKFListContext(context.Background())
+}
+
+// GetOnlineKFListContext is GetOnlineKFList with the given context.
+func (s *Service) GetOnlineKFListContext(ctx context.Context) ([]*OnlineKF, error) {
+	call := s.call("get online kf list", getOnlineKFListURL)
+	call.Idempotent = true
+
+	resp, err := wxapi.GetJSON[OnlineKFListResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.KFOnlineList, nil
+}
+
+// CreateKFSession assigns the user to the customer service account, the account must be online.
+func (s *Service) CreateKFSession(kfAccount, openID string) error {
+	return s.CreateKFSessionContext(context.Background(), kfAccount, openID)
+}
+
+// CreateKFSessionContext is CreateKFSession with the given context.
+func (s *Service) CreateKFSessionContext(ctx context.Context, kfAccount, openID string) error {
+	call := s.call("create kf session", createKFSessionURL)
+	call.Summary = fmt.Sprintf("kf_account: %s | openid: %s", kfAccount, openID)
+
+	_, err := wxapi.PostJSON[struct{}](ctx, call, &KFSessionRequest{KFAccount: kfAccount, OpenID: openID})
+
+	return err
+}
+
+// CloseKFSession closes the session of the user with the customer service account.
+func (s *Service) CloseKFSession(kfAccount, openID string) error {
+	return s.CloseKFSessionContext(context.Background(), kfAccount, openID)
+}
+
+// CloseKFSessionContext is CloseKFSession with the given context.
+func (s *Service) CloseKFSessionContext(ctx context.Context, kfAccount, openID string) error {
+	call := s.call("close kf session", closeKFSessionURL)
+	call.Summary = fmt.Sprintf("kf_account: %s | openid: %s", kfAccount, openID)
+
+	_, err := wxapi.PostJSON[struct{}](ctx, call, &KFSessionRequest{KFAccount: kfAccount, OpenID: openID})
+
+	return err
+}
+
+// GetKFSession returns the customer service session of the user, its KFAccount is empty if the
+// user is not received by any account.
+func (s *Service) GetKFSession(openID string) (*KFSession, error) {
+	return s.GetKFSessionContext(context.Background(), openID)
+}
+
+// GetKFSessionContext is GetKFSession with the given context.
+func (s *Service) GetKFSessionContext(ctx context.Context, openID string) (*KFSession, error) {
+	call := s.call("get kf session", getKFSessionURL, url.QueryEscape(openID))
+	call.Idempotent = true
+
+	return wxapi.GetJSON[KFSession](ctx, call)
+}
+
+// GetKFSessionList lists the sessions received by the customer service account.
+func (s *Service) GetKFSessionList(kfAccount string) ([]*KFSessionItem, error) {
+	return s.GetKFSessionListContext(context.Background(), kfAccount)
+}
+
+// GetKFSessionListContext is GetKFSessionList with the given context.
+func (s *Service) GetKFSessionListContext(ctx context.Context, kfAccount string) ([]*KFSessionItem, error) {
+	call := s.call("get kf session list", getKFSessionListURL, url.QueryEscape(kfAccount))
+	call.Idempotent = true
+
+	resp, err := wxapi.GetJSON[KFSessionListResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.SessionList, nil
+}
+
+// GetKFWaitCase lists the users waiting for a customer service session, at most 100 in order of arrival.
+func (s *Service) GetKFWaitCase() (*KFWaitCaseResponse, error) {
+	return s.GetKFWaitCaseContext(context.Background())
+}
+
+// GetKFWaitCaseContext is GetKFWaitCase with the given context.
+func (s *Service) GetKFWaitCaseContext(ctx context.Context) (*KFWaitCaseResponse, error) {
+	call := s.call("get kf wait case", getKFWaitCaseURL)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[KFWaitCaseResponse](ctx, call)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vogo/vwx/vwxpush"
+)
+
+// ErrNoOnlineKF is returned when no customer service account is online to receive the session.
+var ErrNoOnlineKF = errors.New("no customer service account online")
+
+// kfRoutedMsgTypes are the user message types routed to the customer service accounts by Register.
+var kfRoutedMsgTypes = []string{textMsgType, "image", "voice", "video", "shortvideo", "location", "link"}
+
+// KFRouteStrategy decides the customer service account receiving a new session.
+type KFRouteStrategy int
+
+const (
+	// KFRouteRoundRobin assigns the sessions to the accounts online in turn.
+	KFRouteRoundRobin KFRouteStrategy = iota
+	// KFRouteLeastActive assigns the session to the account online receiving the fewest sessions.
+	KFRouteLeastActive
+)
+
+// KFRouter assigns the users messaging the official account to the customer service accounts
+// online, creating the sessions by the session api. Users already received by an account are
+// left to it.
+type KFRouter struct {
+	manager  KFSessionManager
+	Strategy KFRouteStrategy // 分配策略，默认轮询
+
+	mu   sync.Mutex
+	next int
+}
+
+// NewKFRouter creates a customer service router on the session manager, e.g. a *Service.
+func NewKFRouter(manager KFSessionManager, options ...func(*KFRouter)) *KFRouter {
+	r := &KFRouter{manager: manager}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// WithKFRouteStrategy sets the strategy assigning the sessions, round-robin by default.
+func WithKFRouteStrategy(strategy KFRouteStrategy) func(*KFRouter) {
+	return func(r *KFRouter) {
+		r.Strategy = strategy
+	}
+}
+
+// Assign creates the session of the user with the customer service account chosen by the strategy
+// and returns the account, ErrNoOnlineKF is returned if no account is online.
+func (r *KFRouter) Assign(ctx context.Context, openID string) (string, error) {
+	online, err := r.manager.GetOnlineKFListContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get online kf list error: %w", err)
+	}
+
+	kf := r.choose(online)
+	if kf == nil {
+		return "", ErrNoOnlineKF
+	}
+
+	if err := r.manager.CreateKFSessionContext(ctx, kf.KFAccount, openID); err != nil {
+		return "", err
+	}
+
+	return kf.KFAccount, nil
+}
+
+// choose returns the account online receiving the next session, nil if none.
+func (r *KFRouter) choose(online []*OnlineKF) *OnlineKF {
+	if len(online) == 0 {
+		return nil
+	}
+
+	// a stable order keeps the turns whatever the order of the list
+	sorted := make([]*OnlineKF, len(online))
+	copy(sorted, online)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].KFAccount < sorted[j].KFAccount })
+
+	if r.Strategy == KFRouteLeastActive {
+		least := sorted[0]
+		for _, kf := range sorted[1:] {
+			if kf.AcceptedCase < least.AcceptedCase {
+				least = kf
+			}
+		}
+
+		return least
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kf := sorted[r.next%len(sorted)]
+	r.next++
+
+	return kf
+}
+
+// Register registers the router for the user messages of the dispatcher.
+func (r *KFRouter) Register(d *vwxpush.Dispatcher) {
+	for _, msgType := range kfRoutedMsgTypes {
+		d.HandleMsgType(msgType, r.HandleEvent)
+	}
+}
+
+// HandleEvent assigns the user of the pushed message to a customer service account unless already
+// received by one, it matches vwxpush.MessageHandler. Events are ignored, and the users stay
+// waiting when no account is online.
+func (r *KFRouter) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, _ []byte) ([]byte, error) {
+	if baseInfo.MsgType == "event" {
+		return nil, nil
+	}
+
+	ctx := baseInfo.Context(context.Background())
+
+	session, err := r.manager.GetKFSessionContext(ctx, baseInfo.FromUserName)
+	if err != nil {
+		return nil, fmt.Errorf("get kf session error: %w", err)
+	}
+
+	if session.KFAccount != "" {
+		return nil, nil
+	}
+
+	if _, err := r.Assign(ctx, baseInfo.FromUserName); err != nil && !errors.Is(err, ErrNoOnlineKF) {
+		return nil, err
+	}
+
+	return nil, nil
+}
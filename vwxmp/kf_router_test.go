@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestKFRouter(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	kfs, err := svc.GetKFList()
+	assert.NoError(t, err)
+	assert.Len(t, kfs, len(vwxmock.KFAccounts))
+
+	// round-robin over the accounts online
+	server.SetOnlineKFs(vwxmock.KFAccounts[:2]...)
+	router := vwxmp.NewKFRouter(svc)
+	d := vwxpush.NewDispatcher()
+	router.Register(d)
+
+	for _, openID := range []string{"user1", "user2", "user3"} {
+		_, err = d.Dispatch("appid", &vwxpush.PushBaseInfo{FromUserName: openID, MsgType: "text"}, nil)
+		assert.NoError(t, err)
+	}
+
+	sessions, err := svc.GetKFSessionList(vwxmock.KFAccounts[0])
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	session, err := svc.GetKFSession("user2")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.KFAccounts[1], session.KFAccount)
+
+	// users received already are left to their accounts
+	_, err = d.Dispatch("appid", &vwxpush.PushBaseInfo{FromUserName: "user2", MsgType: "image"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/customservice/kfsession/create"), 3)
+
+	// the least active account receives the next session
+	server.SetOnlineKFs(vwxmock.KFAccounts...)
+	least := vwxmp.NewKFRouter(svc, vwxmp.WithKFRouteStrategy(vwxmp.KFRouteLeastActive))
+
+	account, err := least.Assign(context.Background(), "user4")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.KFAccounts[2], account)
+
+	assert.NoError(t, svc.CloseKFSession(vwxmock.KFAccounts[1], "user2"))
+	account, err = least.Assign(context.Background(), "user5")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.KFAccounts[1], account)
+
+	// users stay waiting when no account is online
+	server.SetOnlineKFs()
+	_, err = least.Assign(context.Background(), "user6")
+	assert.ErrorIs(t, err, vwxmp.ErrNoOnlineKF)
+
+	_, err = least.HandleEvent("appid", &vwxpush.PushBaseInfo{FromUserName: "user6", MsgType: "text"}, nil)
+	assert.NoError(t, err)
+
+	session, err = svc.GetKFSession("user6")
+	assert.NoError(t, err)
+	assert.Empty(t, session.KFAccount)
+
+	waitCase, err := svc.GetKFWaitCase()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, waitCase.Count)
+}
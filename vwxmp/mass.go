@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	massSendAllURL = "https://api.weixin.qq.com/cgi-bin/message/mass/sendall?access_token=%s"
+	massSendURL    = "https://api.weixin.qq.com/cgi-bin/message/mass/send?access_token=%s"
+	massGetURL     = "https://api.weixin.qq.com/cgi-bin/message/mass/get?access_token=%s"
+
+	// MaxMassOpenIDs is the max number of openids of a mass send by openid list.
+	MaxMassOpenIDs = 10000
+
+	// ErrCodeMassClientMsgIDExists is returned when a mass send of the same clientmsgid exists,
+	// the msg_id of the existing mass send is returned along.
+	ErrCodeMassClientMsgIDExists = 45065
+)
+
+// MassMedia is the media of a mass message.
+type MassMedia struct {
+	MediaID string `json:"media_id"` // 素材id，图文消息为草稿的media_id
+}
+
+// MassText is the text of a mass message.
+type MassText struct {
+	Content string `json:"content"` // 文本内容
+}
+
+// MassCard is the card of a mass message.
+type MassCard struct {
+	CardID string `json:"card_id"` // 卡券id
+}
+
+// MassMessage is the content of a mass message.
+type MassMessage struct {
+	MsgType           string     `json:"msgtype"`               // 消息类型：mpnews、text、voice、image、mpvideo、wxcard
+	MPNews            *MassMedia `json:"mpnews,omitempty"`      // 图文消息
+	Text              *MassText  `json:"text,omitempty"`        // 文本消息
+	Voice             *MassMedia `json:"voice,omitempty"`       // 语音消息
+	Image             *MassMedia `json:"image,omitempty"`       // 图片消息
+	MPVideo           *MassMedia `json:"mpvideo,omitempty"`     // 视频消息
+	WxCard            *MassCard  `json:"wxcard,omitempty"`      // 卡券消息
+	SendIgnoreReprint int        `json:"send_ignore_reprint"`   // 图文被判定为转载时，1继续群发，0停止群发
+	ClientMsgID       string     `json:"clientmsgid,omitempty"` // 群发的唯一标识，24小时内重复的群发返回45065，避免重试重复群发
+}
+
+// MassFilter selects the users of a mass send by tag.
+type MassFilter struct {
+	IsToAll bool  `json:"is_to_all"`        // 是否群发给全部用户
+	TagID   int64 `json:"tag_id,omitempty"` // 群发的标签id，is_to_all为true时忽略
+}
+
+// MassSendResponse represents the response of a mass send.
+type MassSendResponse struct {
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+	MsgID     int64  `json:"msg_id"`      // 群发任务的id
+	MsgDataID int64  `json:"msg_data_id"` // 图文消息的数据id
+}
+
+// MassStatusResponse represents the status of a mass send.
+type MassStatusResponse struct {
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+	MsgID     int64  `json:"msg_id"`     // 群发任务的id
+	MsgStatus string `json:"msg_status"` // 群发状态：SEND_SUCCESS、SENDING、SEND_FAIL、DELETE
+}
+
+type massSendAllRequest struct {
+	Filter *MassFilter `json:"filter"`
+	*MassMessage
+}
+
+type massSendRequest struct {
+	ToUser []string `json:"touser"`
+	*MassMessage
+}
+
+// SendMassByTag sends the message to the users of the tag, or to all users if the tag id is 0.
+func (s *Service) SendMassByTag(tagID int64, msg *MassMessage) (*MassSendResponse, error) {
+	return s.SendMassByTagContext(context.Background(), tagID, msg)
+}
+
+// SendMassByTagContext is SendMassByTag with the given context.
+func (s *Service) SendMassByTagContext(ctx context.Context, tagID int64, msg *MassMessage) (*MassSendResponse, error) {
+	filter := &MassFilter{IsToAll: tagID == 0, TagID: tagID}
+
+	summary := fmt.Sprintf("msgtype: %s | is_to_all: %t | tag_id: %d", msg.MsgType, filter.IsToAll, tagID)
+
+	return s.postMassSend(ctx, massSendAllURL, &massSendAllRequest{Filter: filter, MassMessage: msg}, summary)
+}
+
+// SendMassByOpenIDs sends the message to the users of the openids, 2 to MaxMassOpenIDs openids.
+func (s *Service) SendMassByOpenIDs(openIDs []string, msg *MassMessage) (*MassSendResponse, error) {
+	return s.SendMassByOpenIDsContext(context.Background(), openIDs, msg)
+}
+
+// SendMassByOpenIDsContext is SendMassByOpenIDs with the given context.
+func (s *Service) SendMassByOpenIDsContext(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResponse, error) {
+	if len(openIDs) < 2 || len(openIDs) > MaxMassOpenIDs {
+		return nil, fmt.Errorf("mass send requires 2 to %d openids, got %d", MaxMassOpenIDs, len(openIDs))
+	}
+
+	summary := fmt.Sprintf("msgtype: %s | openids: %d", msg.MsgType, len(openIDs))
+
+	return s.postMassSend(ctx, massSendURL, &massSendRequest{ToUser: openIDs, MassMessage: msg}, summary)
+}
+
+// GetMassStatus returns the status of the mass send.
+func (s *Service) GetMassStatus(msgID int64) (*MassStatusResponse, error) {
+	return s.GetMassStatusContext(context.Background(), msgID)
+}
+
+// GetMassStatusContext is GetMassStatus with the given context.
+func (s *Service) GetMassStatusContext(ctx context.Context, msgID int64) (*MassStatusResponse, error) {
+	call := s.call("get mass status", massGetURL)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[MassStatusResponse](ctx, call, map[string]int64{"msg_id": msgID})
+}
+
+// postMassSend sends the mass request logged by the summary, the response of an existing
+// clientmsgid (45065) is returned along with the error.
+func (s *Service) postMassSend(ctx context.Context, apiURL string, req any, summary string) (*MassSendResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	if s.client.DryRun {
+		s.client.Logger.Infof("dry run, skip mass send | request_id: %s | %s", requestID, summary)
+		return &MassSendResponse{ErrCode: 0, ErrMsg: "ok"}, nil
+	}
+
+	call := s.call("mass send", apiURL)
+	call.Summary = summary
+
+	result, err := wxapi.PostJSON[MassSendResponse](ctx, call, req)
+	if err != nil {
+		return result, err
+	}
+
+	s.client.Logger.Infof("mass send | request_id: %s | msg_id: %d", requestID, result.MsgID)
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	uploadTempMediaURL = "https://api.weixin.qq.com/cgi-bin/media/upload?access_token=%s&type=%s"
+)
+
+// MediaType represents the type of media files.
+type MediaType string
+
+const (
+	MediaTypeImage MediaType = "image" // 图片，10M，支持PNG、JPEG、JPG、GIF格式
+	MediaTypeVoice MediaType = "voice" // 语音，2M，播放长度不超过60s，支持AMR、MP3格式
+	MediaTypeVideo MediaType = "video" // 视频，10MB，支持MP4格式
+	MediaTypeThumb MediaType = "thumb" // 缩略图，64KB，支持JPG格式
+)
+
+// UploadMediaResponse represents the response from uploading temporary media.
+type UploadMediaResponse struct {
+	Type      string `json:"type"`       // 媒体文件类型
+	MediaID   string `json:"media_id"`   // 媒体文件上传后获取的唯一标识，3天内有效
+	CreatedAt int64  `json:"created_at"` // 媒体文件上传时间戳
+	ErrCode   int    `json:"errcode"`
+	ErrMsg    string `json:"errmsg"`
+}
+
+// UploadTempMedia uploads a temporary media file which is valid for 3 days.
+// filename: file name with extension, WeChat detects the file format by it
+func (s *Service) UploadTempMedia(mediaType MediaType, filename string, r io.Reader) (*UploadMediaResponse, error) {
+	return s.UploadTempMediaContext(context.Background(), mediaType, filename, r)
+}
+
+// UploadTempMediaContext is UploadTempMedia with the given context.
+func (s *Service) UploadTempMediaContext(ctx context.Context, mediaType MediaType, filename string, r io.Reader) (*UploadMediaResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	accessToken, err := s.authSvc.GetAccessTokenContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get access token error: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("media", filename)
+	if err != nil {
+		return nil, fmt.Errorf("create form file error: %w", err)
+	}
+
+	size, err := io.Copy(part, r)
+	if err != nil {
+		return nil, fmt.Errorf("read media error: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer error: %w", err)
+	}
+
+	s.client.Logger.Infof("upload temp media | request_id: %s | type: %s | filename: %s | size: %d", requestID, mediaType, filename, size)
+
+	requestURL := fmt.Sprintf(uploadTempMediaURL, accessToken, mediaType)
+
+	resp, err := s.client.Post(ctx, requestURL, writer.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if err := vwx.CheckContentType(resp, vwx.JSONContentTypes...); err != nil {
+		return nil, err
+	}
+
+	var result UploadMediaResponse
+	if err := json.NewDecoder(s.client.LimitResponse(resp)).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if result.ErrCode != 0 {
+		return nil, vwx.NewAPIError(uploadTempMediaURL, result.ErrCode, result.ErrMsg, requestID)
+	}
+
+	s.client.Logger.Infof("upload temp media | request_id: %s | media_id: %s", requestID, result.MediaID)
+
+	return &result, nil
+}
@@ -0,0 +1,441 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	menuCreateURL = "https://api.weixin.qq.com/cgi-bin/menu/create?access_token=%s"
+	menuGetURL    = "https://api.weixin.qq.com/cgi-bin/menu/get?access_token=%s"
+	menuDeleteURL = "https://api.weixin.qq.com/cgi-bin/menu/delete?access_token=%s"
+
+	// errCodeMenuNotExist is returned by menu/get if no menu is created.
+	errCodeMenuNotExist = 46003
+
+	maxMenuButtons       = 3
+	maxMenuSubButtons    = 5
+	maxMenuNameBytes     = 16
+	maxMenuSubNameBytes  = 60
+	maxMenuKeyBytes      = 128
+	maxMenuURLBytes      = 1024
+	menuButtonPathFormat = "button[%d]"
+)
+
+// MenuButtonType is the type of a menu button.
+type MenuButtonType string
+
+const (
+	MenuButtonClick              MenuButtonType = "click"                // 点击推事件
+	MenuButtonView               MenuButtonType = "view"                 // 跳转URL
+	MenuButtonMiniProgram        MenuButtonType = "miniprogram"          // 跳转小程序
+	MenuButtonScanCodePush       MenuButtonType = "scancode_push"        // 扫码推事件
+	MenuButtonScanCodeWaitMsg    MenuButtonType = "scancode_waitmsg"     // 扫码推事件且弹出"消息接收中"提示框
+	MenuButtonPicSysPhoto        MenuButtonType = "pic_sysphoto"         // 弹出系统拍照发图
+	MenuButtonPicPhotoOrAlbum    MenuButtonType = "pic_photo_or_album"   // 弹出拍照或者相册发图
+	MenuButtonPicWeixin          MenuButtonType = "pic_weixin"           // 弹出微信相册发图器
+	MenuButtonLocationSelect     MenuButtonType = "location_select"      // 弹出地理位置选择器
+	MenuButtonMediaID            MenuButtonType = "media_id"             // 下发消息（除文本消息）
+	MenuButtonArticleID          MenuButtonType = "article_id"           // 跳转图文消息
+	MenuButtonArticleViewLimited MenuButtonType = "article_view_limited" // 跳转图文消息（限制）
+)
+
+// Menu is the declarative definition of the custom menu of an official account.
+type Menu struct {
+	Buttons []*MenuButton `json:"button"` // 一级菜单，1-3个
+}
+
+// MenuButton is a button of the menu, a top-level button with sub buttons has no type.
+type MenuButton struct {
+	Type       MenuButtonType `json:"type,omitempty"`       // 菜单的响应动作类型
+	Name       string         `json:"name"`                 // 菜单标题，一级菜单不超过16个字节，子菜单不超过60个字节
+	Key        string         `json:"key,omitempty"`        // 菜单KEY值，用于消息接口推送，click等点击类型必须
+	URL        string         `json:"url,omitempty"`        // 网页链接，view、miniprogram类型必须
+	MediaID    string         `json:"media_id,omitempty"`   // 永久素材media_id，media_id类型必须
+	AppID      string         `json:"appid,omitempty"`      // 小程序的appid，miniprogram类型必须
+	PagePath   string         `json:"pagepath,omitempty"`   // 小程序的页面路径，miniprogram类型必须
+	ArticleID  string         `json:"article_id,omitempty"` // 发布后获得的article_id，article_id类型必须
+	SubButtons []*MenuButton  `json:"sub_button,omitempty"` // 二级菜单，1-5个
+}
+
+// NewMenu creates a menu of the top-level buttons.
+func NewMenu(buttons ...*MenuButton) *Menu {
+	return &Menu{Buttons: buttons}
+}
+
+// SubMenu creates a top-level button opening the sub buttons.
+func SubMenu(name string, buttons ...*MenuButton) *MenuButton {
+	return &MenuButton{Name: name, SubButtons: buttons}
+}
+
+// ClickButton creates a button pushing a click event with the key.
+func ClickButton(name, key string) *MenuButton {
+	return &MenuButton{Type: MenuButtonClick, Name: name, Key: key}
+}
+
+// ViewButton creates a button opening the url.
+func ViewButton(name, url string) *MenuButton {
+	return &MenuButton{Type: MenuButtonView, Name: name, URL: url}
+}
+
+// MiniProgramButton creates a button opening the mini program page, the url is opened
+// by clients not supporting mini programs.
+func MiniProgramButton(name, url, appID, pagePath string) *MenuButton {
+	return &MenuButton{Type: MenuButtonMiniProgram, Name: name, URL: url, AppID: appID, PagePath: pagePath}
+}
+
+// ArticleButton creates a button opening the published article.
+func ArticleButton(name, articleID string) *MenuButton {
+	return &MenuButton{Type: MenuButtonArticleID, Name: name, ArticleID: articleID}
+}
+
+// Validate checks the menu against the limits of WeChat before creating it.
+func (m *Menu) Validate() error {
+	if len(m.Buttons) == 0 || len(m.Buttons) > maxMenuButtons {
+		return fmt.Errorf("menu must have 1-%d buttons, got %d", maxMenuButtons, len(m.Buttons))
+	}
+
+	for i, b := range m.Buttons {
+		path := fmt.Sprintf(menuButtonPathFormat, i)
+
+		if b == nil {
+			return fmt.Errorf("menu %s: button is nil", path)
+		}
+
+		if len(b.Name) == 0 || len(b.Name) > maxMenuNameBytes {
+			return fmt.Errorf("menu %s: name must be 1-%d bytes", path, maxMenuNameBytes)
+		}
+
+		if len(b.SubButtons) == 0 {
+			if err := b.validateAction(path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(b.SubButtons) > maxMenuSubButtons {
+			return fmt.Errorf("menu %s: must have at most %d sub buttons, got %d", path, maxMenuSubButtons, len(b.SubButtons))
+		}
+
+		for j, sub := range b.SubButtons {
+			subPath := fmt.Sprintf("%s.sub_button[%d]", path, j)
+
+			if sub == nil {
+				return fmt.Errorf("menu %s: button is nil", subPath)
+			}
+
+			if len(sub.Name) == 0 || len(sub.Name) > maxMenuSubNameBytes {
+				return fmt.Errorf("menu %s: name must be 1-%d bytes", subPath, maxMenuSubNameBytes)
+			}
+
+			if len(sub.SubButtons) > 0 {
+				return fmt.Errorf("menu %s: sub button can not have sub buttons", subPath)
+			}
+
+			if err := sub.validateAction(subPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *MenuButton) validateAction(path string) error {
+	switch b.Type {
+	case MenuButtonClick, MenuButtonScanCodePush, MenuButtonScanCodeWaitMsg, MenuButtonPicSysPhoto,
+		MenuButtonPicPhotoOrAlbum, MenuButtonPicWeixin, MenuButtonLocationSelect:
+		if b.Key == "" || len(b.Key) > maxMenuKeyBytes {
+			return fmt.Errorf("menu %s: key must be 1-%d bytes for %s button", path, maxMenuKeyBytes, b.Type)
+		}
+	case MenuButtonView:
+		if b.URL == "" || len(b.URL) > maxMenuURLBytes {
+			return fmt.Errorf("menu %s: url must be 1-%d bytes for %s button", path, maxMenuURLBytes, b.Type)
+		}
+	case MenuButtonMiniProgram:
+		if b.URL == "" || b.AppID == "" || b.PagePath == "" {
+			return fmt.Errorf("menu %s: url, appid and pagepath are required for %s button", path, b.Type)
+		}
+	case MenuButtonMediaID:
+		if b.MediaID == "" {
+			return fmt.Errorf("menu %s: media_id is required for %s button", path, b.Type)
+		}
+	case MenuButtonArticleID, MenuButtonArticleViewLimited:
+		if b.ArticleID == "" {
+			return fmt.Errorf("menu %s: article_id is required for %s button", path, b.Type)
+		}
+	case "":
+		return fmt.Errorf("menu %s: type is required for button without sub buttons", path)
+	default:
+		return fmt.Errorf("menu %s: unknown button type %s", path, b.Type)
+	}
+
+	return nil
+}
+
+// MenuChangeKind is the kind of a menu change.
+type MenuChangeKind string
+
+const (
+	MenuChangeAdded    MenuChangeKind = "added"    // 新增按钮
+	MenuChangeRemoved  MenuChangeKind = "removed"  // 删除按钮
+	MenuChangeModified MenuChangeKind = "modified" // 修改按钮
+)
+
+// MenuChange is a difference of a button between the current and the desired menu.
+type MenuChange struct {
+	Path    string         // 按钮位置，如 button[1].sub_button[0]
+	Kind    MenuChangeKind // 变更类型
+	Current *MenuButton    // 当前按钮，新增时为nil
+	Desired *MenuButton    // 期望按钮，删除时为nil
+}
+
+// String describes the change in one line.
+func (c *MenuChange) String() string {
+	switch c.Kind {
+	case MenuChangeAdded:
+		return fmt.Sprintf("+ %s %s", c.Path, c.Desired.describe())
+	case MenuChangeRemoved:
+		return fmt.Sprintf("- %s %s", c.Path, c.Current.describe())
+	default:
+		return fmt.Sprintf("~ %s %s -> %s", c.Path, c.Current.describe(), c.Desired.describe())
+	}
+}
+
+// MenuDiff is the differences between the current and the desired menu.
+type MenuDiff struct {
+	Changes []*MenuChange
+}
+
+// Changed reports whether the desired menu differs from the current one.
+func (d *MenuDiff) Changed() bool {
+	return len(d.Changes) > 0
+}
+
+// String describes the changes one per line, e.g. for plan output of a deployment.
+func (d *MenuDiff) String() string {
+	lines := make([]string, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		lines = append(lines, c.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// DiffMenus computes the differences from the current to the desired menu by button position,
+// a nil menu is treated as no menu.
+func DiffMenus(current, desired *Menu) *MenuDiff {
+	diff := &MenuDiff{}
+	diffMenuButtons(diff, "", menuButtons(current), menuButtons(desired))
+
+	return diff
+}
+
+func menuButtons(m *Menu) []*MenuButton {
+	if m == nil {
+		return nil
+	}
+
+	return m.Buttons
+}
+
+func diffMenuButtons(diff *MenuDiff, parent string, current, desired []*MenuButton) {
+	for i := 0; i < max(len(current), len(desired)); i++ {
+		path := fmt.Sprintf(menuButtonPathFormat, i)
+		if parent != "" {
+			path = fmt.Sprintf("%s.sub_button[%d]", parent, i)
+		}
+
+		switch {
+		case i >= len(current):
+			diff.Changes = append(diff.Changes, &MenuChange{Path: path, Kind: MenuChangeAdded, Desired: desired[i]})
+		case i >= len(desired):
+			diff.Changes = append(diff.Changes, &MenuChange{Path: path, Kind: MenuChangeRemoved, Current: current[i]})
+		default:
+			if !current[i].sameAction(desired[i]) {
+				diff.Changes = append(diff.Changes, &MenuChange{Path: path, Kind: MenuChangeModified, Current: current[i], Desired: desired[i]})
+			}
+
+			diffMenuButtons(diff, path, current[i].SubButtons, desired[i].SubButtons)
+		}
+	}
+}
+
+// sameAction compares the buttons ignoring the sub buttons.
+func (b *MenuButton) sameAction(o *MenuButton) bool {
+	return b.Type == o.Type && b.Name == o.Name && b.Key == o.Key && b.URL == o.URL &&
+		b.MediaID == o.MediaID && b.AppID == o.AppID && b.PagePath == o.PagePath && b.ArticleID == o.ArticleID
+}
+
+func (b *MenuButton) describe() string {
+	if len(b.SubButtons) > 0 || b.Type == "" {
+		return fmt.Sprintf("%q (sub menu)", b.Name)
+	}
+
+	switch b.Type {
+	case MenuButtonView:
+		return fmt.Sprintf("%q (view %s)", b.Name, b.URL)
+	case MenuButtonMiniProgram:
+		return fmt.Sprintf("%q (miniprogram %s %s)", b.Name, b.AppID, b.PagePath)
+	case MenuButtonMediaID:
+		return fmt.Sprintf("%q (media_id %s)", b.Name, b.MediaID)
+	case MenuButtonArticleID, MenuButtonArticleViewLimited:
+		return fmt.Sprintf("%q (%s %s)", b.Name, b.Type, b.ArticleID)
+	default:
+		return fmt.Sprintf("%q (%s %s)", b.Name, b.Type, b.Key)
+	}
+}
+
+// MenuResponse represents the response of the menu APIs.
+type MenuResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Menu    *Menu  `json:"menu"` // 默认菜单，仅查询接口返回
+}
+
+// GetMenu returns the current default menu, an empty menu is returned if no menu is created.
+func (s *Service) GetMenu() (*Menu, error) {
+	return s.GetMenuContext(context.Background())
+}
+
+// GetMenuContext is GetMenu with the given context.
+func (s *Service) GetMenuContext(ctx context.Context) (*Menu, error) {
+	result, err := s.callMenuAPI(ctx, "get menu", menuGetURL, nil)
+	if result != nil && result.ErrCode == errCodeMenuNotExist {
+		return &Menu{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Menu == nil {
+		return &Menu{}, nil
+	}
+
+	return result.Menu, nil
+}
+
+// CreateMenu validates and creates the menu, replacing the current one.
+func (s *Service) CreateMenu(menu *Menu) error {
+	return s.CreateMenuContext(context.Background(), menu)
+}
+
+// CreateMenuContext is CreateMenu with the given context.
+func (s *Service) CreateMenuContext(ctx context.Context, menu *Menu) error {
+	if err := menu.Validate(); err != nil {
+		return err
+	}
+
+	if s.client.DryRun {
+		data, err := json.Marshal(menu)
+		if err != nil {
+			return fmt.Errorf("marshal request error: %w", err)
+		}
+
+		s.client.Logger.Infof("dry run, skip create menu | req: %s", data)
+		return nil
+	}
+
+	_, err := s.callMenuAPI(ctx, "create menu", menuCreateURL, menu)
+
+	return err
+}
+
+// DeleteMenu deletes the current menu, including the conditional menus.
+func (s *Service) DeleteMenu() error {
+	return s.DeleteMenuContext(context.Background())
+}
+
+// DeleteMenuContext is DeleteMenu with the given context.
+func (s *Service) DeleteMenuContext(ctx context.Context) error {
+	if s.client.DryRun {
+		s.client.Logger.Infof("dry run, skip delete menu")
+		return nil
+	}
+
+	_, err := s.callMenuAPI(ctx, "delete menu", menuDeleteURL, nil)
+
+	return err
+}
+
+// DiffMenu fetches the current menu and computes the differences to the desired menu.
+func (s *Service) DiffMenu(desired *Menu) (*MenuDiff, error) {
+	return s.DiffMenuContext(context.Background(), desired)
+}
+
+// DiffMenuContext is DiffMenu with the given context.
+func (s *Service) DiffMenuContext(ctx context.Context, desired *Menu) (*MenuDiff, error) {
+	current, err := s.GetMenuContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get current menu error: %w", err)
+	}
+
+	return DiffMenus(current, desired), nil
+}
+
+// ApplyMenu makes the menu of the official account the desired one, the menu is only created
+// if it differs from the current one (and deleted if the desired menu has no buttons).
+// The applied differences are returned.
+func (s *Service) ApplyMenu(desired *Menu) (*MenuDiff, error) {
+	return s.ApplyMenuContext(context.Background(), desired)
+}
+
+// ApplyMenuContext is ApplyMenu with the given context.
+func (s *Service) ApplyMenuContext(ctx context.Context, desired *Menu) (*MenuDiff, error) {
+	if len(menuButtons(desired)) > 0 {
+		if err := desired.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := s.DiffMenuContext(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if !diff.Changed() {
+		s.client.Logger.Infof("menu unchanged, skip apply")
+		return diff, nil
+	}
+
+	s.client.Logger.Infof("apply menu changes:\n%s", diff)
+
+	if len(menuButtons(desired)) == 0 {
+		return diff, s.DeleteMenuContext(ctx)
+	}
+
+	return diff, s.CreateMenuContext(ctx, desired)
+}
+
+// callMenuAPI calls the menu API with GET if req is nil, or POST with the json encoded req otherwise.
+func (s *Service) callMenuAPI(ctx context.Context, name, urlFormat string, req any) (*MenuResponse, error) {
+	call := s.call(name, urlFormat)
+	call.Idempotent = true
+
+	if req == nil {
+		return wxapi.GetJSON[MenuResponse](ctx, call)
+	}
+
+	return wxapi.PostJSON[MenuResponse](ctx, call, req)
+}
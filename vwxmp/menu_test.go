@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestApplyMenu(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxmp.NewService(client)
+
+	current, err := svc.GetMenu()
+	assert.NoError(t, err)
+	assert.Empty(t, current.Buttons)
+
+	desired := vwxmp.NewMenu(
+		vwxmp.ClickButton("签到", "checkin"),
+		vwxmp.SubMenu("更多",
+			vwxmp.ViewButton("官网", "https://example.com"),
+			vwxmp.MiniProgramButton("小程序", "https://example.com", "wx123", "pages/index"),
+		),
+	)
+
+	diff, err := svc.ApplyMenu(desired)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 2)
+	assert.Len(t, server.Requests("/cgi-bin/menu/create"), 1)
+
+	// applying the same menu again does not write
+	diff, err = svc.ApplyMenu(desired)
+	assert.NoError(t, err)
+	assert.False(t, diff.Changed())
+	assert.Len(t, server.Requests("/cgi-bin/menu/create"), 1)
+
+	desired.Buttons[1].SubButtons[0].URL = "https://example.com/home"
+	diff, err = svc.DiffMenu(desired)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 1)
+	assert.Equal(t, "button[1].sub_button[0]", diff.Changes[0].Path)
+	assert.Equal(t, vwxmp.MenuChangeModified, diff.Changes[0].Kind)
+
+	_, err = svc.ApplyMenu(vwxmp.NewMenu(vwxmp.ClickButton("", "checkin")))
+	assert.Error(t, err)
+
+	diff, err = svc.ApplyMenu(vwxmp.NewMenu())
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 2)
+	assert.Len(t, server.Requests("/cgi-bin/menu/delete"), 1)
+}
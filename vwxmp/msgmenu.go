@@ -0,0 +1,264 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	textMsgType    = "text"
+	msgMenuMsgType = "msgmenu"
+
+	// DefaultMsgMenuSessionTTL is the default time a menu message waits for the selection,
+	// the same as the customer service message window.
+	DefaultMsgMenuSessionTTL = 48 * time.Hour
+)
+
+// MsgMenuSession is a menu message sent to a user and waiting for the selection.
+type MsgMenuSession struct {
+	Question string                `json:"question"` // 问题标识，由应用定义，用于串联多轮问答
+	Menu     *CustomMessageMsgMenu `json:"menu"`     // 发送的菜单
+	SentAt   time.Time             `json:"sent_at"`  // 发送时间
+}
+
+// Item returns the menu item of the id, nil if the menu has no such item.
+func (s *MsgMenuSession) Item(menuID string) *CustomMessageMsgMenuItem {
+	for _, item := range s.Menu.List {
+		if item.ID == menuID {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// MsgMenuSessionStore stores the menu message waiting for the selection of users, one per user.
+type MsgMenuSessionStore interface {
+	// GetMsgMenuSession returns the session of the user, nil if not found.
+	GetMsgMenuSession(ctx context.Context, openID string) (*MsgMenuSession, error)
+	SetMsgMenuSession(ctx context.Context, openID string, session *MsgMenuSession) error
+	DeleteMsgMenuSession(ctx context.Context, openID string) error
+}
+
+// MemoryMsgMenuSessionStore is an in-memory MsgMenuSessionStore, suitable for a single instance.
+type MemoryMsgMenuSessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*MsgMenuSession
+}
+
+// NewMemoryMsgMenuSessionStore creates an in-memory menu message session store.
+func NewMemoryMsgMenuSessionStore() *MemoryMsgMenuSessionStore {
+	return &MemoryMsgMenuSessionStore{sessions: make(map[string]*MsgMenuSession)}
+}
+
+// GetMsgMenuSession returns the session of the user, nil if not found.
+func (m *MemoryMsgMenuSessionStore) GetMsgMenuSession(_ context.Context, openID string) (*MsgMenuSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.sessions[openID], nil
+}
+
+// SetMsgMenuSession sets the session of the user, replacing the previous one.
+func (m *MemoryMsgMenuSessionStore) SetMsgMenuSession(_ context.Context, openID string, session *MsgMenuSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[openID] = session
+
+	return nil
+}
+
+// DeleteMsgMenuSession deletes the session of the user.
+func (m *MemoryMsgMenuSessionStore) DeleteMsgMenuSession(_ context.Context, openID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, openID)
+
+	return nil
+}
+
+// MsgMenuSelection is a user selecting an item of a menu message.
+type MsgMenuSelection struct {
+	OpenID     string          // 用户openid
+	MenuID     string          // 选择的菜单项id
+	Content    string          // 选择的菜单项内容
+	MsgID      int64           // 消息id
+	SelectedAt time.Time       // 选择时间
+	Session    *MsgMenuSession // 对应的菜单会话，菜单已过期或已被新菜单替换时为nil
+}
+
+// MsgMenuHandler is called when a user selects an item of a menu message.
+type MsgMenuHandler func(selection *MsgMenuSelection) error
+
+// MsgMenuFlow sends menu messages as questions and correlates the selections pushed back
+// by the menu id, so that interactive question flows can be built. A user has at most one
+// question waiting for the selection, asking a new one replaces it.
+type MsgMenuFlow struct {
+	sender  CustomMessageSender
+	store   MsgMenuSessionStore
+	handler MsgMenuHandler
+	TTL     time.Duration // 菜单会话有效期，默认为48小时
+}
+
+// NewMsgMenuFlow creates a menu message flow reporting selections to the handler.
+func NewMsgMenuFlow(sender CustomMessageSender, store MsgMenuSessionStore, handler MsgMenuHandler, options ...func(*MsgMenuFlow)) *MsgMenuFlow {
+	f := &MsgMenuFlow{
+		sender:  sender,
+		store:   store,
+		handler: handler,
+		TTL:     DefaultMsgMenuSessionTTL,
+	}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+// WithMsgMenuSessionTTL sets the time a menu message waits for the selection.
+func WithMsgMenuSessionTTL(ttl time.Duration) func(*MsgMenuFlow) {
+	return func(f *MsgMenuFlow) {
+		f.TTL = ttl
+	}
+}
+
+// Ask sends the menu message to the user and waits for the selection of the question.
+func (f *MsgMenuFlow) Ask(openID, question string, menu *CustomMessageMsgMenu) error {
+	if err := validateMsgMenu(menu); err != nil {
+		return err
+	}
+
+	_, err := f.sender.SendCustomMessage(&CustomMessageRequest{
+		ToUser:  openID,
+		MsgType: msgMenuMsgType,
+		MsgMenu: menu,
+	})
+	if err != nil {
+		return err
+	}
+
+	session := &MsgMenuSession{
+		Question: question,
+		Menu:     menu,
+		SentAt:   time.Now(),
+	}
+
+	if err := f.store.SetMsgMenuSession(context.Background(), openID, session); err != nil {
+		return fmt.Errorf("save msgmenu session error: %w", err)
+	}
+
+	return nil
+}
+
+// Register registers the flow for the text messages of the dispatcher.
+func (f *MsgMenuFlow) Register(d *vwxpush.Dispatcher) {
+	d.HandleMsgType(textMsgType, f.HandleEvent)
+}
+
+// HandleEvent reports the selection of a menu message push, it matches vwxpush.MessageHandler.
+// Text messages not from menu messages are ignored.
+func (f *MsgMenuFlow) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	selection, err := ParseMsgMenuSelection(baseInfo, data)
+	if err != nil || selection == nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	session, err := f.store.GetMsgMenuSession(ctx, selection.OpenID)
+	if err != nil {
+		return nil, fmt.Errorf("get msgmenu session error: %w", err)
+	}
+
+	if session != nil && f.TTL > 0 && time.Since(session.SentAt) > f.TTL {
+		if err := f.store.DeleteMsgMenuSession(ctx, selection.OpenID); err != nil {
+			return nil, fmt.Errorf("delete msgmenu session error: %w", err)
+		}
+		session = nil
+	}
+
+	// a selection from a replaced menu leaves the waiting session untouched
+	if session != nil && session.Item(selection.MenuID) != nil {
+		selection.Session = session
+		if err := f.store.DeleteMsgMenuSession(ctx, selection.OpenID); err != nil {
+			return nil, fmt.Errorf("delete msgmenu session error: %w", err)
+		}
+	}
+
+	return nil, f.handler(selection)
+}
+
+// ParseMsgMenuSelection parses the selection from a text message push,
+// nil is returned if the message is not from a menu message.
+func ParseMsgMenuSelection(baseInfo *vwxpush.PushBaseInfo, data []byte) (*MsgMenuSelection, error) {
+	if !strings.EqualFold(baseInfo.MsgType, textMsgType) {
+		return nil, nil
+	}
+
+	var msg struct {
+		Content      string `xml:"Content" json:"Content"`
+		MsgID        int64  `xml:"MsgId" json:"MsgId"`
+		BizMsgMenuID string `xml:"bizmsgmenuid" json:"bizmsgmenuid"`
+	}
+
+	if err := unmarshalPushData(data, &msg); err != nil {
+		return nil, fmt.Errorf("unmarshal msgmenu message error: %w", err)
+	}
+
+	if msg.BizMsgMenuID == "" {
+		return nil, nil
+	}
+
+	return &MsgMenuSelection{
+		OpenID:     baseInfo.FromUserName,
+		MenuID:     msg.BizMsgMenuID,
+		Content:    msg.Content,
+		MsgID:      msg.MsgID,
+		SelectedAt: time.Unix(baseInfo.CreateTime, 0),
+	}, nil
+}
+
+func validateMsgMenu(menu *CustomMessageMsgMenu) error {
+	if menu == nil || len(menu.List) == 0 {
+		return fmt.Errorf("msgmenu must have at least one item")
+	}
+
+	ids := make(map[string]bool, len(menu.List))
+	for i, item := range menu.List {
+		if item.ID == "" || item.Content == "" {
+			return fmt.Errorf("msgmenu list[%d] requires id and content", i)
+		}
+
+		if ids[item.ID] {
+			return fmt.Errorf("duplicate msgmenu id: %s", item.ID)
+		}
+		ids[item.ID] = true
+	}
+
+	return nil
+}
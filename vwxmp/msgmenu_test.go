@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestMsgMenuFlow(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	var selections []*vwxmp.MsgMenuSelection
+	flow := vwxmp.NewMsgMenuFlow(svc, vwxmp.NewMemoryMsgMenuSessionStore(), func(selection *vwxmp.MsgMenuSelection) error {
+		selections = append(selections, selection)
+		return nil
+	})
+
+	menu := &vwxmp.CustomMessageMsgMenu{
+		HeadContent: "您对本次服务是否满意呢?",
+		List: []*vwxmp.CustomMessageMsgMenuItem{
+			{ID: "101", Content: "满意"},
+			{ID: "102", Content: "不满意"},
+		},
+		TailContent: "欢迎再次光临",
+	}
+
+	assert.NoError(t, flow.Ask("openid", "satisfaction", menu))
+	assert.Error(t, flow.Ask("openid", "empty", &vwxmp.CustomMessageMsgMenu{}))
+
+	requests := server.Requests("/cgi-bin/message/custom/send")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), `"msgtype":"msgmenu"`)
+	assert.Contains(t, string(requests[0].Body), `{"id":"101","content":"满意"}`)
+
+	d := vwxpush.NewDispatcher()
+	flow.Register(d)
+
+	push := func(content, menuID string) {
+		baseInfo := &vwxpush.PushBaseInfo{FromUserName: "openid", MsgType: "text", CreateTime: 1700000000}
+		_, err := d.Dispatch("", baseInfo, []byte(fmt.Sprintf(`<xml><FromUserName><![CDATA[openid]]></FromUserName>
+<MsgType><![CDATA[text]]></MsgType><Content><![CDATA[%s]]></Content><MsgId>1234567890</MsgId>
+<bizmsgmenuid>%s</bizmsgmenuid></xml>`, content, menuID)))
+		assert.NoError(t, err)
+	}
+
+	push("hello", "")
+	push("满意", "101")
+	push("不满意", "102")
+
+	assert.Len(t, selections, 2)
+	assert.Equal(t, "101", selections[0].MenuID)
+	assert.Equal(t, "满意", selections[0].Content)
+	assert.Equal(t, int64(1234567890), selections[0].MsgID)
+	assert.Equal(t, "satisfaction", selections[0].Session.Question)
+
+	// the session is consumed by the first selection
+	assert.Nil(t, selections[1].Session)
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	msgRecordListURL = "https://api.weixin.qq.com/customservice/msgrecord/getmsglist?access_token=%s"
+
+	// MaxMsgRecordPageSize is the max number of message records of a page.
+	MaxMsgRecordPageSize = 10000
+
+	// MaxMsgRecordSpan is the max time span of a message record query.
+	MaxMsgRecordSpan = 24 * time.Hour
+)
+
+// Operation codes of the message records.
+const (
+	MsgRecordOperCreateSession  = 1000 // 创建未接入会话
+	MsgRecordOperAcceptSession  = 1001 // 接入会话
+	MsgRecordOperInviteSession  = 1002 // 主动发起会话
+	MsgRecordOperTransfer       = 1003 // 转接会话
+	MsgRecordOperCloseSession   = 1004 // 关闭会话
+	MsgRecordOperGrabSession    = 1005 // 抢接会话
+	MsgRecordOperReceiveMessage = 2001 // 公众号收到消息
+	MsgRecordOperSendMessage    = 2002 // 客服发送消息
+	MsgRecordOperWorkerReceive  = 2003 // 客服收到消息
+)
+
+// MsgRecordListRequest represents the request of listing a page of the customer service message records.
+type MsgRecordListRequest struct {
+	StartTime int64 `json:"starttime"` // 起始时间，unix时间戳
+	EndTime   int64 `json:"endtime"`   // 结束时间，unix时间戳，每次查询时段不能超过24小时
+	MsgID     int64 `json:"msgid"`     // 消息id顺序从小到大，从1开始
+	Number    int   `json:"number"`    // 每次获取条数，最多10000条
+}
+
+// MsgRecord is a customer service message record, the interaction of a fan and the workers.
+type MsgRecord struct {
+	OpenID   string `json:"openid"`   // 用户标识
+	OperCode int    `json:"opercode"` // 操作码
+	Text     string `json:"text"`     // 聊天记录
+	Time     int64  `json:"time"`     // 操作时间，unix时间戳
+	Worker   string `json:"worker"`   // 完整客服帐号，格式为：帐号前缀@公众号微信号
+}
+
+// MsgRecordListResponse represents a page of the customer service message records.
+type MsgRecordListResponse struct {
+	ErrCode    int          `json:"errcode"`
+	ErrMsg     string       `json:"errmsg"`
+	RecordList []*MsgRecord `json:"recordlist"` // 聊天记录
+	Number     int          `json:"number"`     // 本次返回的条数
+	MsgID      int64        `json:"msgid"`      // 下一次请求的msgid
+}
+
+// ListMsgRecords returns a page of the customer service message records in the time span.
+func (s *Service) ListMsgRecords(req *MsgRecordListRequest) (*MsgRecordListResponse, error) {
+	return s.ListMsgRecordsContext(context.Background(), req)
+}
+
+// ListMsgRecordsContext is ListMsgRecords with the given context.
+func (s *Service) ListMsgRecordsContext(ctx context.Context, req *MsgRecordListRequest) (*MsgRecordListResponse, error) {
+	if req.Number <= 0 || req.Number > MaxMsgRecordPageSize {
+		return nil, fmt.Errorf("message record number must be in 1-%d, got %d", MaxMsgRecordPageSize, req.Number)
+	}
+
+	if span := time.Duration(req.EndTime-req.StartTime) * time.Second; span <= 0 || span > MaxMsgRecordSpan {
+		return nil, fmt.Errorf("message record time span must be in (0, %s], got %s", MaxMsgRecordSpan, span)
+	}
+
+	call := s.call("list message records", msgRecordListURL)
+	call.Idempotent = true
+	call.Summary = fmt.Sprintf("starttime: %d | endtime: %d | msgid: %d", req.StartTime, req.EndTime, req.MsgID)
+
+	return wxapi.PostJSON[MsgRecordListResponse](ctx, call, req)
+}
+
+// MsgRecords iterates the customer service message records from start to end, the time range is split
+// into spans of MaxMsgRecordSpan and each span is paged by msgid. The iteration stops at the first error yielded.
+func (s *Service) MsgRecords(start, end time.Time) iter.Seq2[*MsgRecord, error] {
+	return s.MsgRecordsContext(context.Background(), start, end)
+}
+
+// MsgRecordsContext is MsgRecords with the given context.
+func (s *Service) MsgRecordsContext(ctx context.Context, start, end time.Time) iter.Seq2[*MsgRecord, error] {
+	span := int64(MaxMsgRecordSpan / time.Second)
+
+	return func(yield func(*MsgRecord, error) bool) {
+		// the spans end a second before the next one starts, so that no record is yielded twice
+		for spanStart := start.Unix(); spanStart < end.Unix(); spanStart += span {
+			spanEnd := min(spanStart+span-1, end.Unix())
+			if !s.yieldMsgRecords(ctx, spanStart, spanEnd, yield) {
+				return
+			}
+		}
+	}
+}
+
+// yieldMsgRecords yields the records of a time span page by page, it returns false if the iteration stopped.
+func (s *Service) yieldMsgRecords(ctx context.Context, startTime, endTime int64, yield func(*MsgRecord, error) bool) bool {
+	for msgID := int64(1); ; {
+		resp, err := s.ListMsgRecordsContext(ctx, &MsgRecordListRequest{
+			StartTime: startTime,
+			EndTime:   endTime,
+			MsgID:     msgID,
+			Number:    MaxMsgRecordPageSize,
+		})
+		if err != nil {
+			yield(nil, err)
+			return false
+		}
+
+		for _, record := range resp.RecordList {
+			if !yield(record, nil) {
+				return false
+			}
+		}
+
+		if len(resp.RecordList) < MaxMsgRecordPageSize || resp.MsgID <= msgID {
+			return true
+		}
+
+		msgID = resp.MsgID
+	}
+}
@@ -18,12 +18,12 @@
 package vwxmp
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"net/url"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
@@ -82,90 +82,75 @@ func (s *Service) BuildAuthorizeURL(redirectURI string, scope OAuthScope, state
 // GetOAuthAccessToken exchanges authorization code for access token.
 // code: authorization code obtained from redirect callback
 func (s *Service) GetOAuthAccessToken(code string) (*OAuthAccessTokenResponse, error) {
-	vlog.Infof("get oauth access token | appid: %s | code: %s", s.client.AppID, code)
-
-	requestURL := fmt.Sprintf(oauthAccessTokenURL, s.client.AppID, s.client.AppSecret, code)
+	return s.GetOAuthAccessTokenContext(context.Background(), code)
+}
 
-	resp, err := http.Get(requestURL)
-	if err != nil {
-		return nil, err
+// GetOAuthAccessTokenContext is GetOAuthAccessToken with the given context.
+func (s *Service) GetOAuthAccessTokenContext(ctx context.Context, code string) (*OAuthAccessTokenResponse, error) {
+	if s.client.SecretFree() {
+		return nil, vwx.ErrAppSecretRequired
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
-
-	var result OAuthAccessTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+
+	call := &wxapi.Call{
+		Client:  s.client,
+		Name:    "get oauth access token",
+		URL:     oauthAccessTokenURL,
+		Args:    []any{s.client.AppID, s.client.AppSecret, code},
+		Summary: fmt.Sprintf("appid: %s | code: %s", s.client.AppID, code),
 	}
 
-	if result.ErrCode != 0 {
-		return nil, fmt.Errorf("wechat error: %d %s", result.ErrCode, result.ErrMsg)
+	result, err := wxapi.GetJSON[OAuthAccessTokenResponse](ctx, call)
+	if err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // RefreshOAuthAccessToken refreshes the access token using refresh token.
 // refreshToken: refresh token obtained from GetOAuthAccessToken
 func (s *Service) RefreshOAuthAccessToken(refreshToken string) (*OAuthAccessTokenResponse, error) {
-	vlog.Infof("refresh oauth access token | appid: %s", s.client.AppID)
+	return s.RefreshOAuthAccessTokenContext(context.Background(), refreshToken)
+}
 
-	requestURL := fmt.Sprintf(oauthRefreshTokenURL, s.client.AppID, refreshToken)
+// RefreshOAuthAccessTokenContext is RefreshOAuthAccessToken with the given context.
+func (s *Service) RefreshOAuthAccessTokenContext(ctx context.Context, refreshToken string) (*OAuthAccessTokenResponse, error) {
+	call := &wxapi.Call{
+		Client:     s.client,
+		Name:       "refresh oauth access token",
+		URL:        oauthRefreshTokenURL,
+		Args:       []any{s.client.AppID, refreshToken},
+		Summary:    "appid: " + s.client.AppID,
+		Idempotent: true,
+	}
 
-	resp, err := http.Get(requestURL)
+	result, err := wxapi.GetJSON[OAuthAccessTokenResponse](ctx, call)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
-
-	var result OAuthAccessTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
 
-	if result.ErrCode != 0 {
-		return nil, fmt.Errorf("wechat error: %d %s", result.ErrCode, result.ErrMsg)
-	}
-
-	return &result, nil
+	return result, nil
 }
 
 // CheckOAuthAccessToken validates the access token.
 // accessToken: OAuth access token to validate
 // openID: user's openid
 func (s *Service) CheckOAuthAccessToken(accessToken, openID string) error {
-	vlog.Infof("check oauth access token | openid: %s", openID)
-
-	requestURL := fmt.Sprintf(oauthCheckTokenURL, accessToken, openID)
+	return s.CheckOAuthAccessTokenContext(context.Background(), accessToken, openID)
+}
 
-	resp, err := http.Get(requestURL)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
-
-	var result struct {
-		ErrCode int    `json:"errcode"`
-		ErrMsg  string `json:"errmsg"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+// CheckOAuthAccessTokenContext is CheckOAuthAccessToken with the given context.
+func (s *Service) CheckOAuthAccessTokenContext(ctx context.Context, accessToken, openID string) error {
+	call := &wxapi.Call{
+		Client:     s.client,
+		Name:       "check oauth access token",
+		URL:        oauthCheckTokenURL,
+		Args:       []any{accessToken, openID},
+		Summary:    "openid: " + openID,
+		Idempotent: true,
 	}
 
-	if result.ErrCode != 0 {
-		return fmt.Errorf("wechat error: %d %s", result.ErrCode, result.ErrMsg)
-	}
+	_, err := wxapi.GetJSON[struct{}](ctx, call)
 
-	return nil
+	return err
 }
@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scopeUpgradeStateTTL is the time the user has to confirm the re-authorization.
+const scopeUpgradeStateTTL = 10 * time.Minute
+
+var (
+	// ErrScopeUpgradeStateInvalid is returned when the state of the callback is unknown or expired.
+	ErrScopeUpgradeStateInvalid = errors.New("scope upgrade state invalid or expired")
+	// ErrScopeUpgradeOpenIDMismatch is returned when the user authorized is not the one the upgrade was issued for.
+	ErrScopeUpgradeOpenIDMismatch = errors.New("scope upgrade openid mismatch")
+)
+
+// ScopeUpgrade is a pending snsapi_userinfo re-authorization issued for a snsapi_base identity.
+type ScopeUpgrade struct {
+	OpenID    string    `json:"openid"`     // 发起升级授权的用户openid
+	Continue  string    `json:"continue"`   // 授权完成后继续使用的业务state
+	ExpiresAt time.Time `json:"expires_at"` // 升级授权的过期时间
+}
+
+// ScopeUpgradeStore keeps the pending scope upgrades by the state passed to the authorize URL.
+type ScopeUpgradeStore interface {
+	// SaveScopeUpgrade stores the pending upgrade by state.
+	SaveScopeUpgrade(ctx context.Context, state string, upgrade *ScopeUpgrade) error
+	// TakeScopeUpgrade returns and removes the pending upgrade of the state, or nil if absent.
+	TakeScopeUpgrade(ctx context.Context, state string) (*ScopeUpgrade, error)
+}
+
+// MemoryScopeUpgradeStore is an in-memory ScopeUpgradeStore, suitable for a single instance.
+type MemoryScopeUpgradeStore struct {
+	mu       sync.Mutex
+	upgrades map[string]*ScopeUpgrade
+}
+
+// NewMemoryScopeUpgradeStore creates an in-memory scope upgrade store.
+func NewMemoryScopeUpgradeStore() *MemoryScopeUpgradeStore {
+	return &MemoryScopeUpgradeStore{upgrades: make(map[string]*ScopeUpgrade)}
+}
+
+// SaveScopeUpgrade implements ScopeUpgradeStore, expired upgrades never confirmed are dropped on the way.
+func (m *MemoryScopeUpgradeStore) SaveScopeUpgrade(_ context.Context, state string, upgrade *ScopeUpgrade) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for key, u := range m.upgrades {
+		if !now.Before(u.ExpiresAt) {
+			delete(m.upgrades, key)
+		}
+	}
+
+	u := *upgrade
+	m.upgrades[state] = &u
+	return nil
+}
+
+// TakeScopeUpgrade implements ScopeUpgradeStore.
+func (m *MemoryScopeUpgradeStore) TakeScopeUpgrade(_ context.Context, state string) (*ScopeUpgrade, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upgrade, ok := m.upgrades[state]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(m.upgrades, state)
+	return upgrade, nil
+}
+
+// WithScopeUpgradeStore sets the store of pending scope upgrades, an in-memory store is used by default.
+func WithScopeUpgradeStore(store ScopeUpgradeStore) func(*Service) {
+	return func(s *Service) {
+		s.upgradeStore = store
+	}
+}
+
+// HasUserInfoScope reports whether the user has authorized with snsapi_userinfo scope.
+func (t *OAuthToken) HasUserInfoScope() bool {
+	return strings.Contains(t.Scope, string(ScopeUserInfo))
+}
+
+// BuildScopeUpgradeURL builds the snsapi_userinfo authorize URL for a user identified by snsapi_base.
+// The state passed to WeChat is generated and mapped to the openid and continueState,
+// which is returned by CompleteScopeUpgrade once the user confirmed.
+func (s *Service) BuildScopeUpgradeURL(openID, redirectURI, continueState string) (string, error) {
+	return s.BuildScopeUpgradeURLContext(context.Background(), openID, redirectURI, continueState)
+}
+
+// BuildScopeUpgradeURLContext is BuildScopeUpgradeURL with the given context.
+func (s *Service) BuildScopeUpgradeURLContext(ctx context.Context, openID, redirectURI, continueState string) (string, error) {
+	state, err := newScopeUpgradeState()
+	if err != nil {
+		return "", fmt.Errorf("generate state error: %w", err)
+	}
+
+	upgrade := &ScopeUpgrade{
+		OpenID:    openID,
+		Continue:  continueState,
+		ExpiresAt: time.Now().Add(scopeUpgradeStateTTL),
+	}
+
+	if err := s.upgradeStore.SaveScopeUpgrade(ctx, state, upgrade); err != nil {
+		return "", fmt.Errorf("save scope upgrade error: %w", err)
+	}
+
+	return s.BuildAuthorizeURL(redirectURI, ScopeUserInfo, state, false), nil
+}
+
+// CompleteScopeUpgrade handles the callback of the URL built by BuildScopeUpgradeURL.
+// It exchanges the code, fetches the profile of the user and merges both into the
+// stored identity (saved to the token store if set), and returns the merged token
+// along with the continue state given when building the URL.
+func (s *Service) CompleteScopeUpgrade(code, state string, lang UserInfoLang) (*OAuthToken, string, error) {
+	return s.CompleteScopeUpgradeContext(context.Background(), code, state, lang)
+}
+
+// CompleteScopeUpgradeContext is CompleteScopeUpgrade with the given context.
+func (s *Service) CompleteScopeUpgradeContext(ctx context.Context, code, state string, lang UserInfoLang) (*OAuthToken, string, error) {
+	upgrade, err := s.upgradeStore.TakeScopeUpgrade(ctx, state)
+	if err != nil {
+		return nil, "", fmt.Errorf("take scope upgrade error: %w", err)
+	}
+
+	if upgrade == nil || !time.Now().Before(upgrade.ExpiresAt) {
+		return nil, "", ErrScopeUpgradeStateInvalid
+	}
+
+	resp, err := s.GetOAuthAccessTokenContext(ctx, code)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.OpenID != upgrade.OpenID {
+		return nil, "", ErrScopeUpgradeOpenIDMismatch
+	}
+
+	token := newOAuthToken(resp, time.Now())
+
+	profile, err := s.GetUserInfoContext(ctx, token.AccessToken, token.OpenID, lang)
+	if err != nil {
+		return nil, "", fmt.Errorf("get user info error: %w", err)
+	}
+	token.Profile = profile
+
+	if s.tokenStore == nil {
+		mergeOAuthIdentity(token, nil)
+		return token, upgrade.Continue, nil
+	}
+
+	stored, err := s.tokenStore.Load(ctx, token.OpenID)
+	if err != nil {
+		return nil, "", fmt.Errorf("load oauth token error: %w", err)
+	}
+
+	mergeOAuthIdentity(token, stored)
+
+	if err := s.tokenStore.Save(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("save oauth token error: %w", err)
+	}
+
+	return token, upgrade.Continue, nil
+}
+
+// mergeOAuthIdentity fills the identity fields missing in the upgraded token from the profile and the stored token.
+func mergeOAuthIdentity(token, stored *OAuthToken) {
+	if token.UnionID == "" && token.Profile != nil {
+		token.UnionID = token.Profile.UnionID
+	}
+
+	if stored == nil {
+		return
+	}
+
+	if token.UnionID == "" {
+		token.UnionID = stored.UnionID
+	}
+}
+
+func newScopeUpgradeState() (string, error) {
+	// WeChat only accepts [a-zA-Z0-9] in state, hex keeps it within
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestScopeUpgrade(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	store := vwxmp.NewMemoryOAuthTokenStore()
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)), vwxmp.WithOAuthTokenStore(store))
+
+	openID := vwxmock.OpenIDPrefix + "code"
+	assert.NoError(t, store.Save(context.Background(), &vwxmp.OAuthToken{OpenID: openID, UnionID: "union", Scope: "snsapi_base"}))
+
+	authURL, err := svc.BuildScopeUpgradeURL(openID, "https://example.com/callback", "page=order")
+	assert.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	state := u.Query().Get("state")
+	assert.Equal(t, "snsapi_userinfo", u.Query().Get("scope"))
+	assert.NotContains(t, state, "page")
+
+	token, continueState, err := svc.CompleteScopeUpgrade("code", state, vwxmp.LangZhCN)
+	assert.NoError(t, err)
+	assert.Equal(t, "page=order", continueState)
+	assert.True(t, token.HasUserInfoScope())
+	assert.Equal(t, "union", token.UnionID)
+	assert.Equal(t, "mock-"+openID, token.Profile.Nickname)
+
+	stored, err := store.Load(context.Background(), openID)
+	assert.NoError(t, err)
+	assert.Equal(t, "mock-"+openID, stored.Profile.Nickname)
+
+	// the state is single use
+	_, _, err = svc.CompleteScopeUpgrade("code", state, vwxmp.LangZhCN)
+	assert.ErrorIs(t, err, vwxmp.ErrScopeUpgradeStateInvalid)
+
+	// another user authorizing with the link is rejected
+	authURL, err = svc.BuildScopeUpgradeURL(openID, "https://example.com/callback", "")
+	assert.NoError(t, err)
+	u, _ = url.Parse(authURL)
+
+	_, _, err = svc.CompleteScopeUpgrade("other", u.Query().Get("state"), vwxmp.LangZhCN)
+	assert.ErrorIs(t, err, vwxmp.ErrScopeUpgradeOpenIDMismatch)
+}
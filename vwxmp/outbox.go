@@ -0,0 +1,348 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	templateSendJobFinishEvent = "TEMPLATESENDJOBFINISH"
+	subscribeMsgSentEvent      = "subscribe_msg_sent_event"
+)
+
+// DeliveryStatus represents the delivery status of a message in the outbox.
+type DeliveryStatus string
+
+const (
+	DeliveryPending         DeliveryStatus = "pending"           // 已提交，等待推送结果
+	DeliverySuccess         DeliveryStatus = "success"           // 送达成功
+	DeliveryFailedUserBlock DeliveryStatus = "failed:user block" // 用户拒收
+	DeliveryFailedSystem    DeliveryStatus = "failed:system"     // 其他原因失败
+)
+
+// ErrOutboxRecordNotFound is returned when the message is not recorded in the outbox.
+var ErrOutboxRecordNotFound = errors.New("outbox record not found")
+
+// OutboxRecord represents a sent message and its delivery status.
+type OutboxRecord struct {
+	MsgID      int64          `json:"msgid"`       // 消息id
+	OpenID     string         `json:"openid"`      // 接收者openid
+	TemplateID string         `json:"template_id"` // 模板id
+	Status     DeliveryStatus `json:"status"`      // 送达状态
+	Detail     string         `json:"detail"`      // 推送事件中的原始状态描述
+	SentAt     time.Time      `json:"sent_at"`     // 发送时间
+	FinishedAt time.Time      `json:"finished_at"` // 收到推送结果的时间
+}
+
+// OutboxStore persists the outbox records.
+type OutboxStore interface {
+	// Save inserts or updates the record by msgid.
+	Save(ctx context.Context, record *OutboxRecord) error
+	// Get returns the record of the msgid, or nil if absent.
+	Get(ctx context.Context, msgID int64) (*OutboxRecord, error)
+	// ListByOpenID returns the latest records sent to the user, newest first.
+	ListByOpenID(ctx context.Context, openID string, limit int) ([]*OutboxRecord, error)
+}
+
+// MemoryOutboxStore is an in-memory OutboxStore, suitable for a single instance and tests.
+type MemoryOutboxStore struct {
+	mu      sync.RWMutex
+	records map[int64]*OutboxRecord
+}
+
+// NewMemoryOutboxStore creates an in-memory outbox store.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{records: make(map[int64]*OutboxRecord)}
+}
+
+// Save implements OutboxStore.
+func (m *MemoryOutboxStore) Save(_ context.Context, record *OutboxRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := *record
+	m.records[record.MsgID] = &r
+	return nil
+}
+
+// Get implements OutboxStore.
+func (m *MemoryOutboxStore) Get(_ context.Context, msgID int64) (*OutboxRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	record, ok := m.records[msgID]
+	if !ok {
+		return nil, nil
+	}
+
+	r := *record
+	return &r, nil
+}
+
+// ListByOpenID implements OutboxStore.
+func (m *MemoryOutboxStore) ListByOpenID(_ context.Context, openID string, limit int) ([]*OutboxRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var records []*OutboxRecord
+	for _, record := range m.records {
+		if record.OpenID == openID {
+			r := *record
+			records = append(records, &r)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].SentAt.After(records[j].SentAt)
+	})
+
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	return records, nil
+}
+
+// Outbox records the sent template/subscribe messages and reconciles their final delivery
+// status from the TEMPLATESENDJOBFINISH and subscribe_msg_sent_event push events.
+type Outbox struct {
+	sender TemplateMessageSender
+	store  OutboxStore
+
+	Logger vwx.Logger // 日志，默认vlog
+}
+
+func (o *Outbox) logger() vwx.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+// NewOutbox creates an outbox sending template messages by the sender.
+func NewOutbox(sender TemplateMessageSender, store OutboxStore) *Outbox {
+	return &Outbox{
+		sender: sender,
+		store:  store,
+	}
+}
+
+// SendTemplateMessage sends the template message and records it as pending.
+func (o *Outbox) SendTemplateMessage(req *TemplateMessageRequest) (*TemplateMessageResponse, error) {
+	resp, err := o.sender.SendTemplateMessage(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// msgid is absent in dry-run mode
+	if resp.MsgID != 0 {
+		if err := o.Record(resp.MsgID, req.ToUser, req.TemplateID); err != nil {
+			o.logger().Errorf("failed to record outbox message | msgid: %d | err: %v", resp.MsgID, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// Record records a message sent by other means (e.g. subscribe messages) as pending.
+func (o *Outbox) Record(msgID int64, openID, templateID string) error {
+	return o.store.Save(context.Background(), &OutboxRecord{
+		MsgID:      msgID,
+		OpenID:     openID,
+		TemplateID: templateID,
+		Status:     DeliveryPending,
+		SentAt:     time.Now(),
+	})
+}
+
+// Get returns the record of the message.
+func (o *Outbox) Get(msgID int64) (*OutboxRecord, error) {
+	record, err := o.store.Get(context.Background(), msgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, ErrOutboxRecordNotFound
+	}
+
+	return record, nil
+}
+
+// ListByOpenID returns the latest messages sent to the user, newest first.
+func (o *Outbox) ListByOpenID(openID string, limit int) ([]*OutboxRecord, error) {
+	return o.store.ListByOpenID(context.Background(), openID, limit)
+}
+
+// Register registers the outbox for the delivery result events of the dispatcher, along with the
+// other handlers of the events, e.g. the subscribe quota tracker of mini programs.
+func (o *Outbox) Register(d *vwxpush.Dispatcher) {
+	d.HandleEvent(templateSendJobFinishEvent, o.HandleEvent)
+	d.HandleEvent(subscribeMsgSentEvent, o.HandleEvent)
+}
+
+// HandleEvent marks the final delivery status from a TEMPLATESENDJOBFINISH or subscribe_msg_sent_event
+// push event, it matches vwxpush.MessageHandler. Events of messages not recorded are ignored.
+func (o *Outbox) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	results, err := parseDeliveryEvent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	for _, result := range results {
+		record, err := o.store.Get(ctx, result.msgID)
+		if err != nil {
+			return nil, fmt.Errorf("get outbox record error: %w", err)
+		}
+
+		if record == nil {
+			o.logger().Warnf("outbox record not found | event: %s | msgid: %d | openid: %s", baseInfo.Event, result.msgID, baseInfo.FromUserName)
+			continue
+		}
+
+		record.Status = result.status
+		record.Detail = result.detail
+		record.FinishedAt = time.Now()
+
+		if err := o.store.Save(ctx, record); err != nil {
+			return nil, fmt.Errorf("save outbox record error: %w", err)
+		}
+	}
+
+	return nil, nil
+}
+
+type deliveryResult struct {
+	msgID  int64
+	status DeliveryStatus
+	detail string
+}
+
+// flexInt64 accepts json numbers and strings, WeChat pushes MsgID in both forms.
+type flexInt64 int64
+
+func (f *flexInt64) UnmarshalJSON(data []byte) error {
+	n, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*f = flexInt64(n)
+	return nil
+}
+
+type sentEventItem struct {
+	MsgID       flexInt64 `xml:"MsgID" json:"MsgID"`
+	ErrorCode   string    `xml:"ErrorCode" json:"ErrorCode"`
+	ErrorStatus string    `xml:"ErrorStatus" json:"ErrorStatus"`
+}
+
+type deliveryEvent struct {
+	Event     string    `xml:"Event" json:"Event"`
+	MsgID     flexInt64 `xml:"MsgID" json:"MsgID"`
+	Status    string    `xml:"Status" json:"Status"`
+	SentEvent struct {
+		List []*sentEventItem `xml:"List"`
+	} `xml:"SubscribeMsgSentEvent" json:"-"`
+	List json.RawMessage `xml:"-" json:"List"`
+}
+
+func parseDeliveryEvent(data []byte) ([]*deliveryResult, error) {
+	data = bytes.TrimSpace(data)
+
+	var event deliveryEvent
+	var err error
+	if bytes.HasPrefix(data, []byte("<")) {
+		err = xml.Unmarshal(data, &event)
+	} else {
+		err = json.Unmarshal(data, &event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal delivery event error: %w", err)
+	}
+
+	if strings.EqualFold(event.Event, templateSendJobFinishEvent) {
+		return []*deliveryResult{{
+			msgID:  int64(event.MsgID),
+			status: templateDeliveryStatus(event.Status),
+			detail: event.Status,
+		}}, nil
+	}
+
+	items := event.SentEvent.List
+	if list := bytes.TrimSpace(event.List); len(list) > 0 {
+		if list[0] == '[' {
+			err = json.Unmarshal(list, &items)
+		} else {
+			var item sentEventItem
+			err = json.Unmarshal(list, &item)
+			items = append(items, &item)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal delivery event list error: %w", err)
+		}
+	}
+
+	results := make([]*deliveryResult, 0, len(items))
+	for _, item := range items {
+		status := DeliverySuccess
+		switch item.ErrorCode {
+		case "0":
+		case "43101":
+			status = DeliveryFailedUserBlock
+		default:
+			status = DeliveryFailedSystem
+		}
+
+		results = append(results, &deliveryResult{
+			msgID:  int64(item.MsgID),
+			status: status,
+			detail: item.ErrorStatus,
+		})
+	}
+
+	return results, nil
+}
+
+// templateDeliveryStatus maps the Status of TEMPLATESENDJOBFINISH events:
+// success, failed:user block, failed: system failed.
+func templateDeliveryStatus(status string) DeliveryStatus {
+	switch {
+	case status == "success":
+		return DeliverySuccess
+	case strings.Contains(status, "user block"):
+		return DeliveryFailedUserBlock
+	default:
+		return DeliveryFailedSystem
+	}
+}
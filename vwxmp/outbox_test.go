@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestOutbox(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	outbox := vwxmp.NewOutbox(svc, vwxmp.NewMemoryOutboxStore())
+
+	d := vwxpush.NewDispatcher()
+	outbox.Register(d)
+
+	send := func() int64 {
+		resp, err := outbox.SendTemplateMessage(&vwxmp.TemplateMessageRequest{
+			ToUser:     "openid",
+			TemplateID: "tmpl-1",
+			Data:       map[string]*vwxmp.TemplateDataItem{"thing1": {Value: "hi"}},
+		})
+		assert.NoError(t, err)
+		return resp.MsgID
+	}
+
+	first, second := send(), send()
+
+	record, err := outbox.Get(first)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmp.DeliveryPending, record.Status)
+
+	baseInfo := &vwxpush.PushBaseInfo{FromUserName: "openid", MsgType: "event", Event: "TEMPLATESENDJOBFINISH"}
+	_, err = d.Dispatch("", baseInfo, []byte(fmt.Sprintf(`<xml><FromUserName><![CDATA[openid]]></FromUserName>
+<MsgType><![CDATA[event]]></MsgType><Event><![CDATA[TEMPLATESENDJOBFINISH]]></Event>
+<MsgID>%d</MsgID><Status><![CDATA[failed:user block]]></Status></xml>`, first)))
+	assert.NoError(t, err)
+
+	record, _ = outbox.Get(first)
+	assert.Equal(t, vwxmp.DeliveryFailedUserBlock, record.Status)
+	assert.False(t, record.FinishedAt.IsZero())
+
+	baseInfo.Event = "subscribe_msg_sent_event"
+	_, err = d.Dispatch("", baseInfo, []byte(fmt.Sprintf(
+		`{"Event":"subscribe_msg_sent_event","List":{"TemplateId":"tmpl-1","MsgID":"%d","ErrorCode":"0","ErrorStatus":"success"}}`, second)))
+	assert.NoError(t, err)
+
+	record, _ = outbox.Get(second)
+	assert.Equal(t, vwxmp.DeliverySuccess, record.Status)
+
+	records, err := outbox.ListByOpenID("openid", 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	_, err = outbox.Get(404)
+	assert.ErrorIs(t, err, vwxmp.ErrOutboxRecordNotFound)
+}
@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	qrcodeCreateURL = "https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token=%s"
+	showQRCodeURL   = "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=%s"
+
+	// MaxQRCodeExpire is the max expire time of temporary QR codes.
+	MaxQRCodeExpire = 30 * 24 * time.Hour
+)
+
+// QRCodeActionName is the type of parametric QR codes.
+type QRCodeActionName string
+
+const (
+	QRCodeScene         QRCodeActionName = "QR_SCENE"           // 临时的整型参数值
+	QRCodeStrScene      QRCodeActionName = "QR_STR_SCENE"       // 临时的字符串参数值
+	QRCodeLimitScene    QRCodeActionName = "QR_LIMIT_SCENE"     // 永久的整型参数值
+	QRCodeLimitStrScene QRCodeActionName = "QR_LIMIT_STR_SCENE" // 永久的字符串参数值
+)
+
+// QRCodeSceneInfo is the scene carried by a parametric QR code.
+type QRCodeSceneInfo struct {
+	SceneID  int    `json:"scene_id,omitempty"`  // 场景值ID，临时二维码时为32位非0整型，永久二维码时最大值为100000
+	SceneStr string `json:"scene_str,omitempty"` // 场景值ID（字符串形式的ID），长度限制为1到64
+}
+
+// QRCodeActionInfo is the action info of a parametric QR code.
+type QRCodeActionInfo struct {
+	Scene QRCodeSceneInfo `json:"scene"` // 二维码详细信息
+}
+
+// QRCodeCreateRequest represents the request of creating a parametric QR code.
+type QRCodeCreateRequest struct {
+	ExpireSeconds int              `json:"expire_seconds,omitempty"` // 临时二维码有效时间，以秒为单位，最大不超过2592000（即30天）
+	ActionName    QRCodeActionName `json:"action_name"`              // 二维码类型
+	ActionInfo    QRCodeActionInfo `json:"action_info"`              // 二维码详细信息
+}
+
+// QRCodeResponse represents the response of creating a parametric QR code.
+type QRCodeResponse struct {
+	ErrCode       int    `json:"errcode"`
+	ErrMsg        string `json:"errmsg"`
+	Ticket        string `json:"ticket"`         // 获取的二维码ticket，凭借此ticket可以在有效时间内换取二维码
+	ExpireSeconds int    `json:"expire_seconds"` // 该二维码有效时间，以秒为单位，永久二维码为0
+	URL           string `json:"url"`            // 二维码图片解析后的地址，开发者可根据该地址自行生成需要的二维码图片
+}
+
+// CreateQRCode creates a parametric QR code, scanning it pushes subscribe or SCAN events with the scene.
+func (s *Service) CreateQRCode(req *QRCodeCreateRequest) (*QRCodeResponse, error) {
+	return s.CreateQRCodeContext(context.Background(), req)
+}
+
+// CreateQRCodeContext is CreateQRCode with the given context.
+func (s *Service) CreateQRCodeContext(ctx context.Context, req *QRCodeCreateRequest) (*QRCodeResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	result, err := wxapi.PostJSON[QRCodeResponse](ctx, s.call("create qrcode", qrcodeCreateURL), req)
+	if err != nil {
+		return result, err
+	}
+
+	s.client.Logger.Infof("create qrcode | request_id: %s | ticket: %s", requestID, result.Ticket)
+
+	return result, nil
+}
+
+// CreateStrSceneQRCode creates a QR code of the string scene, the QR code is permanent if expire is zero.
+func (s *Service) CreateStrSceneQRCode(scene string, expire time.Duration) (*QRCodeResponse, error) {
+	return s.CreateStrSceneQRCodeContext(context.Background(), scene, expire)
+}
+
+// CreateStrSceneQRCodeContext is CreateStrSceneQRCode with the given context.
+func (s *Service) CreateStrSceneQRCodeContext(ctx context.Context, scene string, expire time.Duration) (*QRCodeResponse, error) {
+	req := &QRCodeCreateRequest{
+		ActionName: QRCodeLimitStrScene,
+		ActionInfo: QRCodeActionInfo{Scene: QRCodeSceneInfo{SceneStr: scene}},
+	}
+
+	if expire > 0 {
+		req.ActionName = QRCodeStrScene
+		req.ExpireSeconds = int(min(expire, MaxQRCodeExpire) / time.Second)
+	}
+
+	return s.CreateQRCodeContext(ctx, req)
+}
+
+// QRCodeImageURL returns the url of the QR code image of the ticket.
+func QRCodeImageURL(ticket string) string {
+	return fmt.Sprintf(showQRCodeURL, url.QueryEscape(ticket))
+}
@@ -15,17 +15,50 @@
  * limitations under the License.
  */
 
-// Package vwxmp provides WeChat Web (H5) authorization API client functionality.
+// Package vwxmp provides WeChat Official Account API client functionality,
+// including Web (H5) authorization and customer service messages.
 package vwxmp
 
-import "github.com/vogo/vwx"
+import (
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/token"
+	"github.com/vogo/vwx/vwxauth"
+)
 
 // Service provides WeChat Web (H5) authorization API operations.
 type Service struct {
-	client *vwx.Client
+	client       *vwx.Client
+	authSvc      *vwxauth.Service
+	tokenStore   OAuthTokenStore
+	upgradeStore ScopeUpgradeStore
+	jssdk        *jssdkSignatures
+	jsapiTickets *token.Manager
+
+	publishPollInterval    time.Duration
+	publishPollMaxInterval time.Duration
 }
 
 // NewService creates a new WeChat Web authorization service.
-func NewService(client *vwx.Client) *Service {
-	return &Service{client: client}
+func NewService(client *vwx.Client, options ...func(*Service)) *Service {
+	s := &Service{
+		client:       client,
+		authSvc:      vwxauth.NewService(client),
+		upgradeStore: NewMemoryScopeUpgradeStore(),
+		jssdk:        &jssdkSignatures{},
+	}
+
+	s.jsapiTickets = &token.Manager{
+		Client:   client,
+		Name:     "jsapi ticket",
+		CacheKey: s.cacheKeyJSAPITicket(),
+		Fetch:    s.requestJSAPITicket,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
 }
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	subscriberInfoURL = "https://api.weixin.qq.com/cgi-bin/user/info?access_token=%s&openid=%s&lang=%s"
+)
+
+// SubscriberInfo is the basic info of a user of the official account.
+type SubscriberInfo struct {
+	ErrCode        int    `json:"errcode,omitempty"`
+	ErrMsg         string `json:"errmsg,omitempty"`
+	Subscribe      int    `json:"subscribe"`                 // 用户是否订阅该公众号标识，值为0时，代表此用户没有关注该公众号，拉取不到其余信息
+	OpenID         string `json:"openid"`                    // 用户的标识，对当前公众号唯一
+	Language       string `json:"language,omitempty"`        // 用户的语言，简体中文为zh_CN
+	SubscribeTime  int64  `json:"subscribe_time,omitempty"`  // 用户关注时间，为时间戳。如果用户曾多次关注，则取最后关注时间
+	UnionID        string `json:"unionid,omitempty"`         // 只有在用户将公众号绑定到微信开放平台账号后，才会出现该字段
+	Remark         string `json:"remark,omitempty"`          // 公众号运营者对粉丝的备注
+	GroupID        int    `json:"groupid,omitempty"`         // 用户所在的分组ID
+	TagIDList      []int  `json:"tagid_list,omitempty"`      // 用户被打上的标签ID列表
+	SubscribeScene string `json:"subscribe_scene,omitempty"` // 用户关注的渠道来源
+	QRScene        int    `json:"qr_scene,omitempty"`        // 二维码扫码场景
+	QRSceneStr     string `json:"qr_scene_str,omitempty"`    // 二维码扫码场景描述
+}
+
+// Subscribed reports whether the user subscribes the official account.
+func (i *SubscriberInfo) Subscribed() bool {
+	return i.Subscribe == 1
+}
+
+// GetSubscriberInfo returns the basic info of the user of the official account.
+func (s *Service) GetSubscriberInfo(openID string, lang UserInfoLang) (*SubscriberInfo, error) {
+	return s.GetSubscriberInfoContext(context.Background(), openID, lang)
+}
+
+// GetSubscriberInfoContext is GetSubscriberInfo with the given context.
+func (s *Service) GetSubscriberInfoContext(ctx context.Context, openID string, lang UserInfoLang) (*SubscriberInfo, error) {
+	if lang == "" {
+		lang = LangZhCN
+	}
+
+	call := s.call("get subscriber info", subscriberInfoURL, openID, lang)
+	call.Idempotent = true
+	call.Summary = fmt.Sprintf("openid: %s | lang: %s", openID, lang)
+
+	result, err := wxapi.GetJSON[SubscriberInfo](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
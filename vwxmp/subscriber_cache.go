@@ -0,0 +1,255 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/vwx/vwxpush"
+)
+
+const (
+	unsubscribeEvent = "unsubscribe"
+	locationEvent    = "LOCATION"
+
+	// DefaultSubscriberProfileTTL is the default time a cached subscriber info is used without refetching.
+	DefaultSubscriberProfileTTL = 24 * time.Hour
+)
+
+// SubscriberLocation is the geographic location reported by the LOCATION push event.
+type SubscriberLocation struct {
+	Latitude   float64   `json:"latitude"`    // 地理位置纬度
+	Longitude  float64   `json:"longitude"`   // 地理位置经度
+	Precision  float64   `json:"precision"`   // 地理位置精度
+	ReportedAt time.Time `json:"reported_at"` // 上报时间
+}
+
+// SubscriberProfile is the cached profile of a user: the subscriber info enriched with the location.
+type SubscriberProfile struct {
+	Info      *SubscriberInfo     `json:"info,omitempty"`     // 用户基本信息，nil表示待拉取
+	Location  *SubscriberLocation `json:"location,omitempty"` // 最近上报的地理位置
+	FetchedAt time.Time           `json:"fetched_at"`         // 用户基本信息的拉取时间
+}
+
+// SubscriberProfileStore persists the cached profiles of users by openid.
+type SubscriberProfileStore interface {
+	// GetSubscriberProfile returns the profile of the user, nil if not found.
+	GetSubscriberProfile(ctx context.Context, openID string) (*SubscriberProfile, error)
+	// SetSubscriberProfile sets the profile of the user.
+	SetSubscriberProfile(ctx context.Context, openID string, profile *SubscriberProfile) error
+	// DeleteSubscriberProfile deletes the profile of the user.
+	DeleteSubscriberProfile(ctx context.Context, openID string) error
+}
+
+// MemorySubscriberProfileStore is an in-memory SubscriberProfileStore, suitable for a single instance.
+type MemorySubscriberProfileStore struct {
+	mu       sync.RWMutex
+	profiles map[string]*SubscriberProfile
+}
+
+// NewMemorySubscriberProfileStore creates an in-memory subscriber profile store.
+func NewMemorySubscriberProfileStore() *MemorySubscriberProfileStore {
+	return &MemorySubscriberProfileStore{profiles: make(map[string]*SubscriberProfile)}
+}
+
+// GetSubscriberProfile returns the profile of the user, nil if not found.
+func (m *MemorySubscriberProfileStore) GetSubscriberProfile(_ context.Context, openID string) (*SubscriberProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	profile, ok := m.profiles[openID]
+	if !ok {
+		return nil, nil
+	}
+
+	p := *profile
+	return &p, nil
+}
+
+// SetSubscriberProfile sets the profile of the user.
+func (m *MemorySubscriberProfileStore) SetSubscriberProfile(_ context.Context, openID string, profile *SubscriberProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := *profile
+	m.profiles[openID] = &p
+
+	return nil
+}
+
+// DeleteSubscriberProfile deletes the profile of the user.
+func (m *MemorySubscriberProfileStore) DeleteSubscriberProfile(_ context.Context, openID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.profiles, openID)
+
+	return nil
+}
+
+// SubscriberCache caches the profiles of users, kept fresh by the subscribe, unsubscribe and
+// LOCATION push events instead of polling the user info api.
+type SubscriberCache struct {
+	getter SubscriberInfoGetter
+	store  SubscriberProfileStore
+
+	TTL  time.Duration // 用户基本信息的缓存时间，默认24小时
+	Lang UserInfoLang  // 拉取用户基本信息的语言，默认zh_CN
+}
+
+// NewSubscriberCache creates a subscriber cache fetching the user info by the getter,
+// an in-memory store is used if the store is nil.
+func NewSubscriberCache(getter SubscriberInfoGetter, store SubscriberProfileStore, options ...func(*SubscriberCache)) *SubscriberCache {
+	if store == nil {
+		store = NewMemorySubscriberProfileStore()
+	}
+
+	c := &SubscriberCache{
+		getter: getter,
+		store:  store,
+		TTL:    DefaultSubscriberProfileTTL,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithSubscriberProfileTTL sets the time a cached subscriber info is used without refetching.
+func WithSubscriberProfileTTL(ttl time.Duration) func(*SubscriberCache) {
+	return func(c *SubscriberCache) {
+		c.TTL = ttl
+	}
+}
+
+// WithSubscriberInfoLang sets the language the subscriber info is fetched in.
+func WithSubscriberInfoLang(lang UserInfoLang) func(*SubscriberCache) {
+	return func(c *SubscriberCache) {
+		c.Lang = lang
+	}
+}
+
+// Get returns the profile of the user, the subscriber info is fetched if not cached or expired.
+func (c *SubscriberCache) Get(openID string) (*SubscriberProfile, error) {
+	return c.GetContext(context.Background(), openID)
+}
+
+// GetContext is Get with the given context.
+func (c *SubscriberCache) GetContext(ctx context.Context, openID string) (*SubscriberProfile, error) {
+	profile, err := c.store.GetSubscriberProfile(ctx, openID)
+	if err != nil {
+		return nil, fmt.Errorf("get subscriber profile error: %w", err)
+	}
+
+	if profile != nil && profile.Info != nil && time.Since(profile.FetchedAt) < c.TTL {
+		return profile, nil
+	}
+
+	info, err := c.getter.GetSubscriberInfoContext(ctx, openID, c.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil {
+		profile = &SubscriberProfile{}
+	}
+	profile.Info = info
+	profile.FetchedAt = time.Now()
+
+	if err := c.store.SetSubscriberProfile(ctx, openID, profile); err != nil {
+		return nil, fmt.Errorf("set subscriber profile error: %w", err)
+	}
+
+	return profile, nil
+}
+
+// Invalidate drops the cached subscriber info of the user, it is fetched again on the next Get.
+func (c *SubscriberCache) Invalidate(openID string) error {
+	return c.store.DeleteSubscriberProfile(context.Background(), openID)
+}
+
+// Register registers the cache for the subscribe, unsubscribe and LOCATION events of the dispatcher.
+func (c *SubscriberCache) Register(d *vwxpush.Dispatcher) {
+	d.HandleEvent(subscribeEvent, c.HandleEvent)
+	d.HandleEvent(unsubscribeEvent, c.HandleEvent)
+	d.HandleEvent(locationEvent, c.HandleEvent)
+}
+
+// HandleEvent updates the cached profile from a push event, it matches vwxpush.MessageHandler.
+// Subscriptions invalidate the subscriber info, unsubscriptions mark the user unsubscribed,
+// and locations are recorded in the profile.
+func (c *SubscriberCache) HandleEvent(_ string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+	ctx := context.Background()
+	openID := baseInfo.FromUserName
+
+	if strings.EqualFold(baseInfo.Event, subscribeEvent) {
+		return nil, c.store.DeleteSubscriberProfile(ctx, openID)
+	}
+
+	if !strings.EqualFold(baseInfo.Event, unsubscribeEvent) && !strings.EqualFold(baseInfo.Event, locationEvent) {
+		return nil, nil
+	}
+
+	profile, err := c.store.GetSubscriberProfile(ctx, openID)
+	if err != nil {
+		return nil, fmt.Errorf("get subscriber profile error: %w", err)
+	}
+
+	if profile == nil {
+		profile = &SubscriberProfile{}
+	}
+
+	if strings.EqualFold(baseInfo.Event, unsubscribeEvent) {
+		// the api returns nothing but the openid of unsubscribed users
+		profile.Info = &SubscriberInfo{OpenID: openID}
+		profile.FetchedAt = time.Now()
+	} else {
+		location, err := parseSubscriberLocation(baseInfo, data)
+		if err != nil {
+			return nil, err
+		}
+		profile.Location = location
+	}
+
+	return nil, c.store.SetSubscriberProfile(ctx, openID, profile)
+}
+
+func parseSubscriberLocation(baseInfo *vwxpush.PushBaseInfo, data []byte) (*SubscriberLocation, error) {
+	var event struct {
+		Latitude  float64 `xml:"Latitude" json:"Latitude"`
+		Longitude float64 `xml:"Longitude" json:"Longitude"`
+		Precision float64 `xml:"Precision" json:"Precision"`
+	}
+
+	if err := unmarshalPushData(data, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal location event error: %w", err)
+	}
+
+	return &SubscriberLocation{
+		Latitude:   event.Latitude,
+		Longitude:  event.Longitude,
+		Precision:  event.Precision,
+		ReportedAt: time.Unix(baseInfo.CreateTime, 0),
+	}, nil
+}
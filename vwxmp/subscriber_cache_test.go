@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestSubscriberCache(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	cache := vwxmp.NewSubscriberCache(svc, nil, vwxmp.WithSubscriberInfoLang(vwxmp.LangEN))
+
+	var arrivals []*vwxmp.CampaignArrival
+	tracker := vwxmp.NewCampaignTracker(svc, func(arrival *vwxmp.CampaignArrival) error {
+		arrivals = append(arrivals, arrival)
+		return nil
+	})
+
+	// the cache and the campaign tracker both handle the subscribe events
+	d := vwxpush.NewDispatcher()
+	cache.Register(d)
+	tracker.Register(d)
+
+	push := func(event, extra string) {
+		baseInfo := &vwxpush.PushBaseInfo{FromUserName: "openid", MsgType: "event", Event: event, CreateTime: 1700000000}
+		_, err := d.Dispatch("", baseInfo, []byte(fmt.Sprintf(`<xml><FromUserName><![CDATA[openid]]></FromUserName>
+<MsgType><![CDATA[event]]></MsgType><Event><![CDATA[%s]]></Event>%s</xml>`, event, extra)))
+		assert.NoError(t, err)
+	}
+
+	profile, err := cache.Get("openid")
+	assert.NoError(t, err)
+	assert.True(t, profile.Info.Subscribed())
+	assert.Equal(t, "en", profile.Info.Language)
+
+	_, err = cache.Get("openid")
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/user/info"), 1)
+
+	// locations enrich the cached profile
+	push("LOCATION", "<Latitude>23.137466</Latitude><Longitude>113.352425</Longitude><Precision>119.385040</Precision>")
+
+	profile, err = cache.Get("openid")
+	assert.NoError(t, err)
+	assert.InDelta(t, 23.137466, profile.Location.Latitude, 1e-6)
+	assert.Len(t, server.Requests("/cgi-bin/user/info"), 1)
+
+	// unsubscribing is applied without calling the api
+	push("unsubscribe", "")
+
+	profile, err = cache.Get("openid")
+	assert.NoError(t, err)
+	assert.False(t, profile.Info.Subscribed())
+	assert.Len(t, server.Requests("/cgi-bin/user/info"), 1)
+
+	// subscribing again invalidates the profile
+	push("subscribe", "<EventKey><![CDATA[qrscene_spring-sale]]></EventKey>")
+
+	profile, err = cache.Get("openid")
+	assert.NoError(t, err)
+	assert.True(t, profile.Info.Subscribed())
+	assert.Len(t, server.Requests("/cgi-bin/user/info"), 2)
+	assert.Len(t, arrivals, 1)
+	assert.Equal(t, "spring-sale", arrivals[0].Scene)
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// TemplateKeyFirst is the key of the opening line of the classic templates.
+	TemplateKeyFirst = "first"
+	// TemplateKeyRemark is the key of the closing line of the classic templates.
+	TemplateKeyRemark = "remark"
+
+	// MaxTemplateValueLength is the max length in characters of a template data value.
+	MaxTemplateValueLength = 200
+
+	// DefaultEmphasisColor is the color of the values emphasized by TemplateData.Emphasize.
+	DefaultEmphasisColor = "#FF0000"
+)
+
+var (
+	templateColorRegexp = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	templateKeyRegexp   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// TemplateData builds the data of a template message, assign it to TemplateMessageRequest.Data.
+type TemplateData map[string]*TemplateDataItem
+
+// NewTemplateData creates an empty template data.
+func NewTemplateData() TemplateData {
+	return make(TemplateData)
+}
+
+// Set sets the value of the key.
+func (d TemplateData) Set(key, value string) TemplateData {
+	d[key] = &TemplateDataItem{Value: value}
+	return d
+}
+
+// SetColor sets the value of the key shown in the color, e.g. #173177.
+func (d TemplateData) SetColor(key, value, color string) TemplateData {
+	d[key] = &TemplateDataItem{Value: value, Color: color}
+	return d
+}
+
+// Emphasize sets the value of the key shown in DefaultEmphasisColor.
+func (d TemplateData) Emphasize(key, value string) TemplateData {
+	return d.SetColor(key, value, DefaultEmphasisColor)
+}
+
+// First sets the opening line of the classic templates, the color is optional.
+func (d TemplateData) First(value string, color ...string) TemplateData {
+	return d.setOptionalColor(TemplateKeyFirst, value, color)
+}
+
+// Remark sets the closing line of the classic templates, the color is optional.
+func (d TemplateData) Remark(value string, color ...string) TemplateData {
+	return d.setOptionalColor(TemplateKeyRemark, value, color)
+}
+
+func (d TemplateData) setOptionalColor(key, value string, color []string) TemplateData {
+	if len(color) > 0 {
+		return d.SetColor(key, value, color[0])
+	}
+
+	return d.Set(key, value)
+}
+
+// Validate checks the keys, the value lengths and the hex colors of the data, the keys are
+// checked in order so that the same error is reported for the same data.
+func (d TemplateData) Validate() error {
+	if len(d) == 0 {
+		return fmt.Errorf("template data is empty")
+	}
+
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		item := d[key]
+
+		if !templateKeyRegexp.MatchString(key) {
+			return fmt.Errorf("template data %q: invalid key", key)
+		}
+
+		if item == nil {
+			return fmt.Errorf("template data %s: item is nil", key)
+		}
+
+		if length := utf8.RuneCountInString(item.Value); length > MaxTemplateValueLength {
+			return fmt.Errorf("template data %s: value length %d exceeds %d", key, length, MaxTemplateValueLength)
+		}
+
+		if item.Color != "" && !templateColorRegexp.MatchString(item.Color) {
+			return fmt.Errorf("template data %s: color %q must be #RRGGBB", key, item.Color)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestTemplateData(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	data := vwxmp.NewTemplateData().
+		First("您的订单已发货").
+		Emphasize("keyword1", "SF1234567890").
+		SetColor("keyword2", "顺丰速运", "#173177").
+		Set("keyword3", "2026-10-16").
+		Remark("点击查看物流详情", "#999999")
+
+	_, err := svc.SendTemplateMessage(&vwxmp.TemplateMessageRequest{ToUser: "openid", TemplateID: "tmpl-1", Data: data})
+	assert.NoError(t, err)
+
+	requests := server.Requests("/cgi-bin/message/template/send")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), `"keyword1":{"value":"SF1234567890","color":"#FF0000"}`)
+	assert.Contains(t, string(requests[0].Body), `"keyword3":{"value":"2026-10-16"}`)
+
+	// malformed data never reaches the api
+	for _, data := range []vwxmp.TemplateData{
+		vwxmp.NewTemplateData(),
+		vwxmp.NewTemplateData().SetColor("keyword1", "value", "red"),
+		vwxmp.NewTemplateData().SetColor("keyword1", "value", "#12345"),
+		vwxmp.NewTemplateData().Set("keyword1", strings.Repeat("长", vwxmp.MaxTemplateValueLength+1)),
+		vwxmp.NewTemplateData().Set("key word", "value"),
+	} {
+		_, err = svc.SendTemplateMessage(&vwxmp.TemplateMessageRequest{ToUser: "openid", TemplateID: "tmpl-1", Data: data})
+		assert.Error(t, err)
+	}
+	assert.Len(t, server.Requests("/cgi-bin/message/template/send"), 1)
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	templateMessageSendURL = "https://api.weixin.qq.com/cgi-bin/message/template/send?access_token=%s"
+)
+
+// TemplateMiniProgram represents the mini program to jump to from a template message.
+type TemplateMiniProgram struct {
+	AppID    string `json:"appid"`              // 所需跳转到的小程序appid
+	PagePath string `json:"pagepath,omitempty"` // 所需跳转到小程序的具体页面路径
+}
+
+// TemplateDataItem represents a data item of a template message.
+type TemplateDataItem struct {
+	Value string `json:"value"`           // 模板内容字段值
+	Color string `json:"color,omitempty"` // 模板内容字体颜色，如 #173177，不填默认为黑色
+}
+
+// TemplateMessageRequest represents the request of sending a template message.
+type TemplateMessageRequest struct {
+	ToUser      string                       `json:"touser"`                  // 接收者openid
+	TemplateID  string                       `json:"template_id"`             // 模板ID
+	URL         string                       `json:"url,omitempty"`           // 模板跳转链接
+	MiniProgram *TemplateMiniProgram         `json:"miniprogram,omitempty"`   // 跳小程序所需数据
+	Data        map[string]*TemplateDataItem `json:"data"`                    // 模板数据
+	ClientMsgID string                       `json:"client_msg_id,omitempty"` // 防重入id，对于同一个openid + client_msg_id, 只发送一条消息
+}
+
+// TemplateMessageResponse represents the response from sending a template message.
+type TemplateMessageResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	MsgID   int64  `json:"msgid"` // 消息id，用于匹配推送的发送结果事件
+}
+
+// SendTemplateMessage sends a template message to the user, the data is validated before sending,
+// see TemplateData.Validate.
+func (s *Service) SendTemplateMessage(req *TemplateMessageRequest) (*TemplateMessageResponse, error) {
+	return s.SendTemplateMessageContext(context.Background(), req)
+}
+
+// SendTemplateMessageContext is SendTemplateMessage with the given context.
+func (s *Service) SendTemplateMessageContext(ctx context.Context, req *TemplateMessageRequest) (*TemplateMessageResponse, error) {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	if err := TemplateData(req.Data).Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request error: %w", err)
+	}
+
+	if s.client.DryRun {
+		s.client.Logger.Infof("dry run, skip send template message | request_id: %s | req: %s", requestID, data)
+		return &TemplateMessageResponse{ErrCode: 0, ErrMsg: "ok"}, nil
+	}
+
+	result, err := wxapi.PostJSON[TemplateMessageResponse](ctx, s.call("send template message", templateMessageSendURL), req)
+	if err != nil {
+		return result, err
+	}
+
+	s.client.Logger.Infof("send template message | request_id: %s | msgid: %d", requestID, result.MsgID)
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"time"
+
+	"github.com/vogo/vwx/internal/tmpl"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getAllPrivateTemplatesURL = "https://api.weixin.qq.com/cgi-bin/template/get_all_private_template?access_token=%s"
+
+	defaultTemplateCacheTTL = 10 * time.Minute
+)
+
+// PrivateTemplate represents a template message template added to the account.
+type PrivateTemplate struct {
+	TemplateID      string `json:"template_id"`      // 模板ID
+	Title           string `json:"title"`            // 模板标题
+	PrimaryIndustry string `json:"primary_industry"` // 模板所属行业的一级行业
+	DeputyIndustry  string `json:"deputy_industry"`  // 模板所属行业的二级行业
+	Content         string `json:"content"`          // 模板内容
+	Example         string `json:"example"`          // 模板示例
+}
+
+// Keywords returns the data keys (e.g. first, keyword1, thing2) declared in the template content.
+func (t *PrivateTemplate) Keywords() []string {
+	return tmpl.Keywords(t.Content)
+}
+
+// PrivateTemplateListResponse represents the response of the private template list API.
+type PrivateTemplateListResponse struct {
+	ErrCode      int                `json:"errcode"`
+	ErrMsg       string             `json:"errmsg"`
+	TemplateList []*PrivateTemplate `json:"template_list"`
+}
+
+// GetAllPrivateTemplates retrieves the template message templates added to the account.
+func (s *Service) GetAllPrivateTemplates() (*PrivateTemplateListResponse, error) {
+	return s.GetAllPrivateTemplatesContext(context.Background())
+}
+
+// GetAllPrivateTemplatesContext is GetAllPrivateTemplates with the given context.
+func (s *Service) GetAllPrivateTemplatesContext(ctx context.Context) (*PrivateTemplateListResponse, error) {
+	call := s.call("get all private templates", getAllPrivateTemplatesURL)
+	call.Idempotent = true
+
+	return wxapi.GetJSON[PrivateTemplateListResponse](ctx, call)
+}
+
+// TemplateValidationError describes the mismatches between a template message and its template.
+type TemplateValidationError = tmpl.ValidationError
+
+// TemplateMessageValidator verifies template message data against the live template definitions
+// before sending, the definitions are cached for TTL.
+type TemplateMessageValidator struct {
+	svc *Service
+	TTL time.Duration
+
+	cache tmpl.Cache[*PrivateTemplate]
+}
+
+// NewTemplateMessageValidator creates a template message validator on top of the given service.
+func NewTemplateMessageValidator(svc *Service) *TemplateMessageValidator {
+	return &TemplateMessageValidator{
+		svc: svc,
+		TTL: defaultTemplateCacheTTL,
+	}
+}
+
+// Template returns the cached definition of the template, fetching the template list if the cache
+// is expired or the template is unknown. Templates still not found after a fetch are not fetched
+// again until the cache expires.
+func (v *TemplateMessageValidator) Template(templateID string) (*PrivateTemplate, error) {
+	return v.TemplateContext(context.Background(), templateID)
+}
+
+// TemplateContext is Template with the given context, concurrent lookups share a single fetch.
+func (v *TemplateMessageValidator) TemplateContext(ctx context.Context, templateID string) (*PrivateTemplate, error) {
+	return v.cache.Get(ctx, templateID, v.TTL, func(ctx context.Context) (map[string]*PrivateTemplate, error) {
+		resp, err := v.svc.GetAllPrivateTemplatesContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		templates := make(map[string]*PrivateTemplate, len(resp.TemplateList))
+		for _, t := range resp.TemplateList {
+			templates[t.TemplateID] = t
+		}
+
+		return templates, nil
+	})
+}
+
+// Validate verifies the message data matches the template keywords exactly and each value
+// satisfies the constraint of its keyword type.
+func (v *TemplateMessageValidator) Validate(req *TemplateMessageRequest) error {
+	return v.ValidateContext(context.Background(), req)
+}
+
+// ValidateContext is Validate with the given context.
+func (v *TemplateMessageValidator) ValidateContext(ctx context.Context, req *TemplateMessageRequest) error {
+	t, err := v.TemplateContext(ctx, req.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	return ValidateTemplateMessageData(t, req.Data)
+}
+
+// SendTemplateMessage validates the message and sends it if valid.
+func (v *TemplateMessageValidator) SendTemplateMessage(req *TemplateMessageRequest) (*TemplateMessageResponse, error) {
+	return v.SendTemplateMessageContext(context.Background(), req)
+}
+
+// SendTemplateMessageContext is SendTemplateMessage with the given context, the context applies to
+// the fetch of the template list as well.
+func (v *TemplateMessageValidator) SendTemplateMessageContext(ctx context.Context, req *TemplateMessageRequest) (*TemplateMessageResponse, error) {
+	if err := v.ValidateContext(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return v.svc.SendTemplateMessageContext(ctx, req)
+}
+
+// ValidateTemplateMessageData verifies the message data against the template definition. The typed
+// keywords (e.g. thing1, amount2) are checked like the subscribe messages, the classic first, remark
+// and keywordN only require a value.
+func ValidateTemplateMessageData(t *PrivateTemplate, data map[string]*TemplateDataItem) error {
+	return tmpl.Validate(t.TemplateID, t.Content, data, func(item *TemplateDataItem) string {
+		return item.Value
+	})
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestTemplateMessageValidator(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.SetResponse("/cgi-bin/template/get_all_private_template", map[string]any{
+		"template_list": []map[string]any{
+			{"template_id": "tmpl-classic", "title": "发货通知", "content": "{{first.DATA}}\n快递单号：{{keyword1.DATA}}\n{{remark.DATA}}"},
+			{"template_id": "tmpl-typed", "title": "订单支付成功", "content": "商品名称：{{thing1.DATA}}\n支付金额：{{amount2.DATA}}"},
+		},
+	})
+
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+	v := vwxmp.NewTemplateMessageValidator(svc)
+
+	tmpl, err := v.Template("tmpl-classic")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "keyword1", "remark"}, tmpl.Keywords())
+
+	_, err = v.SendTemplateMessage(&vwxmp.TemplateMessageRequest{
+		ToUser:     "openid",
+		TemplateID: "tmpl-classic",
+		Data:       vwxmp.NewTemplateData().First("您的订单已发货").Set("keyword1", "SF1234567890").Remark("点击查看物流详情"),
+	})
+	assert.NoError(t, err)
+
+	// mismatched data is rejected before sending
+	_, err = v.SendTemplateMessage(&vwxmp.TemplateMessageRequest{
+		ToUser:     "openid",
+		TemplateID: "tmpl-typed",
+		Data:       vwxmp.NewTemplateData().Set("thing1", "这是一个非常非常非常非常非常非常长的商品名称").Set("time3", "2026-10-16"),
+	})
+
+	var validationErr *vwxmp.TemplateValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"amount2"}, validationErr.Missing)
+	assert.Equal(t, []string{"time3"}, validationErr.Extra)
+	assert.Contains(t, validationErr.Invalid, "thing1")
+
+	_, err = v.Template("tmpl-unknown")
+	assert.Error(t, err)
+
+	assert.Len(t, server.Requests("/cgi-bin/message/template/send"), 1)
+	assert.Len(t, server.Requests("/cgi-bin/template/get_all_private_template"), 2)
+}
@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// oauthRefreshTokenTTL is the validity of the refresh token, the user must authorize again after it.
+	oauthRefreshTokenTTL = 30 * 24 * time.Hour
+
+	// oauthTokenRefreshBefore refreshes the access token a while before it expires.
+	oauthTokenRefreshBefore = 5 * time.Minute
+)
+
+var (
+	// ErrOAuthTokenStoreNotSet is returned when the service is created without WithOAuthTokenStore.
+	ErrOAuthTokenStoreNotSet = errors.New("oauth token store not set")
+	// ErrOAuthTokenNotFound is returned when no token is stored for the openid.
+	ErrOAuthTokenNotFound = errors.New("oauth token not found")
+	// ErrOAuthTokenExpired is returned when the refresh token is expired, the user must authorize again.
+	ErrOAuthTokenExpired = errors.New("oauth refresh token expired")
+)
+
+// OAuthToken is the OAuth token of a user kept in the token store.
+type OAuthToken struct {
+	OpenID           string            `json:"openid"`             // 用户唯一标识
+	UnionID          string            `json:"unionid"`            // 用户统一标识
+	Scope            string            `json:"scope"`              // 用户授权的作用域
+	AccessToken      string            `json:"access_token"`       // 网页授权接口调用凭证
+	RefreshToken     string            `json:"refresh_token"`      // 用户刷新access_token
+	ExpiresAt        time.Time         `json:"expires_at"`         // access_token过期时间
+	RefreshExpiresAt time.Time         `json:"refresh_expires_at"` // refresh_token过期时间
+	Profile          *UserInfoResponse `json:"profile,omitempty"`  // 用户资料，snsapi_userinfo升级授权后合并
+}
+
+// OAuthTokenStore persists the OAuth tokens of users by openid.
+type OAuthTokenStore interface {
+	// Load returns the token of the openid, or nil if absent.
+	Load(ctx context.Context, openID string) (*OAuthToken, error)
+	// Save stores the token by its openid.
+	Save(ctx context.Context, token *OAuthToken) error
+	// Delete removes the token of the openid.
+	Delete(ctx context.Context, openID string) error
+}
+
+// MemoryOAuthTokenStore is an in-memory OAuthTokenStore, suitable for a single instance.
+type MemoryOAuthTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*OAuthToken
+}
+
+// NewMemoryOAuthTokenStore creates an in-memory OAuth token store.
+func NewMemoryOAuthTokenStore() *MemoryOAuthTokenStore {
+	return &MemoryOAuthTokenStore{tokens: make(map[string]*OAuthToken)}
+}
+
+// Load implements OAuthTokenStore.
+func (m *MemoryOAuthTokenStore) Load(_ context.Context, openID string) (*OAuthToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	token, ok := m.tokens[openID]
+	if !ok {
+		return nil, nil
+	}
+
+	t := *token
+	return &t, nil
+}
+
+// Save implements OAuthTokenStore.
+func (m *MemoryOAuthTokenStore) Save(_ context.Context, token *OAuthToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := *token
+	m.tokens[token.OpenID] = &t
+	return nil
+}
+
+// Delete implements OAuthTokenStore.
+func (m *MemoryOAuthTokenStore) Delete(_ context.Context, openID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tokens, openID)
+	return nil
+}
+
+// WithOAuthTokenStore enables caching the OAuth tokens of users in the store.
+func WithOAuthTokenStore(store OAuthTokenStore) func(*Service) {
+	return func(s *Service) {
+		s.tokenStore = store
+	}
+}
+
+// ExchangeOAuthToken exchanges the authorization code for the token of the user,
+// and saves it to the token store if set.
+func (s *Service) ExchangeOAuthToken(code string) (*OAuthToken, error) {
+	return s.ExchangeOAuthTokenContext(context.Background(), code)
+}
+
+// ExchangeOAuthTokenContext is ExchangeOAuthToken with the given context.
+func (s *Service) ExchangeOAuthTokenContext(ctx context.Context, code string) (*OAuthToken, error) {
+	resp, err := s.GetOAuthAccessTokenContext(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	token := newOAuthToken(resp, time.Now())
+
+	if s.tokenStore != nil {
+		if err := s.tokenStore.Save(ctx, token); err != nil {
+			return nil, fmt.Errorf("save oauth token error: %w", err)
+		}
+	}
+
+	return token, nil
+}
+
+func newOAuthToken(resp *OAuthAccessTokenResponse, now time.Time) *OAuthToken {
+	return &OAuthToken{
+		OpenID:           resp.OpenID,
+		UnionID:          resp.UnionID,
+		Scope:            resp.Scope,
+		AccessToken:      resp.AccessToken,
+		RefreshToken:     resp.RefreshToken,
+		ExpiresAt:        now.Add(time.Duration(resp.ExpiresIn) * time.Second),
+		RefreshExpiresAt: now.Add(oauthRefreshTokenTTL),
+	}
+}
+
+// GetOAuthToken returns the stored token of the user, refreshing it via
+// RefreshOAuthAccessToken if the access token is (about to be) expired.
+func (s *Service) GetOAuthToken(openID string) (*OAuthToken, error) {
+	return s.GetOAuthTokenContext(context.Background(), openID)
+}
+
+// GetOAuthTokenContext is GetOAuthToken with the given context.
+func (s *Service) GetOAuthTokenContext(ctx context.Context, openID string) (*OAuthToken, error) {
+	if s.tokenStore == nil {
+		return nil, ErrOAuthTokenStoreNotSet
+	}
+
+	token, err := s.tokenStore.Load(ctx, openID)
+	if err != nil {
+		return nil, fmt.Errorf("load oauth token error: %w", err)
+	}
+
+	if token == nil {
+		return nil, ErrOAuthTokenNotFound
+	}
+
+	now := time.Now()
+	if now.Add(oauthTokenRefreshBefore).Before(token.ExpiresAt) {
+		return token, nil
+	}
+
+	if !now.Before(token.RefreshExpiresAt) {
+		if err := s.tokenStore.Delete(ctx, openID); err != nil {
+			s.client.Logger.Errorf("failed to delete expired oauth token | openid: %s | err: %v", openID, err)
+		}
+		return nil, ErrOAuthTokenExpired
+	}
+
+	resp, err := s.RefreshOAuthAccessTokenContext(ctx, token.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("refresh oauth token error: %w", err)
+	}
+
+	token.AccessToken = resp.AccessToken
+	token.ExpiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	if resp.RefreshToken != "" {
+		token.RefreshToken = resp.RefreshToken
+	}
+	if resp.Scope != "" {
+		token.Scope = resp.Scope
+	}
+
+	if err := s.tokenStore.Save(ctx, token); err != nil {
+		return nil, fmt.Errorf("save oauth token error: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetUserInfoByOpenID retrieves the profile of the user with the stored token,
+// the user must have authorized with snsapi_userinfo scope.
+func (s *Service) GetUserInfoByOpenID(openID string, lang UserInfoLang) (*UserInfoResponse, error) {
+	return s.GetUserInfoByOpenIDContext(context.Background(), openID, lang)
+}
+
+// GetUserInfoByOpenIDContext is GetUserInfoByOpenID with the given context.
+func (s *Service) GetUserInfoByOpenIDContext(ctx context.Context, openID string, lang UserInfoLang) (*UserInfoResponse, error) {
+	token, err := s.GetOAuthTokenContext(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUserInfoContext(ctx, token.AccessToken, openID, lang)
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxmp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxmp"
+)
+
+func TestOAuthTokenStore(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	store := vwxmp.NewMemoryOAuthTokenStore()
+	svc := vwxmp.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)), vwxmp.WithOAuthTokenStore(store))
+
+	token, err := svc.ExchangeOAuthToken("code")
+	assert.NoError(t, err)
+	openID := vwxmock.OpenIDPrefix + "code"
+	assert.Equal(t, openID, token.OpenID)
+
+	info, err := svc.GetUserInfoByOpenID(openID, vwxmp.LangZhCN)
+	assert.NoError(t, err)
+	assert.Equal(t, "mock-"+openID, info.Nickname)
+	assert.Empty(t, server.Requests("/sns/oauth2/refresh_token"))
+
+	// expired access token is refreshed transparently
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.NoError(t, store.Save(context.Background(), token))
+
+	refreshed, err := svc.GetOAuthToken(openID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+	assert.True(t, refreshed.ExpiresAt.After(time.Now()))
+	assert.Len(t, server.Requests("/sns/oauth2/refresh_token"), 1)
+
+	// expired refresh token requires authorizing again
+	refreshed.ExpiresAt = time.Now().Add(-time.Minute)
+	refreshed.RefreshExpiresAt = time.Now().Add(-time.Minute)
+	assert.NoError(t, store.Save(context.Background(), refreshed))
+
+	_, err = svc.GetOAuthToken(openID)
+	assert.ErrorIs(t, err, vwxmp.ErrOAuthTokenExpired)
+
+	_, err = svc.GetOAuthToken(openID)
+	assert.ErrorIs(t, err, vwxmp.ErrOAuthTokenNotFound)
+
+	_, err = vwxmp.NewService(vwx.NewClient("appid", "secret")).GetOAuthToken(openID)
+	assert.ErrorIs(t, err, vwxmp.ErrOAuthTokenStoreNotSet)
+}
@@ -18,11 +18,10 @@
 package vwxmp
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 
-	"github.com/vogo/vogo/vlog"
+	"github.com/vogo/vwx/internal/wxapi"
 )
 
 const (
@@ -58,32 +57,28 @@ type UserInfoResponse struct {
 // openID: user's openid
 // lang: language for response (zh_CN, zh_TW, en)
 func (s *Service) GetUserInfo(accessToken, openID string, lang UserInfoLang) (*UserInfoResponse, error) {
-	vlog.Infof("get user info | openid: %s | lang: %s", openID, lang)
+	return s.GetUserInfoContext(context.Background(), accessToken, openID, lang)
+}
 
+// GetUserInfoContext is GetUserInfo with the given context.
+func (s *Service) GetUserInfoContext(ctx context.Context, accessToken, openID string, lang UserInfoLang) (*UserInfoResponse, error) {
 	if lang == "" {
 		lang = LangZhCN
 	}
 
-	requestURL := fmt.Sprintf(userInfoURL, accessToken, openID, lang)
-
-	resp, err := http.Get(requestURL)
-	if err != nil {
-		return nil, err
+	call := &wxapi.Call{
+		Client:     s.client,
+		Name:       "get user info",
+		URL:        userInfoURL,
+		Args:       []any{accessToken, openID, lang},
+		Summary:    fmt.Sprintf("openid: %s | lang: %s", openID, lang),
+		Idempotent: true,
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			vlog.Errorf("failed to close response body | err: %v", closeErr)
-		}
-	}()
 
-	var result UserInfoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	result, err := wxapi.GetJSON[UserInfoResponse](ctx, call)
+	if err != nil {
 		return nil, err
 	}
 
-	if result.ErrCode != 0 {
-		return nil, fmt.Errorf("wechat error: %d %s", result.ErrCode, result.ErrMsg)
-	}
-
-	return &result, nil
+	return result, nil
 }
@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	authorizerListURL      = "https://api.weixin.qq.com/cgi-bin/component/api_get_authorizer_list?component_access_token=%s"
+	authorizerOptionGetURL = "https://api.weixin.qq.com/cgi-bin/component/api_get_authorizer_option?component_access_token=%s"
+	authorizerOptionSetURL = "https://api.weixin.qq.com/cgi-bin/component/api_set_authorizer_option?component_access_token=%s"
+
+	// MaxAuthorizerListCount is the max count of authorizers listed by a single call.
+	MaxAuthorizerListCount = 500
+)
+
+// AuthorizerOptionName is the name of an option of the authorizer account.
+type AuthorizerOptionName string
+
+const (
+	// OptionLocationReport is the location report option, 0: disabled, 1: report on entering the session, 2: report every 5 seconds.
+	OptionLocationReport AuthorizerOptionName = "location_report"
+	// OptionVoiceRecognize is the voice recognition option, 0: disabled, 1: enabled.
+	OptionVoiceRecognize AuthorizerOptionName = "voice_recognize"
+	// OptionCustomerService is the multi customer service option, 0: disabled, 1: enabled.
+	OptionCustomerService AuthorizerOptionName = "customer_service"
+)
+
+// AuthorizerListRequest represents the request of listing the authorized accounts.
+type AuthorizerListRequest struct {
+	ComponentAppID string `json:"component_appid"` // 第三方平台 appid
+	Offset         int    `json:"offset"`          // 偏移位置/起始位置
+	Count          int    `json:"count"`           // 拉取数量，最大为 500
+}
+
+// AuthorizerListItem is an authorized account.
+type AuthorizerListItem struct {
+	AuthorizerAppID string `json:"authorizer_appid"` // 已授权账号的 appid
+	RefreshToken    string `json:"refresh_token"`    // 刷新令牌 authorizer_refresh_token
+	AuthTime        int64  `json:"auth_time"`        // 授权的时间
+}
+
+// AuthorizerListResponse represents the response of listing the authorized accounts.
+type AuthorizerListResponse struct {
+	ErrCode    int                   `json:"errcode"`
+	ErrMsg     string                `json:"errmsg"`
+	TotalCount int                   `json:"total_count"` // 授权的账号总数
+	List       []*AuthorizerListItem `json:"list"`        // 当前查询的账号基本信息列表
+}
+
+// authorizerOptionRequest represents the request of getting or setting an authorizer option.
+type authorizerOptionRequest struct {
+	ComponentAppID  string               `json:"component_appid"`        // 第三方平台 appid
+	AuthorizerAppID string               `json:"authorizer_appid"`       // 授权账号的 appid
+	OptionName      AuthorizerOptionName `json:"option_name"`            // 选项名称
+	OptionValue     string               `json:"option_value,omitempty"` // 设置的选项值
+}
+
+// AuthorizerOptionResponse represents the response of getting an authorizer option.
+type AuthorizerOptionResponse struct {
+	ErrCode         int                  `json:"errcode"`
+	ErrMsg          string               `json:"errmsg"`
+	AuthorizerAppID string               `json:"authorizer_appid"` // 授权账号的 appid
+	OptionName      AuthorizerOptionName `json:"option_name"`      // 选项名称
+	OptionValue     string               `json:"option_value"`     // 选项值
+}
+
+// GetAuthorizerList lists the accounts authorized to the third-party platform, count is at most
+// MaxAuthorizerListCount.
+func (s *Service) GetAuthorizerList(offset, count int) (*AuthorizerListResponse, error) {
+	return s.GetAuthorizerListContext(context.Background(), offset, count)
+}
+
+// GetAuthorizerListContext is GetAuthorizerList with the given context.
+func (s *Service) GetAuthorizerListContext(ctx context.Context, offset, count int) (*AuthorizerListResponse, error) {
+	if count <= 0 || count > MaxAuthorizerListCount {
+		return nil, fmt.Errorf("authorizer list count must be in [1, %d], got %d", MaxAuthorizerListCount, count)
+	}
+
+	call := s.call("get authorizer list", authorizerListURL)
+	call.Idempotent = true
+	call.Summary = fmt.Sprintf("offset: %d | count: %d", offset, count)
+
+	result, err := wxapi.PostJSON[AuthorizerListResponse](ctx, call, &AuthorizerListRequest{
+		ComponentAppID: s.client.AppID,
+		Offset:         offset,
+		Count:          count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetAllAuthorizers lists all the accounts authorized to the third-party platform page by page.
+func (s *Service) GetAllAuthorizers() ([]*AuthorizerListItem, error) {
+	return s.GetAllAuthorizersContext(context.Background())
+}
+
+// GetAllAuthorizersContext is GetAllAuthorizers with the given context.
+func (s *Service) GetAllAuthorizersContext(ctx context.Context) ([]*AuthorizerListItem, error) {
+	var authorizers []*AuthorizerListItem
+
+	for {
+		page, err := s.GetAuthorizerListContext(ctx, len(authorizers), MaxAuthorizerListCount)
+		if err != nil {
+			return authorizers, err
+		}
+
+		authorizers = append(authorizers, page.List...)
+
+		if len(page.List) == 0 || len(authorizers) >= page.TotalCount {
+			return authorizers, nil
+		}
+	}
+}
+
+// GetAuthorizerOption gets the option value of the authorized account.
+func (s *Service) GetAuthorizerOption(authorizerAppID string, name AuthorizerOptionName) (*AuthorizerOptionResponse, error) {
+	return s.GetAuthorizerOptionContext(context.Background(), authorizerAppID, name)
+}
+
+// GetAuthorizerOptionContext is GetAuthorizerOption with the given context.
+func (s *Service) GetAuthorizerOptionContext(ctx context.Context, authorizerAppID string, name AuthorizerOptionName) (*AuthorizerOptionResponse, error) {
+	call := s.call("get authorizer option", authorizerOptionGetURL)
+	call.Idempotent = true
+
+	result, err := wxapi.PostJSON[AuthorizerOptionResponse](ctx, call, &authorizerOptionRequest{
+		ComponentAppID:  s.client.AppID,
+		AuthorizerAppID: authorizerAppID,
+		OptionName:      name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetAuthorizerOption sets the option value of the authorized account.
+func (s *Service) SetAuthorizerOption(authorizerAppID string, name AuthorizerOptionName, value string) error {
+	return s.SetAuthorizerOptionContext(context.Background(), authorizerAppID, name, value)
+}
+
+// SetAuthorizerOptionContext is SetAuthorizerOption with the given context.
+func (s *Service) SetAuthorizerOptionContext(ctx context.Context, authorizerAppID string, name AuthorizerOptionName, value string) error {
+	call := s.call("set authorizer option", authorizerOptionSetURL)
+	call.Idempotent = true
+
+	_, err := wxapi.PostJSON[struct{}](ctx, call, &authorizerOptionRequest{
+		ComponentAppID:  s.client.AppID,
+		AuthorizerAppID: authorizerAppID,
+		OptionName:      name,
+		OptionValue:     value,
+	})
+
+	return err
+}
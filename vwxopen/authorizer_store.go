@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Authorizer is an account authorized to the third-party platform, kept in the authorizer store
+// from the authorization events.
+type Authorizer struct {
+	AppID                      string    `json:"appid"`                         // 公众号或小程序的 appid
+	AuthorizationCode          string    `json:"authorization_code"`            // 授权码，可用于获取授权信息
+	AuthorizationCodeExpiresAt time.Time `json:"authorization_code_expires_at"` // 授权码过期时间
+	PreAuthCode                string    `json:"pre_auth_code"`                 // 预授权码
+	AuthorizedAt               time.Time `json:"authorized_at"`                 // 最近一次授权或授权更新的时间
+}
+
+// AuthorizerStore persists the accounts authorized to the third-party platform by appid.
+type AuthorizerStore interface {
+	// Load returns the authorizer of the appid, or nil if absent.
+	Load(ctx context.Context, appID string) (*Authorizer, error)
+	// Save stores the authorizer by its appid.
+	Save(ctx context.Context, authorizer *Authorizer) error
+	// Delete removes the authorizer of the appid.
+	Delete(ctx context.Context, appID string) error
+}
+
+// MemoryAuthorizerStore is an in-memory AuthorizerStore, suitable for a single instance.
+type MemoryAuthorizerStore struct {
+	mu          sync.RWMutex
+	authorizers map[string]*Authorizer
+}
+
+// NewMemoryAuthorizerStore creates an in-memory authorizer store.
+func NewMemoryAuthorizerStore() *MemoryAuthorizerStore {
+	return &MemoryAuthorizerStore{authorizers: make(map[string]*Authorizer)}
+}
+
+// Load implements AuthorizerStore.
+func (m *MemoryAuthorizerStore) Load(_ context.Context, appID string) (*Authorizer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	authorizer, ok := m.authorizers[appID]
+	if !ok {
+		return nil, nil
+	}
+
+	a := *authorizer
+	return &a, nil
+}
+
+// Save implements AuthorizerStore.
+func (m *MemoryAuthorizerStore) Save(_ context.Context, authorizer *Authorizer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := *authorizer
+	m.authorizers[authorizer.AppID] = &a
+	return nil
+}
+
+// Delete implements AuthorizerStore.
+func (m *MemoryAuthorizerStore) Delete(_ context.Context, appID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.authorizers, appID)
+	return nil
+}
+
+// WithAuthorizerStore keeps the accounts authorized to the third-party platform in the store, they
+// are saved on the authorized and updateauthorized pushes and deleted on the unauthorized ones.
+func WithAuthorizerStore(store AuthorizerStore) func(*Service) {
+	return func(s *Service) {
+		s.authorizerStore = store
+	}
+}
+
+// storeAuthorizer saves or deletes the authorizer of the authorization push in the authorizer store if set.
+func (s *Service) storeAuthorizer(ctx context.Context, push *ComponentPush) error {
+	if s.authorizerStore == nil || push.AuthorizerAppID == "" {
+		return nil
+	}
+
+	switch push.InfoType {
+	case InfoTypeAuthorized, InfoTypeUpdateAuthorized:
+		return s.authorizerStore.Save(ctx, &Authorizer{
+			AppID:                      push.AuthorizerAppID,
+			AuthorizationCode:          push.AuthorizationCode,
+			AuthorizationCodeExpiresAt: time.Unix(push.AuthorizationCodeExpiredTime, 0),
+			PreAuthCode:                push.PreAuthCode,
+			AuthorizedAt:               time.Unix(push.CreateTime, 0),
+		})
+	case InfoTypeUnauthorized:
+		return s.authorizerStore.Delete(ctx, push.AuthorizerAppID)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxopen"
+)
+
+func TestAuthorizers(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("component-appid", "component-secret", vwxmock.WithServer(server))
+	svc := vwxopen.NewService(client)
+
+	_, err := svc.GetAuthorizerList(0, 10)
+	assert.ErrorIs(t, err, vwxopen.ErrComponentVerifyTicketMissing)
+
+	assert.NoError(t, svc.SetComponentVerifyTicket("ticket"))
+
+	appIDs := make([]string, 0, 501)
+	for i := range 501 {
+		appIDs = append(appIDs, fmt.Sprintf("wx%04d", i))
+	}
+	server.AddAuthorizers(appIDs...)
+
+	page, err := svc.GetAuthorizerList(500, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 501, page.TotalCount)
+	assert.Len(t, page.List, 1)
+	assert.Equal(t, vwxmock.AuthorizerRefreshTokenPrefix+"wx0500", page.List[0].RefreshToken)
+
+	_, err = svc.GetAuthorizerList(0, 501)
+	assert.Error(t, err)
+
+	authorizers, err := svc.GetAllAuthorizers()
+	assert.NoError(t, err)
+	assert.Len(t, authorizers, 501)
+	assert.Len(t, server.Requests("/cgi-bin/component/api_get_authorizer_list"), 3)
+
+	// the component access token is cached
+	assert.Len(t, server.Requests("/cgi-bin/component/api_component_token"), 1)
+	assert.Contains(t, string(server.Requests("/cgi-bin/component/api_component_token")[0].Body),
+		`"component_verify_ticket":"ticket"`)
+
+	assert.NoError(t, svc.SetAuthorizerOption("wx0001", vwxopen.OptionVoiceRecognize, "1"))
+
+	option, err := svc.GetAuthorizerOption("wx0001", vwxopen.OptionVoiceRecognize)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", option.OptionValue)
+
+	option, err = svc.GetAuthorizerOption("wx0002", vwxopen.OptionVoiceRecognize)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", option.OptionValue)
+}
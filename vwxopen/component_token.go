@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	componentTokenURL = "https://api.weixin.qq.com/cgi-bin/component/api_component_token"
+
+	// componentVerifyTicketExpire is the validity of the component verify ticket, WeChat pushes
+	// a new one every 10 minutes.
+	componentVerifyTicketExpire = 12 * time.Hour
+)
+
+// ErrComponentVerifyTicketMissing is returned when the component access token is requested before
+// the component verify ticket pushed by WeChat is set.
+var ErrComponentVerifyTicketMissing = errors.New("component verify ticket is missing, it is pushed by wechat every 10 minutes")
+
+// componentTokenRequest represents the request of the api_component_token endpoint.
+type componentTokenRequest struct {
+	ComponentAppID        string `json:"component_appid"`         // 第三方平台 appid
+	ComponentAppSecret    string `json:"component_appsecret"`     // 第三方平台 appsecret
+	ComponentVerifyTicket string `json:"component_verify_ticket"` // 微信后台推送的 ticket
+}
+
+// componentTokenResponse represents the response of the api_component_token endpoint.
+type componentTokenResponse struct {
+	ErrCode              int    `json:"errcode"`
+	ErrMsg               string `json:"errmsg"`
+	ComponentAccessToken string `json:"component_access_token"` // 第三方平台 access_token
+	ExpiresIn            int    `json:"expires_in"`             // 有效期，单位：秒
+}
+
+func (s *Service) cacheKeyComponentVerifyTicket() string {
+	return s.client.CacheKeyPrefix + "vwxopen:component_verify_ticket:" + s.client.AppID
+}
+
+func (s *Service) cacheKeyComponentAccessToken() string {
+	return s.client.CacheKeyPrefix + "vwxopen:component_access_token:" + s.client.AppID
+}
+
+// SetComponentVerifyTicket caches the component verify ticket pushed by WeChat, it is required to
+// request the component access token.
+func (s *Service) SetComponentVerifyTicket(ticket string) error {
+	return s.SetComponentVerifyTicketContext(context.Background(), ticket)
+}
+
+// SetComponentVerifyTicketContext is SetComponentVerifyTicket with the given context.
+func (s *Service) SetComponentVerifyTicketContext(ctx context.Context, ticket string) error {
+	return s.client.CacheProvider.Set(ctx, s.cacheKeyComponentVerifyTicket(), ticket, componentVerifyTicketExpire)
+}
+
+// GetComponentAccessToken retrieves the component access token with caching support.
+func (s *Service) GetComponentAccessToken() (string, error) {
+	return s.GetComponentAccessTokenContext(context.Background())
+}
+
+// GetComponentAccessTokenContext is GetComponentAccessToken with the given context.
+func (s *Service) GetComponentAccessTokenContext(ctx context.Context) (string, error) {
+	if s.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return s.componentTokens.Get(ctx)
+}
+
+// requestComponentAccessToken requests a new component access token from WeChat.
+func (s *Service) requestComponentAccessToken(ctx context.Context, _ bool) (string, int, error) {
+	ticket := s.client.CacheProvider.Get(ctx, s.cacheKeyComponentVerifyTicket())
+	if ticket == "" {
+		return "", 0, ErrComponentVerifyTicketMissing
+	}
+
+	call := &wxapi.Call{
+		Client:     s.client,
+		Name:       "get component access token",
+		URL:        componentTokenURL,
+		Idempotent: true,
+		Summary:    "component_appid: " + s.client.AppID,
+	}
+
+	result, err := wxapi.PostJSON[componentTokenResponse](ctx, call, &componentTokenRequest{
+		ComponentAppID:        s.client.AppID,
+		ComponentAppSecret:    s.client.AppSecret,
+		ComponentVerifyTicket: ticket,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	return result.ComponentAccessToken, result.ExpiresIn, nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen
+
+import (
+	"context"
+)
+
+// ComponentTokenProvider provides the component access token of the third-party platform.
+type ComponentTokenProvider interface {
+	SetComponentVerifyTicket(ticket string) error
+	SetComponentVerifyTicketContext(ctx context.Context, ticket string) error
+	GetComponentAccessToken() (string, error)
+	GetComponentAccessTokenContext(ctx context.Context) (string, error)
+}
+
+// AuthorizerManager enumerates and configures the accounts authorized to the third-party platform.
+type AuthorizerManager interface {
+	GetAuthorizerList(offset, count int) (*AuthorizerListResponse, error)
+	GetAuthorizerListContext(ctx context.Context, offset, count int) (*AuthorizerListResponse, error)
+	GetAllAuthorizers() ([]*AuthorizerListItem, error)
+	GetAllAuthorizersContext(ctx context.Context) ([]*AuthorizerListItem, error)
+	GetAuthorizerOption(authorizerAppID string, name AuthorizerOptionName) (*AuthorizerOptionResponse, error)
+	GetAuthorizerOptionContext(ctx context.Context, authorizerAppID string, name AuthorizerOptionName) (*AuthorizerOptionResponse, error)
+	SetAuthorizerOption(authorizerAppID string, name AuthorizerOptionName, value string) error
+	SetAuthorizerOptionContext(ctx context.Context, authorizerAppID string, name AuthorizerOptionName, value string) error
+}
+
+var (
+	_ ComponentTokenProvider = (*Service)(nil)
+	_ AuthorizerManager      = (*Service)(nil)
+)
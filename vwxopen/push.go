@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+// Info types of the pushes to the authorization event url of the third-party platform.
+const (
+	InfoTypeComponentVerifyTicket = "component_verify_ticket" // 验证票据，每隔10分钟推送一次
+	InfoTypeAuthorized            = "authorized"              // 授权成功
+	InfoTypeUpdateAuthorized      = "updateauthorized"        // 授权更新
+	InfoTypeUnauthorized          = "unauthorized"            // 取消授权
+)
+
+// ComponentPush represents a push to the authorization event url of the third-party platform.
+type ComponentPush struct {
+	AppID                        string `xml:"AppId" json:"AppId"`                                               // 第三方平台 appid
+	CreateTime                   int64  `xml:"CreateTime" json:"CreateTime"`                                     // 时间戳，单位：秒
+	InfoType                     string `xml:"InfoType" json:"InfoType"`                                         // 通知类型
+	ComponentVerifyTicket        string `xml:"ComponentVerifyTicket" json:"ComponentVerifyTicket"`               // 验证票据，仅 component_verify_ticket
+	AuthorizerAppID              string `xml:"AuthorizerAppid" json:"AuthorizerAppid"`                           // 公众号或小程序的 appid，仅授权事件
+	AuthorizationCode            string `xml:"AuthorizationCode" json:"AuthorizationCode"`                       // 授权码，可用于获取授权信息
+	AuthorizationCodeExpiredTime int64  `xml:"AuthorizationCodeExpiredTime" json:"AuthorizationCodeExpiredTime"` // 授权码过期时间，单位：秒
+	PreAuthCode                  string `xml:"PreAuthCode" json:"PreAuthCode"`                                   // 预授权码
+}
+
+// ParseComponentPush parses the decrypted xml push to the authorization event url.
+func ParseComponentPush(data []byte) (*ComponentPush, error) {
+	return ParseComponentPushOf("xml", data)
+}
+
+// ParseComponentPushOf parses the decrypted push to the authorization event url in the given data
+// type (xml or json), like vwxpush.ParseBaseInfo.
+func ParseComponentPushOf(dataType string, data []byte) (*ComponentPush, error) {
+	if len(data) > vwxpush.MaxPushBodySize {
+		return nil, fmt.Errorf("component push too large")
+	}
+
+	unmarshal := xml.Unmarshal
+	if dataType == "json" {
+		unmarshal = json.Unmarshal
+	}
+
+	var push ComponentPush
+	if err := unmarshal(data, &push); err != nil {
+		return nil, fmt.Errorf("unmarshal component push error: %w", err)
+	}
+
+	return &push, nil
+}
+
+// NewComponentReceiver creates the receiver of the pushes to the authorization event url, decrypting
+// them in secure mode with the component appid of the client.
+func (s *Service) NewComponentReceiver(token, encodingAESKey string, options ...func(*vwxpush.WxPushReceiver)) *vwxpush.WxPushReceiver {
+	return vwxpush.NewWxPushReceiver(s.client.AppID, token, encodingAESKey, "secure", "xml", options...)
+}
+
+// HandleComponentPushes registers the ComponentEventHandler of the handler on the dispatcher for
+// the verify ticket and authorization pushes, so that the authorization event url shares the
+// dispatcher of the receiver with the other handlers.
+func (s *Service) HandleComponentPushes(d *vwxpush.Dispatcher, handler vwx.EventHandler) {
+	componentHandler := s.ComponentEventHandler(handler)
+
+	for _, infoType := range []string{InfoTypeComponentVerifyTicket, InfoTypeAuthorized, InfoTypeUpdateAuthorized, InfoTypeUnauthorized} {
+		d.HandleInfoType(infoType, componentHandler)
+	}
+}
+
+// ComponentEventHandler returns the handler of the pushes to the authorization event url, decrypted by
+// a vwxpush.WxPushReceiver of the component appid and parsed in the data type of the receiver. The
+// component verify tickets are cached for the component access token, the authorizers are kept in
+// the authorizer store if set, then the pushes are emitted to the handler if not nil as events of
+// the payload *ComponentPush.
+func (s *Service) ComponentEventHandler(handler vwx.EventHandler) vwxpush.MessageHandler {
+	return func(appID string, baseInfo *vwxpush.PushBaseInfo, data []byte) ([]byte, error) {
+		push, err := ParseComponentPushOf(baseInfo.DataType, data)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx := baseInfo.Context(context.Background())
+
+		if push.InfoType == InfoTypeComponentVerifyTicket {
+			if err := s.SetComponentVerifyTicketContext(ctx, push.ComponentVerifyTicket); err != nil {
+				return nil, fmt.Errorf("cache component verify ticket error: %w", err)
+			}
+		}
+
+		if err := s.storeAuthorizer(ctx, push); err != nil {
+			return nil, fmt.Errorf("store authorizer %s error: %w", push.AuthorizerAppID, err)
+		}
+
+		if handler == nil {
+			return nil, nil
+		}
+
+		if push.AppID != "" {
+			appID = push.AppID
+		}
+
+		return nil, handler(ctx, &vwx.Event{
+			Source:     vwx.EventSourceOpen,
+			AppID:      appID,
+			Kind:       push.InfoType,
+			CreateTime: time.Unix(push.CreateTime, 0),
+			RequestID:  baseInfo.RequestID,
+			Payload:    push,
+			Raw:        data,
+		})
+	}
+}
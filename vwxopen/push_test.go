@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopen_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxopen"
+	"github.com/vogo/vwx/vwxpush"
+)
+
+func TestComponentEvents(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("component-appid", "component-secret", vwxmock.WithServer(server))
+	svc := vwxopen.NewService(client)
+
+	events := make(chan *vwx.Event, 2)
+	handler := svc.ComponentEventHandler(vwx.ChanEventHandler(events))
+
+	ticket := []byte(`<xml><AppId><![CDATA[component-appid]]></AppId><CreateTime>1413192605</CreateTime>
+<InfoType><![CDATA[component_verify_ticket]]></InfoType><ComponentVerifyTicket><![CDATA[ticket]]></ComponentVerifyTicket></xml>`)
+
+	_, err := handler("component-appid", &vwxpush.PushBaseInfo{RequestID: "rid"}, ticket)
+	assert.NoError(t, err)
+
+	// the pushed ticket is used to request the component access token
+	token, err := svc.GetComponentAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.ComponentAccessToken, token)
+
+	authorized := []byte(`<xml><AppId><![CDATA[component-appid]]></AppId><CreateTime>1413192760</CreateTime>
+<InfoType><![CDATA[authorized]]></InfoType><AuthorizerAppid><![CDATA[wx0001]]></AuthorizerAppid>
+<AuthorizationCode><![CDATA[code]]></AuthorizationCode><AuthorizationCodeExpiredTime>1413196360</AuthorizationCodeExpiredTime></xml>`)
+
+	_, err = handler("component-appid", &vwxpush.PushBaseInfo{}, authorized)
+	assert.NoError(t, err)
+
+	event := <-events
+	assert.Equal(t, vwx.EventSourceOpen, event.Source)
+	assert.Equal(t, vwxopen.InfoTypeComponentVerifyTicket, event.Kind)
+	assert.Equal(t, "rid", event.RequestID)
+
+	event = <-events
+	assert.Equal(t, vwxopen.InfoTypeAuthorized, event.Kind)
+	assert.Equal(t, "wx0001", event.Payload.(*vwxopen.ComponentPush).AuthorizerAppID)
+
+	_, err = svc.ComponentEventHandler(nil)("component-appid", &vwxpush.PushBaseInfo{}, ticket)
+	assert.NoError(t, err)
+}
+
+func TestComponentPushDispatch(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	store := vwxopen.NewMemoryAuthorizerStore()
+	client := vwx.NewClient("component-appid", "component-secret", vwxmock.WithServer(server))
+	svc := vwxopen.NewService(client, vwxopen.WithAuthorizerStore(store))
+
+	const (
+		token          = "component-token"
+		encodingAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	)
+
+	receiver := svc.NewComponentReceiver(token, encodingAESKey)
+
+	events := make(chan *vwx.Event, 4)
+	dispatcher := vwxpush.NewDispatcher()
+	svc.HandleComponentPushes(dispatcher, vwx.ChanEventHandler(events))
+
+	push := func(message string) {
+		encrypted, err := vwxpush.EncryptMessage(encodingAESKey, "component-appid", []byte(message), nil)
+		assert.NoError(t, err)
+
+		timestamp, nonce := "1413192605", "nonce"
+		sign := func(parts ...string) string {
+			sort.Strings(parts)
+			return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(parts, ""))))
+		}
+
+		query := url.Values{
+			"signature":     {sign(token, timestamp, nonce)},
+			"timestamp":     {timestamp},
+			"nonce":         {nonce},
+			"encrypt_type":  {"aes"},
+			"msg_signature": {sign(token, timestamp, nonce, encrypted)},
+		}
+		body := "<xml><ToUserName><![CDATA[component-appid]]></ToUserName><Encrypt><![CDATA[" + encrypted + "]]></Encrypt></xml>"
+
+		_, err = receiver.HandlePushMessage(query.Get, []byte(body), dispatcher.Dispatch)
+		assert.NoError(t, err)
+	}
+
+	push(`<xml><AppId><![CDATA[component-appid]]></AppId><CreateTime>1413192605</CreateTime>
+<InfoType><![CDATA[component_verify_ticket]]></InfoType><ComponentVerifyTicket><![CDATA[ticket]]></ComponentVerifyTicket></xml>`)
+
+	accessToken, err := svc.GetComponentAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.ComponentAccessToken, accessToken)
+
+	push(`<xml><AppId><![CDATA[component-appid]]></AppId><CreateTime>1413192760</CreateTime>
+<InfoType><![CDATA[authorized]]></InfoType><AuthorizerAppid><![CDATA[wx0001]]></AuthorizerAppid>
+<AuthorizationCode><![CDATA[code]]></AuthorizationCode><AuthorizationCodeExpiredTime>1413196360</AuthorizationCodeExpiredTime></xml>`)
+
+	authorizer, err := store.Load(context.Background(), "wx0001")
+	assert.NoError(t, err)
+	assert.Equal(t, "code", authorizer.AuthorizationCode)
+	assert.Equal(t, int64(1413196360), authorizer.AuthorizationCodeExpiresAt.Unix())
+	assert.Equal(t, int64(1413192760), authorizer.AuthorizedAt.Unix())
+
+	push(`<xml><AppId><![CDATA[component-appid]]></AppId><CreateTime>1413192860</CreateTime>
+<InfoType><![CDATA[unauthorized]]></InfoType><AuthorizerAppid><![CDATA[wx0001]]></AuthorizerAppid></xml>`)
+
+	authorizer, err = store.Load(context.Background(), "wx0001")
+	assert.NoError(t, err)
+	assert.Nil(t, authorizer)
+
+	for _, kind := range []string{vwxopen.InfoTypeComponentVerifyTicket, vwxopen.InfoTypeAuthorized, vwxopen.InfoTypeUnauthorized} {
+		event := <-events
+		assert.Equal(t, kind, event.Kind)
+		assert.Equal(t, "component-appid", event.AppID)
+		assert.NotEmpty(t, event.RequestID)
+	}
+
+	// json pushes are parsed in the data type of the receiver
+	handler := svc.ComponentEventHandler(vwx.ChanEventHandler(events))
+	_, err = handler("component-appid", &vwxpush.PushBaseInfo{DataType: "json"},
+		[]byte(`{"AppId":"component-appid","CreateTime":1413192960,"InfoType":"updateauthorized","AuthorizerAppid":"wx0002","AuthorizationCode":"code2"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "wx0002", (<-events).Payload.(*vwxopen.ComponentPush).AuthorizerAppID)
+
+	authorizer, _ = store.Load(context.Background(), "wx0002")
+	assert.Equal(t, "code2", authorizer.AuthorizationCode)
+}
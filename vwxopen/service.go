@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxopen provides WeChat Open Platform third-party platform (component) API client
+// functionality, the client is created with the component appid and appsecret.
+package vwxopen
+
+import (
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/token"
+)
+
+// Service provides WeChat third-party platform API operations.
+type Service struct {
+	client          *vwx.Client
+	componentTokens *token.Manager
+	authorizerStore AuthorizerStore
+}
+
+// NewService creates a new WeChat third-party platform service.
+func NewService(client *vwx.Client, options ...func(*Service)) *Service {
+	s := &Service{client: client}
+
+	// the component verify ticket pushed by WeChat is kept in the cache
+	if client.CacheProvider == nil {
+		client.CacheProvider = vwx.NewMemoryCache()
+	}
+
+	s.componentTokens = &token.Manager{
+		Client:   client,
+		Name:     "component access token",
+		CacheKey: s.cacheKeyComponentAccessToken(),
+		Fetch:    s.requestComponentAccessToken,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopenapi
+
+import (
+	"context"
+)
+
+// QuotaManager manages the api quota of the app and looks up the failed calls.
+type QuotaManager interface {
+	ClearQuota() error
+	ClearQuotaContext(ctx context.Context) error
+	GetQuota(cgiPath string) (*QuotaResponse, error)
+	GetQuotaContext(ctx context.Context, cgiPath string) (*QuotaResponse, error)
+	GetRID(rid string) (*RIDResponse, error)
+	GetRIDContext(ctx context.Context, rid string) (*RIDResponse, error)
+	GetAPIErrorDetail(err error) (*RIDRequest, error)
+	GetAPIErrorDetailContext(ctx context.Context, err error) (*RIDRequest, error)
+}
+
+// NetworkChecker tells the ips of the WeChat servers and checks the network of the callback url.
+type NetworkChecker interface {
+	GetCallbackIPList() ([]string, error)
+	GetCallbackIPListContext(ctx context.Context) ([]string, error)
+	GetAPIDomainIPList() ([]string, error)
+	GetAPIDomainIPListContext(ctx context.Context) ([]string, error)
+	CheckCallback(action, checkOperator string) (*CallbackCheckResponse, error)
+	CheckCallbackContext(ctx context.Context, action, checkOperator string) (*CallbackCheckResponse, error)
+}
+
+var (
+	_ QuotaManager   = (*Service)(nil)
+	_ NetworkChecker = (*Service)(nil)
+)
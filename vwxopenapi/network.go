@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopenapi
+
+import (
+	"context"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	getCallbackIPURL  = "https://api.weixin.qq.com/cgi-bin/getcallbackip?access_token=%s"
+	getAPIDomainIPURL = "https://api.weixin.qq.com/cgi-bin/get_api_domain_ip?access_token=%s"
+	callbackCheckURL  = "https://api.weixin.qq.com/cgi-bin/callback/check?access_token=%s"
+)
+
+// Actions of the callback check.
+const (
+	CallbackCheckAll  = "all"  // 同时检测dns和ping
+	CallbackCheckDNS  = "dns"  // 只检测dns
+	CallbackCheckPing = "ping" // 只检测ping
+)
+
+// Operators checked by the callback check.
+const (
+	CheckOperatorDefault  = "DEFAULT"  // 根据ip来选择运营商
+	CheckOperatorChinaNet = "CHINANET" // 电信出口
+	CheckOperatorUnicom   = "UNICOM"   // 联通出口
+	CheckOperatorCAP      = "CAP"      // 腾讯自建出口
+)
+
+// IPListResponse represents the response of querying the ip list of the WeChat servers.
+type IPListResponse struct {
+	ErrCode int      `json:"errcode"`
+	ErrMsg  string   `json:"errmsg"`
+	IPList  []string `json:"ip_list"` // 微信服务器IP地址列表
+}
+
+// CallbackCheckRequest represents the request of checking the network of the callback url.
+type CallbackCheckRequest struct {
+	Action        string `json:"action"`         // 检测动作：all、dns、ping
+	CheckOperator string `json:"check_operator"` // 指定平台从某个运营商进行检测：DEFAULT、CHINANET、UNICOM、CAP
+}
+
+// CallbackDNS is the dns resolution of the callback domain.
+type CallbackDNS struct {
+	IP           string `json:"ip"`            // 解析出来的ip
+	RealOperator string `json:"real_operator"` // ip对应的运营商
+}
+
+// CallbackPing is the ping result of the callback ip.
+type CallbackPing struct {
+	IP           string `json:"ip"`            // ping的ip，执行命令为ping ip –c 1-w 1 -q
+	FromOperator string `json:"from_operator"` // ping的源头的运营商，由请求中的check_operator控制
+	PackageLoss  string `json:"package_loss"`  // ping的丢包率，0%表示无丢包，100%表示全部丢包
+	Time         string `json:"time"`          // ping的耗时，取ping结果的avg耗时
+}
+
+// CallbackCheckResponse represents the response of checking the network of the callback url.
+type CallbackCheckResponse struct {
+	ErrCode int             `json:"errcode"`
+	ErrMsg  string          `json:"errmsg"`
+	DNS     []*CallbackDNS  `json:"dns"`  // dns结果列表
+	Ping    []*CallbackPing `json:"ping"` // ping结果列表
+}
+
+// GetCallbackIPList returns the ip list of the WeChat servers pushing messages to the callback url,
+// e.g. to whitelist the sources of the push messages in the gateway.
+func (s *Service) GetCallbackIPList() ([]string, error) {
+	return s.GetCallbackIPListContext(context.Background())
+}
+
+// GetCallbackIPListContext is GetCallbackIPList with the given context.
+func (s *Service) GetCallbackIPListContext(ctx context.Context) ([]string, error) {
+	call := s.call("get callback ip", getCallbackIPURL)
+	call.Idempotent = true
+
+	resp, err := wxapi.GetJSON[IPListResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.IPList, nil
+}
+
+// GetAPIDomainIPList returns the ip list of the api domain api.weixin.qq.com,
+// e.g. to whitelist the outbound calls in the firewall.
+func (s *Service) GetAPIDomainIPList() ([]string, error) {
+	return s.GetAPIDomainIPListContext(context.Background())
+}
+
+// GetAPIDomainIPListContext is GetAPIDomainIPList with the given context.
+func (s *Service) GetAPIDomainIPListContext(ctx context.Context) ([]string, error) {
+	call := s.call("get api domain ip", getAPIDomainIPURL)
+	call.Idempotent = true
+
+	resp, err := wxapi.GetJSON[IPListResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.IPList, nil
+}
+
+// CheckCallback asks WeChat to resolve and ping the callback url of the app from the operator,
+// to verify the network between the WeChat servers and the callback url.
+func (s *Service) CheckCallback(action, checkOperator string) (*CallbackCheckResponse, error) {
+	return s.CheckCallbackContext(context.Background(), action, checkOperator)
+}
+
+// CheckCallbackContext is CheckCallback with the given context.
+func (s *Service) CheckCallbackContext(ctx context.Context, action, checkOperator string) (*CallbackCheckResponse, error) {
+	call := s.call("check callback", callbackCheckURL)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[CallbackCheckResponse](ctx, call, &CallbackCheckRequest{Action: action, CheckOperator: checkOperator})
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopenapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxopenapi"
+)
+
+func TestNetworkCheck(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	openapi := vwxopenapi.NewService(vwx.NewClient("appid", "secret", vwxmock.WithServer(server)))
+
+	ips, err := openapi.GetCallbackIPList()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.CallbackIPList, ips)
+
+	ips, err = openapi.GetAPIDomainIPList()
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.APIDomainIPList, ips)
+
+	check, err := openapi.CheckCallback(vwxopenapi.CallbackCheckAll, vwxopenapi.CheckOperatorUnicom)
+	assert.NoError(t, err)
+	assert.Equal(t, "UNICOM", check.DNS[0].RealOperator)
+	assert.Equal(t, "0%", check.Ping[0].PackageLoss)
+	assert.Equal(t, `{"action":"all","check_operator":"UNICOM"}`, string(server.Requests("/cgi-bin/callback/check")[0].Body))
+
+	check, err = openapi.CheckCallback(vwxopenapi.CallbackCheckDNS, vwxopenapi.CheckOperatorDefault)
+	assert.NoError(t, err)
+	assert.Len(t, check.DNS, 1)
+	assert.Empty(t, check.Ping)
+
+	_, err = openapi.CheckCallback("trace", vwxopenapi.CheckOperatorDefault)
+	assert.True(t, vwx.IsErrCode(err, 40097))
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopenapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	clearQuotaURL = "https://api.weixin.qq.com/cgi-bin/clear_quota?access_token=%s"
+	getQuotaURL   = "https://api.weixin.qq.com/cgi-bin/openapi/quota/get?access_token=%s"
+	getRIDURL     = "https://api.weixin.qq.com/cgi-bin/openapi/rid/get?access_token=%s"
+
+	// ErrCodeClearQuotaLimit is returned when the quota is cleared more than 10 times in a month.
+	ErrCodeClearQuotaLimit = 48006
+)
+
+// ErrRIDNotFound is returned by GetAPIErrorDetail for errors carrying no rid.
+var ErrRIDNotFound = errors.New("error carries no rid")
+
+// Quota is the daily quota of an api.
+type Quota struct {
+	DailyLimit int64 `json:"daily_limit"` // 当天该账号可调用该接口的次数
+	Used       int64 `json:"used"`        // 当天已经调用的次数
+	Remain     int64 `json:"remain"`      // 当天剩余调用次数
+}
+
+// RateLimit is the frequency limit of an api.
+type RateLimit struct {
+	CallCount     int64 `json:"call_count"`     // 周期内可调用数量，单位次
+	RefreshSecond int64 `json:"refresh_second"` // 更新周期，单位秒
+}
+
+// QuotaResponse represents the response of querying the quota of an api.
+type QuotaResponse struct {
+	ErrCode            int        `json:"errcode"`
+	ErrMsg             string     `json:"errmsg"`
+	Quota              *Quota     `json:"quota"`                          // 接口每日调用额度
+	RateLimit          *RateLimit `json:"rate_limit,omitempty"`           // 普通调用频率限制
+	ComponentRateLimit *RateLimit `json:"component_rate_limit,omitempty"` // 代调用频率限制
+}
+
+// RIDRequest is the call of a rid.
+type RIDRequest struct {
+	InvokeTime   int64  `json:"invoke_time"`   // 发起请求的时间戳
+	CostInMs     int64  `json:"cost_in_ms"`    // 请求毫秒级耗时
+	RequestURL   string `json:"request_url"`   // 请求的URL参数
+	RequestBody  string `json:"request_body"`  // post请求的请求参数
+	ResponseBody string `json:"response_body"` // 接口请求返回参数
+	ClientIP     string `json:"client_ip"`     // 接口请求的客户端ip
+}
+
+// RIDResponse represents the response of looking up a rid.
+type RIDResponse struct {
+	ErrCode int         `json:"errcode"`
+	ErrMsg  string      `json:"errmsg"`
+	Request *RIDRequest `json:"request"` // 该rid对应的请求详情
+}
+
+// ClearQuota resets the daily quota of all apis of the app to zero, e.g. after hitting 45009.
+// The quota can be cleared 10 times a month, calls beyond fail with ErrCodeClearQuotaLimit.
+func (s *Service) ClearQuota() error {
+	return s.ClearQuotaContext(context.Background())
+}
+
+// ClearQuotaContext is ClearQuota with the given context.
+func (s *Service) ClearQuotaContext(ctx context.Context) error {
+	_, err := wxapi.PostJSON[struct{}](ctx, s.call("clear quota", clearQuotaURL), map[string]string{"appid": s.client.AppID})
+
+	return err
+}
+
+// GetQuota queries the daily quota and the frequency limits of the api of the path, e.g. /cgi-bin/message/custom/send.
+func (s *Service) GetQuota(cgiPath string) (*QuotaResponse, error) {
+	return s.GetQuotaContext(context.Background(), cgiPath)
+}
+
+// GetQuotaContext is GetQuota with the given context.
+func (s *Service) GetQuotaContext(ctx context.Context, cgiPath string) (*QuotaResponse, error) {
+	call := s.call("get quota", getQuotaURL)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[QuotaResponse](ctx, call, map[string]string{"cgi_path": cgiPath})
+}
+
+// GetRID looks up the call of the rid returned in the errmsg of a failed call, see vwx.APIError.RID.
+func (s *Service) GetRID(rid string) (*RIDResponse, error) {
+	return s.GetRIDContext(context.Background(), rid)
+}
+
+// GetRIDContext is GetRID with the given context.
+func (s *Service) GetRIDContext(ctx context.Context, rid string) (*RIDResponse, error) {
+	call := s.call("get rid", getRIDURL)
+	call.Idempotent = true
+
+	return wxapi.PostJSON[RIDResponse](ctx, call, map[string]string{"rid": rid})
+}
+
+// GetAPIErrorDetail looks up the call of the rid of the APIError in the chain of err,
+// ErrRIDNotFound is returned if it carries none.
+func (s *Service) GetAPIErrorDetail(err error) (*RIDRequest, error) {
+	return s.GetAPIErrorDetailContext(context.Background(), err)
+}
+
+// GetAPIErrorDetailContext is GetAPIErrorDetail with the given context.
+func (s *Service) GetAPIErrorDetailContext(ctx context.Context, err error) (*RIDRequest, error) {
+	apiErr, ok := vwx.AsAPIError(err)
+	if !ok || apiErr.RID == "" {
+		return nil, ErrRIDNotFound
+	}
+
+	resp, err := s.GetRIDContext(ctx, apiErr.RID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Request, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxopenapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxopenapi"
+)
+
+func TestQuota(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxa.NewService(client)
+	openapi := vwxopenapi.NewService(client)
+
+	for range 2 {
+		_, err := svc.GenerateSimpleURLLink("/pages/index", "")
+		assert.NoError(t, err)
+	}
+
+	quota, err := openapi.GetQuota("/wxa/generate_urllink")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), quota.Quota.Used)
+	assert.Equal(t, int64(vwxmock.QuotaDailyLimit-2), quota.Quota.Remain)
+	assert.Equal(t, int64(60), quota.RateLimit.RefreshSecond)
+
+	assert.NoError(t, openapi.ClearQuota())
+	assert.Equal(t, `{"appid":"appid"}`, string(server.Requests("/cgi-bin/clear_quota")[0].Body))
+
+	quota, err = openapi.GetQuota("/wxa/generate_urllink")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), quota.Quota.Used)
+
+	for range 9 {
+		assert.NoError(t, openapi.ClearQuota())
+	}
+	assert.True(t, vwx.IsErrCode(openapi.ClearQuota(), vwxopenapi.ErrCodeClearQuotaLimit))
+
+	// the rid of a failed call tells its details
+	server.SetError("/wxa/generate_urllink", 45009, "reach max api daily quota limit rid: 64c1d2a3-1a2b3c4d-5e6f7a8b")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "a=1")
+
+	detail, err := openapi.GetAPIErrorDetail(err)
+	assert.NoError(t, err)
+	assert.Contains(t, detail.RequestBody, `"query":"a=1"`)
+	assert.Contains(t, detail.ResponseBody, "45009")
+
+	_, err = openapi.GetAPIErrorDetail(errors.New("timeout"))
+	assert.ErrorIs(t, err, vwxopenapi.ErrRIDNotFound)
+
+	_, err = openapi.GetRID("64c1d2a3-00000000-00000000")
+	assert.True(t, vwx.IsErrCode(err, 76001))
+}
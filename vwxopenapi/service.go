@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxopenapi provides the openApi management APIs shared by mini programs and official
+// accounts: the api quota, the rid lookup of failed calls and the network diagnostics.
+package vwxopenapi
+
+import (
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxauth"
+)
+
+// Service provides the openApi management operations of the app of the client.
+type Service struct {
+	client  *vwx.Client
+	authSvc *vwxauth.Service
+}
+
+// NewService creates a new openApi management service.
+func NewService(client *vwx.Client, options ...func(*Service)) *Service {
+	s := &Service{
+		client:  client,
+		authSvc: vwxauth.NewService(client),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
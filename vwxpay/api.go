@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vogo/vwx"
+)
+
+// call is a call of a WeChat Pay api.
+type call struct {
+	name   string
+	method string
+	path   string // 接口路径，含查询参数
+
+	// idempotent marks the call safe to be retried by the retry policy.
+	idempotent bool
+}
+
+// dryRunCodeURL is the code url synthesized for native payments in dry run mode.
+const dryRunCodeURL = "weixin://wxpay/bizpayurl?pr=dryrun"
+
+// dryRunPrepayID synthesizes the prepay id of the order in dry run mode.
+func dryRunPrepayID(outTradeNo string) string {
+	return "dryrun_" + outTradeNo
+}
+
+// dryRun logs the request of the mutating call skipped in dry run mode, it returns false if
+// the call should be sent.
+func (s *Service) dryRun(ctx context.Context, name string, req any) bool {
+	if !s.client.DryRun {
+		return false
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		data = []byte(err.Error())
+	}
+
+	s.client.Logger.Infof("dry run, skip %s | request_id: %s | req: %s", name, vwx.RequestIDOrNew(ctx), data)
+
+	return true
+}
+
+// do sends the request encoded in json, nil for none, and decodes the response into result if not nil.
+// Responses of a non-2xx status are returned as *APIError.
+func (s *Service) do(ctx context.Context, c *call, req, result any) error {
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	var (
+		body       []byte
+		bodyReader io.Reader
+	)
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("marshal request error: %w", err)
+		}
+
+		body, bodyReader = data, bytes.NewReader(data)
+
+		s.client.Logger.Infof("%s | request_id: %s | req: %s", c.name, requestID, body)
+	}
+
+	if c.idempotent {
+		ctx = vwx.Idempotent(ctx)
+	}
+
+	httpReq, err := s.newRequest(ctx, c.method, baseURL+c.path, body, bodyReader)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send request error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	respBody, err := s.client.ReadResponse(resp)
+	if err != nil {
+		return fmt.Errorf("read response error: %w", err)
+	}
+
+	s.client.Logger.Infof("%s | request_id: %s | status: %d | resp: %s", c.name, requestID, resp.StatusCode, respBody)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newAPIError(resp.StatusCode, c.path, respBody, requestID)
+	}
+
+	if err := s.verify(resp.Header, respBody); err != nil {
+		return err
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := vwx.CheckContentType(resp, "application/json"); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("unmarshal response error: %w", err)
+	}
+
+	return nil
+}
+
+// newRequest creates the request of the api url signed by the merchant private key, the body is
+// signed as is and sent by the reader.
+func (s *Service) newRequest(ctx context.Context, method, apiURL string, body []byte, bodyReader io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := s.authorization(method, req.URL.RequestURI(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// newAPIError builds the error of a response of a non-2xx status.
+func newAPIError(statusCode int, path string, body []byte, requestID string) *APIError {
+	endpoint, _, _ := strings.Cut(path, "?")
+
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+		RequestID:  requestID,
+	}
+
+	var errResp struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errResp) == nil {
+		apiErr.Code = errResp.Code
+		apiErr.Message = errResp.Message
+	}
+
+	return apiErr
+}
@@ -0,0 +1,234 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	tradeBillPath    = "/v3/bill/tradebill"
+	fundFlowBillPath = "/v3/bill/fundflowbill"
+)
+
+// ErrBillHashMismatch is returned when the hash of the downloaded bill differs from the hash of the bill url.
+var ErrBillHashMismatch = errors.New("bill hash mismatch")
+
+// TradeBillType is the type of the trade bill.
+type TradeBillType string
+
+const (
+	TradeBillAll     TradeBillType = "ALL"     // 当日所有订单信息（不含充值退款订单）
+	TradeBillSuccess TradeBillType = "SUCCESS" // 当日成功支付的订单（不含充值退款订单）
+	TradeBillRefund  TradeBillType = "REFUND"  // 当日退款订单（不含充值退款订单）
+)
+
+// FundFlowAccountType is the account type of the fund flow bill.
+type FundFlowAccountType string
+
+const (
+	FundFlowAccountBasic     FundFlowAccountType = "BASIC"     // 基本账户
+	FundFlowAccountOperation FundFlowAccountType = "OPERATION" // 运营账户
+	FundFlowAccountFees      FundFlowAccountType = "FEES"      // 手续费账户
+)
+
+// BillTarType is the compression of the bill file.
+type BillTarType string
+
+// BillTarGzip compresses the bill file by gzip, the bill is not compressed by default.
+const BillTarGzip BillTarType = "GZIP"
+
+// TradeBillRequest represents the request of applying the trade bill.
+type TradeBillRequest struct {
+	BillDate string        // 账单日期，格式 yyyy-MM-DD，仅支持三个月内的账单
+	SubMchID string        // 子商户号，服务商模式下选填，不填则返回服务商下全部子商户的账单
+	BillType TradeBillType // 账单类型，默认为 ALL
+	TarType  BillTarType   // 压缩类型，不填则以数据流方式返回
+}
+
+// FundFlowBillRequest represents the request of applying the fund flow bill.
+type FundFlowBillRequest struct {
+	BillDate    string              // 账单日期，格式 yyyy-MM-DD，仅支持三个月内的账单
+	AccountType FundFlowAccountType // 资金账户类型，默认为 BASIC
+	TarType     BillTarType         // 压缩类型，不填则以数据流方式返回
+}
+
+// BillResponse represents the download url of a bill.
+type BillResponse struct {
+	HashType    string `json:"hash_type"`    // 哈希类型，固定为 SHA1
+	HashValue   string `json:"hash_value"`   // 原始账单（gzip需要解压缩）的摘要值，用于校验文件的完整性
+	DownloadURL string `json:"download_url"` // 账单下载地址，30秒内有效
+}
+
+// GetTradeBill applies the trade bill of the date, the download url is valid for 30 seconds.
+func (s *Service) GetTradeBill(req *TradeBillRequest) (*BillResponse, error) {
+	return s.GetTradeBillContext(context.Background(), req)
+}
+
+// GetTradeBillContext is GetTradeBill with the given context.
+func (s *Service) GetTradeBillContext(ctx context.Context, req *TradeBillRequest) (*BillResponse, error) {
+	query := url.Values{"bill_date": {req.BillDate}}
+	if req.SubMchID != "" {
+		query.Set("sub_mchid", req.SubMchID)
+	}
+	if req.BillType != "" {
+		query.Set("bill_type", string(req.BillType))
+	}
+	if req.TarType != "" {
+		query.Set("tar_type", string(req.TarType))
+	}
+
+	var result BillResponse
+	if err := s.do(ctx, &call{name: "get trade bill", method: http.MethodGet, path: tradeBillPath + "?" + query.Encode(), idempotent: true}, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetFundFlowBill applies the fund flow bill of the date, the download url is valid for 30 seconds.
+func (s *Service) GetFundFlowBill(req *FundFlowBillRequest) (*BillResponse, error) {
+	return s.GetFundFlowBillContext(context.Background(), req)
+}
+
+// GetFundFlowBillContext is GetFundFlowBill with the given context.
+func (s *Service) GetFundFlowBillContext(ctx context.Context, req *FundFlowBillRequest) (*BillResponse, error) {
+	query := url.Values{"bill_date": {req.BillDate}}
+	if req.AccountType != "" {
+		query.Set("account_type", string(req.AccountType))
+	}
+	if req.TarType != "" {
+		query.Set("tar_type", string(req.TarType))
+	}
+
+	var result BillResponse
+	if err := s.do(ctx, &call{name: "get fund flow bill", method: http.MethodGet, path: fundFlowBillPath + "?" + query.Encode(), idempotent: true}, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DownloadTradeBill applies the trade bill and streams it to w, see DownloadBill.
+func (s *Service) DownloadTradeBill(req *TradeBillRequest, w io.Writer) (int64, error) {
+	return s.DownloadTradeBillContext(context.Background(), req, w)
+}
+
+// DownloadTradeBillContext is DownloadTradeBill with the given context.
+func (s *Service) DownloadTradeBillContext(ctx context.Context, req *TradeBillRequest, w io.Writer) (int64, error) {
+	bill, err := s.GetTradeBillContext(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.DownloadBillContext(ctx, bill, w)
+}
+
+// DownloadFundFlowBill applies the fund flow bill and streams it to w, see DownloadBill.
+func (s *Service) DownloadFundFlowBill(req *FundFlowBillRequest, w io.Writer) (int64, error) {
+	return s.DownloadFundFlowBillContext(context.Background(), req, w)
+}
+
+// DownloadFundFlowBillContext is DownloadFundFlowBill with the given context.
+func (s *Service) DownloadFundFlowBillContext(ctx context.Context, req *FundFlowBillRequest, w io.Writer) (int64, error) {
+	bill, err := s.GetFundFlowBillContext(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.DownloadBillContext(ctx, bill, w)
+}
+
+// DownloadBill streams the bill to w, gzip compressed bills are decompressed. The SHA1 hash of the
+// bill is verified after it is written, ErrBillHashMismatch is returned if it differs, so that the
+// written bill must be discarded. The size of the written bill is returned.
+func (s *Service) DownloadBill(bill *BillResponse, w io.Writer) (int64, error) {
+	return s.DownloadBillContext(context.Background(), bill, w)
+}
+
+// DownloadBillContext is DownloadBill with the given context.
+func (s *Service) DownloadBillContext(ctx context.Context, bill *BillResponse, w io.Writer) (int64, error) {
+	if !strings.EqualFold(bill.HashType, "SHA1") {
+		return 0, fmt.Errorf("unsupported bill hash type: %s", bill.HashType)
+	}
+
+	requestID := vwx.RequestIDOrNew(ctx)
+	ctx = vwx.ContextWithRequestID(ctx, requestID)
+
+	req, err := s.newRequest(vwx.Idempotent(ctx), http.MethodGet, bill.DownloadURL, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	s.client.Logger.Infof("download bill | request_id: %s | path: %s", requestID, req.URL.Path)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request error: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.client.Logger.Errorf("failed to close response body | err: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return 0, newAPIError(resp.StatusCode, req.URL.Path, body, requestID)
+	}
+
+	var r io.Reader = bufio.NewReader(resp.Body)
+
+	// the bill is gzip compressed if applied with BillTarGzip
+	if magic, _ := r.(*bufio.Reader).Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return 0, fmt.Errorf("read gzip bill error: %w", err)
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	hash := sha1.New()
+
+	n, err := io.Copy(io.MultiWriter(w, hash), r)
+	if err != nil {
+		return n, fmt.Errorf("download bill error: %w", err)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, bill.HashValue) {
+		return n, fmt.Errorf("%w: expected %s, got %s | request_id: %s", ErrBillHashMismatch, bill.HashValue, sum, requestID)
+	}
+
+	s.client.Logger.Infof("download bill | request_id: %s | size: %d", requestID, n)
+
+	return n, nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxpay"
+)
+
+func TestBillDownload(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.PayPrivateKey = key
+	server.PayPublicKeyID = "PUB_KEY_ID_1"
+
+	content := []byte("交易时间,公众账号ID,商户号\n`2026-10-15 10:00:00,`appid,`1900000001\n")
+	server.SetBill("2026-10-15", content)
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxpay.NewService(client, &vwxpay.Merchant{
+		MchID:       "1900000001",
+		SerialNo:    "SERIAL",
+		PrivateKey:  key,
+		PublicKeyID: "PUB_KEY_ID_1",
+		PublicKey:   &key.PublicKey,
+	})
+
+	var buf bytes.Buffer
+	n, err := svc.DownloadTradeBill(&vwxpay.TradeBillRequest{BillDate: "2026-10-15", BillType: vwxpay.TradeBillAll}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, buf.Bytes())
+
+	requests := server.Requests("/v3/bill/tradebill")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "ALL", requests[0].Query.Get("bill_type"))
+
+	// gzip bills are decompressed and verified by the hash of the original bill
+	buf.Reset()
+	_, err = svc.DownloadFundFlowBill(&vwxpay.FundFlowBillRequest{BillDate: "2026-10-15", TarType: vwxpay.BillTarGzip}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, content, buf.Bytes())
+
+	downloads := server.Requests(vwxmock.BillDownloadPath)
+	assert.Len(t, downloads, 2)
+	assert.Equal(t, "gzip", downloads[1].Query.Get("tartype"))
+
+	bill, err := svc.GetTradeBill(&vwxpay.TradeBillRequest{BillDate: "2026-10-15"})
+	assert.NoError(t, err)
+	bill.HashValue = strings.Repeat("0", 40)
+
+	_, err = svc.DownloadBill(bill, io.Discard)
+	assert.ErrorIs(t, err, vwxpay.ErrBillHashMismatch)
+
+	_, err = svc.GetTradeBill(&vwxpay.TradeBillRequest{BillDate: "2026-10-14"})
+	assert.True(t, vwxpay.IsErrCode(err, "NO_STATEMENT_EXIST"))
+}
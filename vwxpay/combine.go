@@ -0,0 +1,274 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	combineJSAPIPath  = "/v3/combine-transactions/jsapi"
+	combineNativePath = "/v3/combine-transactions/native"
+	combineQueryPath  = "/v3/combine-transactions/out-trade-no/%s"
+	combineClosePath  = "/v3/combine-transactions/out-trade-no/%s/close"
+
+	// MaxCombineSubOrders is the max count of the sub orders of a combined payment.
+	MaxCombineSubOrders = 50
+)
+
+// TradeState is the state of a payment order.
+type TradeState string
+
+const (
+	TradeStateSuccess    TradeState = "SUCCESS"    // 支付成功
+	TradeStateRefund     TradeState = "REFUND"     // 转入退款
+	TradeStateNotPay     TradeState = "NOTPAY"     // 未支付
+	TradeStateClosed     TradeState = "CLOSED"     // 已关闭
+	TradeStateUserPaying TradeState = "USERPAYING" // 用户支付中
+	TradeStatePayError   TradeState = "PAYERROR"   // 支付失败
+)
+
+// CombineAmount is the amount of a sub order.
+type CombineAmount struct {
+	TotalAmount    int64  `json:"total_amount"`              // 标价金额，单位为分
+	Currency       string `json:"currency"`                  // 标价币种，默认为 CNY
+	PayerAmount    int64  `json:"payer_amount,omitempty"`    // 现金支付金额，仅查询返回
+	PayerCurrency  string `json:"payer_currency,omitempty"`  // 现金支付币种，仅查询返回
+	SettlementRate int64  `json:"settlement_rate,omitempty"` // 结算汇率，仅查询返回
+}
+
+// CombineSettleInfo is the settlement of a sub order.
+type CombineSettleInfo struct {
+	ProfitSharing bool  `json:"profit_sharing,omitempty"` // 是否指定分账
+	SubsidyAmount int64 `json:"subsidy_amount,omitempty"` // 补差金额，单位为分
+}
+
+// CombineSubOrder is a sub order of a combined payment, charged by its own merchant.
+type CombineSubOrder struct {
+	MchID       string             `json:"mchid"`                 // 子单发起方商户号，必须与发起方appid有绑定关系
+	SubMchID    string             `json:"sub_mchid,omitempty"`   // 二级商户号，服务商模式下必填
+	SubAppID    string             `json:"sub_appid,omitempty"`   // 子商户应用ID，服务商模式下选填
+	Attach      string             `json:"attach"`                // 附加数据，在查询和支付通知中原样返回
+	Amount      *CombineAmount     `json:"amount"`                // 订单金额
+	OutTradeNo  string             `json:"out_trade_no"`          // 子单商户订单号
+	GoodsTag    string             `json:"goods_tag,omitempty"`   // 订单优惠标记
+	Description string             `json:"description"`           // 商品描述
+	SettleInfo  *CombineSettleInfo `json:"settle_info,omitempty"` // 结算信息
+}
+
+// CombineSceneInfo is the scene of a combined payment.
+type CombineSceneInfo struct {
+	DeviceID      string `json:"device_id,omitempty"`       // 商户端设备号
+	PayerClientIP string `json:"payer_client_ip,omitempty"` // 用户终端IP
+}
+
+// CombinePayerInfo is the payer of a combined payment.
+type CombinePayerInfo struct {
+	OpenID string `json:"openid"` // 用户在合单发起方appid下的openid
+}
+
+// CombineTransactionRequest represents the request of creating a combined payment.
+type CombineTransactionRequest struct {
+	CombineAppID      string             `json:"combine_appid"`                // 合单发起方的appid
+	CombineMchID      string             `json:"combine_mchid"`                // 合单发起方商户号，为空时使用服务的商户号
+	CombineOutTradeNo string             `json:"combine_out_trade_no"`         // 合单支付总订单号
+	SceneInfo         *CombineSceneInfo  `json:"scene_info,omitempty"`         // 支付场景信息
+	SubOrders         []*CombineSubOrder `json:"sub_orders"`                   // 子单信息，最多50单
+	PayerInfo         *CombinePayerInfo  `json:"combine_payer_info,omitempty"` // 支付者信息，JSAPI支付必填
+	TimeStart         *time.Time         `json:"time_start,omitempty"`         // 交易起始时间
+	TimeExpire        *time.Time         `json:"time_expire,omitempty"`        // 交易结束时间
+	NotifyURL         string             `json:"notify_url"`                   // 接收支付结果通知的回调地址，必须为https
+}
+
+// CombineJSAPIResponse represents the response of creating a JSAPI combined payment.
+type CombineJSAPIResponse struct {
+	PrepayID string `json:"prepay_id"` // 预支付交易会话标识，有效期为2小时
+}
+
+// CombineNativeResponse represents the response of creating a Native combined payment.
+type CombineNativeResponse struct {
+	CodeURL string `json:"code_url"` // 二维码链接，有效期为2小时
+}
+
+// CombineSubOrderState is the state of a sub order of a combined payment.
+type CombineSubOrderState struct {
+	MchID         string         `json:"mchid"`                    // 子单发起方商户号
+	SubMchID      string         `json:"sub_mchid,omitempty"`      // 二级商户号
+	SubAppID      string         `json:"sub_appid,omitempty"`      // 子商户应用ID
+	SubOpenID     string         `json:"sub_openid,omitempty"`     // 用户在子商户应用下的openid
+	TradeType     string         `json:"trade_type"`               // 交易类型，如 JSAPI、NATIVE
+	TradeState    TradeState     `json:"trade_state"`              // 交易状态
+	BankType      string         `json:"bank_type,omitempty"`      // 付款银行
+	Attach        string         `json:"attach"`                   // 附加数据
+	SuccessTime   string         `json:"success_time,omitempty"`   // 支付完成时间，rfc3339格式
+	TransactionID string         `json:"transaction_id,omitempty"` // 微信支付订单号
+	OutTradeNo    string         `json:"out_trade_no"`             // 子单商户订单号
+	Amount        *CombineAmount `json:"amount"`                   // 订单金额
+}
+
+// CombineTransaction is the state of a combined payment.
+type CombineTransaction struct {
+	CombineAppID      string                  `json:"combine_appid"`                // 合单发起方的appid
+	CombineMchID      string                  `json:"combine_mchid"`                // 合单发起方商户号
+	CombineOutTradeNo string                  `json:"combine_out_trade_no"`         // 合单支付总订单号
+	SceneInfo         *CombineSceneInfo       `json:"scene_info,omitempty"`         // 支付场景信息
+	SubOrders         []*CombineSubOrderState `json:"sub_orders"`                   // 子单信息
+	PayerInfo         *CombinePayerInfo       `json:"combine_payer_info,omitempty"` // 支付者信息
+}
+
+// CombineCloseSubOrder is a sub order to close.
+type CombineCloseSubOrder struct {
+	MchID      string `json:"mchid"`               // 子单发起方商户号
+	SubMchID   string `json:"sub_mchid,omitempty"` // 二级商户号，服务商模式下必填
+	SubAppID   string `json:"sub_appid,omitempty"` // 子商户应用ID
+	OutTradeNo string `json:"out_trade_no"`        // 子单商户订单号
+}
+
+// CreateCombineJSAPI creates a combined payment paid in the mini program or official account by the payer,
+// the prepay id is valid for 2 hours.
+func (s *Service) CreateCombineJSAPI(req *CombineTransactionRequest) (*CombineJSAPIResponse, error) {
+	return s.CreateCombineJSAPIContext(context.Background(), req)
+}
+
+// CreateCombineJSAPIContext is CreateCombineJSAPI with the given context.
+func (s *Service) CreateCombineJSAPIContext(ctx context.Context, req *CombineTransactionRequest) (*CombineJSAPIResponse, error) {
+	if req.PayerInfo == nil || req.PayerInfo.OpenID == "" {
+		return nil, errors.New("combine jsapi payment requires the openid of the payer")
+	}
+
+	req, err := s.combineRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.dryRun(ctx, "create combine jsapi", req) {
+		return &CombineJSAPIResponse{PrepayID: dryRunPrepayID(req.CombineOutTradeNo)}, nil
+	}
+
+	var result CombineJSAPIResponse
+	if err := s.do(ctx, &call{name: "create combine jsapi", method: http.MethodPost, path: combineJSAPIPath, idempotent: true}, req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateCombineNative creates a combined payment paid by scanning the QR code of the code url,
+// the code url is valid for 2 hours.
+func (s *Service) CreateCombineNative(req *CombineTransactionRequest) (*CombineNativeResponse, error) {
+	return s.CreateCombineNativeContext(context.Background(), req)
+}
+
+// CreateCombineNativeContext is CreateCombineNative with the given context.
+func (s *Service) CreateCombineNativeContext(ctx context.Context, req *CombineTransactionRequest) (*CombineNativeResponse, error) {
+	req, err := s.combineRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.dryRun(ctx, "create combine native", req) {
+		return &CombineNativeResponse{CodeURL: dryRunCodeURL}, nil
+	}
+
+	var result CombineNativeResponse
+	if err := s.do(ctx, &call{name: "create combine native", method: http.MethodPost, path: combineNativePath, idempotent: true}, req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// combineRequest validates the sub orders of the request and fills the combine mchid and the
+// currency of the sub orders by default.
+func (s *Service) combineRequest(req *CombineTransactionRequest) (*CombineTransactionRequest, error) {
+	if len(req.SubOrders) == 0 || len(req.SubOrders) > MaxCombineSubOrders {
+		return nil, fmt.Errorf("combine payment accepts 1 to %d sub orders, got %d", MaxCombineSubOrders, len(req.SubOrders))
+	}
+
+	filled := *req
+	if filled.CombineMchID == "" {
+		filled.CombineMchID = s.merchant.MchID
+	}
+
+	filled.SubOrders = make([]*CombineSubOrder, len(req.SubOrders))
+	for i, order := range req.SubOrders {
+		if order.Amount == nil || order.Amount.TotalAmount <= 0 {
+			return nil, fmt.Errorf("sub order %s requires a positive amount", order.OutTradeNo)
+		}
+
+		filled.SubOrders[i] = order
+		if order.Amount.Currency == "" {
+			withCurrency := *order
+			withCurrency.Amount = &CombineAmount{TotalAmount: order.Amount.TotalAmount, Currency: "CNY"}
+			filled.SubOrders[i] = &withCurrency
+		}
+	}
+
+	return &filled, nil
+}
+
+// QueryCombineTransaction queries the state of the combined payment and its sub orders.
+func (s *Service) QueryCombineTransaction(combineOutTradeNo string) (*CombineTransaction, error) {
+	return s.QueryCombineTransactionContext(context.Background(), combineOutTradeNo)
+}
+
+// QueryCombineTransactionContext is QueryCombineTransaction with the given context.
+func (s *Service) QueryCombineTransactionContext(ctx context.Context, combineOutTradeNo string) (*CombineTransaction, error) {
+	path := fmt.Sprintf(combineQueryPath, url.PathEscape(combineOutTradeNo))
+
+	var result CombineTransaction
+	if err := s.do(ctx, &call{name: "query combine transaction", method: http.MethodGet, path: path, idempotent: true}, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CloseCombineTransaction closes the unpaid sub orders of the combined payment, all sub orders
+// are closed at once.
+func (s *Service) CloseCombineTransaction(combineAppID, combineOutTradeNo string, subOrders []*CombineCloseSubOrder) error {
+	return s.CloseCombineTransactionContext(context.Background(), combineAppID, combineOutTradeNo, subOrders)
+}
+
+// CloseCombineTransactionContext is CloseCombineTransaction with the given context.
+func (s *Service) CloseCombineTransactionContext(ctx context.Context, combineAppID, combineOutTradeNo string, subOrders []*CombineCloseSubOrder) error {
+	if len(subOrders) == 0 || len(subOrders) > MaxCombineSubOrders {
+		return fmt.Errorf("combine payment accepts 1 to %d sub orders, got %d", MaxCombineSubOrders, len(subOrders))
+	}
+
+	req := struct {
+		CombineAppID string                  `json:"combine_appid"`
+		SubOrders    []*CombineCloseSubOrder `json:"sub_orders"`
+	}{
+		CombineAppID: combineAppID,
+		SubOrders:    subOrders,
+	}
+
+	if s.dryRun(ctx, "close combine transaction", &req) {
+		return nil
+	}
+
+	path := fmt.Sprintf(combineClosePath, url.PathEscape(combineOutTradeNo))
+
+	return s.do(ctx, &call{name: "close combine transaction", method: http.MethodPost, path: path, idempotent: true}, &req, nil)
+}
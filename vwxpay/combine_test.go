@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxpay"
+)
+
+func TestCombinePayment(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	server.PayPrivateKey = key
+	server.PayPublicKeyID = "PUB_KEY_ID_1"
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxpay.NewService(client, &vwxpay.Merchant{
+		MchID:       "1900000001",
+		SerialNo:    "SERIAL",
+		PrivateKey:  key,
+		PublicKeyID: "PUB_KEY_ID_1",
+		PublicKey:   &key.PublicKey,
+	})
+
+	req := &vwxpay.CombineTransactionRequest{
+		CombineAppID:      "appid",
+		CombineOutTradeNo: "combine-1",
+		SubOrders: []*vwxpay.CombineSubOrder{
+			{MchID: "1900000101", Attach: "a", Amount: &vwxpay.CombineAmount{TotalAmount: 100}, OutTradeNo: "sub-1", Description: "goods 1"},
+			{MchID: "1900000102", Attach: "b", Amount: &vwxpay.CombineAmount{TotalAmount: 200}, OutTradeNo: "sub-2", Description: "goods 2"},
+		},
+		NotifyURL: "https://example.com/notify",
+	}
+
+	_, err = svc.CreateCombineJSAPI(req)
+	assert.Error(t, err)
+
+	req.PayerInfo = &vwxpay.CombinePayerInfo{OpenID: "openid"}
+	prepay, err := svc.CreateCombineJSAPI(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "mock-prepay-combine-1", prepay.PrepayID)
+
+	requests := server.Requests("/v3/combine-transactions/jsapi")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), `"combine_mchid":"1900000001"`)
+	assert.Contains(t, string(requests[0].Body), `"amount":{"total_amount":100,"currency":"CNY"}`)
+
+	native, err := svc.CreateCombineNative(&vwxpay.CombineTransactionRequest{
+		CombineAppID:      "appid",
+		CombineOutTradeNo: "combine-2",
+		SubOrders:         req.SubOrders,
+		NotifyURL:         "https://example.com/notify",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, native.CodeURL)
+
+	transaction, err := svc.QueryCombineTransaction("combine-1")
+	assert.NoError(t, err)
+	assert.Len(t, transaction.SubOrders, 2)
+	assert.Equal(t, vwxpay.TradeStateNotPay, transaction.SubOrders[0].TradeState)
+	assert.Equal(t, int64(200), transaction.SubOrders[1].Amount.TotalAmount)
+
+	assert.NoError(t, svc.CloseCombineTransaction("appid", "combine-1", []*vwxpay.CombineCloseSubOrder{
+		{MchID: "1900000101", OutTradeNo: "sub-1"},
+		{MchID: "1900000102", OutTradeNo: "sub-2"},
+	}))
+
+	transaction, err = svc.QueryCombineTransaction("combine-1")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxpay.TradeStateClosed, transaction.SubOrders[0].TradeState)
+
+	_, err = svc.QueryCombineTransaction("combine-missing")
+	assert.True(t, vwxpay.IsErrCode(err, "ORDER_NOT_EXIST"))
+
+	// payments are not created in dry run mode
+	client.DryRun = true
+	prepay, err = svc.CreateCombineJSAPI(&vwxpay.CombineTransactionRequest{
+		CombineAppID:      "appid",
+		CombineOutTradeNo: "combine-3",
+		SubOrders:         req.SubOrders,
+		PayerInfo:         req.PayerInfo,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "dryrun_combine-3", prepay.PrepayID)
+	assert.Len(t, server.Requests("/v3/combine-transactions/jsapi"), 1)
+
+	native, err = svc.CreateCombineNative(&vwxpay.CombineTransactionRequest{
+		CombineAppID:      "appid",
+		CombineOutTradeNo: "combine-4",
+		SubOrders:         req.SubOrders,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, native.CodeURL)
+	assert.Len(t, server.Requests("/v3/combine-transactions/native"), 1)
+
+	assert.NoError(t, svc.CloseCombineTransaction("appid", "combine-2", []*vwxpay.CombineCloseSubOrder{
+		{MchID: "1900000101", OutTradeNo: "sub-1"},
+	}))
+	assert.Len(t, server.Requests("/v3/combine-transactions/out-trade-no/combine-2/close"), 0)
+	client.DryRun = false
+
+	// responses signed by another key are rejected
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	server.PayPrivateKey = other
+
+	_, err = svc.QueryCombineTransaction("combine-1")
+	assert.ErrorIs(t, err, vwxpay.ErrInvalidSignature)
+}
@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"errors"
+	"fmt"
+)
+
+// APIError is returned when a WeChat Pay api responds with a non-2xx status.
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Code       string // 微信支付返回的错误码，如 PARAM_ERROR、ORDER_NOT_EXIST
+	Message    string // 微信支付返回的错误描述
+	Endpoint   string // 接口路径，如 /v3/combine-transactions/jsapi
+	RequestID  string // 本次调用的关联 id
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("wechat pay error: %d %s %s | endpoint: %s | request_id: %s",
+		e.StatusCode, e.Code, e.Message, e.Endpoint, e.RequestID)
+}
+
+// AsAPIError finds the first APIError in the chain of err.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// IsErrCode reports whether err is an APIError of any of the codes.
+func IsErrCode(err error, codes ...string) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
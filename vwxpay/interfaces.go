@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"context"
+	"io"
+)
+
+// JSAPIPayer creates the payment orders paid in the mini program or official account and signs
+// the params of wx.requestPayment.
+type JSAPIPayer interface {
+	CreateJSAPI(req *JSAPITransactionRequest) (*JSAPIResponse, error)
+	CreateJSAPIContext(ctx context.Context, req *JSAPITransactionRequest) (*JSAPIResponse, error)
+	RequestPayment(appID, prepayID string) (*RequestPaymentParams, error)
+	PayJSAPI(order *JSAPIOrder) (*RequestPaymentParams, error)
+	PayJSAPIContext(ctx context.Context, order *JSAPIOrder) (*RequestPaymentParams, error)
+}
+
+var _ JSAPIPayer = (*Service)(nil)
+
+// CombinePayer creates, queries and closes the combined payments (合单支付) of many sub merchants.
+type CombinePayer interface {
+	CreateCombineJSAPI(req *CombineTransactionRequest) (*CombineJSAPIResponse, error)
+	CreateCombineJSAPIContext(ctx context.Context, req *CombineTransactionRequest) (*CombineJSAPIResponse, error)
+	CreateCombineNative(req *CombineTransactionRequest) (*CombineNativeResponse, error)
+	CreateCombineNativeContext(ctx context.Context, req *CombineTransactionRequest) (*CombineNativeResponse, error)
+	QueryCombineTransaction(combineOutTradeNo string) (*CombineTransaction, error)
+	QueryCombineTransactionContext(ctx context.Context, combineOutTradeNo string) (*CombineTransaction, error)
+	CloseCombineTransaction(combineAppID, combineOutTradeNo string, subOrders []*CombineCloseSubOrder) error
+	CloseCombineTransactionContext(ctx context.Context, combineAppID, combineOutTradeNo string, subOrders []*CombineCloseSubOrder) error
+}
+
+var _ CombinePayer = (*Service)(nil)
+
+// BillDownloader applies and downloads the trade and fund flow bills for reconciliation.
+type BillDownloader interface {
+	GetTradeBill(req *TradeBillRequest) (*BillResponse, error)
+	GetTradeBillContext(ctx context.Context, req *TradeBillRequest) (*BillResponse, error)
+	GetFundFlowBill(req *FundFlowBillRequest) (*BillResponse, error)
+	GetFundFlowBillContext(ctx context.Context, req *FundFlowBillRequest) (*BillResponse, error)
+	DownloadBill(bill *BillResponse, w io.Writer) (int64, error)
+	DownloadBillContext(ctx context.Context, bill *BillResponse, w io.Writer) (int64, error)
+	DownloadTradeBill(req *TradeBillRequest, w io.Writer) (int64, error)
+	DownloadTradeBillContext(ctx context.Context, req *TradeBillRequest, w io.Writer) (int64, error)
+	DownloadFundFlowBill(req *FundFlowBillRequest, w io.Writer) (int64, error)
+	DownloadFundFlowBillContext(ctx context.Context, req *FundFlowBillRequest, w io.Writer) (int64, error)
+}
+
+var _ BillDownloader = (*Service)(nil)
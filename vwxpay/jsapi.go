@@ -0,0 +1,193 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	jsapiPath = "/v3/pay/transactions/jsapi"
+
+	// signTypeRSA is the sign type of the payment params of api v3.
+	signTypeRSA = "RSA"
+)
+
+// TransactionAmount is the amount of a payment order.
+type TransactionAmount struct {
+	Total    int64  `json:"total"`    // 订单总金额，单位为分
+	Currency string `json:"currency"` // 货币类型，默认为 CNY
+}
+
+// TransactionPayer is the payer of a payment order.
+type TransactionPayer struct {
+	OpenID string `json:"openid"` // 用户在appid下的openid
+}
+
+// JSAPITransactionRequest represents the request of creating a JSAPI payment order.
+type JSAPITransactionRequest struct {
+	AppID       string             `json:"appid"`                 // 小程序或公众号的appid，为空时使用客户端的appid
+	MchID       string             `json:"mchid"`                 // 商户号，为空时使用服务的商户号
+	Description string             `json:"description"`           // 商品描述
+	OutTradeNo  string             `json:"out_trade_no"`          // 商户订单号
+	TimeExpire  *time.Time         `json:"time_expire,omitempty"` // 支付结束时间
+	Attach      string             `json:"attach,omitempty"`      // 附加数据，在查询和支付通知中原样返回
+	NotifyURL   string             `json:"notify_url"`            // 接收支付结果通知的回调地址，必须为https
+	GoodsTag    string             `json:"goods_tag,omitempty"`   // 订单优惠标记
+	Amount      *TransactionAmount `json:"amount"`                // 订单金额
+	Payer       *TransactionPayer  `json:"payer"`                 // 支付者信息
+}
+
+// JSAPIResponse represents the response of creating a JSAPI payment order.
+type JSAPIResponse struct {
+	PrepayID string `json:"prepay_id"` // 预支付交易会话标识，有效期为2小时
+}
+
+// RequestPaymentParams are the params of wx.requestPayment of the mini program, or of
+// WeixinJSBridge getBrandWCPayRequest of the official account with the appid, signed by the
+// merchant private key.
+type RequestPaymentParams struct {
+	AppID     string `json:"-"`         // 签名所用的appid，公众号调起支付时需一并传入
+	TimeStamp string `json:"timeStamp"` // 时间戳，单位为秒
+	NonceStr  string `json:"nonceStr"`  // 随机字符串
+	Package   string `json:"package"`   // 预支付交易会话标识，格式为 prepay_id=***
+	SignType  string `json:"signType"`  // 签名类型，固定为 RSA
+	PaySign   string `json:"paySign"`   // 签名
+}
+
+// CreateJSAPI creates a payment order paid in the mini program or official account by the payer,
+// the prepay id is valid for 2 hours.
+func (s *Service) CreateJSAPI(req *JSAPITransactionRequest) (*JSAPIResponse, error) {
+	return s.CreateJSAPIContext(context.Background(), req)
+}
+
+// CreateJSAPIContext is CreateJSAPI with the given context.
+func (s *Service) CreateJSAPIContext(ctx context.Context, req *JSAPITransactionRequest) (*JSAPIResponse, error) {
+	req, err := s.jsapiRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.dryRun(ctx, "create jsapi", req) {
+		return &JSAPIResponse{PrepayID: dryRunPrepayID(req.OutTradeNo)}, nil
+	}
+
+	var result JSAPIResponse
+	if err := s.do(ctx, &call{name: "create jsapi", method: http.MethodPost, path: jsapiPath, idempotent: true}, req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// jsapiRequest validates the payer and the amount of the request and fills the appid, the mchid
+// and the currency by default.
+func (s *Service) jsapiRequest(req *JSAPITransactionRequest) (*JSAPITransactionRequest, error) {
+	if req.Payer == nil || req.Payer.OpenID == "" {
+		return nil, errors.New("jsapi payment requires the openid of the payer")
+	}
+
+	if req.Amount == nil || req.Amount.Total <= 0 {
+		return nil, fmt.Errorf("order %s requires a positive amount", req.OutTradeNo)
+	}
+
+	filled := *req
+	if filled.AppID == "" {
+		filled.AppID = s.client.AppID
+	}
+
+	if filled.MchID == "" {
+		filled.MchID = s.merchant.MchID
+	}
+
+	if filled.Amount.Currency == "" {
+		filled.Amount = &TransactionAmount{Total: req.Amount.Total, Currency: "CNY"}
+	}
+
+	return &filled, nil
+}
+
+// RequestPayment signs the params of wx.requestPayment of the prepay id for the app.
+func (s *Service) RequestPayment(appID, prepayID string) (*RequestPaymentParams, error) {
+	params := &RequestPaymentParams{
+		AppID:     appID,
+		TimeStamp: strconv.FormatInt(time.Now().Unix(), 10),
+		NonceStr:  newNonce(),
+		Package:   "prepay_id=" + prepayID,
+		SignType:  signTypeRSA,
+	}
+
+	message := params.AppID + "\n" + params.TimeStamp + "\n" + params.NonceStr + "\n" + params.Package + "\n"
+
+	signature, err := signSHA256(s.merchant.PrivateKey, message)
+	if err != nil {
+		return nil, fmt.Errorf("sign payment error: %w", err)
+	}
+
+	params.PaySign = signature
+
+	return params, nil
+}
+
+// JSAPIOrder is an order paid in the mini program by the user of a session, e.g. the openid
+// returned by vwxauth GetSessionKey on wx.login.
+type JSAPIOrder struct {
+	AppID       string     // 小程序appid，为空时使用客户端的appid
+	OpenID      string     // 用户在小程序下的openid
+	OutTradeNo  string     // 商户订单号
+	Description string     // 商品描述
+	Total       int64      // 订单总金额，单位为分
+	NotifyURL   string     // 接收支付结果通知的回调地址，必须为https
+	Attach      string     // 附加数据，在查询和支付通知中原样返回
+	TimeExpire  *time.Time // 支付结束时间
+}
+
+// PayJSAPI creates the payment order of the user and returns the params of wx.requestPayment
+// to be passed as is to the mini program frontend.
+func (s *Service) PayJSAPI(order *JSAPIOrder) (*RequestPaymentParams, error) {
+	return s.PayJSAPIContext(context.Background(), order)
+}
+
+// PayJSAPIContext is PayJSAPI with the given context.
+func (s *Service) PayJSAPIContext(ctx context.Context, order *JSAPIOrder) (*RequestPaymentParams, error) {
+	appID := order.AppID
+	if appID == "" {
+		appID = s.client.AppID
+	}
+
+	resp, err := s.CreateJSAPIContext(ctx, &JSAPITransactionRequest{
+		AppID:       appID,
+		Description: order.Description,
+		OutTradeNo:  order.OutTradeNo,
+		TimeExpire:  order.TimeExpire,
+		Attach:      order.Attach,
+		NotifyURL:   order.NotifyURL,
+		Amount:      &TransactionAmount{Total: order.Total},
+		Payer:       &TransactionPayer{OpenID: order.OpenID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestPayment(appID, resp.PrepayID)
+}
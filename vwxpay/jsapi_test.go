@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxpay"
+)
+
+func TestJSAPIPayment(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server))
+	svc := vwxpay.NewService(client, &vwxpay.Merchant{MchID: "1900000001", SerialNo: "SERIAL", PrivateKey: key})
+
+	session, err := vwxauth.NewService(client).GetSessionKey("code")
+	assert.NoError(t, err)
+
+	_, err = svc.PayJSAPI(&vwxpay.JSAPIOrder{OutTradeNo: "order-1", Description: "goods", Total: 100})
+	assert.Error(t, err)
+
+	params, err := svc.PayJSAPI(&vwxpay.JSAPIOrder{
+		OpenID:      session.OpenID,
+		OutTradeNo:  "order-1",
+		Description: "goods",
+		Total:       100,
+		NotifyURL:   "https://example.com/notify",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "prepay_id=mock-prepay-order-1", params.Package)
+	assert.Equal(t, "RSA", params.SignType)
+	assert.Len(t, params.NonceStr, 32)
+
+	message := "appid\n" + params.TimeStamp + "\n" + params.NonceStr + "\n" + params.Package + "\n"
+	sum := sha256.Sum256([]byte(message))
+	signature, err := base64.StdEncoding.DecodeString(params.PaySign)
+	assert.NoError(t, err)
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], signature))
+
+	data, err := json.Marshal(params)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\{"timeStamp":"\d+","nonceStr":"\w{32}","package":"prepay_id=mock-prepay-order-1","signType":"RSA","paySign":"[^"]+"\}$`, string(data))
+
+	requests := server.Requests("/v3/pay/transactions/jsapi")
+	assert.Len(t, requests, 1)
+	assert.JSONEq(t, `{"appid":"appid","mchid":"1900000001","description":"goods","out_trade_no":"order-1",
+		"notify_url":"https://example.com/notify","amount":{"total":100,"currency":"CNY"},"payer":{"openid":"`+session.OpenID+`"}}`,
+		string(requests[0].Body))
+
+	// orders are not created in dry run mode
+	client.DryRun = true
+	params, err = svc.PayJSAPI(&vwxpay.JSAPIOrder{OpenID: session.OpenID, OutTradeNo: "order-2", Total: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, "prepay_id=dryrun_order-2", params.Package)
+	assert.Len(t, server.Requests("/v3/pay/transactions/jsapi"), 1)
+}
@@ -0,0 +1,198 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+// MaxNotificationSize is the max size of a notification body, larger bodies are rejected before verification.
+const MaxNotificationSize = 1 << 20
+
+const notificationAlgorithm = "AEAD_AES_256_GCM"
+
+// Notification event types.
+const (
+	EventTransactionSuccess = "TRANSACTION.SUCCESS" // 支付成功
+	EventRefundSuccess      = "REFUND.SUCCESS"      // 退款成功
+	EventRefundAbnormal     = "REFUND.ABNORMAL"     // 退款异常
+	EventRefundClosed       = "REFUND.CLOSED"       // 退款关闭
+)
+
+// Notification represents a notification pushed by WeChat Pay to the notify url, e.g. a payment result.
+type Notification struct {
+	ID           string                `json:"id"`            // 通知的唯一id
+	CreateTime   string                `json:"create_time"`   // 通知创建的时间，遵循rfc3339标准格式
+	EventType    string                `json:"event_type"`    // 通知的类型，如 TRANSACTION.SUCCESS
+	ResourceType string                `json:"resource_type"` // 通知的资源数据类型，固定为 encrypt-resource
+	Summary      string                `json:"summary"`       // 回调摘要
+	Resource     *NotificationResource `json:"resource"`      // 通知资源数据
+
+	Plaintext []byte `json:"-"` // 解密后的资源数据，如支付成功通知的交易信息
+}
+
+// NotificationResource represents the encrypted resource of a notification.
+type NotificationResource struct {
+	Algorithm      string `json:"algorithm"`       // 加密算法类型，目前只支持 AEAD_AES_256_GCM
+	Ciphertext     string `json:"ciphertext"`      // Base64编码后的数据密文
+	AssociatedData string `json:"associated_data"` // 附加数据
+	OriginalType   string `json:"original_type"`   // 原始回调类型，如 transaction
+	Nonce          string `json:"nonce"`           // 加密使用的随机串
+}
+
+// Decode decodes the decrypted resource into v, e.g. a *CombineTransaction of a combined payment.
+func (n *Notification) Decode(v any) error {
+	if err := json.Unmarshal(n.Plaintext, v); err != nil {
+		return fmt.Errorf("unmarshal notification resource error: %w", err)
+	}
+
+	return nil
+}
+
+// Event normalizes the notification of the merchant.
+func (n *Notification) Event(mchID string) *vwx.Event {
+	createTime, _ := time.Parse(time.RFC3339, n.CreateTime)
+
+	return &vwx.Event{
+		Source:     vwx.EventSourcePay,
+		AppID:      mchID,
+		Kind:       n.EventType,
+		CreateTime: createTime,
+		Payload:    n,
+		Raw:        n.Plaintext,
+	}
+}
+
+// ParseNotification verifies the signature of the notification by the WeChat Pay public key and decrypts
+// its resource by the APIv3 key of the merchant, both are required.
+func (s *Service) ParseNotification(header http.Header, body []byte) (*Notification, error) {
+	if s.merchant.PublicKey == nil {
+		return nil, errors.New("wechat pay public key is required to verify notifications")
+	}
+
+	if len(body) > MaxNotificationSize {
+		return nil, fmt.Errorf("notification body too large: %d", len(body))
+	}
+
+	if err := s.verify(header, body); err != nil {
+		return nil, err
+	}
+
+	var notification Notification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("unmarshal notification error: %w", err)
+	}
+
+	if notification.Resource == nil {
+		return nil, errors.New("notification resource is missing")
+	}
+
+	plaintext, err := s.decryptResource(notification.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	notification.Plaintext = plaintext
+
+	return &notification, nil
+}
+
+// decryptResource decrypts the resource of a notification by the APIv3 key.
+func (s *Service) decryptResource(resource *NotificationResource) ([]byte, error) {
+	if s.merchant.APIv3Key == "" {
+		return nil, errors.New("apiv3 key is required to decrypt notifications")
+	}
+
+	if resource.Algorithm != notificationAlgorithm {
+		return nil, fmt.Errorf("unsupported notification algorithm: %s", resource.Algorithm)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(resource.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decode notification ciphertext error: %w", err)
+	}
+
+	block, err := aes.NewCipher([]byte(s.merchant.APIv3Key))
+	if err != nil {
+		return nil, fmt.Errorf("create aes cipher error: %w", err)
+	}
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(resource.Nonce))
+	if err != nil {
+		return nil, fmt.Errorf("create gcm error: %w", err)
+	}
+
+	plaintext, err := gcm.Open(nil, []byte(resource.Nonce), ciphertext, []byte(resource.AssociatedData))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt notification error: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// NotificationHandler returns the http handler of the notify url, the verified notifications are emitted
+// to the handler as events of the payload *Notification. Failures are responded with status 500,
+// so that WeChat Pay sends the notification again.
+func (s *Service) NotificationHandler(handler vwx.EventHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := vwx.NewRequestID()
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxNotificationSize+1))
+		if err != nil {
+			s.writeNotificationFailure(w, requestID, fmt.Errorf("read notification error: %w", err))
+			return
+		}
+
+		notification, err := s.ParseNotification(r.Header, body)
+		if err != nil {
+			s.writeNotificationFailure(w, requestID, err)
+			return
+		}
+
+		s.client.Logger.Infof("pay notification | request_id: %s | id: %s | event_type: %s",
+			requestID, notification.ID, notification.EventType)
+
+		event := notification.Event(s.merchant.MchID)
+		event.RequestID = requestID
+
+		if err := handler(event.Context(r.Context()), event); err != nil {
+			s.writeNotificationFailure(w, requestID, fmt.Errorf("handler failed: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Service) writeNotificationFailure(w http.ResponseWriter, requestID string, err error) {
+	s.client.Logger.Errorf("handle pay notification failed | request_id: %s | err: %v", requestID, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(map[string]string{"code": "FAIL", "message": "失败"})
+}
@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+const testAPIv3Key = "0123456789abcdef0123456789abcdef"
+
+// signedNotification encrypts the resource and signs the notification as WeChat Pay does.
+func signedNotification(t *testing.T, key *rsa.PrivateKey, resource string) (http.Header, []byte) {
+	block, err := aes.NewCipher([]byte(testAPIv3Key))
+	assert.NoError(t, err)
+
+	gcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+
+	nonce := "0123456789ab"
+	ciphertext := gcm.Seal(nil, []byte(nonce), []byte(resource), []byte("transaction"))
+
+	body, _ := json.Marshal(&Notification{
+		ID:           "EV-2018022511223320873",
+		CreateTime:   "2026-10-15T13:29:35+08:00",
+		EventType:    EventTransactionSuccess,
+		ResourceType: "encrypt-resource",
+		Summary:      "支付成功",
+		Resource: &NotificationResource{
+			Algorithm:      "AEAD_AES_256_GCM",
+			Ciphertext:     base64.StdEncoding.EncodeToString(ciphertext),
+			AssociatedData: "transaction",
+			OriginalType:   "transaction",
+			Nonce:          nonce,
+		},
+	})
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := signSHA256(key, timestamp+"\nnonce\n"+string(body)+"\n")
+	assert.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(headerTimestamp, timestamp)
+	header.Set(headerNonce, "nonce")
+	header.Set(headerSignature, signature)
+	header.Set(headerSerial, "PUB_KEY_ID_1")
+
+	return header, body
+}
+
+func TestNotification(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	merchant := &Merchant{
+		MchID:       "1900000001",
+		SerialNo:    "SERIAL",
+		PrivateKey:  key,
+		PublicKeyID: "PUB_KEY_ID_1",
+		PublicKey:   &key.PublicKey,
+		APIv3Key:    testAPIv3Key,
+	}
+	s := NewService(vwx.NewClient("appid", "secret"), merchant)
+
+	header, body := signedNotification(t, key, `{"combine_out_trade_no":"c1"}`)
+
+	notification, err := s.ParseNotification(header, body)
+	assert.NoError(t, err)
+	assert.Equal(t, EventTransactionSuccess, notification.EventType)
+
+	var transaction CombineTransaction
+	assert.NoError(t, notification.Decode(&transaction))
+	assert.Equal(t, "c1", transaction.CombineOutTradeNo)
+
+	_, err = s.ParseNotification(header, bytes.Replace(body, []byte("EV-"), []byte("EX-"), 1))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = NewService(vwx.NewClient("appid", "secret"), &Merchant{MchID: "1900000001", PublicKeyID: "PUB_KEY_ID_1", PublicKey: &key.PublicKey, APIv3Key: "fedcba9876543210fedcba9876543210"}).
+		ParseNotification(header, body)
+	assert.ErrorContains(t, err, "decrypt notification error")
+
+	_, err = NewService(vwx.NewClient("appid", "secret"), &Merchant{MchID: "1900000001", APIv3Key: testAPIv3Key}).
+		ParseNotification(header, body)
+	assert.Error(t, err)
+
+	// notifications are emitted as events, handler failures make wechat pay notify again
+	var events []*vwx.Event
+	var handlerErr error
+	handler := s.NotificationHandler(func(_ context.Context, event *vwx.Event) error {
+		events = append(events, event)
+		return handlerErr
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, &http.Request{Method: http.MethodPost, Header: header, Body: http.NoBody})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, events)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body))
+	req.Header = header
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, events, 1)
+	assert.Equal(t, vwx.EventSourcePay, events[0].Source)
+	assert.Equal(t, "1900000001", events[0].AppID)
+	assert.Equal(t, EventTransactionSuccess, events[0].Kind)
+	assert.JSONEq(t, `{"combine_out_trade_no":"c1"}`, string(events[0].Raw))
+	assert.NotEmpty(t, events[0].RequestID)
+
+	handlerErr = errors.New("db down")
+	req = httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body))
+	req.Header = header
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code":"FAIL","message":"失败"}`, w.Body.String())
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxpay provides WeChat Pay API v3 client functionality. The requests are signed by the
+// merchant private key, and the responses are verified by the WeChat Pay public key if set.
+package vwxpay
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+const (
+	baseURL = "https://api.mch.weixin.qq.com"
+
+	// defaultMaxClockSkew is the max difference between the timestamp of a signed response and the local time.
+	defaultMaxClockSkew = 5 * time.Minute
+)
+
+// Merchant is the credentials of a WeChat Pay merchant.
+type Merchant struct {
+	MchID      string          // 商户号
+	SerialNo   string          // 商户API证书序列号
+	PrivateKey *rsa.PrivateKey // 商户API证书私钥，用于请求签名
+
+	PublicKeyID string         // 微信支付公钥ID
+	PublicKey   *rsa.PublicKey // 微信支付公钥，用于验证应答签名，为空时不验证
+
+	APIv3Key string // APIv3密钥，用于解密回调通知
+}
+
+// Service provides WeChat Pay API operations of the merchant.
+type Service struct {
+	client   *vwx.Client
+	merchant *Merchant
+
+	maxClockSkew time.Duration
+}
+
+// NewService creates a new WeChat Pay service of the merchant, the http client, logger and
+// interceptors of the client are used to send the requests.
+func NewService(client *vwx.Client, merchant *Merchant, options ...func(*Service)) *Service {
+	s := &Service{
+		client:       client,
+		merchant:     merchant,
+		maxClockSkew: defaultMaxClockSkew,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// WithMaxClockSkew sets the max difference between the timestamp of a signed response and the local time.
+func WithMaxClockSkew(skew time.Duration) func(*Service) {
+	return func(s *Service) {
+		s.maxClockSkew = skew
+	}
+}
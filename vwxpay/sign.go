@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	authorizationSchema = "WECHATPAY2-SHA256-RSA2048"
+
+	headerTimestamp = "Wechatpay-Timestamp"
+	headerNonce     = "Wechatpay-Nonce"
+	headerSignature = "Wechatpay-Signature"
+	headerSerial    = "Wechatpay-Serial"
+)
+
+// ErrInvalidSignature is returned when the signature of a response or notification is invalid.
+var ErrInvalidSignature = errors.New("wechat pay signature is invalid")
+
+// LoadPrivateKey parses the PEM encoded PKCS#8 private key of the merchant, i.e. apiclient_key.pem.
+func LoadPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key error: %w", err)
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not rsa", key)
+	}
+
+	return privateKey, nil
+}
+
+// LoadPublicKey parses the PEM encoded PKIX WeChat Pay public key, i.e. pub_key.pem.
+func LoadPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key error: %w", err)
+	}
+
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is %T, not rsa", key)
+	}
+
+	return publicKey, nil
+}
+
+// newNonce returns a random nonce string of 32 hex characters.
+func newNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// signSHA256 signs the message by the private key with SHA256-RSA, the signature is base64 encoded.
+func signSHA256(privateKey *rsa.PrivateKey, message string) (string, error) {
+	sum := sha256.Sum256([]byte(message))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// verifySHA256 verifies the base64 encoded SHA256-RSA signature of the message by the public key.
+func verifySHA256(publicKey *rsa.PublicKey, message, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sum := sha256.Sum256([]byte(message))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, sum[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// authorization returns the Authorization header of the request of the method, the url path with
+// the query and the body.
+func (s *Service) authorization(method, pathWithQuery string, body []byte) (string, error) {
+	nonce := newNonce()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	message := method + "\n" + pathWithQuery + "\n" + timestamp + "\n" + nonce + "\n" + string(body) + "\n"
+
+	signature, err := signSHA256(s.merchant.PrivateKey, message)
+	if err != nil {
+		return "", fmt.Errorf("sign request error: %w", err)
+	}
+
+	return fmt.Sprintf(`%s mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		authorizationSchema, s.merchant.MchID, nonce, signature, timestamp, s.merchant.SerialNo), nil
+}
+
+// verify verifies the signature headers of a response or notification with the body, it is skipped
+// if the WeChat Pay public key of the merchant is not set.
+func (s *Service) verify(header http.Header, body []byte) error {
+	if s.merchant.PublicKey == nil {
+		return nil
+	}
+
+	if serial := header.Get(headerSerial); serial != s.merchant.PublicKeyID {
+		return fmt.Errorf("%w: unknown serial %q", ErrInvalidSignature, serial)
+	}
+
+	timestamp := header.Get(headerTimestamp)
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidSignature, timestamp)
+	}
+
+	if skew := time.Since(time.Unix(unix, 0)); skew > s.maxClockSkew || skew < -s.maxClockSkew {
+		return fmt.Errorf("%w: timestamp %s expired", ErrInvalidSignature, timestamp)
+	}
+
+	message := timestamp + "\n" + header.Get(headerNonce) + "\n" + string(body) + "\n"
+
+	return verifySHA256(s.merchant.PublicKey, message, header.Get(headerSignature))
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpay
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+)
+
+func TestLoadKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	privateKey, err := LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(privateKey))
+
+	der, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey, err := LoadPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+
+	_, err = LoadPrivateKey([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	s := NewService(vwx.NewClient("appid", "secret"), &Merchant{
+		MchID:       "1900000001",
+		SerialNo:    "SERIAL",
+		PrivateKey:  key,
+		PublicKeyID: "PUB_KEY_ID_1",
+		PublicKey:   &key.PublicKey,
+	})
+
+	body := []byte(`{"combine_out_trade_no":"c1"}`)
+
+	authorization, err := s.authorization(http.MethodPost, "/v3/combine-transactions/jsapi", body)
+	assert.NoError(t, err)
+
+	matches := regexp.MustCompile(`^WECHATPAY2-SHA256-RSA2048 mchid="1900000001",nonce_str="(\w{32})",signature="([^"]+)",timestamp="(\d+)",serial_no="SERIAL"$`).
+		FindStringSubmatch(authorization)
+	assert.Len(t, matches, 4)
+
+	message := "POST\n/v3/combine-transactions/jsapi\n" + matches[3] + "\n" + matches[1] + "\n" + string(body) + "\n"
+	assert.NoError(t, verifySHA256(&key.PublicKey, message, matches[2]))
+
+	// responses are signed by wechat pay the same way without the method and path
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := signSHA256(key, timestamp+"\nnonce\n"+string(body)+"\n")
+	assert.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(headerTimestamp, timestamp)
+	header.Set(headerNonce, "nonce")
+	header.Set(headerSignature, signature)
+	header.Set(headerSerial, "PUB_KEY_ID_1")
+	assert.NoError(t, s.verify(header, body))
+
+	assert.ErrorIs(t, s.verify(header, []byte(`{"combine_out_trade_no":"c2"}`)), ErrInvalidSignature)
+
+	header.Set(headerSerial, "PUB_KEY_ID_2")
+	assert.ErrorIs(t, s.verify(header, body), ErrInvalidSignature)
+
+	header.Set(headerSerial, "PUB_KEY_ID_1")
+	header.Set(headerTimestamp, strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+	assert.ErrorIs(t, s.verify(header, body), ErrInvalidSignature)
+}
@@ -0,0 +1,212 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/vogo/vwx"
+)
+
+const defaultAsyncQueueSize = 1024
+
+var (
+	// ErrAsyncQueueFull is returned when the queue of the async handler is full, the push fails
+	// so that WeChat retries it later.
+	ErrAsyncQueueFull = errors.New("async push queue is full")
+	// ErrAsyncHandlerClosed is returned when a message is pushed after the async handler is closed.
+	ErrAsyncHandlerClosed = errors.New("async push handler is closed")
+)
+
+// AsyncHandler acknowledges push messages immediately and processes them by the handler in
+// background workers, so that slow handlers never exceed the 5 seconds response limit of WeChat.
+// Replies of the handler are discarded, as passive replies are impossible in async mode.
+type AsyncHandler struct {
+	handler MessageHandler
+
+	Workers       int                                     // 工作协程数，默认为CPU核数
+	QueueSize     int                                     // 每个队列的长度，默认为1024
+	OrderedByUser bool                                    // 是否按FromUserName串行处理，同一用户的消息按推送顺序处理，不同用户并行处理
+	OnError       func(baseInfo *PushBaseInfo, err error) // 处理失败回调，默认记录日志
+	Logger        vwx.Logger                              // 日志，默认vlog
+
+	mu     sync.RWMutex
+	closed bool
+	queues []chan *asyncMessage
+	wg     sync.WaitGroup
+}
+
+type asyncMessage struct {
+	appID    string
+	baseInfo *PushBaseInfo
+	data     []byte
+}
+
+// NewAsyncHandler creates an async handler processing messages by the handler and starts its workers.
+func NewAsyncHandler(handler MessageHandler, options ...func(*AsyncHandler)) *AsyncHandler {
+	a := &AsyncHandler{
+		handler:   handler,
+		Workers:   runtime.NumCPU(),
+		QueueSize: defaultAsyncQueueSize,
+	}
+
+	for _, option := range options {
+		option(a)
+	}
+
+	a.Workers = max(a.Workers, 1)
+
+	// ordered workers consume their own partition, otherwise all workers share a queue
+	queueCount := 1
+	if a.OrderedByUser {
+		queueCount = a.Workers
+	}
+
+	a.queues = make([]chan *asyncMessage, queueCount)
+	for i := range a.queues {
+		a.queues[i] = make(chan *asyncMessage, a.QueueSize)
+	}
+
+	for i := range a.Workers {
+		a.wg.Add(1)
+		go a.work(a.queues[i%queueCount])
+	}
+
+	return a
+}
+
+// WithAsyncWorkers sets the number of the background workers.
+func WithAsyncWorkers(workers int) func(*AsyncHandler) {
+	return func(a *AsyncHandler) {
+		a.Workers = workers
+	}
+}
+
+// WithAsyncQueueSize sets the length of the queues of the workers.
+func WithAsyncQueueSize(size int) func(*AsyncHandler) {
+	return func(a *AsyncHandler) {
+		a.QueueSize = size
+	}
+}
+
+// WithOrderedByUser serializes the messages of the same FromUserName by partitioning them to the
+// workers by hash, so that conversational state updates never race for the same user.
+func WithOrderedByUser() func(*AsyncHandler) {
+	return func(a *AsyncHandler) {
+		a.OrderedByUser = true
+	}
+}
+
+// WithAsyncErrorHandler sets the callback of the messages failed to be processed.
+func WithAsyncErrorHandler(onError func(baseInfo *PushBaseInfo, err error)) func(*AsyncHandler) {
+	return func(a *AsyncHandler) {
+		a.OnError = onError
+	}
+}
+
+// Handle queues the message for the workers, it matches MessageHandler. An error is returned if
+// the queue is full, so that WeChat retries the push.
+func (a *AsyncHandler) Handle(appID string, baseInfo *PushBaseInfo, data []byte) ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return nil, ErrAsyncHandlerClosed
+	}
+
+	msg := &asyncMessage{
+		appID:    appID,
+		baseInfo: baseInfo,
+		data:     append([]byte(nil), data...),
+	}
+
+	select {
+	case a.queue(baseInfo) <- msg:
+		return nil, nil
+	default:
+		return nil, ErrAsyncQueueFull
+	}
+}
+
+// Close stops accepting messages and waits until the queued messages are processed.
+func (a *AsyncHandler) Close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	for _, queue := range a.queues {
+		close(queue)
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+}
+
+func (a *AsyncHandler) queue(baseInfo *PushBaseInfo) chan *asyncMessage {
+	if len(a.queues) == 1 {
+		return a.queues[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(baseInfo.FromUserName))
+
+	return a.queues[h.Sum32()%uint32(len(a.queues))]
+}
+
+func (a *AsyncHandler) work(queue <-chan *asyncMessage) {
+	defer a.wg.Done()
+
+	for msg := range queue {
+		if err := a.process(msg); err != nil {
+			if a.OnError != nil {
+				a.OnError(msg.baseInfo, err)
+				continue
+			}
+
+			a.logger().Errorf("async handle push message error | request_id: %s | from: %s | err: %v",
+				msg.baseInfo.RequestID, msg.baseInfo.FromUserName, err)
+		}
+	}
+}
+
+func (a *AsyncHandler) logger() vwx.Logger {
+	if a.Logger != nil {
+		return a.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+func (a *AsyncHandler) process(msg *asyncMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger().Errorf("async handle push message panic: %v, stack: %s", r, debug.Stack())
+			err = fmt.Errorf("async handle push message panic: %v", r)
+		}
+	}()
+
+	_, err = a.handler(msg.appID, msg.baseInfo, msg.data)
+
+	return err
+}
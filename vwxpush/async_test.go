@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncHandlerOrderedByUser(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[string][]int)
+	)
+
+	a := NewAsyncHandler(func(_ string, baseInfo *PushBaseInfo, data []byte) ([]byte, error) {
+		var seq int
+		if _, err := fmt.Sscanf(string(data), "%d", &seq); err != nil {
+			return nil, err
+		}
+
+		// later messages of a user would overtake slower earlier ones without ordering
+		time.Sleep(time.Duration(3-seq%3) * time.Millisecond)
+
+		mu.Lock()
+		received[baseInfo.FromUserName] = append(received[baseInfo.FromUserName], seq)
+		mu.Unlock()
+
+		return []byte("reply"), nil
+	}, WithAsyncWorkers(4), WithOrderedByUser())
+
+	for seq := range 9 {
+		for _, user := range []string{"user-a", "user-b", "user-c"} {
+			resp, err := a.Handle("", &PushBaseInfo{FromUserName: user}, []byte(fmt.Sprint(seq)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp != nil {
+				t.Errorf("Expected no reply in async mode, got '%s'", resp)
+			}
+		}
+	}
+
+	a.Close()
+
+	for user, seqs := range received {
+		if len(seqs) != 9 {
+			t.Fatalf("Expected 9 messages of %s, got %d", user, len(seqs))
+		}
+		for i, seq := range seqs {
+			if seq != i {
+				t.Errorf("Expected messages of %s in order, got %v", user, seqs)
+				break
+			}
+		}
+	}
+
+	if _, err := a.Handle("", &PushBaseInfo{FromUserName: "user-a"}, nil); err != ErrAsyncHandlerClosed {
+		t.Errorf("Expected ErrAsyncHandlerClosed, got %v", err)
+	}
+}
+
+func TestAsyncHandlerQueueFull(t *testing.T) {
+	started := make(chan struct{}, 1)
+	block := make(chan struct{})
+
+	var failed []string
+	a := NewAsyncHandler(func(string, *PushBaseInfo, []byte) ([]byte, error) {
+		started <- struct{}{}
+		<-block
+		return nil, fmt.Errorf("handler failed")
+	}, WithAsyncWorkers(1), WithAsyncQueueSize(1), WithAsyncErrorHandler(func(baseInfo *PushBaseInfo, _ error) {
+		failed = append(failed, baseInfo.FromUserName)
+	}))
+
+	push := func(user string) error {
+		_, err := a.Handle("", &PushBaseInfo{FromUserName: user}, nil)
+		return err
+	}
+
+	if err := push("0"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-started
+
+	if err := push("1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := push("2"); err != ErrAsyncQueueFull {
+		t.Errorf("Expected ErrAsyncQueueFull, got %v", err)
+	}
+
+	close(block)
+	a.Close()
+
+	if len(failed) != 2 {
+		t.Errorf("Expected 2 failed messages, got %v", failed)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Fixed-random encryption vectors with the parameters of the official WXBizMsgCrypt sample (token,
+// EncodingAESKey, appid, timestamp and nonce). The ciphertexts and msg_signatures were produced by
+// EncryptMessage, not published by the sample, so they pin the output of this package against
+// regressions rather than prove compatibility with other implementations.
+const (
+	compatToken          = "spamtest"
+	compatEncodingAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	compatAppID          = "wx2c2769f8efd9abc2"
+	compatTimestamp      = "1409735669"
+	compatNonce          = "1320562132"
+
+	compatMessage = "<xml><ToUserName><![CDATA[oia2TjjewbmiOUlr6X-1crbLOvLw]]></ToUserName>" +
+		"<FromUserName><![CDATA[gh_7f083739789a]]></FromUserName><CreateTime>1407743423</CreateTime>" +
+		"<MsgType><![CDATA[video]]></MsgType><Video><MediaId><![CDATA[eYJ1MbwPRJtOvIEabaxHs7TX2D-HV71s79GUxqdUkjm6Gs2Ed1KF3ulAOA9H1xG0]]></MediaId>" +
+		"<Title><![CDATA[testCallBackReplyVideo]]></Title><Description><![CDATA[testCallBackReplyVideo]]></Description></Video></xml>"
+	compatRandom       = "aaaabbbbccccdddd"
+	compatMsgSignature = "8cdeb277042555f41612aab3621d9f425fda1a85"
+	compatEncrypt      = "jn1L23DB+6ELqJ+6bruv296O82I12hMOL/W9ioUY2j+dQAxfxkycqLXrLUIwhAv/KC4RQM4kPqgnkVPCCDKVWtQDepRRVPxR3eMOMEcpI73n6y/uGWXaX0Upi8Cvdj/caESvgwlNlb8yUD7vkIktPZ1eF3ArU0stCzgRxZ4Knk0Zt2/R57z6No8a/lBhMFafB2qf9Jj3L7+yUYh2AVb26Y3i4tnjQhEEgbfwnziH6YxR9gYOcLSmI+KlnxFCdVXHtzIpyVmcKYzqeyUq7dRPPmZeUvCyh+QkkgTjEeAEEZganpHmNxMMjdI9pcbobK/ARXwvR1QGWvoyJIzHlmaSWUf55K03ma3q6jFluvJJ9N5e4byEN86fZrgv0cbtf0sKyk70tA4lYGMPS/BGXr8/FzllO4bDApXvzuhkq8IWU8RvP1wUbJBsbNZFhfdAIwEBIHzFD+gGERVYRbAEfu4miDUIZa6KbHrjC0DGuoAbfU1XRuMswRgAp34L7k7b4B3EuKl4t0cNabPOZsOi/1fKI7pBPux5lr+3qtt50Od2C3Qv32ULQyURBoqSwrA6r8Hqgx5p8NbVdizydAtStfGqhx7SVJYDGdLK0Tl6El/lawVVjSDL0u764ridUBODNrnr"
+
+	compatReply = "<xml><ToUserName><![CDATA[gh_7f083739789a]]></ToUserName>" +
+		"<FromUserName><![CDATA[oia2TjjewbmiOUlr6X-1crbLOvLw]]></FromUserName><CreateTime>1407743423</CreateTime>" +
+		"<MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content></xml>"
+	compatReplyRandom       = "0123456789abcdef"
+	compatReplyMsgSignature = "50e501c4f8db90be70c0e5dc182a1de6b2f6de61"
+	compatReplyEncrypt      = "Q3stYC6hdFzMh9T8HCvyDNMeYCOnAyf3OrKC+Ct5/vHY+4HxRM+EWjQUOGPCeNxeAFFK9M4xAFopvYIZ2/80rkoBRzhgY+AYqV/gRoikwuRSdKeBkVNk2+kvJmYE/aV9pDbgwFA6eG+j3D/12e77BqLLQbr8iuZLmS8hBMX0BM/lwD68WElVmnwXz35yZzDC0mwIyi8aPdW91Wxr5Ws2WhYv4bXN51Da+wNlTRole5D+6X0L1VmywYfJ7/uu4iVjtc4pijBMPMbXnZe7eKUJgMV05O2pjjVpQ9T74ajVvjQSzuxpxe2d+MD5reb5d31ZLCuprCu0AiBBP5nhFLoKoMGIS0znI+LuHxLWUTlGg87quQH0q0sKY3cKoVAu8qfo"
+)
+
+func TestEncryptionVectors(t *testing.T) {
+	vectors := []struct {
+		name         string
+		message      string
+		random       string
+		encrypt      string
+		msgSignature string
+	}{
+		{"message", compatMessage, compatRandom, compatEncrypt, compatMsgSignature},
+		{"reply", compatReply, compatReplyRandom, compatReplyEncrypt, compatReplyMsgSignature},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			encrypt, err := EncryptMessage(compatEncodingAESKey, compatAppID, []byte(v.message), []byte(v.random))
+			if err != nil {
+				t.Fatalf("EncryptMessage failed: %v", err)
+			}
+			if encrypt != v.encrypt {
+				t.Errorf("EncryptMessage mismatch:\n got %s\nwant %s", encrypt, v.encrypt)
+			}
+
+			if got := sha1Signature(compatToken, compatTimestamp, compatNonce, v.encrypt); got != v.msgSignature {
+				t.Errorf("msg_signature mismatch: got %s, want %s", got, v.msgSignature)
+			}
+
+			message, appID, err := DecryptMessage(compatEncodingAESKey, v.encrypt)
+			if err != nil {
+				t.Fatalf("DecryptMessage failed: %v", err)
+			}
+			if string(message) != v.message || appID != compatAppID {
+				t.Errorf("DecryptMessage mismatch: got %s (%s)", message, appID)
+			}
+		})
+	}
+
+	if _, err := EncryptMessage(compatEncodingAESKey, compatAppID, []byte(compatReply), []byte("short")); err == nil {
+		t.Error("expected error for a random block not of 16 bytes")
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	params := map[string]string{
+		"signature":     sha1Signature(compatToken, compatTimestamp, compatNonce),
+		"timestamp":     compatTimestamp,
+		"nonce":         compatNonce,
+		"msg_signature": compatMsgSignature,
+		"encrypt_type":  "aes",
+	}
+	fetcher := func(name string) string { return params[name] }
+	body := []byte("<xml><ToUserName><![CDATA[gh_7f083739789a]]></ToUserName><Encrypt><![CDATA[" + compatEncrypt + "]]></Encrypt></xml>")
+
+	receiver := NewWxPushReceiver(compatAppID, compatToken, compatEncodingAESKey, "secure", "xml", WithStrictMode(true))
+
+	response, err := receiver.HandlePushMessage(fetcher, body, func(appID string, info *PushBaseInfo, data []byte) ([]byte, error) {
+		if string(data) != compatMessage {
+			t.Errorf("unexpected message: %s", data)
+		}
+		return []byte(compatReply), nil
+	})
+	if err != nil {
+		t.Fatalf("HandlePushMessage failed: %v", err)
+	}
+
+	if !strings.HasPrefix(string(response), "<xml><Encrypt><![CDATA[") ||
+		!strings.Contains(string(response), "]]></Encrypt><MsgSignature><![CDATA[") {
+		t.Errorf("reply not in the reference format: %s", response)
+	}
+
+	var reply EncryptedResponse
+	if err = xml.Unmarshal(response, &reply); err != nil {
+		t.Fatalf("unmarshal reply failed: %v", err)
+	}
+
+	if !verifySHA1Signature(reply.MsgSignature, compatToken, strconv.FormatInt(reply.TimeStamp, 10), reply.Nonce, reply.Encrypt) {
+		t.Error("invalid reply msg_signature")
+	}
+
+	message, appID, err := DecryptMessage(compatEncodingAESKey, reply.Encrypt)
+	if err != nil {
+		t.Fatalf("decrypt reply failed: %v", err)
+	}
+	if string(message) != compatReply || appID != compatAppID {
+		t.Errorf("unexpected reply: %s (%s)", message, appID)
+	}
+
+	// messages encrypted for another appid are rejected in strict mode only
+	other := NewWxPushReceiver("wx0000000000000000", compatToken, compatEncodingAESKey, "secure", "xml", WithStrictMode(true))
+	if _, err = other.HandlePushMessage(fetcher, body, func(string, *PushBaseInfo, []byte) ([]byte, error) {
+		return nil, nil
+	}); err == nil || !strings.Contains(err.Error(), "appid mismatch") {
+		t.Errorf("expected appid mismatch error, got %v", err)
+	}
+
+	other.Strict = false
+	if _, err = other.HandlePushMessage(fetcher, body, func(string, *PushBaseInfo, []byte) ([]byte, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("non strict receiver failed: %v", err)
+	}
+}
+
+func TestCrossImplementation(t *testing.T) {
+	// replies of the default mode decrypt as reference messages
+	receiver := NewWxPushReceiver(compatAppID, compatToken, compatEncodingAESKey, "secure", "xml")
+
+	response, err := receiver.encryptResponse(compatAppID, []byte(compatReply))
+	if err != nil {
+		t.Fatalf("encryptResponse failed: %v", err)
+	}
+
+	message, appID, err := DecryptMessage(compatEncodingAESKey, response.Encrypt)
+	if err != nil {
+		t.Fatalf("DecryptMessage failed: %v", err)
+	}
+	if string(message) != compatReply || appID != compatAppID {
+		t.Errorf("unexpected message: %s (%s)", message, appID)
+	}
+
+	// random blocks of the strict mode are alphanumeric
+	random, err := randomAlphanumeric(16)
+	if err != nil {
+		t.Fatalf("randomAlphanumeric failed: %v", err)
+	}
+	for _, b := range random {
+		if !strings.ContainsRune(referenceRandomLetters, rune(b)) {
+			t.Errorf("unexpected random byte: %q", b)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// MessageHandler handles a decrypted push message, the signature matches the handler of HandlePushMessage.
+type MessageHandler func(appID string, baseInfo *PushBaseInfo, data []byte) ([]byte, error)
+
+// Dispatcher routes push messages to handlers by event (for event messages) or message type, and
+// the pushes of third-party platforms by info type. Event, message type and info type matching is
+// case-insensitive.
+//
+// Handlers registered for the same event, message type or info type are all called in the order of
+// registration, so that components tracking the same events can be registered together. The first
+// non-empty response is replied, and the errors of the handlers are joined.
+type Dispatcher struct {
+	mu               sync.RWMutex
+	eventHandlers    map[string][]MessageHandler
+	msgTypeHandler   map[string][]MessageHandler
+	infoTypeHandlers map[string][]MessageHandler
+	defaultHandler   MessageHandler
+}
+
+// NewDispatcher creates a new push message dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		eventHandlers:    make(map[string][]MessageHandler),
+		msgTypeHandler:   make(map[string][]MessageHandler),
+		infoTypeHandlers: make(map[string][]MessageHandler),
+	}
+}
+
+// HandleEvent registers the handler for event messages (MsgType=event) of the given event.
+func (d *Dispatcher) HandleEvent(event string, handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := strings.ToLower(event)
+	d.eventHandlers[key] = append(d.eventHandlers[key], handler)
+}
+
+// HandleMsgType registers the handler for messages of the given message type.
+func (d *Dispatcher) HandleMsgType(msgType string, handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := strings.ToLower(msgType)
+	d.msgTypeHandler[key] = append(d.msgTypeHandler[key], handler)
+}
+
+// HandleInfoType registers the handler for the pushes to the authorization event url of third-party
+// platforms of the given info type, e.g. component_verify_ticket.
+func (d *Dispatcher) HandleInfoType(infoType string, handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := strings.ToLower(infoType)
+	d.infoTypeHandlers[key] = append(d.infoTypeHandlers[key], handler)
+}
+
+// HandleDefault registers the handler for messages not matched by any other handler,
+// replacing the previous one.
+func (d *Dispatcher) HandleDefault(handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.defaultHandler = handler
+}
+
+// Dispatch routes the message to the matched handlers, it can be passed to HandlePushMessage directly.
+// An empty response is returned if no handler matches.
+func (d *Dispatcher) Dispatch(appID string, baseInfo *PushBaseInfo, data []byte) ([]byte, error) {
+	var (
+		reply []byte
+		errs  []error
+	)
+
+	for _, handler := range d.match(baseInfo) {
+		resp, err := handler(appID, baseInfo, data)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if len(reply) == 0 {
+			reply = resp
+		}
+	}
+
+	return reply, errors.Join(errs...)
+}
+
+func (d *Dispatcher) match(baseInfo *PushBaseInfo) []MessageHandler {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if baseInfo.InfoType != "" {
+		if handlers, ok := d.infoTypeHandlers[strings.ToLower(baseInfo.InfoType)]; ok {
+			return handlers
+		}
+	}
+
+	msgType := strings.ToLower(baseInfo.MsgType)
+
+	if msgType == "event" {
+		if handlers, ok := d.eventHandlers[strings.ToLower(baseInfo.Event)]; ok {
+			return handlers
+		}
+	}
+
+	if handlers, ok := d.msgTypeHandler[msgType]; ok {
+		return handlers
+	}
+
+	if d.defaultHandler != nil {
+		return []MessageHandler{d.defaultHandler}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+
+	reply := func(s string) MessageHandler {
+		return func(string, *PushBaseInfo, []byte) ([]byte, error) {
+			return []byte(s), nil
+		}
+	}
+
+	d.HandleEvent("subscribe", reply("subscribe"))
+	d.HandleMsgType("event", reply("event"))
+	d.HandleMsgType("text", reply("text"))
+	d.HandleInfoType("component_verify_ticket", reply("ticket"))
+
+	tests := []struct {
+		name     string
+		baseInfo *PushBaseInfo
+		expected string
+	}{
+		{name: "event case insensitive", baseInfo: &PushBaseInfo{MsgType: "event", Event: "SUBSCRIBE"}, expected: "subscribe"},
+		{name: "event fallback to msg type", baseInfo: &PushBaseInfo{MsgType: "event", Event: "SCAN"}, expected: "event"},
+		{name: "msg type", baseInfo: &PushBaseInfo{MsgType: "text"}, expected: "text"},
+		{name: "no handler", baseInfo: &PushBaseInfo{MsgType: "image"}, expected: ""},
+		{name: "info type", baseInfo: &PushBaseInfo{InfoType: "COMPONENT_VERIFY_TICKET"}, expected: "ticket"},
+		{name: "no info type handler", baseInfo: &PushBaseInfo{InfoType: "authorized"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := d.Dispatch("", tt.baseInfo, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(resp) != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, string(resp))
+			}
+		})
+	}
+
+	d.HandleDefault(reply("default"))
+	resp, _ := d.Dispatch("", &PushBaseInfo{MsgType: "image"}, nil)
+	if string(resp) != "default" {
+		t.Errorf("Expected 'default', got '%s'", string(resp))
+	}
+}
+
+func TestDispatcherFanOut(t *testing.T) {
+	d := NewDispatcher()
+
+	var calls []string
+	handler := func(name string, reply []byte, err error) MessageHandler {
+		return func(string, *PushBaseInfo, []byte) ([]byte, error) {
+			calls = append(calls, name)
+			return reply, err
+		}
+	}
+
+	errTracker := errors.New("tracker error")
+	d.HandleEvent("subscribe", handler("tracker", nil, errTracker))
+	d.HandleEvent("SUBSCRIBE", handler("cache", nil, nil))
+	d.HandleEvent("subscribe", handler("welcome", []byte("welcome"), nil))
+	d.HandleEvent("subscribe", handler("other", []byte("other"), nil))
+
+	resp, err := d.Dispatch("", &PushBaseInfo{MsgType: "event", Event: "subscribe"}, nil)
+	if !errors.Is(err, errTracker) {
+		t.Errorf("Expected the tracker error, got %v", err)
+	}
+	if string(resp) != "welcome" {
+		t.Errorf("Expected 'welcome', got '%s'", string(resp))
+	}
+	if len(calls) != 4 || calls[0] != "tracker" || calls[3] != "other" {
+		t.Errorf("Expected all handlers called in order, got %v", calls)
+	}
+}
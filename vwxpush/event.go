@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"context"
+	"time"
+
+	"github.com/vogo/vwx"
+)
+
+// EventHandler adapts the handler of normalized events to a MessageHandler, so that the pushes are consumed
+// with the other WeChat callbacks by one handler. The push is responded with the acknowledgement.
+func EventHandler(handler vwx.EventHandler) MessageHandler {
+	return func(appID string, baseInfo *PushBaseInfo, data []byte) ([]byte, error) {
+		return nil, handler(baseInfo.Context(context.Background()), NewEvent(appID, baseInfo, data))
+	}
+}
+
+// NewEvent normalizes the push message of the payload baseInfo, the kind is the event of event messages
+// or else the message type. The appid is empty for pushes in plain mode.
+func NewEvent(appID string, baseInfo *PushBaseInfo, data []byte) *vwx.Event {
+	kind := baseInfo.MsgType
+	if baseInfo.MsgType == "event" {
+		kind = baseInfo.Event
+	}
+
+	return &vwx.Event{
+		Source:     vwx.EventSourcePush,
+		AppID:      appID,
+		Kind:       kind,
+		CreateTime: time.Unix(baseInfo.CreateTime, 0),
+		RequestID:  baseInfo.RequestID,
+		Payload:    baseInfo,
+		Raw:        data,
+	}
+}
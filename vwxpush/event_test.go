@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vogo/vwx"
+)
+
+func TestEventHandler(t *testing.T) {
+	var events []*vwx.Event
+	handler := EventHandler(func(ctx context.Context, event *vwx.Event) error {
+		if vwx.RequestIDFromContext(ctx) != event.RequestID {
+			t.Errorf("Expected request id '%s' in context", event.RequestID)
+		}
+		events = append(events, event)
+		return nil
+	})
+
+	d := NewDispatcher()
+	d.HandleDefault(handler)
+
+	if _, err := d.Dispatch("wxappid", &PushBaseInfo{MsgType: "event", Event: "subscribe", CreateTime: 1700000000, RequestID: "rid"}, []byte("<xml/>")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := d.Dispatch("", &PushBaseInfo{MsgType: "text"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.Source != vwx.EventSourcePush || event.AppID != "wxappid" || event.Kind != "subscribe" ||
+		event.CreateTime.Unix() != 1700000000 || string(event.Raw) != "<xml/>" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if events[1].Kind != "text" {
+		t.Errorf("Expected kind 'text', got '%s'", events[1].Kind)
+	}
+
+	failed := EventHandler(func(context.Context, *vwx.Event) error {
+		return errors.New("failed")
+	})
+	if _, err := failed("", &PushBaseInfo{MsgType: "text"}, nil); err == nil {
+		t.Error("Expected error of the event handler")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"io"
+	"net/http"
+)
+
+// HTTPHandler returns a http handler of the push endpoint: GET requests perform the URL
+// verification, other requests are handled by HandlePushMessage with the handler.
+// Errors are responded with FailureStatus (default 500) and FailureBody.
+func (c *WxPushReceiver) HTTPHandler(handler MessageHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if r.Method == http.MethodGet {
+			echo, err := c.VerifyURL(query.Get("signature"), query.Get("timestamp"), query.Get("nonce"), query.Get("echostr"))
+			if err != nil {
+				c.logger().Errorf("verify url error: %v", err)
+				c.writeFailure(w)
+				return
+			}
+
+			_, _ = w.Write([]byte(echo))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxPushBodySize+1))
+		if err != nil {
+			c.logger().Errorf("read push body error: %v", err)
+			c.writeFailure(w)
+			return
+		}
+
+		resp, err := c.HandlePushMessage(query.Get, body, handler)
+		if err != nil {
+			c.logger().Errorf("handle push message error: %v", err)
+			c.writeFailure(w)
+			return
+		}
+
+		_, _ = w.Write(resp)
+	})
+}
+
+func (c *WxPushReceiver) writeFailure(w http.ResponseWriter) {
+	status := c.FailureStatus
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(c.FailureBody)
+}
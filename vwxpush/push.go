@@ -18,25 +18,31 @@
 package vwxpush
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"runtime/debug"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
-	"github.com/vogo/vogo/vlog"
 	"github.com/vogo/vogo/vrand"
+	"github.com/vogo/vwx"
 )
 
+// MaxPushBodySize is the max size of a push request body, larger bodies are rejected
+// before decryption or parsing.
+const MaxPushBodySize = 1 << 20
+
 // WxPushReceiver WeChat message push receiver
 type WxPushReceiver struct {
 	AppID          string // AppID
@@ -44,17 +50,97 @@ type WxPushReceiver struct {
 	EncodingAESKey string // Message encryption/decryption key
 	SecurityMode   string // Security mode: plain(plain text mode), secure(secure mode)
 	DataType       string // Data format: xml, json
+	Strict         bool   // Strict mode: match the WXBizMsgCrypt reference implementation byte-for-byte, see WithStrictMode
+
+	AckBody        []byte             // Acknowledgement body when the handler has no response, default "success", set an empty non-nil slice to respond empty
+	OnHandlerError HandlerErrorPolicy // Behavior when the handler fails, default propagating the error
+	FailureStatus  int                // HTTP status responded by HTTPHandler on errors, default 500 to trigger WeChat retry
+	FailureBody    []byte             // HTTP body responded by HTTPHandler on errors
+	Logger         vwx.Logger         // Logger of the push messages, default vlog
 }
 
+// HandlerErrorPolicy decides how HandlePushMessage responds when the handler fails.
+type HandlerErrorPolicy int
+
+const (
+	// HandlerErrorPropagate returns the handler error, so that the http layer responds an
+	// error status and WeChat retries the push.
+	HandlerErrorPropagate HandlerErrorPolicy = iota
+	// HandlerErrorAck logs the handler error and responds the acknowledgement,
+	// so that WeChat doesn't retry the push.
+	HandlerErrorAck
+)
+
+var defaultAckBody = []byte("success")
+
 // NewWxPushReceiver creates a new WeChat message push receiver
-func NewWxPushReceiver(appID, token, encodingAESKey, securityMode, dataType string) *WxPushReceiver {
-	return &WxPushReceiver{
+func NewWxPushReceiver(appID, token, encodingAESKey, securityMode, dataType string, options ...func(*WxPushReceiver)) *WxPushReceiver {
+	c := &WxPushReceiver{
 		AppID:          appID,
 		Token:          token,
 		EncodingAESKey: encodingAESKey,
 		SecurityMode:   securityMode,
 		DataType:       dataType,
 	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithAckBody sets the acknowledgement body responded when the handler has no response.
+func WithAckBody(body []byte) func(*WxPushReceiver) {
+	return func(c *WxPushReceiver) {
+		c.AckBody = body
+	}
+}
+
+// WithHandlerErrorPolicy sets how to respond when the handler fails.
+func WithHandlerErrorPolicy(policy HandlerErrorPolicy) func(*WxPushReceiver) {
+	return func(c *WxPushReceiver) {
+		c.OnHandlerError = policy
+	}
+}
+
+// WithFailureResponse sets the http status and body responded by HTTPHandler on errors.
+func WithFailureResponse(status int, body []byte) func(*WxPushReceiver) {
+	return func(c *WxPushReceiver) {
+		c.FailureStatus = status
+		c.FailureBody = body
+	}
+}
+
+// WithLogger sets the logger of the push messages.
+func WithLogger(logger vwx.Logger) func(*WxPushReceiver) {
+	return func(c *WxPushReceiver) {
+		c.Logger = logger
+	}
+}
+
+func (c *WxPushReceiver) logger() vwx.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return vwx.DefaultLogger()
+}
+
+func (c *WxPushReceiver) ackBody() []byte {
+	if c.AckBody == nil {
+		return defaultAckBody
+	}
+	return c.AckBody
+}
+
+// handlerError applies the handler error policy, it returns nil if the error is acknowledged.
+func (c *WxPushReceiver) handlerError(requestID string, err error) error {
+	if c.OnHandlerError == HandlerErrorAck {
+		c.logger().Errorf("handler failed, acknowledge push | request_id: %s | err: %v", requestID, err)
+		return nil
+	}
+
+	return fmt.Errorf("handler failed: %w", err)
 }
 
 // EncryptedResponse encrypted message structure
@@ -72,6 +158,16 @@ type PushBaseInfo struct {
 	CreateTime   int64  `xml:"CreateTime" json:"CreateTime"`
 	MsgType      string `xml:"MsgType" json:"MsgType"`
 	Event        string `xml:"Event" json:"Event"`
+	InfoType     string `xml:"InfoType" json:"InfoType"` // 第三方平台授权事件的通知类型，如 component_verify_ticket
+
+	RequestID string `xml:"-" json:"-"` // 本次推送处理的关联 id，用于串联应用日志与 SDK 日志
+	DataType  string `xml:"-" json:"-"` // 接收器的数据格式：xml、json，用于解析推送内容
+}
+
+// Context returns a context carrying the request id of the push message,
+// so that API calls made by the handler can be correlated with the push.
+func (b *PushBaseInfo) Context(ctx context.Context) context.Context {
+	return vwx.ContextWithRequestID(ctx, b.RequestID)
 }
 
 // HandlePushMessage handles WeChat message push
@@ -86,7 +182,7 @@ func (c *WxPushReceiver) HandlePushMessage(
 ) (_response []byte, _err error) {
 	defer func() {
 		if err := recover(); err != nil {
-			vlog.Errorf("handle push message error: %v, stack: %s", err, debug.Stack())
+			c.logger().Errorf("handle push message error: %v, stack: %s", err, debug.Stack())
 			_err = fmt.Errorf("handle push message error: %v", err)
 		}
 	}()
@@ -98,22 +194,28 @@ func (c *WxPushReceiver) HandlePushMessage(
 	msgSignature := parameterFetcher("msg_signature")
 	encryptType := parameterFetcher("encrypt_type")
 
-	vlog.Infof("handle push message: signature=%s, timestamp=%s, nonce=%s, msg_signature=%s, encrypt_type=%s",
-		signature, timestamp, nonce, msgSignature, encryptType)
+	requestID := vwx.NewRequestID()
+
+	c.logger().Infof("handle push message: request_id=%s, signature=%s, timestamp=%s, nonce=%s, msg_signature=%s, encrypt_type=%s",
+		requestID, signature, timestamp, nonce, msgSignature, encryptType)
+
+	if len(body) > MaxPushBodySize {
+		return nil, fmt.Errorf("push message body too large: %d", len(body))
+	}
 
 	// Process according to security mode
 	if encryptType == "aes" || c.SecurityMode == "secure" {
 		// Secure mode: requires decryption
-		return c.handleEncryptedMessage(signature, msgSignature, timestamp, nonce, body, handler)
+		return c.handleEncryptedMessage(requestID, signature, msgSignature, timestamp, nonce, body, handler)
 	} else {
 		// Plain text mode: only verify signature
-		return c.handlePlainMessage(signature, timestamp, nonce, body, handler)
+		return c.handlePlainMessage(requestID, signature, timestamp, nonce, body, handler)
 	}
 }
 
 // handleEncryptedMessage handles encrypted messages
 func (c *WxPushReceiver) handleEncryptedMessage(
-	signature, msgSignature, timestamp, nonce string,
+	requestID, signature, msgSignature, timestamp, nonce string,
 	body []byte,
 	handler func(string, *PushBaseInfo, []byte) ([]byte, error),
 ) ([]byte, error) {
@@ -122,24 +224,29 @@ func (c *WxPushReceiver) handleEncryptedMessage(
 	}
 
 	if len(body) == 0 {
-		response, err := c.encryptResponse(c.AppID, []byte("success"))
+		ack := c.ackBody()
+		if len(ack) == 0 {
+			return ack, nil
+		}
+
+		response, err := c.encryptResponse(c.AppID, ack)
 		if err != nil {
-			return nil, fmt.Errorf("encrypt response failed: %v", err)
+			return nil, fmt.Errorf("encrypt response failed: %w", err)
 		}
 
-		return c.marshal(response)
+		return c.marshalEncrypted(response)
 	}
 
 	// Parse encrypted message
 	var encryptedMsg EncryptedResponse
 	if c.DataType == "json" {
 		if err := json.Unmarshal(body, &encryptedMsg); err != nil {
-			return nil, fmt.Errorf("unmarshal encrypted message failed: %v", err)
+			return nil, fmt.Errorf("unmarshal encrypted message failed: %w", err)
 		}
 	} else {
 		// Default XML format
 		if err := xml.Unmarshal(body, &encryptedMsg); err != nil {
-			return nil, fmt.Errorf("unmarshal encrypted message failed: %v", err)
+			return nil, fmt.Errorf("unmarshal encrypted message failed: %w", err)
 		}
 	}
 
@@ -155,39 +262,49 @@ func (c *WxPushReceiver) handleEncryptedMessage(
 	var decryptedData []byte
 	decryptedData, appid, err = c.decryptMessage(encryptedMsg.Encrypt)
 	if err != nil {
-		return nil, fmt.Errorf("decrypt message failed: %v", err)
+		return nil, fmt.Errorf("decrypt message failed: %w", err)
+	}
+
+	if c.Strict && appid != c.AppID {
+		return nil, fmt.Errorf("message appid mismatch: %s", appid)
 	}
 
-	vlog.Infof("push message, appid: %s, message: %s", appid, string(decryptedData))
+	c.logger().Infof("push message, request_id: %s, appid: %s, message: %s", requestID, appid, decryptedData)
 
 	// Parse base info
 	baseInfo, err := c.parseBaseInfo(decryptedData)
 	if err != nil {
-		return nil, fmt.Errorf("parse base info failed: %v", err)
+		return nil, fmt.Errorf("parse base info failed: %w", err)
 	}
+	baseInfo.RequestID = requestID
 
 	// Call business processing function
 	responseData, err = handler(appid, baseInfo, decryptedData)
 	if err != nil {
-		return nil, fmt.Errorf("handler failed: %v", err)
+		if err = c.handlerError(requestID, err); err != nil {
+			return nil, err
+		}
 	}
 
 	// If there is response data, it needs to be encrypted and returned
 	if len(responseData) == 0 {
-		responseData = []byte("success")
+		responseData = c.ackBody()
+		if len(responseData) == 0 {
+			return responseData, nil
+		}
 	}
 
 	response, err := c.encryptResponse(appid, responseData)
 	if err != nil {
-		return nil, fmt.Errorf("encrypt response failed: %v", err)
+		return nil, fmt.Errorf("encrypt response failed: %w", err)
 	}
 
-	return c.marshal(response)
+	return c.marshalEncrypted(response)
 }
 
 // handlePlainMessage handles plain text messages
 func (c *WxPushReceiver) handlePlainMessage(
-	signature, timestamp, nonce string,
+	requestID, signature, timestamp, nonce string,
 	body []byte,
 	handler func(string, *PushBaseInfo, []byte) ([]byte, error),
 ) ([]byte, error) {
@@ -197,21 +314,24 @@ func (c *WxPushReceiver) handlePlainMessage(
 	}
 
 	if len(body) == 0 {
-		return []byte("success"), nil
+		return c.ackBody(), nil
 	}
 
-	vlog.Infof("plain message: %s", string(body))
+	c.logger().Infof("plain message, request_id: %s, message: %s", requestID, body)
 
 	// Parse base info
 	baseInfo, err := c.parseBaseInfo(body)
 	if err != nil {
-		return nil, fmt.Errorf("parse base info failed: %v", err)
+		return nil, fmt.Errorf("parse base info failed: %w", err)
 	}
+	baseInfo.RequestID = requestID
 
 	// Call business processing function
 	responseData, err := handler("", baseInfo, body)
 	if err != nil {
-		return nil, fmt.Errorf("handler failed: %v", err)
+		if err = c.handlerError(requestID, err); err != nil {
+			return nil, err
+		}
 	}
 
 	// Plain text mode returns directly
@@ -219,68 +339,82 @@ func (c *WxPushReceiver) handlePlainMessage(
 		return responseData, nil
 	}
 
-	// Default return success
-	return []byte("success"), nil
+	// Default return acknowledgement
+	return c.ackBody(), nil
 }
 
 // verifySignature verifies signature (plain text mode)
 func (c *WxPushReceiver) verifySignature(token, timestamp, nonce, signature string) bool {
-	// Sort token, timestamp, nonce parameters in dictionary order
-	params := []string{token, timestamp, nonce}
-	sort.Strings(params)
-
-	// Concatenate strings
-	str := strings.Join(params, "")
-
-	// Calculate SHA1
-	h := sha1.New()
-	h.Write([]byte(str))
-	calcSignature := fmt.Sprintf("%x", h.Sum(nil))
-
-	return calcSignature == signature
+	return verifySHA1Signature(signature, token, timestamp, nonce)
 }
 
 // verifyMsgSignature verifies message signature (secure mode)
 func (c *WxPushReceiver) verifyMsgSignature(token, timestamp, nonce, encrypt, msgSignature string) bool {
-	// Sort token, timestamp, nonce, encrypt parameters in dictionary order
-	params := []string{token, timestamp, nonce, encrypt}
+	return verifySHA1Signature(msgSignature, token, timestamp, nonce, encrypt)
+}
+
+// verifySHA1Signature checks the signature equals SHA1 of the params sorted in dictionary order and concatenated.
+func verifySHA1Signature(signature string, params ...string) bool {
+	return subtle.ConstantTimeCompare([]byte(sha1Signature(params...)), []byte(signature)) == 1
+}
+
+// sha1Signature sorts params in dictionary order, concatenates them and calculates the hex encoded SHA1.
+func sha1Signature(params ...string) string {
 	sort.Strings(params)
 
-	// Concatenate strings
-	str := strings.Join(params, "")
+	size := 0
+	for _, param := range params {
+		size += len(param)
+	}
 
-	// Calculate SHA1
-	h := sha1.New()
-	h.Write([]byte(str))
-	calcSignature := fmt.Sprintf("%x", h.Sum(nil))
+	data := make([]byte, 0, size)
+	for _, param := range params {
+		data = append(data, param...)
+	}
 
-	return calcSignature == msgSignature
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // decryptMessage decrypts message, returns message content and appid
 func (c *WxPushReceiver) decryptMessage(encryptedData string) ([]byte, string, error) {
+	return DecryptMessage(c.EncodingAESKey, encryptedData)
+}
+
+// DecryptMessage decrypts the Encrypt field of a secure mode push message, returns message content and appid.
+// It is safe to call with attacker-controlled input: malformed data returns an error and never panics.
+func DecryptMessage(encodingAESKey, encryptedData string) ([]byte, string, error) {
+	if len(encryptedData) > MaxPushBodySize {
+		return nil, "", fmt.Errorf("encrypted data too large")
+	}
+
 	// Base64 decode
 	cipherText, err := base64.StdEncoding.DecodeString(encryptedData)
 	if err != nil {
-		return nil, "", fmt.Errorf("base64 decode failed: %v", err)
+		return nil, "", fmt.Errorf("base64 decode failed: %w", err)
 	}
 
 	// Decode AES key
-	aesKey, err := base64.StdEncoding.DecodeString(c.EncodingAESKey + "=")
+	aesKey, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
 	if err != nil {
-		return nil, "", fmt.Errorf("decode aes key failed: %v", err)
+		return nil, "", fmt.Errorf("decode aes key failed: %w", err)
 	}
 
 	// AES decrypt
 	block, err := aes.NewCipher(aesKey)
 	if err != nil {
-		return nil, "", fmt.Errorf("create aes cipher failed: %v", err)
+		return nil, "", fmt.Errorf("create aes cipher failed: %w", err)
 	}
 
-	if len(cipherText) < aes.BlockSize {
+	// The first block is the IV of the remaining blocks, at least one more block is required
+	if len(cipherText) < 2*aes.BlockSize {
 		return nil, "", fmt.Errorf("cipher text too short")
 	}
 
+	if len(cipherText)%aes.BlockSize != 0 {
+		return nil, "", fmt.Errorf("cipher text is not a multiple of the block size")
+	}
+
 	iv := cipherText[:aes.BlockSize]
 	cipherText = cipherText[aes.BlockSize:]
 
@@ -288,9 +422,9 @@ func (c *WxPushReceiver) decryptMessage(encryptedData string) ([]byte, string, e
 	mode.CryptBlocks(cipherText, cipherText)
 
 	// Remove PKCS#7 padding
-	cipherText = pkcs7Unpad(cipherText)
-	if cipherText == nil {
-		return nil, "", fmt.Errorf("pkcs7 unpad failed")
+	cipherText, err = PKCS7Unpad(cipherText)
+	if err != nil {
+		return nil, "", fmt.Errorf("pkcs7 unpad failed: %w", err)
 	}
 
 	// Parse FullStr format: random(16B) + msg_len(4B) + msg + appid
@@ -301,14 +435,10 @@ func (c *WxPushReceiver) decryptMessage(encryptedData string) ([]byte, string, e
 	content := cipherText
 
 	// Read message length (4 bytes, network byte order)
-	if len(content) < 4 {
-		return nil, "", fmt.Errorf("content too short")
-	}
-
-	msgLen := int(content[0])<<24 | int(content[1])<<16 | int(content[2])<<8 | int(content[3])
+	msgLen := uint64(binary.BigEndian.Uint32(content[:4]))
 	content = content[4:]
 
-	if len(content) < msgLen {
+	if uint64(len(content)) < msgLen {
 		return nil, "", fmt.Errorf("content length mismatch")
 	}
 
@@ -324,11 +454,20 @@ func (c *WxPushReceiver) decryptMessage(encryptedData string) ([]byte, string, e
 
 // encryptResponse encrypts response data
 func (c *WxPushReceiver) encryptResponse(appID string, responseData []byte) (*EncryptedResponse, error) {
+	if c.Strict {
+		encryptStr, err := EncryptMessage(c.EncodingAESKey, appID, responseData, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.signResponse(encryptStr), nil
+	}
+
 	// Generate 16 bytes random string
 	randomBytes := make([]byte, 16)
 	_, err := rand.Read(randomBytes)
 	if err != nil {
-		return nil, fmt.Errorf("generate random bytes failed: %v", err)
+		return nil, fmt.Errorf("generate random bytes failed: %w", err)
 	}
 
 	// Construct message: random(16B) + msg_len(4B) + msg + appid
@@ -349,13 +488,13 @@ func (c *WxPushReceiver) encryptResponse(appID string, responseData []byte) (*En
 	// Decode AES key: Base64_Decode(EncodingAESKey + "=")
 	aesKey, err := base64.StdEncoding.DecodeString(c.EncodingAESKey + "=")
 	if err != nil {
-		return nil, fmt.Errorf("decode aes key failed: %v", err)
+		return nil, fmt.Errorf("decode aes key failed: %w", err)
 	}
 
 	// Create AES cipher
 	block, err := aes.NewCipher(aesKey)
 	if err != nil {
-		return nil, fmt.Errorf("create aes cipher failed: %v", err)
+		return nil, fmt.Errorf("create aes cipher failed: %w", err)
 	}
 
 	// Use the first 16 bytes of random string as IV for CBC mode
@@ -369,6 +508,11 @@ func (c *WxPushReceiver) encryptResponse(appID string, responseData []byte) (*En
 	// Base64 encode the encrypted data (cipherText)
 	encryptStr := base64.StdEncoding.EncodeToString(cipherText)
 
+	return c.signResponse(encryptStr), nil
+}
+
+// signResponse signs the encrypted reply with a new timestamp and nonce.
+func (c *WxPushReceiver) signResponse(encryptStr string) *EncryptedResponse {
 	// Generate timestamp
 	timeStamp := time.Now().Unix()
 
@@ -376,36 +520,45 @@ func (c *WxPushReceiver) encryptResponse(appID string, responseData []byte) (*En
 	nonce := vrand.RandomString("0123456789", 9) // 9 digit random number
 
 	// Generate MsgSignature: SHA1(sort([token, timestamp, nonce, encrypt]))
-	timeStampStr := strconv.FormatInt(timeStamp, 10)
-	signatureParams := []string{c.Token, timeStampStr, nonce, encryptStr}
-	sort.Strings(signatureParams)
-	signatureStr := strings.Join(signatureParams, "")
-	h := sha1.New()
-	h.Write([]byte(signatureStr))
-	msgSignature := fmt.Sprintf("%x", h.Sum(nil))
+	msgSignature := sha1Signature(c.Token, strconv.FormatInt(timeStamp, 10), nonce, encryptStr)
 
 	// Create response message
-	response := EncryptedResponse{
+	return &EncryptedResponse{
 		Encrypt:      encryptStr,
 		MsgSignature: msgSignature,
 		TimeStamp:    timeStamp,
 		Nonce:        nonce,
 	}
-
-	return &response, nil
 }
 
 func (c *WxPushReceiver) parseBaseInfo(decryptedData []byte) (*PushBaseInfo, error) {
+	baseInfo, err := ParseBaseInfo(c.DataType, decryptedData)
+	if err != nil {
+		return nil, err
+	}
+
+	baseInfo.DataType = c.DataType
+
+	return baseInfo, nil
+}
+
+// ParseBaseInfo parses the base info of a decrypted push message in the given data type (xml or json).
+// It is safe to call with attacker-controlled input.
+func ParseBaseInfo(dataType string, data []byte) (*PushBaseInfo, error) {
+	if len(data) > MaxPushBodySize {
+		return nil, fmt.Errorf("push message too large")
+	}
+
 	var pushMsg PushBaseInfo
 
-	if c.DataType == "json" {
-		if err := json.Unmarshal(decryptedData, &pushMsg); err != nil {
-			return nil, fmt.Errorf("unmarshal push message failed: %v", err)
+	if dataType == "json" {
+		if err := json.Unmarshal(data, &pushMsg); err != nil {
+			return nil, fmt.Errorf("unmarshal push message failed: %w", err)
 		}
 	} else {
 		// Default XML format
-		if err := xml.Unmarshal(decryptedData, &pushMsg); err != nil {
-			return nil, fmt.Errorf("unmarshal push message failed: %v", err)
+		if err := xml.Unmarshal(data, &pushMsg); err != nil {
+			return nil, fmt.Errorf("unmarshal push message failed: %w", err)
 		}
 	}
 
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vogo/vogo/vlog"
+)
+
+func benchSignature(params ...string) string {
+	sort.Strings(params)
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(params, ""))))
+}
+
+func BenchmarkHandlePushMessage(b *testing.B) {
+	vlog.SetOutput(io.Discard)
+	b.Cleanup(func() { vlog.SetOutput(os.Stdout) })
+
+	receiver := &WxPushReceiver{
+		AppID:          "test-app-id",
+		Token:          "01234567800123456780012345678001",
+		EncodingAESKey: "0123456780012345678001234567800123456780012",
+		DataType:       "xml",
+	}
+
+	timestamp := "1234567890"
+	nonce := "test-nonce"
+	message := []byte(`<xml><ToUserName><![CDATA[to]]></ToUserName><FromUserName><![CDATA[from]]></FromUserName>` +
+		`<CreateTime>1234567890</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[subscribe]]></Event></xml>`)
+
+	handler := func(string, *PushBaseInfo, []byte) ([]byte, error) {
+		return nil, nil
+	}
+
+	b.Run("plain", func(b *testing.B) {
+		receiver.SecurityMode = "plain"
+		params := map[string]string{
+			"signature": benchSignature(receiver.Token, timestamp, nonce),
+			"timestamp": timestamp,
+			"nonce":     nonce,
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := receiver.HandlePushMessage(func(name string) string { return params[name] }, message, handler); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("secure", func(b *testing.B) {
+		receiver.SecurityMode = "secure"
+		encMsg, err := receiver.encryptResponse(receiver.AppID, message)
+		if err != nil {
+			b.Fatal(err)
+		}
+		body, err := receiver.marshal(encMsg)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		params := map[string]string{
+			"signature":     benchSignature(receiver.Token, timestamp, nonce),
+			"msg_signature": benchSignature(receiver.Token, timestamp, nonce, encMsg.Encrypt),
+			"timestamp":     timestamp,
+			"nonce":         nonce,
+			"encrypt_type":  "aes",
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := receiver.HandlePushMessage(func(name string) string { return params[name] }, body, handler); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
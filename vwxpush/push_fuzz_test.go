@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"testing"
+)
+
+const fuzzEncodingAESKey = "0123456780012345678001234567800123456780012"
+
+func FuzzDecryptMessage(f *testing.F) {
+	receiver := &WxPushReceiver{
+		AppID:          "test-app-id",
+		Token:          "01234567800123456780012345678001",
+		EncodingAESKey: fuzzEncodingAESKey,
+	}
+
+	encMsg, err := receiver.encryptResponse(receiver.AppID, []byte("<xml><MsgType>text</MsgType></xml>"))
+	if err != nil {
+		f.Fatalf("Unexpected error: %v", err)
+	}
+
+	f.Add(encMsg.Encrypt)
+	f.Add("")
+	f.Add("AAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	f.Add("not base64")
+
+	f.Fuzz(func(t *testing.T, encrypted string) {
+		message, appid, err := DecryptMessage(fuzzEncodingAESKey, encrypted)
+		if err != nil {
+			return
+		}
+		if len(message)+len(appid) > len(encrypted) {
+			t.Errorf("decrypted data larger than input: %d > %d", len(message)+len(appid), len(encrypted))
+		}
+	})
+}
+
+func FuzzParseBaseInfo(f *testing.F) {
+	f.Add("xml", []byte(`<xml><ToUserName><![CDATA[to]]></ToUserName><MsgType><![CDATA[event]]></MsgType></xml>`))
+	f.Add("json", []byte(`{"ToUserName":"to","MsgType":"event","CreateTime":1}`))
+	f.Add("xml", []byte(`<xml><CreateTime>not a number</CreateTime></xml>`))
+	f.Add("json", []byte(`{"CreateTime":"1"}`))
+
+	f.Fuzz(func(t *testing.T, dataType string, data []byte) {
+		_, _ = ParseBaseInfo(dataType, data)
+	})
+}
+
+func FuzzPKCS7Unpad(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 5, 3, 3, 3})
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{1, 2, 3, 200})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		unpadded, err := PKCS7Unpad(data)
+		if err != nil {
+			return
+		}
+
+		padding := len(data) - len(unpadded)
+		if padding < 1 || padding > maxPaddingSize {
+			t.Errorf("invalid padding size %d accepted", padding)
+		}
+	})
+}
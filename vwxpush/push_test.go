@@ -19,13 +19,18 @@ package vwxpush
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/vogo/vogo/vstrconv"
+	"github.com/vogo/vwx"
 )
 
 func TestNewWxPushReceiver(t *testing.T) {
@@ -212,7 +217,7 @@ func TestHandlePlainMessage(t *testing.T) {
 	}
 
 	// Test with empty body
-	_, err := receiver.handlePlainMessage("invalid-signature", "1234567890", "test-nonce", []byte{}, nil)
+	_, err := receiver.handlePlainMessage("test-request-id", "invalid-signature", "1234567890", "test-nonce", []byte{}, nil)
 	if err == nil {
 		t.Error("Expected error with invalid signature")
 	}
@@ -220,7 +225,7 @@ func TestHandlePlainMessage(t *testing.T) {
 	// Test with valid signature but empty body
 	// Note: This test will fail unless we provide a valid signature
 	// For now, we'll test the error path
-	_, err = receiver.handlePlainMessage("invalid-signature", "1234567890", "test-nonce", []byte{}, nil)
+	_, err = receiver.handlePlainMessage("test-request-id", "invalid-signature", "1234567890", "test-nonce", []byte{}, nil)
 	if err == nil {
 		t.Error("Expected error with invalid signature")
 	}
@@ -232,7 +237,7 @@ func TestHandlePlainMessage(t *testing.T) {
 	}
 
 	// This will fail due to signature verification, but tests the error path
-	_, err = receiver.handlePlainMessage("invalid-signature", "1234567890", "test-nonce", []byte(xmlData), handler)
+	_, err = receiver.handlePlainMessage("test-request-id", "invalid-signature", "1234567890", "test-nonce", []byte(xmlData), handler)
 	if err == nil {
 		t.Error("Expected error with invalid signature")
 	}
@@ -247,13 +252,13 @@ func TestHandleEncryptedMessage(t *testing.T) {
 	}
 
 	// Test with invalid signature
-	_, err := receiver.handleEncryptedMessage("invalid-signature", "invalid-msg-signature", "1234567890", "test-nonce", []byte{}, nil)
+	_, err := receiver.handleEncryptedMessage("test-request-id", "invalid-signature", "invalid-msg-signature", "1234567890", "test-nonce", []byte{}, nil)
 	if err == nil {
 		t.Error("Expected error with invalid signature")
 	}
 
 	// Test with empty body and invalid signature
-	_, err = receiver.handleEncryptedMessage("invalid-signature", "invalid-msg-signature", "1234567890", "test-nonce", []byte{}, nil)
+	_, err = receiver.handleEncryptedMessage("test-request-id", "invalid-signature", "invalid-msg-signature", "1234567890", "test-nonce", []byte{}, nil)
 	if err == nil {
 		t.Error("Expected error with invalid signature")
 	}
@@ -316,6 +321,35 @@ func TestHandlePushMessage(t *testing.T) {
 	}
 }
 
+func TestHandlePushMessageRequestID(t *testing.T) {
+	receiver := &WxPushReceiver{
+		Token:        "01234567800123456780012345678001",
+		SecurityMode: "plain",
+		DataType:     "xml",
+	}
+
+	params := map[string]string{
+		"timestamp": "1234567890",
+		"nonce":     "test-nonce",
+		"signature": sha1Signature(receiver.Token, "1234567890", "test-nonce"),
+	}
+
+	var requestID string
+	handler := func(appID string, baseInfo *PushBaseInfo, data []byte) ([]byte, error) {
+		requestID = vwx.RequestIDFromContext(baseInfo.Context(context.Background()))
+		return nil, nil
+	}
+
+	xmlData := `<xml><ToUserName><![CDATA[test]]></ToUserName><MsgType><![CDATA[text]]></MsgType></xml>`
+	if _, err := receiver.HandlePushMessage(func(name string) string { return params[name] }, []byte(xmlData), handler); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requestID == "" {
+		t.Error("Expected request id in handler context")
+	}
+}
+
 func TestPkcs7Pad(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -524,3 +558,115 @@ func TestEncryptAndDecrypt(t *testing.T) {
 		t.Errorf("Expected 'test-app-id', got '%s'", appid)
 	}
 }
+
+func TestVerifyURL(t *testing.T) {
+	receiver := &WxPushReceiver{
+		AppID:          "test-app-id",
+		Token:          "01234567800123456780012345678001",
+		EncodingAESKey: "0123456780012345678001234567800123456780012",
+		SecurityMode:   "plain",
+	}
+
+	signature := sha1Signature(receiver.Token, "1234567890", "test-nonce")
+
+	echo, err := receiver.VerifyURL(signature, "1234567890", "test-nonce", "echo-123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if echo != "echo-123" {
+		t.Errorf("Expected 'echo-123', got '%s'", echo)
+	}
+
+	if _, err := receiver.VerifyURL("invalid-signature", "1234567890", "test-nonce", "echo-123"); err == nil {
+		t.Error("Expected error with invalid signature")
+	}
+
+	// encrypted echostr in secure mode
+	receiver.SecurityMode = "secure"
+	encrypted, err := receiver.encryptResponse(receiver.AppID, []byte("echo-456"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msgSignature := sha1Signature(receiver.Token, "1234567890", "test-nonce", encrypted.Encrypt)
+	echo, err = receiver.VerifyURL(msgSignature, "1234567890", "test-nonce", encrypted.Encrypt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if echo != "echo-456" {
+		t.Errorf("Expected 'echo-456', got '%s'", echo)
+	}
+
+	other, err := (&WxPushReceiver{AppID: "other-app-id", EncodingAESKey: receiver.EncodingAESKey}).encryptResponse("other-app-id", []byte("echo"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	msgSignature = sha1Signature(receiver.Token, "1234567890", "test-nonce", other.Encrypt)
+	if _, err := receiver.VerifyURL(msgSignature, "1234567890", "test-nonce", other.Encrypt); err == nil {
+		t.Error("Expected error with mismatched appid")
+	}
+}
+
+func TestAckAndHandlerErrorPolicy(t *testing.T) {
+	receiver := NewWxPushReceiver("", "01234567800123456780012345678001", "", "plain", "xml",
+		WithAckBody([]byte{}),
+		WithHandlerErrorPolicy(HandlerErrorAck),
+		WithFailureResponse(http.StatusServiceUnavailable, []byte("retry")),
+	)
+
+	params := map[string]string{
+		"timestamp": "1234567890",
+		"nonce":     "test-nonce",
+		"signature": sha1Signature(receiver.Token, "1234567890", "test-nonce"),
+	}
+	fetcher := func(name string) string { return params[name] }
+	xmlData := []byte(`<xml><ToUserName><![CDATA[test]]></ToUserName><MsgType><![CDATA[text]]></MsgType></xml>`)
+
+	failing := func(string, *PushBaseInfo, []byte) ([]byte, error) {
+		return nil, fmt.Errorf("handler error")
+	}
+
+	resp, err := receiver.HandlePushMessage(fetcher, xmlData, failing)
+	if err != nil {
+		t.Fatalf("Expected handler error acknowledged, got %v", err)
+	}
+	if len(resp) != 0 {
+		t.Errorf("Expected empty ack, got '%s'", resp)
+	}
+
+	receiver.OnHandlerError = HandlerErrorPropagate
+	receiver.AckBody = nil
+	if _, err := receiver.HandlePushMessage(fetcher, xmlData, failing); err == nil {
+		t.Error("Expected handler error propagated")
+	}
+
+	resp, _ = receiver.HandlePushMessage(fetcher, xmlData, func(string, *PushBaseInfo, []byte) ([]byte, error) { return nil, nil })
+	if string(resp) != "success" {
+		t.Errorf("Expected 'success', got '%s'", resp)
+	}
+
+	server := httptest.NewServer(receiver.HTTPHandler(failing))
+	defer server.Close()
+
+	query := fmt.Sprintf("?timestamp=%s&nonce=%s&signature=%s", params["timestamp"], params["nonce"], params["signature"])
+
+	httpResp, err := http.Post(server.URL+query, "text/xml", bytes.NewReader(xmlData))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(httpResp.Body)
+	_ = httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusServiceUnavailable || string(body) != "retry" {
+		t.Errorf("Expected 503 'retry', got %d '%s'", httpResp.StatusCode, body)
+	}
+
+	httpResp, err = http.Get(server.URL + query + "&echostr=echo-123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ = io.ReadAll(httpResp.Body)
+	_ = httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK || string(body) != "echo-123" {
+		t.Errorf("Expected 200 'echo-123', got %d '%s'", httpResp.StatusCode, body)
+	}
+}
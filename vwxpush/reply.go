@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Reply message types.
+const (
+	ReplyText                    = "text"
+	ReplyImage                   = "image"
+	ReplyVoice                   = "voice"
+	ReplyVideo                   = "video"
+	ReplyMusic                   = "music"
+	ReplyNews                    = "news"
+	ReplyTransferCustomerService = "transfer_customer_service"
+)
+
+// cdata marshals the string wrapped in CDATA, some WeChat parsers reject escaped text.
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// MarshalReply marshals the reply to be returned by a MessageHandler, e.g.
+//
+//	return vwxpush.MarshalReply(vwxpush.NewTextReply(baseInfo, "hello"))
+func MarshalReply(reply xml.Marshaler) ([]byte, error) {
+	return xml.Marshal(reply)
+}
+
+// ReplyHeader is the common header of the passive replies, the To and From of the push message swapped.
+type ReplyHeader struct {
+	ToUserName   string `json:"ToUserName"`   // 接收方，即推送消息的FromUserName
+	FromUserName string `json:"FromUserName"` // 开发者微信号，即推送消息的ToUserName
+	CreateTime   int64  `json:"CreateTime"`   // 消息创建时间
+}
+
+// NewReplyHeader creates the header of the reply to the push message.
+func NewReplyHeader(baseInfo *PushBaseInfo) ReplyHeader {
+	return ReplyHeader{
+		ToUserName:   baseInfo.FromUserName,
+		FromUserName: baseInfo.ToUserName,
+		CreateTime:   time.Now().Unix(),
+	}
+}
+
+type replyHeaderXML struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   cdata
+	FromUserName cdata
+	CreateTime   int64
+	MsgType      cdata
+}
+
+func (h *ReplyHeader) xml(msgType string) replyHeaderXML {
+	return replyHeaderXML{
+		ToUserName:   cdata{h.ToUserName},
+		FromUserName: cdata{h.FromUserName},
+		CreateTime:   h.CreateTime,
+		MsgType:      cdata{msgType},
+	}
+}
+
+// TextReply is the passive reply of a text message.
+type TextReply struct {
+	ReplyHeader
+	Content string `json:"Content"` // 回复的消息内容
+}
+
+// NewTextReply creates the text reply to the push message.
+func NewTextReply(baseInfo *PushBaseInfo, content string) *TextReply {
+	return &TextReply{ReplyHeader: NewReplyHeader(baseInfo), Content: content}
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r TextReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(struct {
+		replyHeaderXML
+		Content cdata
+	}{r.xml(ReplyText), cdata{r.Content}})
+}
+
+type mediaXML struct {
+	MediaId cdata
+}
+
+// ImageReply is the passive reply of an image message.
+type ImageReply struct {
+	ReplyHeader
+	MediaID string `json:"MediaId"` // 图片的媒体id
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r ImageReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(struct {
+		replyHeaderXML
+		Image mediaXML
+	}{r.xml(ReplyImage), mediaXML{cdata{r.MediaID}}})
+}
+
+// VoiceReply is the passive reply of a voice message.
+type VoiceReply struct {
+	ReplyHeader
+	MediaID string `json:"MediaId"` // 语音的媒体id
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r VoiceReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(struct {
+		replyHeaderXML
+		Voice mediaXML
+	}{r.xml(ReplyVoice), mediaXML{cdata{r.MediaID}}})
+}
+
+// VideoReply is the passive reply of a video message.
+type VideoReply struct {
+	ReplyHeader
+	MediaID     string `json:"MediaId"`     // 视频的媒体id
+	Title       string `json:"Title"`       // 视频标题
+	Description string `json:"Description"` // 视频描述
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r VideoReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type videoXML struct {
+		MediaId     cdata
+		Title       cdata
+		Description cdata
+	}
+
+	return e.Encode(struct {
+		replyHeaderXML
+		Video videoXML
+	}{r.xml(ReplyVideo), videoXML{cdata{r.MediaID}, cdata{r.Title}, cdata{r.Description}}})
+}
+
+// MusicReply is the passive reply of a music message.
+type MusicReply struct {
+	ReplyHeader
+	Title        string `json:"Title"`        // 音乐标题
+	Description  string `json:"Description"`  // 音乐描述
+	MusicURL     string `json:"MusicUrl"`     // 音乐链接
+	HQMusicURL   string `json:"HQMusicUrl"`   // 高质量音乐链接，WIFI环境优先使用
+	ThumbMediaID string `json:"ThumbMediaId"` // 缩略图的媒体id
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r MusicReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type musicXML struct {
+		Title        cdata
+		Description  cdata
+		MusicUrl     cdata
+		HQMusicUrl   cdata
+		ThumbMediaId cdata
+	}
+
+	return e.Encode(struct {
+		replyHeaderXML
+		Music musicXML
+	}{r.xml(ReplyMusic), musicXML{
+		cdata{r.Title}, cdata{r.Description}, cdata{r.MusicURL}, cdata{r.HQMusicURL}, cdata{r.ThumbMediaID},
+	}})
+}
+
+// NewsArticle is an article of a news reply.
+type NewsArticle struct {
+	Title       string `json:"Title"`       // 图文消息标题
+	Description string `json:"Description"` // 图文消息描述
+	PicURL      string `json:"PicUrl"`      // 图片链接，大图360*200，小图200*200
+	URL         string `json:"Url"`         // 点击图文消息跳转链接
+}
+
+// NewsReply is the passive reply of a news message, WeChat shows only the first article.
+type NewsReply struct {
+	ReplyHeader
+	Articles []*NewsArticle `json:"Articles"` // 图文消息
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r NewsReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type articleXML struct {
+		Title       cdata
+		Description cdata
+		PicUrl      cdata
+		Url         cdata
+	}
+
+	articles := make([]*articleXML, 0, len(r.Articles))
+	for _, article := range r.Articles {
+		articles = append(articles, &articleXML{
+			cdata{article.Title}, cdata{article.Description}, cdata{article.PicURL}, cdata{article.URL},
+		})
+	}
+
+	return e.Encode(struct {
+		replyHeaderXML
+		ArticleCount int
+		Articles     []*articleXML `xml:"Articles>item"`
+	}{r.xml(ReplyNews), len(articles), articles})
+}
+
+// TransferCustomerServiceReply transfers the message to the customer service.
+type TransferCustomerServiceReply struct {
+	ReplyHeader
+	KfAccount string `json:"KfAccount,omitempty"` // 指定接待的客服账号，为空时由系统分配
+}
+
+// MarshalXML marshals the reply with the string fields wrapped in CDATA.
+func (r TransferCustomerServiceReply) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type transInfoXML struct {
+		KfAccount cdata
+	}
+
+	var transInfo *transInfoXML
+	if r.KfAccount != "" {
+		transInfo = &transInfoXML{cdata{r.KfAccount}}
+	}
+
+	return e.Encode(struct {
+		replyHeaderXML
+		TransInfo *transInfoXML `xml:",omitempty"`
+	}{r.xml(ReplyTransferCustomerService), transInfo})
+}
+
+// MarshalXML marshals the response with the string fields wrapped in CDATA as the samples of WeChat.
+func (r EncryptedResponse) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(struct {
+		XMLName      xml.Name `xml:"xml"`
+		Encrypt      cdata
+		MsgSignature cdata
+		TimeStamp    int64
+		Nonce        cdata
+	}{Encrypt: cdata{r.Encrypt}, MsgSignature: cdata{r.MsgSignature}, TimeStamp: r.TimeStamp, Nonce: cdata{r.Nonce}})
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestReplyMarshalXML(t *testing.T) {
+	header := ReplyHeader{ToUserName: "user", FromUserName: "gh_account", CreateTime: 1700000000}
+	prefix := `<xml><ToUserName><![CDATA[user]]></ToUserName><FromUserName><![CDATA[gh_account]]></FromUserName>` +
+		`<CreateTime>1700000000</CreateTime>`
+
+	tests := []struct {
+		name  string
+		reply xml.Marshaler
+		want  string
+	}{
+		{
+			name:  "text",
+			reply: &TextReply{ReplyHeader: header, Content: "a < b & c"},
+			want:  prefix + `<MsgType><![CDATA[text]]></MsgType><Content><![CDATA[a < b & c]]></Content></xml>`,
+		},
+		{
+			name:  "image",
+			reply: &ImageReply{ReplyHeader: header, MediaID: "media"},
+			want:  prefix + `<MsgType><![CDATA[image]]></MsgType><Image><MediaId><![CDATA[media]]></MediaId></Image></xml>`,
+		},
+		{
+			name:  "voice",
+			reply: VoiceReply{ReplyHeader: header, MediaID: "media"},
+			want:  prefix + `<MsgType><![CDATA[voice]]></MsgType><Voice><MediaId><![CDATA[media]]></MediaId></Voice></xml>`,
+		},
+		{
+			name:  "video",
+			reply: &VideoReply{ReplyHeader: header, MediaID: "media", Title: "title", Description: "desc"},
+			want: prefix + `<MsgType><![CDATA[video]]></MsgType><Video><MediaId><![CDATA[media]]></MediaId>` +
+				`<Title><![CDATA[title]]></Title><Description><![CDATA[desc]]></Description></Video></xml>`,
+		},
+		{
+			name: "music",
+			reply: &MusicReply{ReplyHeader: header, Title: "title", Description: "desc", MusicURL: "http://m",
+				HQMusicURL: "http://hq", ThumbMediaID: "thumb"},
+			want: prefix + `<MsgType><![CDATA[music]]></MsgType><Music><Title><![CDATA[title]]></Title>` +
+				`<Description><![CDATA[desc]]></Description><MusicUrl><![CDATA[http://m]]></MusicUrl>` +
+				`<HQMusicUrl><![CDATA[http://hq]]></HQMusicUrl><ThumbMediaId><![CDATA[thumb]]></ThumbMediaId></Music></xml>`,
+		},
+		{
+			name: "news",
+			reply: &NewsReply{ReplyHeader: header, Articles: []*NewsArticle{
+				{Title: "title", Description: "desc", PicURL: "http://p", URL: "http://u?a=1&b=2"},
+			}},
+			want: prefix + `<MsgType><![CDATA[news]]></MsgType><ArticleCount>1</ArticleCount><Articles><item>` +
+				`<Title><![CDATA[title]]></Title><Description><![CDATA[desc]]></Description>` +
+				`<PicUrl><![CDATA[http://p]]></PicUrl><Url><![CDATA[http://u?a=1&b=2]]></Url></item></Articles></xml>`,
+		},
+		{
+			name:  "transfer",
+			reply: &TransferCustomerServiceReply{ReplyHeader: header},
+			want:  prefix + `<MsgType><![CDATA[transfer_customer_service]]></MsgType></xml>`,
+		},
+		{
+			name:  "transfer to account",
+			reply: &TransferCustomerServiceReply{ReplyHeader: header, KfAccount: "kf@gh"},
+			want: prefix + `<MsgType><![CDATA[transfer_customer_service]]></MsgType>` +
+				`<TransInfo><KfAccount><![CDATA[kf@gh]]></KfAccount></TransInfo></xml>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalReply(tt.reply)
+			if err != nil {
+				t.Fatalf("marshal reply error: %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("got  %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTextReply(t *testing.T) {
+	reply := NewTextReply(&PushBaseInfo{ToUserName: "gh_account", FromUserName: "user"}, "hi")
+
+	if reply.ToUserName != "user" || reply.FromUserName != "gh_account" || reply.CreateTime == 0 {
+		t.Errorf("unexpected reply header: %+v", reply.ReplyHeader)
+	}
+}
+
+func TestEncryptedResponseMarshalXML(t *testing.T) {
+	data, err := xml.Marshal(&EncryptedResponse{Encrypt: "enc", MsgSignature: "sig", TimeStamp: 1700000000, Nonce: "nonce"})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	want := `<xml><Encrypt><![CDATA[enc]]></Encrypt><MsgSignature><![CDATA[sig]]></MsgSignature>` +
+		`<TimeStamp>1700000000</TimeStamp><Nonce><![CDATA[nonce]]></Nonce></xml>`
+	if string(data) != want {
+		t.Errorf("got  %s\nwant %s", data, want)
+	}
+
+	// the marshaled response can be parsed back
+	var parsed EncryptedResponse
+	if err := xml.Unmarshal(data, &parsed); err != nil || parsed.Encrypt != "enc" || parsed.Nonce != "nonce" {
+		t.Errorf("unmarshal error: %v, %+v", err, parsed)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// referenceBlockSize is the PKCS#7 block size of the WXBizMsgCrypt reference implementation.
+	referenceBlockSize = 32
+
+	// referenceRandomLetters are the characters of the random block of the reference implementation.
+	referenceRandomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// referenceResponseFormat is the encrypted reply of the reference implementation.
+	referenceResponseFormat = "<xml><Encrypt><![CDATA[%s]]></Encrypt><MsgSignature><![CDATA[%s]]></MsgSignature>" +
+		"<TimeStamp>%d</TimeStamp><Nonce><![CDATA[%s]]></Nonce></xml>"
+)
+
+// WithStrictMode makes the receiver match the WXBizMsgCrypt reference implementation byte-for-byte:
+// replies are encrypted with an alphanumeric random block, the first 16 bytes of the AES key as IV and
+// 32 bytes PKCS#7 padding, marshaled as the reference CDATA xml, and messages encrypted for another
+// appid are rejected.
+func WithStrictMode(strict bool) func(*WxPushReceiver) {
+	return func(c *WxPushReceiver) {
+		c.Strict = strict
+	}
+}
+
+// EncryptMessage encrypts the message of the appid as the WXBizMsgCrypt reference implementation does,
+// returning the base64 encoded Encrypt field: AES-256-CBC of random(16B) + msg_len(4B) + msg + appid,
+// padded to 32 bytes blocks, with the first 16 bytes of the AES key as IV. The random block is
+// generated if nil, pass a fixed one to reproduce the output of other implementations.
+func EncryptMessage(encodingAESKey, appID string, message, random []byte) (string, error) {
+	if random == nil {
+		var err error
+		if random, err = randomAlphanumeric(16); err != nil {
+			return "", fmt.Errorf("generate random bytes failed: %w", err)
+		}
+	}
+
+	if len(random) != 16 {
+		return "", fmt.Errorf("random block must be 16 bytes, got %d", len(random))
+	}
+
+	aesKey, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if err != nil {
+		return "", fmt.Errorf("decode aes key failed: %w", err)
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return "", fmt.Errorf("create aes cipher failed: %w", err)
+	}
+
+	plainText := make([]byte, 0, 16+4+len(message)+len(appID)+referenceBlockSize)
+	plainText = append(plainText, random...)
+	plainText = binary.BigEndian.AppendUint32(plainText, uint32(len(message)))
+	plainText = append(plainText, message...)
+	plainText = append(plainText, appID...)
+	plainText = pkcs7Pad(plainText, referenceBlockSize)
+
+	cipherText := make([]byte, len(plainText))
+	cipher.NewCBCEncrypter(block, aesKey[:aes.BlockSize]).CryptBlocks(cipherText, plainText)
+
+	return base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+// randomAlphanumeric returns n random letters and digits.
+func randomAlphanumeric(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	for i := range b {
+		b[i] = referenceRandomLetters[int(b[i])%len(referenceRandomLetters)]
+	}
+
+	return b, nil
+}
+
+// marshalEncrypted marshals the encrypted reply, as the reference implementation in strict mode.
+func (c *WxPushReceiver) marshalEncrypted(response *EncryptedResponse) ([]byte, error) {
+	if c.Strict && c.DataType != "json" {
+		return fmt.Appendf(nil, referenceResponseFormat, response.Encrypt, response.MsgSignature, response.TimeStamp, response.Nonce), nil
+	}
+
+	return c.marshal(response)
+}
@@ -17,6 +17,14 @@
 
 package vwxpush
 
+import (
+	"errors"
+	"fmt"
+)
+
+// maxPaddingSize is the max PKCS#7 padding size, WeChat pads to 32 bytes blocks.
+const maxPaddingSize = 32
+
 // pkcs7Pad PKCS#7 padding
 func pkcs7Pad(data []byte, blockSize int) []byte {
 	padding := blockSize - len(data)%blockSize
@@ -28,21 +36,30 @@ func pkcs7Pad(data []byte, blockSize int) []byte {
 }
 
 func pkcs7Unpad(data []byte) []byte {
+	unpadded, err := PKCS7Unpad(data)
+	if err != nil {
+		return nil
+	}
+	return unpadded
+}
+
+// PKCS7Unpad removes PKCS#7 padding, WeChat pads to a block size of at most 32 bytes.
+func PKCS7Unpad(data []byte) ([]byte, error) {
 	length := len(data)
 	if length == 0 {
-		return nil
+		return nil, errors.New("empty data")
 	}
 
 	padding := int(data[length-1])
-	if padding > length {
-		return nil
+	if padding == 0 || padding > maxPaddingSize || padding > length {
+		return nil, fmt.Errorf("invalid padding size %d", padding)
 	}
 
 	for i := length - padding; i < length; i++ {
 		if data[i] != byte(padding) {
-			return nil
+			return nil, errors.New("inconsistent padding bytes")
 		}
 	}
 
-	return data[:length-padding]
+	return data[:length-padding], nil
 }
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxpush
+
+import (
+	"fmt"
+)
+
+// VerifyURL performs the URL configuration verification handshake and returns the content
+// to respond. The echostr is returned as is once the signature (sha1 of token, timestamp
+// and nonce) is verified. In secure mode, an encrypted echostr signed together with the
+// token, timestamp and nonce is also accepted, and the decrypted content is returned.
+//
+// It can be used to unit-test the endpoint configuration and in health checks.
+func (c *WxPushReceiver) VerifyURL(signature, timestamp, nonce, echostr string) (string, error) {
+	if c.verifySignature(c.Token, timestamp, nonce, signature) {
+		return echostr, nil
+	}
+
+	if c.SecurityMode == "secure" && c.verifyMsgSignature(c.Token, timestamp, nonce, echostr, signature) {
+		content, appid, err := c.decryptMessage(echostr)
+		if err != nil {
+			return "", fmt.Errorf("decrypt echostr failed: %w", err)
+		}
+
+		if c.AppID != "" && appid != c.AppID {
+			return "", fmt.Errorf("echostr appid mismatch: %s", appid)
+		}
+
+		return string(content), nil
+	}
+
+	return "", fmt.Errorf("invalid signature")
+}
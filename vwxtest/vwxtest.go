@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxtest provides the fake WeChat API server for testing the code using the vwx modules
+// without real credentials. It is the vwxmock server, which serves the token, jscode2session,
+// urllink, subscribe send and the other apis with programmable responses and errors.
+package vwxtest
+
+import (
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+)
+
+const (
+	// AccessToken is the default access token issued by the fake server.
+	AccessToken = vwxmock.AccessToken
+	// SessionKey is the session key returned by jscode2session.
+	SessionKey = vwxmock.SessionKey
+	// OpenIDPrefix is prepended to the login code to build the openid returned by jscode2session.
+	OpenIDPrefix = vwxmock.OpenIDPrefix
+)
+
+// Server is the fake WeChat API server.
+type Server = vwxmock.Server
+
+// Request is a request recorded by the fake server.
+type Request = vwxmock.Request
+
+// NewServer starts a fake WeChat API server, which should be closed after the test.
+func NewServer() *Server {
+	return vwxmock.NewServer()
+}
+
+// WithServer returns a client option pointing all modules at the fake server.
+func WithServer(s *Server) func(*vwx.Client) {
+	return vwxmock.WithServer(s)
+}
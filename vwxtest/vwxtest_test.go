@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxtest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxauth"
+	"github.com/vogo/vwx/vwxtest"
+)
+
+func TestServer(t *testing.T) {
+	server := vwxtest.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("appid", "secret", vwxtest.WithServer(server))
+
+	session, err := vwxauth.NewService(client).GetSessionKey("code")
+	assert.NoError(t, err)
+	assert.Equal(t, vwxtest.OpenIDPrefix+"code", session.OpenID)
+	assert.Equal(t, vwxtest.SessionKey, session.SessionKey)
+
+	svc := vwxa.NewService(client)
+
+	link, err := svc.GenerateSimpleURLLink("/pages/index", "id=1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, link)
+	assert.Equal(t, vwxtest.AccessToken, server.Requests("/wxa/generate_urllink")[0].Query.Get("access_token"))
+
+	// responses are programmable per endpoint
+	server.SetError("/cgi-bin/message/subscribe/send", 43101, "user refuse to accept the msg")
+
+	_, err = svc.SendSubscribeMessageSimple(session.OpenID, "template-id", "pages/index", map[string]string{"thing1": "hello"})
+	assert.True(t, vwx.IsErrCode(err, 43101))
+}
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package otel traces the calls to the WeChat APIs with OpenTelemetry, a span per call covering
+// its retries and the resend with a refreshed token, as a child of the span of the caller context.
+package otel
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/vogo/vwx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer.
+const ScopeName = "github.com/vogo/vwx"
+
+// Attributes of the spans.
+const (
+	AttrAppID      = attribute.Key("wechat.appid")
+	AttrEndpoint   = attribute.Key("wechat.endpoint")
+	AttrErrCode    = attribute.Key("wechat.errcode")
+	AttrRequestID  = attribute.Key("wechat.request_id")
+	AttrMethod     = attribute.Key("http.request.method")
+	AttrStatusCode = attribute.Key("http.response.status_code")
+)
+
+// Tracer is a vwx.MetricsHook starting a span per call to the WeChat APIs.
+type Tracer struct {
+	tracer trace.Tracer
+	spans  sync.Map // *vwx.APICall -> trace.Span
+}
+
+var _ vwx.MetricsHook = (*Tracer)(nil)
+
+// NewTracer creates the tracer of the calls on the tracer provider.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: tp.Tracer(ScopeName)}
+}
+
+// WithTracerProvider traces the calls of the client and the services created on it by the tracer
+// provider. The tracer is added to the metrics hook of the client, set it after vwx.WithMetricsHook.
+func WithTracerProvider(tp trace.TracerProvider) func(*vwx.Client) {
+	return func(c *vwx.Client) {
+		c.MetricsHook = vwx.MultiMetricsHook(c.MetricsHook, NewTracer(tp))
+	}
+}
+
+// OnRequest starts the span of the call.
+func (t *Tracer) OnRequest(ctx context.Context, call *vwx.APICall) {
+	_, span := t.tracer.Start(ctx, "wechat "+call.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(call.Start),
+		trace.WithAttributes(
+			AttrAppID.String(call.AppID),
+			AttrEndpoint.String(call.Endpoint),
+			AttrMethod.String(call.Method),
+		))
+
+	if call.RequestID != "" {
+		span.SetAttributes(AttrRequestID.String(call.RequestID))
+	}
+
+	t.spans.Store(call, span)
+}
+
+// OnResponse ends the span of the call with its result, calls failing or answered with a
+// non-zero errcode are marked as errors.
+func (t *Tracer) OnResponse(_ context.Context, call *vwx.APICall) {
+	value, ok := t.spans.LoadAndDelete(call)
+	if !ok {
+		return
+	}
+
+	span := value.(trace.Span)
+
+	span.SetAttributes(AttrErrCode.Int(call.ErrCode))
+	if call.Status != 0 {
+		span.SetAttributes(AttrStatusCode.Int(call.Status))
+	}
+
+	switch {
+	case call.Err != nil:
+		span.RecordError(call.Err)
+		span.SetStatus(codes.Error, call.Err.Error())
+	case call.ErrCode != 0:
+		span.SetStatus(codes.Error, "errcode "+strconv.Itoa(call.ErrCode))
+	case call.Status >= 400:
+		span.SetStatus(codes.Error, "status "+strconv.Itoa(call.Status))
+	}
+
+	span.End(trace.WithTimestamp(call.Start.Add(call.Duration)))
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxa"
+	"github.com/vogo/vwx/vwxmock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := vwx.NewClient("appid", "secret", vwxmock.WithServer(server), WithTracerProvider(tp))
+	svc := vwxa.NewService(client)
+
+	_, err := svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.NoError(t, err)
+
+	server.SetError("/wxa/generate_urllink", 40165, "invalid weapp pagepath")
+	_, err = svc.GenerateSimpleURLLink("/pages/index", "")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+
+	// the token request and the two url link calls
+	assert.Len(t, spans, 3)
+
+	link := spans[1]
+	assert.Equal(t, "wechat /wxa/generate_urllink", link.Name())
+	assert.Equal(t, trace.SpanKindClient, link.SpanKind())
+	assert.Contains(t, link.Attributes(), AttrAppID.String("appid"))
+	assert.Contains(t, link.Attributes(), AttrEndpoint.String("/wxa/generate_urllink"))
+	assert.Contains(t, link.Attributes(), AttrErrCode.Int(0))
+	assert.Contains(t, link.Attributes(), attribute.Int(string(AttrStatusCode), 200))
+	assert.Equal(t, codes.Unset, link.Status().Code)
+
+	failed := spans[2]
+	assert.Contains(t, failed.Attributes(), AttrErrCode.Int(40165))
+	assert.Equal(t, codes.Error, failed.Status().Code)
+}
@@ -0,0 +1,34 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+// call returns the json call of the api url with the access token of the service,
+// the args follow the access token in the url.
+func (s *Service) call(name, apiURL string, args ...any) *wxapi.Call {
+	return &wxapi.Call{
+		Client:      s.client,
+		Name:        name,
+		URL:         apiURL,
+		Args:        args,
+		AccessToken: s.GetAccessTokenContext,
+	}
+}
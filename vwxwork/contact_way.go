@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	contactWayAddURL    = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/add_contact_way?access_token=%s"
+	contactWayDeleteURL = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/del_contact_way?access_token=%s"
+)
+
+// ContactWayType is the type of the contact way.
+type ContactWayType int
+
+const (
+	// ContactWaySingle is the contact way of a single member.
+	ContactWaySingle ContactWayType = 1
+	// ContactWayMulti is the contact way of multiple members.
+	ContactWayMulti ContactWayType = 2
+)
+
+// ContactWayScene is the scene of the contact way.
+type ContactWayScene int
+
+const (
+	// ContactWaySceneMiniProgram is the contact way opened in the mini program.
+	ContactWaySceneMiniProgram ContactWayScene = 1
+	// ContactWaySceneQRCode is the contact way of a QR code.
+	ContactWaySceneQRCode ContactWayScene = 2
+)
+
+// ContactWayRequest represents the request of adding a contact way (联系我).
+type ContactWayRequest struct {
+	Type          ContactWayType  `json:"type"`                      // 联系方式类型,1-单人, 2-多人
+	Scene         ContactWayScene `json:"scene"`                     // 场景，1-在小程序中联系，2-通过二维码联系
+	Style         int             `json:"style,omitempty"`           // 在小程序中联系时使用的控件样式
+	Remark        string          `json:"remark,omitempty"`          // 联系方式的备注信息，用于助记，不超过30个字符
+	SkipVerify    *bool           `json:"skip_verify,omitempty"`     // 外部客户添加时是否无需验证，默认为true
+	State         string          `json:"state,omitempty"`           // 企业自定义的state参数，用于区分不同的添加渠道，不超过30个字符
+	User          []string        `json:"user,omitempty"`            // 使用该联系方式的用户userID列表，在type为1时为必填，且只能有一个
+	Party         []int           `json:"party,omitempty"`           // 使用该联系方式的部门id列表，只在type为2时有效
+	IsTemp        bool            `json:"is_temp,omitempty"`         // 是否临时会话模式，true表示使用临时会话模式，默认为false
+	ExpiresIn     int             `json:"expires_in,omitempty"`      // 临时会话二维码有效期，以秒为单位
+	ChatExpiresIn int             `json:"chat_expires_in,omitempty"` // 临时会话有效期，以秒为单位
+	UnionID       string          `json:"unionid,omitempty"`         // 可进行临时会话的客户unionid
+	IsExclusive   bool            `json:"is_exclusive,omitempty"`    // 是否开启同一外部企业客户只能添加同一个员工，默认为否
+}
+
+// ContactWayResponse represents the response of adding a contact way.
+type ContactWayResponse struct {
+	ErrCode  int    `json:"errcode"`
+	ErrMsg   string `json:"errmsg"`
+	ConfigID string `json:"config_id"` // 新增联系方式的配置id
+	QRCode   string `json:"qr_code"`   // 联系我二维码链接，仅在scene为2时返回
+}
+
+// AddContactWay adds a contact way (联系我) for the members, the state of the request is carried by
+// the external contacts added through it.
+func (s *Service) AddContactWay(req *ContactWayRequest) (*ContactWayResponse, error) {
+	return s.AddContactWayContext(context.Background(), req)
+}
+
+// AddContactWayContext is AddContactWay with the given context.
+func (s *Service) AddContactWayContext(ctx context.Context, req *ContactWayRequest) (*ContactWayResponse, error) {
+	if req.Type == ContactWaySingle && len(req.User) != 1 {
+		return nil, fmt.Errorf("single contact way requires exactly one user, got %d", len(req.User))
+	}
+
+	result, err := wxapi.PostJSON[ContactWayResponse](ctx, s.call("add contact way", contactWayAddURL), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteContactWay deletes the contact way of the config id.
+func (s *Service) DeleteContactWay(configID string) error {
+	return s.DeleteContactWayContext(context.Background(), configID)
+}
+
+// DeleteContactWayContext is DeleteContactWay with the given context.
+func (s *Service) DeleteContactWayContext(ctx context.Context, configID string) error {
+	call := s.call("delete contact way", contactWayDeleteURL)
+	call.Idempotent = true
+
+	_, err := wxapi.PostJSON[struct{}](ctx, call, map[string]string{"config_id": configID})
+
+	return err
+}
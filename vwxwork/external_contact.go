@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	followUserListURL      = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/get_follow_user_list?access_token=%s"
+	externalContactListURL = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/list?access_token=%s&userid=%s"
+	externalContactGetURL  = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/get?access_token=%s&external_userid=%s&cursor=%s"
+)
+
+// ExternalContactType is the type of the external contact.
+type ExternalContactType int
+
+const (
+	// ExternalContactWeChat is an external contact of a WeChat user.
+	ExternalContactWeChat ExternalContactType = 1
+	// ExternalContactWork is an external contact of a WeCom user.
+	ExternalContactWork ExternalContactType = 2
+)
+
+// ExternalContact is the profile of an external contact.
+type ExternalContact struct {
+	ExternalUserID string              `json:"external_userid"`          // 外部联系人的 userid
+	Name           string              `json:"name"`                     // 外部联系人的名称
+	Avatar         string              `json:"avatar"`                   // 外部联系人头像
+	Type           ExternalContactType `json:"type"`                     // 外部联系人的类型，1表示该外部联系人是微信用户，2表示该外部联系人是企业微信用户
+	Gender         int                 `json:"gender"`                   // 外部联系人性别 0-未知 1-男性 2-女性
+	UnionID        string              `json:"unionid,omitempty"`        // 外部联系人在微信开放平台的唯一身份标识
+	Position       string              `json:"position,omitempty"`       // 外部联系人的职位，仅企业微信用户
+	CorpName       string              `json:"corp_name,omitempty"`      // 外部联系人所在企业的简称，仅企业微信用户
+	CorpFullName   string              `json:"corp_full_name,omitempty"` // 外部联系人所在企业的主体名称，仅企业微信用户
+}
+
+// FollowUserTag is a tag marked by the follow user on the external contact.
+type FollowUserTag struct {
+	GroupName string `json:"group_name"` // 该成员添加此外部联系人所打标签的分组名称
+	TagName   string `json:"tag_name"`   // 该成员添加此外部联系人所打标签名称
+	TagID     string `json:"tag_id"`     // 该成员添加此外部联系人所打企业标签的id，用户自定义类型标签（type=2）不返回
+	Type      int    `json:"type"`       // 该成员添加此外部联系人所打标签类型, 1-企业设置，2-用户自定义，3-规则组标签
+}
+
+// FollowUser is a member of the corp adding the external contact.
+type FollowUser struct {
+	UserID         string           `json:"userid"`                     // 添加了此外部联系人的企业成员userid
+	Remark         string           `json:"remark"`                     // 该成员对此外部联系人的备注
+	Description    string           `json:"description"`                // 该成员对此外部联系人的描述
+	CreateTime     int64            `json:"createtime"`                 // 该成员添加此外部联系人的时间
+	Tags           []*FollowUserTag `json:"tags,omitempty"`             // 该成员添加此外部联系人所打标签
+	RemarkCorpName string           `json:"remark_corp_name,omitempty"` // 该成员对此微信客户备注的企业名称
+	RemarkMobiles  []string         `json:"remark_mobiles,omitempty"`   // 该成员对此客户备注的手机号码
+	AddWay         int              `json:"add_way"`                    // 该成员添加此客户的来源
+	OperUserID     string           `json:"oper_userid"`                // 发起添加的userid
+	State          string           `json:"state,omitempty"`            // 企业自定义的state参数，用于区分客户具体是通过哪个「联系我」或获客链接添加
+}
+
+// ExternalContactResponse represents the response of getting an external contact.
+type ExternalContactResponse struct {
+	ErrCode         int              `json:"errcode"`
+	ErrMsg          string           `json:"errmsg"`
+	ExternalContact *ExternalContact `json:"external_contact"` // 外部联系人详情
+	FollowUser      []*FollowUser    `json:"follow_user"`      // 添加了此外部联系人的企业成员，超过500人时分页返回
+	NextCursor      string           `json:"next_cursor"`      // 分页的cursor，当跟进人多于500人时返回
+}
+
+// GetFollowUserList lists the members of the corp who have the customer contact permission.
+func (s *Service) GetFollowUserList() ([]string, error) {
+	return s.GetFollowUserListContext(context.Background())
+}
+
+// GetFollowUserListContext is GetFollowUserList with the given context.
+func (s *Service) GetFollowUserListContext(ctx context.Context) ([]string, error) {
+	call := s.call("get follow user list", followUserListURL)
+	call.Idempotent = true
+
+	result, err := wxapi.GetJSON[struct {
+		FollowUser []string `json:"follow_user"`
+	}](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.FollowUser, nil
+}
+
+// ListExternalContacts lists the external userids of the customers added by the member.
+func (s *Service) ListExternalContacts(userID string) ([]string, error) {
+	return s.ListExternalContactsContext(context.Background(), userID)
+}
+
+// ListExternalContactsContext is ListExternalContacts with the given context.
+func (s *Service) ListExternalContactsContext(ctx context.Context, userID string) ([]string, error) {
+	call := s.call("list external contacts", externalContactListURL, url.QueryEscape(userID))
+	call.Idempotent = true
+	call.Summary = "userid: " + userID
+
+	result, err := wxapi.GetJSON[struct {
+		ExternalUserID []string `json:"external_userid"`
+	}](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ExternalUserID, nil
+}
+
+// GetExternalContact gets the profile of the external contact and the members following it, the
+// cursor is empty for the first page of the follow users.
+func (s *Service) GetExternalContact(externalUserID, cursor string) (*ExternalContactResponse, error) {
+	return s.GetExternalContactContext(context.Background(), externalUserID, cursor)
+}
+
+// GetExternalContactContext is GetExternalContact with the given context.
+func (s *Service) GetExternalContactContext(ctx context.Context, externalUserID, cursor string) (*ExternalContactResponse, error) {
+	call := s.call("get external contact", externalContactGetURL, url.QueryEscape(externalUserID), url.QueryEscape(cursor))
+	call.Idempotent = true
+	call.Summary = "external_userid: " + externalUserID
+
+	result, err := wxapi.GetJSON[ExternalContactResponse](ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxwork"
+)
+
+func TestExternalContacts(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	client := vwx.NewClient("corpid", "contact-secret", vwxmock.WithServer(server))
+	svc := vwxwork.NewService(client)
+
+	users, err := svc.GetFollowUserList()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{vwxmock.FollowUserID}, users)
+
+	externalUserIDs, err := svc.ListExternalContacts(users[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{vwxmock.ExternalUserIDPrefix + vwxmock.FollowUserID}, externalUserIDs)
+
+	contact, err := svc.GetExternalContact(externalUserIDs[0], "")
+	assert.NoError(t, err)
+	assert.Equal(t, externalUserIDs[0], contact.ExternalContact.ExternalUserID)
+	assert.Equal(t, vwxwork.ExternalContactWeChat, contact.ExternalContact.Type)
+	assert.Equal(t, vwxmock.FollowUserID, contact.FollowUser[0].UserID)
+
+	// the access token is fetched once and sent in the query
+	assert.Len(t, server.Requests("/cgi-bin/gettoken"), 1)
+	assert.Equal(t, "corpid", server.Requests("/cgi-bin/gettoken")[0].Query.Get("corpid"))
+
+	_, err = svc.AddContactWay(&vwxwork.ContactWayRequest{Type: vwxwork.ContactWaySingle, Scene: vwxwork.ContactWaySceneQRCode})
+	assert.Error(t, err)
+
+	way, err := svc.AddContactWay(&vwxwork.ContactWayRequest{
+		Type:  vwxwork.ContactWaySingle,
+		Scene: vwxwork.ContactWaySceneQRCode,
+		User:  []string{vwxmock.FollowUserID},
+		State: "campaign-1",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, way.ConfigID)
+	assert.NotEmpty(t, way.QRCode)
+	assert.Contains(t, string(server.Requests("/cgi-bin/externalcontact/add_contact_way")[0].Body), `"state":"campaign-1"`)
+	assert.NoError(t, svc.DeleteContactWay(way.ConfigID))
+
+	var chatIDs []string
+	req := &vwxwork.GroupChatListRequest{Limit: 2}
+	for {
+		page, err := svc.ListGroupChats(req)
+		assert.NoError(t, err)
+
+		for _, chat := range page.GroupChatList {
+			chatIDs = append(chatIDs, chat.ChatID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"mock-chat-0", "mock-chat-1", "mock-chat-2"}, chatIDs)
+
+	chat, err := svc.GetGroupChat(chatIDs[0], true)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.FollowUserID, chat.Owner)
+	assert.Equal(t, "mock-name", chat.MemberList[0].Name)
+
+	// an invalidated access token is refreshed and the call resent
+	server.AccessToken = "rotated-token"
+	_, err = svc.GetFollowUserList()
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests("/cgi-bin/gettoken"), 2)
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	groupChatListURL = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/groupchat/list?access_token=%s"
+	groupChatGetURL  = "https://qyapi.weixin.qq.com/cgi-bin/externalcontact/groupchat/get?access_token=%s"
+
+	// MaxGroupChatListLimit is the max count of group chats listed by a single call.
+	MaxGroupChatListLimit = 1000
+)
+
+// GroupChatStatusFilter filters the group chats by the status of their owner.
+type GroupChatStatusFilter int
+
+const (
+	// GroupChatStatusAll lists all group chats.
+	GroupChatStatusAll GroupChatStatusFilter = 0
+	// GroupChatStatusWaitInherit lists the group chats of resigned owners waiting for inheritance.
+	GroupChatStatusWaitInherit GroupChatStatusFilter = 1
+	// GroupChatStatusInheriting lists the group chats being inherited.
+	GroupChatStatusInheriting GroupChatStatusFilter = 2
+	// GroupChatStatusInherited lists the group chats inherited.
+	GroupChatStatusInherited GroupChatStatusFilter = 3
+)
+
+// GroupChatOwnerFilter filters the group chats by their owners.
+type GroupChatOwnerFilter struct {
+	UserIDList []string `json:"userid_list"` // 用户ID列表，最多100个
+}
+
+// GroupChatListRequest represents the request of listing the customer group chats.
+type GroupChatListRequest struct {
+	StatusFilter GroupChatStatusFilter `json:"status_filter,omitempty"` // 客户群跟进状态过滤
+	OwnerFilter  *GroupChatOwnerFilter `json:"owner_filter,omitempty"`  // 群主过滤
+	Cursor       string                `json:"cursor,omitempty"`        // 用于分页查询的游标，由上一次调用返回，首次调用不填
+	Limit        int                   `json:"limit"`                   // 分页，预期请求的数据量，取值范围 1 ~ 1000
+}
+
+// GroupChatListItem is a customer group chat.
+type GroupChatListItem struct {
+	ChatID string                `json:"chat_id"` // 客户群ID
+	Status GroupChatStatusFilter `json:"status"`  // 客户群跟进状态
+}
+
+// GroupChatListResponse represents the response of listing the customer group chats.
+type GroupChatListResponse struct {
+	ErrCode       int                  `json:"errcode"`
+	ErrMsg        string               `json:"errmsg"`
+	GroupChatList []*GroupChatListItem `json:"group_chat_list"` // 客户群列表
+	NextCursor    string               `json:"next_cursor"`     // 分页游标，下次请求时填写以获取之后分页的记录，已经没有更多的数据时为空
+}
+
+// GroupChatMember is a member of the customer group chat.
+type GroupChatMember struct {
+	UserID        string `json:"userid"`                   // 群成员id
+	Type          int    `json:"type"`                     // 成员类型，1 - 企业成员，2 - 外部联系人
+	UnionID       string `json:"unionid,omitempty"`        // 外部联系人在微信开放平台的唯一身份标识
+	JoinTime      int64  `json:"join_time"`                // 入群时间
+	JoinScene     int    `json:"join_scene"`               // 入群方式，1 - 由群成员邀请入群，2 - 由群成员邀请链接入群，3 - 通过扫描群二维码入群
+	GroupNickname string `json:"group_nickname,omitempty"` // 在群里的昵称
+	Name          string `json:"name,omitempty"`           // 名字，仅当 need_name = 1 时返回
+	Invitor       *struct {
+		UserID string `json:"userid"` // 邀请者的userid
+	} `json:"invitor,omitempty"` // 邀请者，目前仅当是由本企业内部成员邀请入群时会返回
+}
+
+// GroupChat is the detail of a customer group chat.
+type GroupChat struct {
+	ChatID     string             `json:"chat_id"`     // 客户群ID
+	Name       string             `json:"name"`        // 群名
+	Owner      string             `json:"owner"`       // 群主ID
+	CreateTime int64              `json:"create_time"` // 群的创建时间
+	Notice     string             `json:"notice"`      // 群公告
+	MemberList []*GroupChatMember `json:"member_list"` // 群成员列表
+	AdminList  []struct {
+		UserID string `json:"userid"` // 群管理员userid
+	} `json:"admin_list"` // 群管理员列表
+	MemberVersion string `json:"member_version"` // 当前群成员版本号
+}
+
+// GroupChatResponse represents the response of getting a customer group chat.
+type GroupChatResponse struct {
+	ErrCode   int        `json:"errcode"`
+	ErrMsg    string     `json:"errmsg"`
+	GroupChat *GroupChat `json:"group_chat"` // 客户群详情
+}
+
+// ListGroupChats lists the customer group chats, pages are requested by the next cursor of the response.
+func (s *Service) ListGroupChats(req *GroupChatListRequest) (*GroupChatListResponse, error) {
+	return s.ListGroupChatsContext(context.Background(), req)
+}
+
+// ListGroupChatsContext is ListGroupChats with the given context.
+func (s *Service) ListGroupChatsContext(ctx context.Context, req *GroupChatListRequest) (*GroupChatListResponse, error) {
+	if req.Limit <= 0 || req.Limit > MaxGroupChatListLimit {
+		limited := *req
+		limited.Limit = MaxGroupChatListLimit
+		req = &limited
+	}
+
+	call := s.call("list group chats", groupChatListURL)
+	call.Idempotent = true
+
+	result, err := wxapi.PostJSON[GroupChatListResponse](ctx, call, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetGroupChat gets the detail of the customer group chat, the names of the members are returned if needName.
+func (s *Service) GetGroupChat(chatID string, needName bool) (*GroupChat, error) {
+	return s.GetGroupChatContext(context.Background(), chatID, needName)
+}
+
+// GetGroupChatContext is GetGroupChat with the given context.
+func (s *Service) GetGroupChatContext(ctx context.Context, chatID string, needName bool) (*GroupChat, error) {
+	req := struct {
+		ChatID   string `json:"chat_id"`
+		NeedName int    `json:"need_name"`
+	}{ChatID: chatID}
+	if needName {
+		req.NeedName = 1
+	}
+
+	call := s.call("get group chat", groupChatGetURL)
+	call.Idempotent = true
+	call.Summary = "chat_id: " + chatID
+
+	result, err := wxapi.PostJSON[GroupChatResponse](ctx, call, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.GroupChat, nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+)
+
+// AccessTokenProvider provides the access token of the WeCom app.
+type AccessTokenProvider interface {
+	GetAccessToken() (string, error)
+	GetAccessTokenContext(ctx context.Context) (string, error)
+	RefreshAccessToken(ctx context.Context, staleToken string) (string, error)
+}
+
+// ExternalContactManager reads the external contacts (客户) of the members.
+type ExternalContactManager interface {
+	GetFollowUserList() ([]string, error)
+	GetFollowUserListContext(ctx context.Context) ([]string, error)
+	ListExternalContacts(userID string) ([]string, error)
+	ListExternalContactsContext(ctx context.Context, userID string) ([]string, error)
+	GetExternalContact(externalUserID, cursor string) (*ExternalContactResponse, error)
+	GetExternalContactContext(ctx context.Context, externalUserID, cursor string) (*ExternalContactResponse, error)
+}
+
+// ContactWayManager manages the contact ways (联系我) of the members.
+type ContactWayManager interface {
+	AddContactWay(req *ContactWayRequest) (*ContactWayResponse, error)
+	AddContactWayContext(ctx context.Context, req *ContactWayRequest) (*ContactWayResponse, error)
+	DeleteContactWay(configID string) error
+	DeleteContactWayContext(ctx context.Context, configID string) error
+}
+
+// GroupChatReader reads the customer group chats.
+type GroupChatReader interface {
+	ListGroupChats(req *GroupChatListRequest) (*GroupChatListResponse, error)
+	ListGroupChatsContext(ctx context.Context, req *GroupChatListRequest) (*GroupChatListResponse, error)
+	GetGroupChat(chatID string, needName bool) (*GroupChat, error)
+	GetGroupChatContext(ctx context.Context, chatID string, needName bool) (*GroupChat, error)
+}
+
+// MessageManager recalls the app messages and measures their reach.
+type MessageManager interface {
+	RecallMessage(msgID string) error
+	RecallMessageContext(ctx context.Context, msgID string) error
+	GetMessageStatistics(timeType StatisticsTimeType) ([]*MessageStatistics, error)
+	GetMessageStatisticsContext(ctx context.Context, timeType StatisticsTimeType) ([]*MessageStatistics, error)
+}
+
+var (
+	_ AccessTokenProvider    = (*Service)(nil)
+	_ ExternalContactManager = (*Service)(nil)
+	_ ContactWayManager      = (*Service)(nil)
+	_ GroupChatReader        = (*Service)(nil)
+	_ MessageManager         = (*Service)(nil)
+)
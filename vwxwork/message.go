@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	messageRecallURL     = "https://qyapi.weixin.qq.com/cgi-bin/message/recall?access_token=%s"
+	messageStatisticsURL = "https://qyapi.weixin.qq.com/cgi-bin/message/get_statistics?access_token=%s"
+)
+
+// StatisticsTimeType is the day of the message statistics.
+type StatisticsTimeType int
+
+const (
+	// StatisticsToday is the statistics of today.
+	StatisticsToday StatisticsTimeType = 0
+	// StatisticsYesterday is the statistics of yesterday.
+	StatisticsYesterday StatisticsTimeType = 1
+)
+
+// MessageStatistics is the count of the messages sent by an app.
+type MessageStatistics struct {
+	AgentID int    `json:"agentid"`  // 应用id
+	AppName string `json:"app_name"` // 应用名
+	Count   int64  `json:"count"`    // 发消息成功人次
+}
+
+// MessageStatisticsResponse represents the response of getting the message statistics.
+type MessageStatisticsResponse struct {
+	ErrCode    int                  `json:"errcode"`
+	ErrMsg     string               `json:"errmsg"`
+	Statistics []*MessageStatistics `json:"statistics"` // 各应用的消息统计
+}
+
+// RecallMessage recalls an app message of the msgid returned on sending, only the messages sent
+// in the last 24 hours can be recalled.
+func (s *Service) RecallMessage(msgID string) error {
+	return s.RecallMessageContext(context.Background(), msgID)
+}
+
+// RecallMessageContext is RecallMessage with the given context.
+func (s *Service) RecallMessageContext(ctx context.Context, msgID string) error {
+	if msgID == "" {
+		return errors.New("recall message requires the msgid")
+	}
+
+	call := s.call("recall message", messageRecallURL)
+	call.Idempotent = true
+
+	_, err := wxapi.PostJSON[struct{}](ctx, call, map[string]string{"msgid": msgID})
+
+	return err
+}
+
+// GetMessageStatistics retrieves the counts of the app messages sent successfully of the day,
+// by the apps of the corp.
+func (s *Service) GetMessageStatistics(timeType StatisticsTimeType) ([]*MessageStatistics, error) {
+	return s.GetMessageStatisticsContext(context.Background(), timeType)
+}
+
+// GetMessageStatisticsContext is GetMessageStatistics with the given context.
+func (s *Service) GetMessageStatisticsContext(ctx context.Context, timeType StatisticsTimeType) ([]*MessageStatistics, error) {
+	call := s.call("get message statistics", messageStatisticsURL)
+	call.Idempotent = true
+
+	result, err := wxapi.PostJSON[MessageStatisticsResponse](ctx, call, map[string]StatisticsTimeType{"time_type": timeType})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Statistics, nil
+}
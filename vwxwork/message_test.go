@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/vwxmock"
+	"github.com/vogo/vwx/vwxwork"
+)
+
+func TestMessages(t *testing.T) {
+	server := vwxmock.NewServer()
+	defer server.Close()
+
+	svc := vwxwork.NewService(vwx.NewClient("corpid", "app-secret", vwxmock.WithServer(server)))
+
+	assert.NoError(t, svc.RecallMessage(vwxmock.WorkMsgIDPrefix+"1"))
+	assert.Error(t, svc.RecallMessage(""))
+	assert.True(t, vwx.IsErrCode(svc.RecallMessage("unknown"), 40008))
+	assert.JSONEq(t, `{"msgid":"mock-msgid-1"}`, string(server.Requests("/cgi-bin/message/recall")[0].Body))
+
+	statistics, err := svc.GetMessageStatistics(vwxwork.StatisticsYesterday)
+	assert.NoError(t, err)
+	assert.Equal(t, vwxmock.WorkAgentID, statistics[0].AgentID)
+	assert.Equal(t, int64(101), statistics[0].Count)
+}
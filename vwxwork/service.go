@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwxwork provides WeCom (企业微信) API client functionality, including the external
+// contact (客户联系) APIs. The client is created with the corpid and the secret of the app,
+// e.g. the secret of the customer contact app for the external contact APIs.
+package vwxwork
+
+import (
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/token"
+)
+
+// Service provides WeCom API operations.
+type Service struct {
+	client *vwx.Client
+	tokens *token.Manager
+}
+
+// NewService creates a new WeCom service, it installs the access token refresher of the client
+// if not set, so that calls failing with an invalidated token are resent with a new one.
+func NewService(client *vwx.Client, options ...func(*Service)) *Service {
+	s := &Service{client: client}
+	s.tokens = &token.Manager{
+		Client:   client,
+		Name:     "access token",
+		CacheKey: s.cacheKeyAccessToken(),
+		Fetch:    s.requestAccessToken,
+	}
+
+	if client.TokenRefresher == nil {
+		client.TokenRefresher = s.RefreshAccessToken
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
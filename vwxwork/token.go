@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwxwork
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/vogo/vwx"
+	"github.com/vogo/vwx/internal/wxapi"
+)
+
+const (
+	accessTokenURL = "https://qyapi.weixin.qq.com/cgi-bin/gettoken?corpid=%s&corpsecret=%s"
+)
+
+// accessTokenResponse represents the response of the gettoken endpoint.
+type accessTokenResponse struct {
+	ErrCode     int    `json:"errcode"`
+	ErrMsg      string `json:"errmsg"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// cacheKeyAccessToken returns the cache key of the access token, the apps of a corp have their own
+// tokens, so that the key is distinguished by the digest of the secret.
+func (s *Service) cacheKeyAccessToken() string {
+	sum := sha256.Sum256([]byte(s.client.AppSecret))
+
+	return s.client.CacheKeyPrefix + "vwxwork:access_token:" + s.client.AppID + ":" + hex.EncodeToString(sum[:4])
+}
+
+// GetAccessToken retrieves the access token of the app with caching support.
+func (s *Service) GetAccessToken() (string, error) {
+	return s.GetAccessTokenContext(context.Background())
+}
+
+// GetAccessTokenContext is GetAccessToken with the given context.
+func (s *Service) GetAccessTokenContext(ctx context.Context) (string, error) {
+	if p := s.client.TokenProvider(); p != nil {
+		return p.AccessToken(ctx)
+	}
+
+	if s.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return s.tokens.Get(ctx)
+}
+
+// RefreshAccessToken replaces the stale access token invalidated by WeCom with a new one, the cached
+// token is returned if it was already refreshed by another call.
+func (s *Service) RefreshAccessToken(ctx context.Context, staleToken string) (string, error) {
+	if p := s.client.TokenProvider(); p != nil {
+		return p.RefreshAccessToken(ctx, staleToken)
+	}
+
+	if s.client.SecretFree() {
+		return "", vwx.ErrAppSecretRequired
+	}
+
+	return s.tokens.Refresh(ctx, staleToken)
+}
+
+// requestAccessToken requests a new access token from WeCom.
+func (s *Service) requestAccessToken(ctx context.Context, _ bool) (string, int, error) {
+	call := &wxapi.Call{
+		Client:     s.client,
+		Name:       "get work access token",
+		URL:        accessTokenURL,
+		Args:       []any{s.client.AppID, s.client.AppSecret},
+		Idempotent: true,
+		Summary:    "corpid: " + s.client.AppID,
+	}
+
+	result, err := wxapi.GetJSON[accessTokenResponse](ctx, call)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return result.AccessToken, result.ExpiresIn, nil
+}